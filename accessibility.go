@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// accessibleRow is a row of the data list in accessible mode: a focusable label that
+// spells out the line, such as "offset 00000040, bytes 4D 5A 90 00, text MZ..", instead
+// of drawing it as columns of text that assistive technology can't read
+type accessibleRow struct {
+	widget.BaseWidget
+	h          *HexDumpApp
+	line       int
+	focused    bool
+	label      *widget.Label
+	background *canvas.Rectangle
+}
+
+// newAccessibleRow creates an accessible row widget for the given application
+func newAccessibleRow(h *HexDumpApp) *accessibleRow {
+	row := &accessibleRow{h: h, line: -1, label: widget.NewLabel(""),
+		background: canvas.NewRectangle(color.Transparent)}
+	row.label.TextStyle.Monospace = true
+	row.ExtendBaseWidget(row)
+	return row
+}
+
+// setLine changes the line described by the row
+func (r *accessibleRow) setLine(line int) {
+	r.line = line
+	r.update()
+}
+
+// update refreshes the row's description and its background, which shows the focus
+// and selected bytes
+func (r *accessibleRow) update() {
+	h := r.h
+	offset := h.rowStart(r.line)
+	description := ""
+	if r.line >= 0 && offset < len(h.fileData) {
+		description = h.describeRow(offset)
+	}
+	r.label.SetText(description)
+	switch {
+	case description == "":
+		r.background.FillColor = color.Transparent
+	case r.focused:
+		r.background.FillColor = theme.Color(theme.ColorNameFocus)
+	case h.selStart < h.rowEnd(offset) && h.selEnd > offset:
+		r.background.FillColor = theme.Color(colorNameSelection)
+	default:
+		r.background.FillColor = color.Transparent
+	}
+	r.background.Refresh()
+}
+
+// CreateRenderer implements fyne.Widget
+func (r *accessibleRow) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(r.background, r.label))
+}
+
+// Refresh implements fyne.Widget, redescribing the row as the selection changes
+func (r *accessibleRow) Refresh() {
+	r.update()
+	r.BaseWidget.Refresh()
+}
+
+// Tapped focuses the row and moves the caret to its first byte
+func (r *accessibleRow) Tapped(*fyne.PointEvent) {
+	r.h.focusRow(r.line)
+}
+
+// FocusGained implements fyne.Focusable
+func (r *accessibleRow) FocusGained() {
+	r.focused = true
+	r.update()
+}
+
+// FocusLost implements fyne.Focusable
+func (r *accessibleRow) FocusLost() {
+	r.focused = false
+	r.update()
+}
+
+// TypedRune implements fyne.Focusable
+func (r *accessibleRow) TypedRune(rune) {}
+
+// TypedKey moves through the data from the focused row: Up and Down, Page Up and Page
+// Down, and Home and End move between rows; Left and Right select the previous or next
+// byte, extending the selection with Shift; Space and Enter select the whole row
+func (r *accessibleRow) TypedKey(event *fyne.KeyEvent) {
+	h := r.h
+	if r.line < 0 || len(h.fileData) == 0 {
+		return
+	}
+	switch event.Name {
+	case fyne.KeyUp:
+		h.focusRow(r.line - 1)
+	case fyne.KeyDown:
+		h.focusRow(r.line + 1)
+	case fyne.KeyPageUp:
+		h.focusRow(r.line - h.visibleRows())
+	case fyne.KeyPageDown:
+		h.focusRow(r.line + h.visibleRows())
+	case fyne.KeyHome:
+		h.focusRow(0)
+	case fyne.KeyEnd:
+		h.focusRow(h.listLength() - 1)
+	case fyne.KeyLeft, fyne.KeyRight:
+		offset := h.caret - 1
+		if event.Name == fyne.KeyRight {
+			offset = h.caret + 1
+		}
+		offset = max(0, min(offset, len(h.fileData)-1))
+		if h.shiftDown {
+			h.extendSelection(offset)
+		} else {
+			h.caret, h.selAnchor = offset, offset
+			h.setSelection(offset, offset+1)
+		}
+		h.focusRow(h.rowOf(offset))
+	case fyne.KeySpace, fyne.KeyReturn, fyne.KeyEnter:
+		offset := h.rowStart(r.line)
+		h.setSelection(offset, h.rowEnd(offset))
+	}
+}
+
+// KeyDown implements desktop.Keyable, noting when Shift is held to extend the selection
+func (r *accessibleRow) KeyDown(event *fyne.KeyEvent) {
+	if event.Name == desktop.KeyShiftLeft || event.Name == desktop.KeyShiftRight {
+		r.h.shiftDown = true
+	}
+}
+
+// KeyUp implements desktop.Keyable
+func (r *accessibleRow) KeyUp(event *fyne.KeyEvent) {
+	if event.Name == desktop.KeyShiftLeft || event.Name == desktop.KeyShiftRight {
+		r.h.shiftDown = false
+	}
+}
+
+// describeRow spells out the row starting at offset: its offset, bytes, and characters,
+// followed by how many of them are selected and the bookmark they're in, which the
+// normal rows show only as colors
+func (h *HexDumpApp) describeRow(offset int) string {
+	end := h.rowEnd(offset)
+	address := strings.TrimSpace(h.formatAddress(offset))
+	var text string
+	if lines := h.collapsedAt(offset); lines != nil {
+		text = fmt.Sprintf("offset %s, %d bytes of %s padding", address, end-offset, formatHex(uint64(lines.value), 2))
+	} else {
+		text = fmt.Sprintf("offset %s, bytes %s, text %s", address, formatPatchBytes(h.fileData[offset:end]),
+			h.generateCharLine(offset))
+	}
+	if selected := min(end, h.selEnd) - max(offset, h.selStart); selected > 0 {
+		text += fmt.Sprintf(", %d selected", selected)
+	}
+	if label := h.bookmarkAt(offset); label != "" {
+		text += ", bookmark " + label
+	}
+	return text
+}
+
+// visibleRows returns the number of rows the data list shows at once in accessible mode
+func (h *HexDumpApp) visibleRows() int {
+	return max(1, int(h.dataList.Size().Height/h.rowPitch()))
+}
+
+// focusRow scrolls row of the data list into view and focuses it, moving the caret into
+// it unless the caret is there already
+func (h *HexDumpApp) focusRow(row int) {
+	row = max(0, min(row, h.listLength()-1))
+	offset := h.rowStart(row)
+	if h.caret < offset || h.caret >= h.rowEnd(offset) {
+		h.caret, h.selAnchor = offset, offset
+		h.updateStatus()
+	}
+	h.dataList.ScrollTo(row)
+	h.syncPositionSlider(offset)
+
+	// Rows are reused as the list scrolls, so the focused row may now show another line
+	for _, r := range h.accessibleRows {
+		if r.line == row {
+			if h.window.Canvas().Focused() == r {
+				r.update()
+			} else {
+				h.window.Canvas().Focus(r)
+			}
+			return
+		}
+	}
+}
+
+// toggleAccessibleMode switches the data list between drawn rows and accessible rows,
+// recreating the list since it keeps the rows it has made
+func (h *HexDumpApp) toggleAccessibleMode() {
+	appSettings.AccessibleMode = !appSettings.AccessibleMode
+	saveSettings()
+	h.window.Canvas().Unfocus()
+	h.rebuildDataList()
+}
+
+// focusNextPane moves the focus to the next of the toolbar, the data list, and the side
+// panel. Outside accessible mode the data list has the keyboard when nothing is focused.
+func (h *HexDumpApp) focusNextPane() {
+	panes := []fyne.CanvasObject{h.toolbarBox, h.dataList}
+	if appSettings.PanelVisible {
+		panes = append(panes, h.panelSplit.Trailing)
+	}
+	current := 1
+	if focused := h.window.Canvas().Focused(); focused != nil {
+		for index, pane := range panes {
+			if objectWithin(focused, pane) {
+				current = index
+			}
+		}
+	}
+	for step := 1; step <= len(panes); step++ {
+		pane := panes[(current+step)%len(panes)]
+		if pane == h.dataList {
+			h.window.Canvas().Unfocus()
+			if appSettings.AccessibleMode && len(h.fileData) > 0 {
+				h.focusRow(h.rowOf(h.caret))
+			}
+			return
+		}
+		if h.focusWithin(pane) {
+			return
+		}
+	}
+}
+
+// focusWithin focuses the first control in pane that Tab reaches, reporting whether
+// there is one
+func (h *HexDumpApp) focusWithin(pane fyne.CanvasObject) bool {
+	c := h.window.Canvas()
+	c.Unfocus()
+	var first fyne.Focusable
+	for {
+		c.FocusNext()
+		focused := c.Focused()
+		if focused == nil || focused == first {
+			c.Unfocus()
+			return false
+		}
+		if first == nil {
+			first = focused
+		}
+		if objectWithin(focused, pane) {
+			return true
+		}
+	}
+}
+
+// objectWithin reports whether object lies inside the visible region
+func objectWithin(object fyne.Focusable, region fyne.CanvasObject) bool {
+	canvasObject, ok := object.(fyne.CanvasObject)
+	if !ok || region == nil || !region.Visible() {
+		return false
+	}
+	driver := fyne.CurrentApp().Driver()
+	position := driver.AbsolutePositionForObject(canvasObject)
+	start := driver.AbsolutePositionForObject(region)
+	end := start.Add(region.Size())
+	return position.X >= start.X && position.Y >= start.Y && position.X < end.X && position.Y < end.Y
+}
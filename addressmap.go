@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/pe"
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// addressSegment maps a range of file offsets onto a range of virtual addresses
+type addressSegment struct {
+	name           string
+	fileOffset     int
+	virtualAddress uint64
+	length         int
+}
+
+// segmentsFromHeaders derives address segments from the PE section table or the ELF
+// program headers of data
+func segmentsFromHeaders(data []byte) ([]addressSegment, error) {
+	if elfFile, err := elf.NewFile(bytes.NewReader(data)); err == nil {
+		var segments []addressSegment
+		for index, prog := range elfFile.Progs {
+			if prog.Type != elf.PT_LOAD || prog.Filesz == 0 {
+				continue
+			}
+			segments = append(segments, addressSegment{
+				name:           fmt.Sprintf("LOAD %d", index),
+				fileOffset:     int(prog.Off),
+				virtualAddress: prog.Vaddr,
+				length:         int(prog.Filesz),
+			})
+		}
+		return segments, nil
+	}
+
+	if peFile, err := pe.NewFile(bytes.NewReader(data)); err == nil {
+		var imageBase uint64
+		var headersSize uint32
+		switch header := peFile.OptionalHeader.(type) {
+		case *pe.OptionalHeader32:
+			imageBase, headersSize = uint64(header.ImageBase), header.SizeOfHeaders
+		case *pe.OptionalHeader64:
+			imageBase, headersSize = header.ImageBase, header.SizeOfHeaders
+		}
+
+		segments := []addressSegment{{name: "Headers", virtualAddress: imageBase, length: int(headersSize)}}
+		for _, section := range peFile.Sections {
+			if section.Size == 0 {
+				continue
+			}
+			length := section.Size
+			if section.VirtualSize > 0 && section.VirtualSize < length {
+				length = section.VirtualSize // The rest of the raw data is alignment padding
+			}
+			segments = append(segments, addressSegment{
+				name:           section.Name,
+				fileOffset:     int(section.Offset),
+				virtualAddress: imageBase + uint64(section.VirtualAddress),
+				length:         int(length),
+			})
+		}
+		return segments, nil
+	}
+
+	return nil, fmt.Errorf("the file is not a PE or ELF executable")
+}
+
+// setSegments replaces the address segments, keeping them sorted by file offset
+func (h *HexDumpApp) setSegments(segments []addressSegment) {
+	h.segments = segments
+	sort.SliceStable(h.segments, func(i, j int) bool {
+		return h.segments[i].fileOffset < h.segments[j].fileOffset
+	})
+	h.addressMapChanged()
+}
+
+// addressMapChanged redraws everything that depends on the address segments
+func (h *HexDumpApp) addressMapChanged() {
+	if len(h.segments) == 0 {
+		h.showVirtual = false
+	}
+	if h.segmentList != nil {
+		h.segmentList.Refresh()
+	}
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// toVirtual translates a file offset into a virtual address
+func (h *HexDumpApp) toVirtual(offset int) (uint64, bool) {
+	for _, segment := range h.segments {
+		if offset >= segment.fileOffset && offset < segment.fileOffset+segment.length {
+			return segment.virtualAddress + uint64(offset-segment.fileOffset), true
+		}
+	}
+	return 0, false
+}
+
+// fromVirtual translates a virtual address into a file offset
+func (h *HexDumpApp) fromVirtual(address uint64) (int, bool) {
+	for _, segment := range h.segments {
+		if address >= segment.virtualAddress && address < segment.virtualAddress+uint64(segment.length) {
+			return segment.fileOffset + int(address-segment.virtualAddress), true
+		}
+	}
+	return 0, false
+}
+
+// addressDigitChoices are the numbers of hex digits the address column can have
+var addressDigitChoices = []int{8, 12, 16}
+
+// addressDigits returns the number of hex digits in the address column: the width chosen
+// in the preferences, or more if the largest address shown needs them
+func (h *HexDumpApp) addressDigits() int {
+	largest := uint64(max(len(h.fileData)-1, 0))
+	if h.showVirtual {
+		for _, segment := range h.segments {
+			largest = max(largest, segment.virtualAddress+uint64(max(segment.length-1, 0)))
+		}
+	}
+	for _, digits := range addressDigitChoices {
+		if digits >= appSettings.AddressDigits && (digits == 16 || largest < 1<<(4*digits)) {
+			return digits
+		}
+	}
+	return 16
+}
+
+// addressColumns returns the width of the address column, including the colon and
+// the space after it
+func (h *HexDumpApp) addressColumns() int {
+	return h.addressDigits() + 2
+}
+
+// formatAddress formats the address of the line at offset for the address column,
+// as a virtual address if they are shown and the offset is mapped
+func (h *HexDumpApp) formatAddress(offset int) string {
+	digits := h.addressDigits()
+	if h.showVirtual {
+		address, ok := h.toVirtual(offset)
+		if !ok {
+			return fmt.Sprintf("%*s", digits, "unmapped")
+		}
+		return formatHex(address, digits)
+	}
+	return formatHex(uint64(offset), digits)
+}
+
+// showAddressMap opens the address map window, where segments can be added, loaded
+// from the file's headers, and removed, and virtual addresses can be switched on
+func (h *HexDumpApp) showAddressMap() {
+	if h.segmentWindow != nil {
+		h.segmentWindow.RequestFocus()
+		return
+	}
+
+	selected := -1
+	h.segmentList = widget.NewList(
+		func() int { return len(h.segments) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			segment := h.segments[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %016X  %8X  %s",
+				segment.fileOffset, segment.virtualAddress, segment.length, segment.name))
+		},
+	)
+	h.segmentList.OnSelected = func(id widget.ListItemID) { selected = id }
+
+	virtualCheck := widget.NewCheck(lang.L("Show virtual addresses"), func(checked bool) {
+		h.showVirtual = checked && len(h.segments) > 0
+		h.updateDisplay()
+	})
+	virtualCheck.SetChecked(h.showVirtual)
+
+	loadBtn := widget.NewButton(lang.L("Load from Headers"), func() {
+		segments, err := segmentsFromHeaders(h.fileData)
+		if err != nil {
+			dialog.ShowError(err, h.segmentWindow)
+			return
+		}
+		h.setSegments(segments)
+	})
+	addBtn := widget.NewButton(lang.L("Add..."), h.showAddSegment)
+	removeBtn := widget.NewButton(lang.L("Remove"), func() {
+		if selected >= 0 && selected < len(h.segments) {
+			h.setSegments(append(h.segments[:selected:selected], h.segments[selected+1:]...))
+			h.segmentList.UnselectAll()
+			selected = -1
+			virtualCheck.SetChecked(h.showVirtual)
+		}
+	})
+
+	window := h.app.NewWindow(lang.L("Address Map"))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(virtualCheck, widget.NewLabel("Offset    Virtual address   Length    Name")),
+		container.NewHBox(loadBtn, addBtn, removeBtn),
+		nil, nil,
+		h.segmentList,
+	))
+	window.Resize(fyne.NewSize(520, 350))
+	window.SetOnClosed(func() {
+		h.segmentWindow = nil
+		h.segmentList = nil
+	})
+	h.segmentWindow = window
+	window.Show()
+}
+
+// showAddSegment asks for a new address segment. The fields default to the selection.
+func (h *HexDumpApp) showAddSegment() {
+	parent := h.window
+	if h.segmentWindow != nil {
+		parent = h.segmentWindow
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(fmt.Sprintf("Segment %d", len(h.segments)+1))
+	offsetEntry := widget.NewEntry()
+	lengthEntry := widget.NewEntry()
+	if h.hasSelection() {
+		offsetEntry.SetText(fmt.Sprintf("0x%X", h.selStart))
+		lengthEntry.SetText(fmt.Sprintf("0x%X", h.selEnd-h.selStart))
+	}
+	addressEntry := widget.NewEntry()
+	addressEntry.SetPlaceHolder(lang.L("e.g. 0x08000000"))
+
+	dialog.ShowForm(lang.L("Add Segment"), lang.L("Add"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Name"), nameEntry),
+		widget.NewFormItem(lang.L("File offset"), offsetEntry),
+		widget.NewFormItem(lang.L("Virtual address"), addressEntry),
+		widget.NewFormItem(lang.L("Length"), lengthEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		offset, err1 := parseOffset(offsetEntry.Text)
+		address, err2 := parseOffset(addressEntry.Text)
+		length, err3 := parseOffset(lengthEntry.Text)
+		if err1 != nil || err2 != nil || err3 != nil || offset < 0 || address < 0 || length <= 0 {
+			dialog.ShowError(fmt.Errorf("the offset, address, and length must be non-negative numbers"), parent)
+			return
+		}
+		h.setSegments(append(h.segments, addressSegment{
+			name:           nameEntry.Text,
+			fileOffset:     offset,
+			virtualAddress: uint64(address),
+			length:         length,
+		}))
+	}, parent)
+}
+
+// showGoTo asks for a file offset or virtual address and moves the caret there
+func (h *HexDumpApp) showGoTo() {
+	if len(h.fileData) == 0 {
+		return
+	}
+
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetPlaceHolder(lang.L("0x1F00, 7936, 1F00h, or end-0x200"))
+	kindRadio := widget.NewRadioGroup([]string{"File offset", "Virtual address"}, nil)
+	kindRadio.Horizontal = true
+	kindRadio.SetSelected("File offset")
+	if h.showVirtual {
+		kindRadio.SetSelected("Virtual address")
+	}
+	if len(h.segments) == 0 {
+		kindRadio.Disable()
+	}
+
+	form := dialog.NewForm(lang.L("Go To"), lang.L("Go"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Address"), offsetEntry),
+		widget.NewFormItem(lang.L("Type"), kindRadio),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		var value int
+		var err error
+		if kindRadio.Selected == "Virtual address" {
+			value, err = parseOffset(offsetEntry.Text)
+		} else {
+			value, err = h.evaluateOffset(offsetEntry.Text)
+		}
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+
+		offset := value
+		if kindRadio.Selected == "Virtual address" {
+			var mapped bool
+			if offset, mapped = h.fromVirtual(uint64(value)); !mapped {
+				dialog.ShowError(fmt.Errorf("virtual address 0x%X is not mapped to the file", value), h.window)
+				return
+			}
+		}
+		if offset < 0 || offset >= len(h.fileData) {
+			dialog.ShowError(fmt.Errorf("offset 0x%X is outside the file", offset), h.window)
+			return
+		}
+
+		h.selAnchor = offset
+		h.caret = offset
+		h.setSelection(offset, offset+1)
+		h.scrollToByte(offset)
+	}, h.window)
+	form.Resize(fyne.NewSize(350, 180))
+	form.Show()
+	h.window.Canvas().Focus(offsetEntry)
+}
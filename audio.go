@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PCM sample formats, as shown in the GUI
+const (
+	sampleU8  = "8-bit unsigned"
+	sampleS8  = "8-bit signed"
+	sampleS16 = "16-bit signed"
+	sampleS24 = "24-bit signed"
+	sampleS32 = "32-bit signed"
+)
+
+// sampleFormats lists the PCM sample formats in display order
+var sampleFormats = []string{sampleU8, sampleS8, sampleS16, sampleS24, sampleS32}
+
+// sampleRates lists the sample rates offered by the audio preview, in Hz
+var sampleRates = []string{"8000", "11025", "16000", "22050", "32000", "44100", "48000"}
+
+// Waveform image dimensions
+const (
+	waveformWidth  = 512
+	waveformHeight = 96
+)
+
+// sampleWidth returns the number of bytes in one sample of the given format
+func sampleWidth(format string) int {
+	switch format {
+	case sampleS16:
+		return 2
+	case sampleS24:
+		return 3
+	case sampleS32:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// decodeSamples interprets data as PCM samples of the given format and byte order,
+// returning them scaled to [-1, 1). An incomplete sample at the end is ignored.
+func decodeSamples(data []byte, format string, order binary.ByteOrder) []float64 {
+	width := sampleWidth(format)
+	samples := make([]float64, len(data)/width)
+	for index := range samples {
+		sample := data[index*width : (index+1)*width]
+		switch format {
+		case sampleU8:
+			samples[index] = (float64(sample[0]) - 128) / 128
+		case sampleS8:
+			samples[index] = float64(int8(sample[0])) / 128
+		case sampleS16:
+			samples[index] = float64(int16(order.Uint16(sample))) / (1 << 15)
+		case sampleS24:
+			var value int32
+			if order == binary.BigEndian {
+				value = int32(sample[0])<<16 | int32(sample[1])<<8 | int32(sample[2])
+			} else {
+				value = int32(sample[2])<<16 | int32(sample[1])<<8 | int32(sample[0])
+			}
+			value = value << 8 >> 8 // Sign-extend the 24-bit value
+			samples[index] = float64(value) / (1 << 23)
+		case sampleS32:
+			samples[index] = float64(int32(order.Uint32(sample))) / (1 << 31)
+		}
+	}
+	return samples
+}
+
+// renderWaveform draws interleaved samples as a waveform, with the channels mixed and
+// each column spanning the lowest to the highest sample it represents
+func renderWaveform(samples []float64, channels int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, waveformWidth, waveformHeight))
+	frames := len(samples) / channels
+	if frames == 0 {
+		return img
+	}
+
+	middle := waveformHeight / 2
+	waveformColor := paletteRGBA(colorNameWaveform)
+	for x := 0; x < waveformWidth; x++ {
+		first := x * frames / waveformWidth
+		last := max(first+1, (x+1)*frames/waveformWidth)
+		if first >= frames {
+			break
+		}
+
+		low, high := math.Inf(1), math.Inf(-1)
+		for frame := first; frame < last; frame++ {
+			var sum float64
+			for channel := 0; channel < channels; channel++ {
+				sum += samples[frame*channels+channel]
+			}
+			low = math.Min(low, sum/float64(channels))
+			high = math.Max(high, sum/float64(channels))
+		}
+
+		top := middle - int(high*float64(middle))
+		bottom := middle - int(low*float64(middle))
+		for y := max(0, top); y <= min(waveformHeight-1, bottom); y++ {
+			img.SetRGBA(x, y, waveformColor)
+		}
+	}
+	return img
+}
+
+// wavFormat is the format chunk of a WAV file, after its ID
+type wavFormat struct {
+	ChunkSize     uint32
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+// writeWAV writes interleaved samples to w as a 16-bit PCM WAV file
+func writeWAV(w io.Writer, samples []float64, rate, channels int) error {
+	writer := bufio.NewWriter(w)
+	dataSize := uint32(len(samples) / channels * channels * 2)
+
+	writer.WriteString("RIFF")
+	binary.Write(writer, binary.LittleEndian, 36+dataSize)
+	writer.WriteString("WAVEfmt ")
+	binary.Write(writer, binary.LittleEndian, wavFormat{
+		ChunkSize:     16,
+		AudioFormat:   1, // PCM
+		Channels:      uint16(channels),
+		SampleRate:    uint32(rate),
+		ByteRate:      uint32(rate * channels * 2),
+		BlockAlign:    uint16(channels * 2),
+		BitsPerSample: 16,
+	})
+	writer.WriteString("data")
+	binary.Write(writer, binary.LittleEndian, dataSize)
+	for _, sample := range samples[:dataSize/2] {
+		binary.Write(writer, binary.LittleEndian, int16(math.Max(-1, math.Min(1, sample))*math.MaxInt16))
+	}
+	return writer.Flush()
+}
+
+// showAudioPreview interprets the selection as PCM audio of a chosen format, showing
+// its waveform and playing it with the system's audio player. Clicking the waveform
+// moves the caret to the bytes it represents.
+func (h *HexDumpApp) showAudioPreview() {
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("Audio Preview"), lang.L("Select the bytes to play first."), h.window)
+		return
+	}
+
+	start := h.selStart
+	data := append([]byte(nil), h.selectedBytes()...)
+	infoLabel := widget.NewLabel("")
+	view := newPixelView(image.NewRGBA(image.Rect(0, 0, 1, 1)), fyne.NewSize(waveformWidth, waveformHeight))
+
+	formatSelect := widget.NewSelect(sampleFormats, nil)
+	channelsSelect := widget.NewSelect([]string{"Mono", "Stereo"}, nil)
+	rateSelect := widget.NewSelectEntry(sampleRates)
+	rateSelect.SetText("8000")
+
+	var samples []float64
+	channels := 1
+	redraw := func(string) {
+		if formatSelect.Selected == "" || channelsSelect.Selected == "" {
+			return
+		}
+		channels = 1
+		if channelsSelect.Selected == "Stereo" {
+			channels = 2
+		}
+		samples = decodeSamples(data, formatSelect.Selected, h.byteOrder())
+		view.setImage(renderWaveform(samples, channels))
+		info := fmt.Sprintf("%d samples per channel", len(samples)/channels)
+		if rate, err := strconv.Atoi(strings.TrimSpace(rateSelect.Text)); err == nil && rate > 0 {
+			info += fmt.Sprintf(", %.2f seconds", float64(len(samples)/channels)/float64(rate))
+		}
+		infoLabel.SetText(info)
+	}
+	formatSelect.OnChanged = redraw
+	channelsSelect.OnChanged = redraw
+	rateSelect.OnChanged = redraw
+	formatSelect.SetSelected(sampleU8)
+	channelsSelect.SetSelected("Mono")
+
+	view.onTapped = func(x, _ int) {
+		offset := start + x*len(data)/waveformWidth
+		h.caret = offset
+		h.selAnchor = offset
+		h.setSelection(offset, offset+1)
+		h.goToOffset(offset)
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("Audio Preview - %08X-%08X", start, start+len(data)-1))
+	playBtn := widget.NewButton(lang.L("Play as Audio"), func() {
+		rate, err := strconv.Atoi(strings.TrimSpace(rateSelect.Text))
+		if err != nil || rate <= 0 {
+			dialog.ShowError(fmt.Errorf("sample rate must be a positive number"), window)
+			return
+		}
+		if err := h.playSamples(samples, rate, channels); err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+
+	controls := container.NewHBox(
+		widget.NewLabel(lang.L("Samples:")), formatSelect,
+		widget.NewLabel(lang.L("Channels:")), channelsSelect,
+		widget.NewLabel(lang.L("Rate:")), container.NewGridWrap(fyne.NewSize(110, rateSelect.MinSize().Height), rateSelect),
+	)
+	window.SetContent(container.NewBorder(
+		container.NewVBox(controls, infoLabel),
+		container.NewHBox(playBtn, widget.NewLabel(lang.L("Multi-byte samples use the chosen byte order"))),
+		nil, nil,
+		view,
+	))
+	window.Resize(fyne.NewSize(720, 260))
+	window.Show()
+}
+
+// playSamples writes samples to a temporary WAV file and opens it with the system's
+// default audio player
+func (h *HexDumpApp) playSamples(samples []float64, rate, channels int) error {
+	if len(samples) < channels {
+		return fmt.Errorf("the selection is too short to hold a sample")
+	}
+
+	file, err := os.CreateTemp("", "hexdump-*.wav")
+	if err != nil {
+		return fmt.Errorf("failed to create the audio file: %w", err)
+	}
+	defer file.Close()
+	if err := writeWAV(file, samples, rate, channels); err != nil {
+		return fmt.Errorf("failed to write the audio file: %w", err)
+	}
+
+	path := filepath.ToSlash(file.Name())
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive paths
+	}
+	return h.app.OpenURL(&url.URL{Scheme: "file", Path: path})
+}
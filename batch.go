@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// batchJob describes one batch conversion: an export or transform applied to every
+// file in sourceDir matching pattern, with the results written to destDir
+type batchJob struct {
+	sourceDir string
+	pattern   string
+	destDir   string
+	operation string // One of exportFormats or transformNames
+
+	// Transform parameters
+	xorKey    []byte
+	swapWidth int
+
+	// Export parameters
+	bytesPerGroup int
+	encoding      string
+}
+
+// validate checks the job for missing or conflicting parameters
+func (job *batchJob) validate() error {
+	if job.sourceDir == "" || job.destDir == "" {
+		return fmt.Errorf("both a source and a destination folder are required")
+	}
+	if job.pattern == "" {
+		return fmt.Errorf("a file pattern is required")
+	}
+	if _, err := filepath.Match(job.pattern, ""); err != nil {
+		return fmt.Errorf("invalid file pattern %q: %w", job.pattern, err)
+	}
+
+	sourceAbs, _ := filepath.Abs(job.sourceDir)
+	destAbs, _ := filepath.Abs(job.destDir)
+	if job.isTransform() && sourceAbs == destAbs {
+		return fmt.Errorf("the destination folder must differ from the source folder")
+	}
+
+	switch job.operation {
+	case transformXOR:
+		if len(job.xorKey) == 0 {
+			return fmt.Errorf("an XOR key is required")
+		}
+	case transformSwap:
+		if job.swapWidth != 2 && job.swapWidth != 4 && job.swapWidth != 8 {
+			return fmt.Errorf("swap width must be 2, 4, or 8 bytes")
+		}
+	case exportFormatText, exportFormatHTML, exportFormatCArray:
+	default:
+		return fmt.Errorf("unknown operation: %s", job.operation)
+	}
+	return nil
+}
+
+// isTransform reports whether the job transforms bytes rather than exporting them
+func (job *batchJob) isTransform() bool {
+	return job.operation == transformXOR || job.operation == transformSwap
+}
+
+// files returns the regular files in the source folder matching the job's pattern
+func (job *batchJob) files() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(job.sourceDir, job.pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err == nil && info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
+
+// convert applies the job's operation to the data read from path and returns the
+// output file name and contents
+func (job *batchJob) convert(path string, data []byte) (string, []byte, error) {
+	base := filepath.Base(path)
+
+	switch job.operation {
+	case transformXOR:
+		return base, xorBytes(data, job.xorKey), nil
+	case transformSwap:
+		return base, swapEndianness(data, job.swapWidth), nil
+	}
+
+	formatter := NewHexDumpApp(nil, nil)
+	formatter.fileData = data
+	formatter.bytesPerGroup = job.bytesPerGroup
+	formatter.encoding = job.encoding
+
+	var output bytes.Buffer
+	if err := formatter.writeExport(&output, job.operation, base); err != nil {
+		return "", nil, err
+	}
+	return base + exportExtension(job.operation), output.Bytes(), nil
+}
+
+// runBatch runs the job over all matching files, calling progress before each file,
+// which stops the job by returning false, and once more on completion. It returns the number of files written and the errors
+// for any files that failed.
+func runBatch(job batchJob, progress func(done, total int, name string) bool) (int, []error) {
+	if err := job.validate(); err != nil {
+		return 0, []error{err}
+	}
+
+	files, err := job.files()
+	if err != nil {
+		return 0, []error{err}
+	}
+	if err := os.MkdirAll(job.destDir, 0755); err != nil {
+		return 0, []error{err}
+	}
+
+	written := 0
+	var errs []error
+
+	for index, path := range files {
+		if progress != nil && !progress(index, len(files), filepath.Base(path)) {
+			break
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		outName, output, err := job.convert(path, data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+
+		if err := writeDataFile(filepath.Join(job.destDir, outName), output); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		written++
+	}
+
+	if progress != nil {
+		progress(len(files), len(files), "")
+	}
+	return written, errs
+}
+
+// showBatchDialog shows the batch conversion wizard
+func (h *HexDumpApp) showBatchDialog() {
+	sourceEntry := widget.NewEntry()
+	sourceEntry.SetPlaceHolder(lang.L("Folder containing the input files"))
+	patternEntry := widget.NewEntry()
+	patternEntry.SetText("*")
+	destEntry := widget.NewEntry()
+	destEntry.SetPlaceHolder(lang.L("Folder for the output files"))
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder(lang.L("Hex bytes, e.g. 5A or DE AD BE EF"))
+	widthSelect := widget.NewSelect([]string{"2", "4", "8"}, nil)
+	widthSelect.SetSelected("4")
+
+	// Enable only the parameters relevant to the chosen operation
+	operationSelect := widget.NewSelect(append(append([]string{}, exportFormats...), transformNames...), func(value string) {
+		if value == transformXOR {
+			keyEntry.Enable()
+		} else {
+			keyEntry.Disable()
+		}
+		if value == transformSwap {
+			widthSelect.Enable()
+		} else {
+			widthSelect.Disable()
+		}
+	})
+	operationSelect.SetSelected(exportFormatText)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(lang.L("Source folder"), h.folderField(sourceEntry)),
+		widget.NewFormItem(lang.L("File pattern"), patternEntry),
+		widget.NewFormItem(lang.L("Operation"), operationSelect),
+		widget.NewFormItem(lang.L("XOR key"), keyEntry),
+		widget.NewFormItem(lang.L("Swap width"), widthSelect),
+		widget.NewFormItem(lang.L("Destination folder"), h.folderField(destEntry)),
+	}
+
+	form := dialog.NewForm(lang.L("Batch Convert"), lang.L("Run"), lang.L("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		job := batchJob{
+			sourceDir:     sourceEntry.Text,
+			pattern:       patternEntry.Text,
+			destDir:       destEntry.Text,
+			operation:     operationSelect.Selected,
+			bytesPerGroup: h.bytesPerGroup,
+			encoding:      h.encoding,
+		}
+		if job.operation == transformXOR {
+			key, err := parseHexBytes(keyEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			job.xorKey = key
+		}
+		job.swapWidth, _ = strconv.Atoi(widthSelect.Selected)
+
+		if err := job.validate(); err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		h.runBatchWithProgress(job)
+	}, h.window)
+	form.Resize(fyne.NewSize(500, 350))
+	form.Show()
+}
+
+// folderField returns an entry paired with a button that fills it from a folder chooser
+func (h *HexDumpApp) folderField(entry *widget.Entry) fyne.CanvasObject {
+	browseBtn := widget.NewButton(lang.L("Browse..."), func() {
+		directory, err := nativedialog.Directory().Browse()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, h.window)
+			}
+			return
+		}
+		entry.SetText(directory)
+	})
+	return container.NewBorder(nil, nil, nil, browseBtn, entry)
+}
+
+// runBatchWithProgress runs the job as a background task
+func (h *HexDumpApp) runBatchWithProgress(job batchJob) {
+	task := h.startTask(lang.L("Batch Convert"))
+	go func() {
+		defer h.recoverPanic()
+		written, errs := runBatch(job, func(done, total int, name string) bool {
+			return task.report(done, total)
+		})
+
+		fyne.Do(func() {
+			message := fmt.Sprintf("%d file(s) written to %s", written, job.destDir)
+			if len(errs) > 0 {
+				message += fmt.Sprintf("\n\n%d error(s), the first being:\n%v", len(errs), errs[0])
+			}
+			show := func() { dialog.ShowInformation(lang.L("Batch Convert"), message, h.window) }
+			if h.finishTask(task, message, show) {
+				show()
+			}
+		})
+	}()
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Bit orders of the Bit Stream panel: MSB first reads each byte from its high bit, as in
+// MPEG headers; LSB first reads it from its low bit, as in DEFLATE and many radio protocols
+const (
+	bitOrderMSB = "MSB first"
+	bitOrderLSB = "LSB first"
+)
+
+// Layout of the bits shown by the Bit Stream panel
+const (
+	bitStreamLineBytes = 4
+	bitStreamLines     = 8
+)
+
+// bitField is a field of width bits named name
+type bitField struct {
+	name  string
+	width int
+}
+
+// parseBitFields parses field widths separated by spaces or commas, each optionally
+// preceded by a name and a colon, such as "sync:11 version:2 layer:2 1"
+func parseBitFields(text string) ([]bitField, error) {
+	var fields []bitField
+	for _, item := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		name, widthText, found := strings.Cut(item, ":")
+		if !found {
+			name, widthText = "", item
+		}
+		width, err := strconv.Atoi(widthText)
+		if err != nil || width < 1 || width > 64 {
+			return nil, fmt.Errorf("%q: widths must be 1 to 64 bits", item)
+		}
+		if name == "" {
+			name = fmt.Sprintf("field %d", len(fields)+1)
+		}
+		fields = append(fields, bitField{name: name, width: width})
+	}
+	return fields, nil
+}
+
+// bitAt returns bit number position of data, counting bits in the given order
+func bitAt(data []byte, position int, order string) byte {
+	if order == bitOrderLSB {
+		return data[position/8] >> (position % 8) & 1
+	}
+	return data[position/8] >> (7 - position%8) & 1
+}
+
+// readBits reads width bits of data starting at bit number position. MSB-first values
+// have their first bit as their highest bit; LSB-first values have it as their lowest.
+func readBits(data []byte, position, width int, order string) (uint64, bool) {
+	if position < 0 || width < 1 || width > 64 || position+width > 8*len(data) {
+		return 0, false
+	}
+	var value uint64
+	for index := 0; index < width; index++ {
+		bit := uint64(bitAt(data, position+index, order))
+		if order == bitOrderLSB {
+			value |= bit << index
+		} else {
+			value = value<<1 | bit
+		}
+	}
+	return value, true
+}
+
+// formatBitPosition formats bit number position as the offset of its byte and the bit
+// within it, such as "00000010.3"
+func formatBitPosition(position int) string {
+	return fmt.Sprintf("%s.%d", formatHex(uint64(position/8), 8), position%8)
+}
+
+// parseBitPosition parses a bit position written as formatBitPosition writes it: a hex
+// offset optionally followed by a dot and the bit within the byte, 0 to 7
+func parseBitPosition(text string) (int, error) {
+	offsetText, bitText, found := strings.Cut(strings.TrimSpace(text), ".")
+	offset, err := parseOffset("0x" + strings.TrimPrefix(strings.ToLower(offsetText), "0x"))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid offset %q", offsetText)
+	}
+	bit := 0
+	if found {
+		bit, err = strconv.Atoi(bitText)
+		if err != nil || bit < 0 || bit > 7 {
+			return 0, fmt.Errorf("the bit must be 0 to 7")
+		}
+	}
+	return 8*offset + bit, nil
+}
+
+// createBitStreamPanel creates the Bit Stream side panel, which reads the file as a
+// stream of bits from a bit position that follows the caret and can be moved a bit at a
+// time, and extracts fields of any width from it
+func (h *HexDumpApp) createBitStreamPanel() panelContent {
+	position, lastCaret := 0, -1
+	order := bitOrderMSB
+	var fields []bitField
+
+	positionEntry := widget.NewEntry()
+	positionEntry.SetPlaceHolder(lang.L("Hex offset and bit, e.g. 10.3"))
+	bitsText := widget.NewRichText()
+	errorLabel := widget.NewLabel("")
+	errorLabel.Wrapping = fyne.TextWrapWord
+
+	// Each field's start and value at the current position, or false past the end
+	type fieldValue struct {
+		start int
+		value uint64
+		ok    bool
+	}
+	var values []fieldValue
+	list := widget.NewList(
+		func() int { return len(values) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			field, value := fields[id], values[id]
+			text := fmt.Sprintf("%s  %s  %d bits: ", formatBitPosition(value.start), field.name, field.width)
+			if value.ok {
+				text += fmt.Sprintf("%0*b = 0x%X (%d)", field.width, value.value, value.value, value.value)
+			} else {
+				text += "past the end"
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+
+	update := func() {
+		position = max(min(position, 8*len(h.fileData)-1), 0)
+		positionEntry.SetText(formatBitPosition(position))
+
+		values = nil
+		fieldBits := map[int]int{} // Field index of each bit covered by a field
+		start := position
+		for index, field := range fields {
+			value, ok := readBits(h.fileData, start, field.width, order)
+			values = append(values, fieldValue{start: start, value: value, ok: ok})
+			for bit := start; bit < start+field.width; bit++ {
+				fieldBits[bit] = index
+			}
+			start += field.width
+		}
+		list.UnselectAll()
+		list.Refresh()
+
+		// Show whole bytes from the one holding the position, in reading order
+		style := widget.RichTextStyle{Inline: true, TextStyle: fyne.TextStyle{Monospace: true}}
+		var segments []widget.RichTextSegment
+		firstByte := position / 8
+		for line := 0; line < bitStreamLines; line++ {
+			lineStart := firstByte + line*bitStreamLineBytes
+			if lineStart >= len(h.fileData) {
+				break
+			}
+			if line > 0 {
+				segments = append(segments, &widget.TextSegment{Text: "\n", Style: style})
+			}
+			segments = append(segments, &widget.TextSegment{Text: formatHex(uint64(lineStart), 8) + " ", Style: style})
+			for offset := lineStart; offset < min(lineStart+bitStreamLineBytes, len(h.fileData)); offset++ {
+				segments = append(segments, &widget.TextSegment{Text: " ", Style: style})
+				for bit := 8 * offset; bit < 8*offset+8; bit++ {
+					bitStyle := style
+					if index, ok := fieldBits[bit]; ok {
+						bitStyle.ColorName = theme.ColorNamePrimary
+						if index%2 == 1 {
+							bitStyle.ColorName = theme.ColorNameWarning
+						}
+					} else if bit < position {
+						bitStyle.ColorName = theme.ColorNameDisabled
+					}
+					if bit == position {
+						bitStyle.TextStyle.Bold = true
+					}
+					segments = append(segments, &widget.TextSegment{Text: strconv.Itoa(int(bitAt(h.fileData, bit, order))),
+						Style: bitStyle})
+				}
+			}
+		}
+		bitsText.Segments = segments
+		bitsText.Refresh()
+	}
+
+	positionEntry.OnSubmitted = func(text string) {
+		if parsed, err := parseBitPosition(text); err == nil {
+			position = parsed
+		}
+		update()
+	}
+	step := func(bits int) func() {
+		return func() {
+			position += bits
+			update()
+		}
+	}
+	advanceBtn := widget.NewButton(lang.L("Advance"), func() {
+		for _, field := range fields {
+			position += field.width
+		}
+		update()
+	})
+	orderSelect := widget.NewRadioGroup([]string{bitOrderMSB, bitOrderLSB}, func(value string) {
+		order = value
+		update()
+	})
+	orderSelect.Horizontal = true
+	orderSelect.Required = true
+	orderSelect.SetSelected(bitOrderMSB)
+
+	fieldsEntry := widget.NewEntry()
+	fieldsEntry.SetPlaceHolder(lang.L("e.g. sync:11 version:2 layer:2 1"))
+	fieldsEntry.SetText(appSettings.BitFields)
+	setFields := func(text string) {
+		var err error
+		if fields, err = parseBitFields(text); err != nil {
+			errorLabel.SetText(err.Error())
+			errorLabel.Show()
+		} else {
+			errorLabel.Hide()
+			appSettings.BitFields = text
+			saveSettings()
+		}
+		update()
+	}
+	fieldsEntry.OnSubmitted = setFields
+	fields, _ = parseBitFields(appSettings.BitFields)
+	errorLabel.Hide()
+
+	// Selecting a field selects the bytes holding it and moves the position to it
+	list.OnSelected = func(id widget.ListItemID) {
+		start := values[id].start
+		end := min((start+fields[id].width+7)/8, len(h.fileData))
+		if start/8 < end {
+			h.setSelection(start/8, end)
+			h.goToOffset(start / 8)
+		}
+	}
+
+	controls := container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(
+			widget.NewButtonWithIcon("", theme.NavigateBackIcon(), step(-1)),
+			widget.NewButtonWithIcon("", theme.NavigateNextIcon(), step(1)),
+		), positionEntry),
+		orderSelect,
+		bitsText,
+		container.NewBorder(nil, nil, nil, container.NewHBox(
+			widget.NewButton(lang.L("Set"), func() { setFields(fieldsEntry.Text) }), advanceBtn,
+		), fieldsEntry),
+		errorLabel,
+	)
+	return panelContent{
+		object: container.NewBorder(controls, nil, nil, nil, list),
+		refresh: func() {
+			// The position follows the caret, but stays put while the caret doesn't move
+			if h.caret != lastCaret {
+				position, lastCaret = 8*h.caret, h.caret
+			}
+			update()
+		},
+		reset: func() { lastCaret = -1 },
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// Bookmark import formats, as shown in the GUI
+const (
+	importAuto    = "Auto-detect"
+	importCSV     = "CSV (offset,length,label)"
+	importBinwalk = "binwalk log"
+	importSymbols = "IDA / Ghidra export"
+)
+
+// importFormats lists the bookmark import formats in display order
+var importFormats = []string{importAuto, importCSV, importBinwalk, importSymbols}
+
+var (
+	// binwalkLine matches a result line of a binwalk scan: decimal, hex, description
+	binwalkLine = regexp.MustCompile(`^\s*(\d+)\s+0x([0-9A-Fa-f]+)\s+(.*\S)\s*$`)
+
+	// idaMapLine matches a public symbol line of an IDA .map file: segment:offset name
+	idaMapLine = regexp.MustCompile(`^\s*[0-9A-Fa-f]{4}:([0-9A-Fa-f]{8,16})\s+([A-Za-z_.$?@]\S*)\s*$`)
+
+	// addressLine matches a generic "address name" line, as in IDA and Ghidra name lists
+	addressLine = regexp.MustCompile(`^\s*(?:[A-Za-z_.]+:)?(?:0x)?([0-9A-Fa-f]{4,16})h?\s+(\S.*?)\s*$`)
+
+	// prefixedHex matches a 0x-prefixed hex number anywhere in a line
+	prefixedHex = regexp.MustCompile(`\b0[xX]([0-9A-Fa-f]{1,16})\b`)
+
+	// leadingHex matches a line starting with a bare or h-suffixed hex number. The number
+	// must have a decimal digit, so that words such as "Added" aren't taken for one.
+	leadingHex = regexp.MustCompile(`^\s*([0-9A-Fa-f]*[0-9][0-9A-Fa-f]*)[hH]?(?:[\s:,;]|$)`)
+)
+
+// detectImportFormat guesses the format of an offset list from its contents
+func detectImportFormat(text string) string {
+	firstLine, _, _ := strings.Cut(text, "\n")
+	switch {
+	case strings.Contains(text, "DECIMAL") && strings.Contains(text, "HEXADECIMAL"):
+		return importBinwalk
+	case strings.Contains(firstLine, "Location") && strings.Contains(firstLine, "Name"):
+		return importSymbols // Ghidra symbol table export
+	case strings.Contains(text, "Publics by Value") || idaMapLine.MatchString(firstLine):
+		return importSymbols
+	default:
+		return importCSV
+	}
+}
+
+// parseBookmarks parses an offset list in the given format. Addresses in the list have
+// base subtracted from them to give file offsets, so that virtual addresses exported by
+// a disassembler can be mapped onto the file.
+func parseBookmarks(text string, format string, base int) ([]bookmark, error) {
+	if format == importAuto {
+		format = detectImportFormat(text)
+	}
+	switch format {
+	case importCSV:
+		return parseBookmarkCSV(text, base)
+	case importBinwalk:
+		return parseBinwalkLog(text, base), nil
+	case importSymbols:
+		return parseSymbolExport(text, base)
+	default:
+		return nil, fmt.Errorf("unknown import format: %s", format)
+	}
+}
+
+// parseBookmarkCSV parses lines of offset,length,label. The length and label are
+// optional, and lines whose first field is not an offset (headers, comments) are skipped.
+func parseBookmarkCSV(text string, base int) ([]bookmark, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []bookmark
+	for _, record := range records {
+		offset, err := parseOffset(record[0])
+		if err != nil {
+			continue
+		}
+		b := bookmark{offset: offset - base}
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			if b.length, err = parseOffset(record[1]); err != nil {
+				return nil, fmt.Errorf("invalid length %q for offset %s", record[1], record[0])
+			}
+		}
+		if len(record) > 2 {
+			b.label = strings.Join(record[2:], ",")
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	return bookmarks, nil
+}
+
+// parseBinwalkLog parses the result lines of a binwalk signature scan
+func parseBinwalkLog(text string, base int) []bookmark {
+	var bookmarks []bookmark
+	for _, line := range strings.Split(text, "\n") {
+		match := binwalkLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		offset, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		bookmarks = append(bookmarks, bookmark{offset: offset - base, label: match[3]})
+	}
+	return bookmarks
+}
+
+// parseSymbolExport parses a Ghidra symbol table CSV export, an IDA .map file, or a
+// plain list of "address name" lines as exported by either tool
+func parseSymbolExport(text string, base int) ([]bookmark, error) {
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Contains(firstLine, "Location") {
+		return parseGhidraCSV(text, base)
+	}
+
+	var bookmarks []bookmark
+	for _, line := range strings.Split(text, "\n") {
+		match := idaMapLine.FindStringSubmatch(line)
+		if match == nil {
+			match = addressLine.FindStringSubmatch(line)
+		}
+		if match == nil {
+			continue
+		}
+		address, err := strconv.ParseInt(match[1], 16, 64)
+		if err != nil {
+			continue
+		}
+		bookmarks = append(bookmarks, bookmark{offset: int(address) - base, label: match[2]})
+	}
+	return bookmarks, nil
+}
+
+// parseGhidraCSV parses a Ghidra symbol table exported as CSV, using its Name and
+// Location columns
+func parseGhidraCSV(text string, base int) ([]bookmark, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	nameColumn, locationColumn := -1, -1
+	for index, heading := range records[0] {
+		switch strings.TrimSpace(heading) {
+		case "Name":
+			nameColumn = index
+		case "Location":
+			locationColumn = index
+		}
+	}
+	if nameColumn < 0 || locationColumn < 0 {
+		return nil, fmt.Errorf("the export has no Name and Location columns")
+	}
+
+	var bookmarks []bookmark
+	for _, record := range records[1:] {
+		if len(record) <= max(nameColumn, locationColumn) {
+			continue
+		}
+		// Locations may carry an address space prefix, as in "ram:00401000"
+		location := record[locationColumn]
+		if _, after, found := strings.Cut(location, ":"); found {
+			location = after
+		}
+		address, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(location), "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		bookmarks = append(bookmarks, bookmark{offset: int(address) - base, label: record[nameColumn]})
+	}
+	return bookmarks, nil
+}
+
+// showImportBookmarks imports bookmarks from an offset list chosen by the user
+func (h *HexDumpApp) showImportBookmarks() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Import Bookmarks"), lang.L("Open a file before importing bookmarks."), h.window)
+		return
+	}
+
+	formatSelect := widget.NewSelect(importFormats, nil)
+	formatSelect.SetSelected(importAuto)
+	baseEntry := widget.NewEntry()
+	baseEntry.SetText("0")
+
+	form := dialog.NewForm(lang.L("Import Bookmarks"), lang.L("Choose File..."), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Format"), formatSelect),
+		widget.NewFormItem(lang.L("Address base"), baseEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		base, err := parseOffset(baseEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+
+		filename, err := nativedialog.File().Filter("Offset lists", "csv", "txt", "log", "map").
+			Filter("All Files", "*").Load()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, h.window)
+			}
+			return
+		}
+		h.importBookmarksFromPath(filename, formatSelect.Selected, base)
+	}, h.window)
+	form.Resize(fyne.NewSize(400, 200))
+	form.Show()
+}
+
+// importBookmarksFromPath reads an offset list and adds its entries as bookmarks,
+// skipping entries that fall outside the file
+func (h *HexDumpApp) importBookmarksFromPath(path string, format string, base int) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+
+	parsed, err := parseBookmarks(string(text), format, base)
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+
+	var inRange []bookmark
+	for _, b := range parsed {
+		if b.offset >= 0 && b.offset < len(h.fileData) {
+			b.length = min(b.length, len(h.fileData)-b.offset)
+			inRange = append(inRange, b)
+		}
+	}
+	h.addBookmarks(inRange...)
+
+	message := fmt.Sprintf("Imported %d bookmark(s).", len(inRange))
+	if skipped := len(parsed) - len(inRange); skipped > 0 {
+		message += fmt.Sprintf("\n%d entries were outside the file and were skipped.", skipped)
+	}
+	dialog.ShowInformation(lang.L("Import Bookmarks"), message, h.window)
+}
+
+// parseOffsetList parses a list of hex offsets, one per line, as logged by other tools.
+// The offset of a line is its first 0x-prefixed number, or else the hex number the line
+// starts with, and the whole line becomes the label of its marker. Lines without an
+// offset are counted as skipped.
+func parseOffsetList(text string) (markers []bookmark, skipped int) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		match := prefixedHex.FindStringSubmatch(line)
+		if match == nil {
+			match = leadingHex.FindStringSubmatch(line)
+		}
+		if match == nil {
+			skipped++
+			continue
+		}
+		address, err := strconv.ParseUint(match[1], 16, 63)
+		if err != nil {
+			skipped++
+			continue
+		}
+		markers = append(markers, bookmark{offset: int(address), label: line, temporary: true})
+	}
+	return markers, skipped
+}
+
+// importOffsetsFromClipboard marks every hex offset listed in the clipboard, one per
+// line, with a temporary bookmark, so that the findings of another tool can be looked up
+// in the data. Bookmarks → Clear Clipboard Markers removes them again.
+func (h *HexDumpApp) importOffsetsFromClipboard() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Import Offsets"), lang.L("Open a file before importing bookmarks."), h.window)
+		return
+	}
+
+	parsed, skipped := parseOffsetList(h.window.Clipboard().Content())
+	var inRange []bookmark
+	for _, b := range parsed {
+		if b.offset >= 0 && b.offset < len(h.fileData) {
+			inRange = append(inRange, b)
+		}
+	}
+	if len(inRange) == 0 {
+		dialog.ShowInformation(lang.L("Import Offsets"),
+			lang.L("The clipboard holds no hex offsets inside the file, one per line."), h.window)
+		return
+	}
+	h.addBookmarks(inRange...)
+	h.setSelection(inRange[0].offset, inRange[0].end())
+	h.goToOffset(inRange[0].offset)
+
+	message := fmt.Sprintf("Marked %d offset(s).", len(inRange))
+	if outside := len(parsed) - len(inRange); outside > 0 {
+		message += fmt.Sprintf("\n%d offsets were outside the file and were skipped.", outside)
+	}
+	if skipped > 0 {
+		message += fmt.Sprintf("\n%d lines without an offset were skipped.", skipped)
+	}
+	dialog.ShowInformation(lang.L("Import Offsets"), message, h.window)
+}
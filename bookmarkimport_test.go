@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBookmarks(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format string
+		base   int
+		want   []bookmark
+	}{
+		{
+			name:   "csv",
+			text:   "offset,length,label\n0x10,4,magic\n# comment\n32,,no length\n40h,0x8,a,b\n",
+			format: importAuto,
+			want:   []bookmark{{offset: 0x10, length: 4, label: "magic"}, {offset: 32, label: "no length"}, {offset: 0x40, length: 8, label: "a,b"}},
+		},
+		{
+			name: "binwalk",
+			text: "\nDECIMAL       HEXADECIMAL     DESCRIPTION\n" +
+				"--------------------------------------------------------------------------------\n" +
+				"0             0x0             ELF, 64-bit LSB executable\n" +
+				"4096          0x1000          gzip compressed data\n",
+			format: importAuto,
+			want:   []bookmark{{offset: 0, label: "ELF, 64-bit LSB executable"}, {offset: 4096, label: "gzip compressed data"}},
+		},
+		{
+			name:   "ghidra csv",
+			text:   "\"Name\",\"Location\",\"Type\"\n\"main\",\"ram:00401000\",\"Function\"\n\"entry\",\"00401100\",\"Function\"\n",
+			format: importAuto,
+			base:   0x400000,
+			want:   []bookmark{{offset: 0x1000, label: "main"}, {offset: 0x1100, label: "entry"}},
+		},
+		{
+			name:   "ida map",
+			text:   " Address         Publics by Value\n\n 0001:00001000       _main\n 0001:00001234       _helper\n",
+			format: importAuto,
+			want:   []bookmark{{offset: 0x1000, label: "_main"}, {offset: 0x1234, label: "_helper"}},
+		},
+		{
+			name:   "address list",
+			text:   "0x00401000 start\n00401200h  loop\n",
+			format: importSymbols,
+			base:   0x400000,
+			want:   []bookmark{{offset: 0x1000, label: "start"}, {offset: 0x1200, label: "loop"}},
+		},
+	}
+	for _, test := range tests {
+		got, err := parseBookmarks(test.text, test.format, test.base)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseBookmarksErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format string
+	}{
+		{"bad length", "0x10,four,label\n", importCSV},
+		{"ghidra without location", "\"Name\",\"Location Type\"\n\"main\",\"x\"\n", importSymbols},
+		{"unknown format", "0x10\n", "Hex editor"},
+	}
+	for _, test := range tests {
+		if got, err := parseBookmarks(test.text, test.format, 0); err == nil {
+			t.Errorf("%s: got %+v, want an error", test.name, got)
+		}
+	}
+}
+
+func TestParseOffsetList(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantOffsets []int
+		wantSkipped int
+	}{
+		{"prefixed", "found key at 0x1F40\nheader 0x10 (size 0x20)\n", []int{0x1F40, 0x10}, 0},
+		{"leading hex", "1000: jump table\n00A0h entry\n", []int{0x1000, 0xA0}, 0},
+		{"skipped lines", "Added 3 items\n\n0x20\nno offset here\n", []int{0x20}, 2},
+		{"too large", "0xFFFFFFFFFFFFFFFF\n", nil, 1},
+	}
+	for _, test := range tests {
+		markers, skipped := parseOffsetList(test.text)
+		var offsets []int
+		for _, marker := range markers {
+			offsets = append(offsets, marker.offset)
+			if !marker.temporary {
+				t.Errorf("%s: marker at %X is not temporary", test.name, marker.offset)
+			}
+		}
+		if !reflect.DeepEqual(offsets, test.wantOffsets) || skipped != test.wantSkipped {
+			t.Errorf("%s: got offsets %X and %d skipped, want %X and %d skipped",
+				test.name, offsets, skipped, test.wantOffsets, test.wantSkipped)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// bookmark is a labeled byte range highlighted in the data view. A bookmark with
+// zero length marks a single offset and highlights the byte there.
+type bookmark struct {
+	offset    int
+	length    int
+	label     string
+	color     fyne.ThemeColorName
+	tags      []string // Lowercase, without the leading #
+	temporary bool     // Marker pasted from the clipboard, cleared together with the others
+}
+
+// end returns the offset just past the highlighted bytes of the bookmark
+func (b *bookmark) end() int {
+	return b.offset + max(b.length, 1)
+}
+
+// addBookmarks adds bookmarks, assigning colors to any that have none, and keeps the
+// list sorted by offset
+func (h *HexDumpApp) addBookmarks(bookmarks ...bookmark) {
+	for _, b := range bookmarks {
+		if b.color == "" {
+			b.color = bookmarkColors[len(h.bookmarks)%len(bookmarkColors)]
+		}
+		h.bookmarks = append(h.bookmarks, b)
+	}
+	sort.SliceStable(h.bookmarks, func(i, j int) bool {
+		return h.bookmarks[i].offset < h.bookmarks[j].offset
+	})
+	h.bookmarksChanged()
+}
+
+// removeBookmark deletes the bookmark at the given index
+func (h *HexDumpApp) removeBookmark(index int) {
+	if index < 0 || index >= len(h.bookmarks) {
+		return
+	}
+	h.bookmarks = append(h.bookmarks[:index], h.bookmarks[index+1:]...)
+	h.dropStaleTagFilter()
+	h.bookmarksChanged()
+}
+
+// clearBookmarks deletes all bookmarks
+func (h *HexDumpApp) clearBookmarks() {
+	h.bookmarks = nil
+	h.tagFilter = ""
+	h.bookmarksChanged()
+}
+
+// clearTemporaryBookmarks deletes the markers pasted from the clipboard, keeping the
+// other bookmarks
+func (h *HexDumpApp) clearTemporaryBookmarks() {
+	h.bookmarks = slices.DeleteFunc(h.bookmarks, func(b bookmark) bool { return b.temporary })
+	h.dropStaleTagFilter()
+	h.bookmarksChanged()
+}
+
+// bookmarksChanged redraws everything that shows bookmarks
+func (h *HexDumpApp) bookmarksChanged() {
+	if h.dataList != nil {
+		h.dataList.Refresh()
+	}
+	h.updateStatus() // Which refreshes the Bookmarks panel
+}
+
+// bookmarkAt returns the label of the innermost bookmark containing offset, or ""
+func (h *HexDumpApp) bookmarkAt(offset int) string {
+	label := ""
+	for index := range h.bookmarks {
+		b := &h.bookmarks[index]
+		if b.offset > offset {
+			break
+		}
+		if offset < b.end() {
+			label = b.label
+		}
+	}
+	return label
+}
+
+// bookmarkSpans returns highlight spans for the shown bookmarks intersecting
+// [lineStart, lineEnd)
+func (h *HexDumpApp) bookmarkSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	for index := range h.bookmarks {
+		b := &h.bookmarks[index]
+		if b.offset >= lineEnd {
+			break
+		}
+		if !b.hasTag(h.tagFilter) {
+			continue
+		}
+		start := max(b.offset, lineStart)
+		end := min(b.end(), lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(b.color)})
+		}
+	}
+	return spans
+}
+
+// showAddBookmark bookmarks the selected bytes under a label entered by the user
+func (h *HexDumpApp) showAddBookmark() {
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("Add Bookmark"), lang.L("Select the bytes to bookmark first."), h.window)
+		return
+	}
+
+	start, end := h.selStart, h.selEnd
+	labelEntry := widget.NewEntry()
+	labelEntry.SetText(fmt.Sprintf("Bookmark %d", len(h.bookmarks)+1))
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder(lang.L("e.g. #header #todo"))
+	if h.tagFilter != "" {
+		tagsEntry.SetText(formatTags([]string{h.tagFilter}))
+	}
+
+	dialog.ShowForm(fmt.Sprintf("Add Bookmark at %08X (%d bytes)", start, end-start), "Add", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem(lang.L("Label"), labelEntry),
+			widget.NewFormItem(lang.L("Tags"), tagsEntry),
+		},
+		func(ok bool) {
+			if ok {
+				h.addBookmarks(bookmark{offset: start, length: end - start, label: labelEntry.Text,
+					tags: parseTags(tagsEntry.Text)})
+			}
+		}, h.window)
+}
+
+// showBookmarks shows the Bookmarks side panel
+func (h *HexDumpApp) showBookmarks() {
+	h.showPanel(panelBookmarks)
+}
+
+// createBookmarksPanel creates the Bookmarks side panel: the tags of the bookmarks with
+// their counts above the bookmarks themselves. Tapping a tag shows only the bookmarks
+// having it, and tapping a bookmark selects its bytes.
+func (h *HexDumpApp) createBookmarksPanel() panelContent {
+	selected := -1 // Index in h.bookmarks
+	var shown []int
+	var tags []tagCount
+	tagList := widget.NewList(
+		func() int { return len(tags) + 1 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id == 0 {
+				item.(*widget.Label).SetText(fmt.Sprintf("All bookmarks (%d)", len(h.bookmarks)))
+			} else if id <= len(tags) {
+				item.(*widget.Label).SetText(fmt.Sprintf("#%s (%d)", tags[id-1].tag, tags[id-1].count))
+			}
+		},
+	)
+	tagList.OnSelected = func(id widget.ListItemID) {
+		tag := ""
+		if id > 0 && id <= len(tags) {
+			tag = tags[id-1].tag
+		}
+		if tag != h.tagFilter {
+			h.setTagFilter(tag)
+		}
+	}
+
+	h.bookmarkList = widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id >= len(shown) {
+				return
+			}
+			b := h.bookmarks[shown[id]]
+			text := fmt.Sprintf("%08X  %6d  %s", b.offset, b.length, b.label)
+			if len(b.tags) > 0 {
+				text += "  " + formatTags(b.tags)
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	h.bookmarkList.OnSelected = func(id widget.ListItemID) {
+		if id < len(shown) {
+			selected = shown[id]
+			h.selectBookmark(selected)
+		}
+	}
+	update := func() {
+		shown = h.shownBookmarks()
+		tags = h.bookmarkTags()
+		tagList.Refresh()
+		h.bookmarkList.Refresh()
+		tagIndex := slices.IndexFunc(tags, func(t tagCount) bool { return t.tag == h.tagFilter })
+		tagList.Select(tagIndex + 1) // All bookmarks when not filtered
+	}
+	update()
+
+	deleteBtn := widget.NewButton(lang.L("Delete"), func() {
+		if selected >= 0 && selected < len(h.bookmarks) {
+			h.removeBookmark(selected)
+			h.bookmarkList.UnselectAll()
+			selected = -1
+		}
+	})
+	tagsBtn := widget.NewButton(lang.L("Tags..."), func() { h.showEditTags(selected) })
+	clearBtn := widget.NewButton(lang.L("Clear All"), h.clearBookmarks)
+	importBtn := widget.NewButton(lang.L("Import..."), h.showImportBookmarks)
+
+	split := container.NewVSplit(tagList, container.NewBorder(
+		widget.NewLabel("Offset    Length  Label"), nil, nil, nil, h.bookmarkList))
+	split.SetOffset(0.25)
+	return panelContent{
+		object:  container.NewBorder(nil, container.NewHBox(importBtn, tagsBtn, deleteBtn, clearBtn), nil, nil, split),
+		refresh: update,
+		reset: func() {
+			h.bookmarkList.UnselectAll()
+			selected = -1
+		},
+	}
+}
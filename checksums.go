@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// checksumAlgorithm is a checksum or hash shown by the Checksums panel
+type checksumAlgorithm struct {
+	name string
+	new  func() hash.Hash
+}
+
+// checksumAlgorithms lists the checksums shown by the Checksums panel, in display order
+var checksumAlgorithms = []checksumAlgorithm{
+	{"CRC-32", func() hash.Hash { return crc32.NewIEEE() }},
+	{"Adler-32", func() hash.Hash { return adler32.New() }},
+	{"MD5", md5.New},
+	{"SHA-1", sha1.New},
+	{"SHA-256", sha256.New},
+}
+
+// computeChecksum returns the hex digest of data with the given algorithm
+func computeChecksum(algorithm checksumAlgorithm, data []byte) string {
+	digest := algorithm.new()
+	digest.Write(data)
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+// createChecksumsPanel creates the Checksums side panel, which computes checksums of the
+// selection, or of the whole file when nothing is selected
+func (h *HexDumpApp) createChecksumsPanel() panelContent {
+	rangeLabel := widget.NewLabel("")
+	grid := container.NewGridWithColumns(2)
+	valueLabels := make([]*widget.Label, len(checksumAlgorithms))
+	for index, algorithm := range checksumAlgorithms {
+		valueLabels[index] = widget.NewLabel("")
+		valueLabels[index].Selectable = true
+		valueLabels[index].Wrapping = fyne.TextWrapBreak
+		grid.Add(widget.NewLabel(algorithm.name))
+		grid.Add(valueLabels[index])
+	}
+
+	clearValues := func() {
+		for _, label := range valueLabels {
+			label.SetText("-")
+		}
+	}
+	computeBtn := widget.NewButton(lang.L("Compute"), func() {
+		data := h.fileData
+		if h.hasAnySelection() {
+			data = h.selectionData()
+		}
+		for index, algorithm := range checksumAlgorithms {
+			valueLabels[index].SetText(computeChecksum(algorithm, data))
+		}
+	})
+
+	// Checksums are only computed on request, since hashing a large file takes a while,
+	// so a change of selection just clears them
+	var lastRanges []byteRange
+	refresh := func() {
+		ranges := h.selectedRanges()
+		switch {
+		case len(ranges) > 1:
+			rangeLabel.SetText(fmt.Sprintf("%d selected ranges (%d bytes)", len(ranges), h.selectionLength()))
+		case len(ranges) == 1:
+			rangeLabel.SetText(fmt.Sprintf("Selection %08X-%08X (%d bytes)", ranges[0].start, ranges[0].end-1,
+				ranges[0].end-ranges[0].start))
+		default:
+			rangeLabel.SetText(fmt.Sprintf("Whole file (%d bytes)", len(h.fileData)))
+		}
+		if !slices.Equal(ranges, lastRanges) {
+			clearValues()
+			lastRanges = ranges
+		}
+	}
+	clearValues()
+
+	return panelContent{
+		object: container.NewVScroll(container.NewVBox(
+			container.NewBorder(nil, nil, nil, computeBtn, rangeLabel),
+			grid,
+		)),
+		refresh: refresh,
+		reset: func() {
+			lastRanges = nil
+			clearValues()
+		},
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cliCommands maps subcommand names to their implementations. Each command receives
+// the arguments following its name and returns the process exit code.
+var cliCommands = map[string]func(args []string) int{
+	"batch": runBatchCommand,
+	"dump":  runDumpCommand,
+	"json":  runJSONCommand,
+	"diff":  runDiffCommand,
+	"grep":  runGrepCommand,
+	"cut":   runCutCommand,
+	"serve": runServeCommand,
+}
+
+// runCLI runs the subcommand named by args[0], if there is one. It reports whether a
+// subcommand was found, and if so the exit code the process should exit with.
+func runCLI(args []string) (bool, int) {
+	if len(args) == 0 {
+		return false, 0
+	}
+	command, ok := cliCommands[args[0]]
+	if !ok {
+		// The options of hexdump(1) dump without naming the command, so that scripts
+		// written for it run unchanged
+		if !isDumpOption(args[0]) {
+			return false, 0
+		}
+		command = runDumpCommand
+		return true, command(args)
+	}
+	return true, command(args[1:])
+}
+
+// newFlagSet creates a flag set for a subcommand that reports errors to stderr
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: hexdump %s %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// cliError prints an error message for a subcommand to stderr and returns exit code 2
+func cliError(w io.Writer, command string, err error) int {
+	fmt.Fprintf(w, "hexdump %s: %v\n", command, err)
+	return 2
+}
+
+// runBatchCommand implements "hexdump batch"
+func runBatchCommand(args []string) int {
+	flags := newFlagSet("batch", "[options] -in DIR -out DIR")
+	sourceDir := flags.String("in", "", "source `folder`")
+	destDir := flags.String("out", "", "destination `folder`")
+	pattern := flags.String("glob", "*", "file name `pattern` to match in the source folder")
+	operation := flags.String("op", "text", "operation: text, html, c, xor, or swap")
+	key := flags.String("key", "", "XOR key as `hex` bytes (for -op xor)")
+	width := flags.Int("width", 4, "unit width in bytes (for -op swap)")
+	group := flags.Int("group", 1, "bytes per group (for exports)")
+	encoding := flags.String("encoding", "ISO Latin-1", "character `encoding` (for exports)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	job := batchJob{
+		sourceDir:     *sourceDir,
+		pattern:       *pattern,
+		destDir:       *destDir,
+		swapWidth:     *width,
+		bytesPerGroup: *group,
+		encoding:      *encoding,
+	}
+
+	switch strings.ToLower(*operation) {
+	case "text":
+		job.operation = exportFormatText
+	case "html":
+		job.operation = exportFormatHTML
+	case "c":
+		job.operation = exportFormatCArray
+	case "xor":
+		job.operation = transformXOR
+		xorKey, err := parseHexBytes(*key)
+		if err != nil {
+			return cliError(os.Stderr, "batch", err)
+		}
+		job.xorKey = xorKey
+	case "swap":
+		job.operation = transformSwap
+	default:
+		return cliError(os.Stderr, "batch", fmt.Errorf("unknown operation: %s", *operation))
+	}
+
+	if job.bytesPerGroup < 1 || 16%job.bytesPerGroup != 0 {
+		return cliError(os.Stderr, "batch", fmt.Errorf("group must be 1, 2, 4, 8, or 16"))
+	}
+
+	written, errs := runBatch(job, func(done, total int, name string) bool {
+		if name != "" {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", done+1, total, name)
+		}
+		return true
+	})
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "hexdump batch: %v\n", err)
+	}
+	fmt.Printf("%d file(s) written to %s\n", written, job.destDir)
+
+	if len(errs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// dumpUsage is the usage of "hexdump dump", after that of hexdump(1)
+const dumpUsage = "Usage: hexdump [dump] [-bcCdovx] [-e format_string] [-f format_file] [-n length] [-s offset] [file ...]\n"
+
+// isDumpOption reports whether arg is one of the options of hexdump(1), such as -C
+func isDumpOption(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && strings.IndexByte("bcCdoxefnsv", arg[1]) >= 0
+}
+
+// runDumpCommand implements "hexdump dump", which writes files, or standard input, to
+// standard output in the layouts of hexdump(1). Its options are parsed as hexdump(1)
+// parses them: single letters that may be combined, as in -Cv, with display formats
+// applied in the order given.
+func runDumpCommand(args []string) int {
+	var formats []string
+	verbose := false
+	var skip, length int64 = 0, -1
+	index := 0
+	for ; index < len(args); index++ {
+		arg := args[index]
+		if arg == "--" {
+			index++
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+		for at := 1; at < len(arg); at++ {
+			option := arg[at]
+			switch option {
+			case 'b':
+				formats = append(formats, dumpFormatOctal1...)
+			case 'c':
+				formats = append(formats, dumpFormatChar...)
+			case 'C':
+				formats = append(formats, dumpFormatCanonical...)
+			case 'd':
+				formats = append(formats, dumpFormatDecimal2...)
+			case 'o':
+				formats = append(formats, dumpFormatOctal2...)
+			case 'x':
+				formats = append(formats, dumpFormatHex2...)
+			case 'v':
+				verbose = true
+			case 'e', 'f', 'n', 's':
+				value := arg[at+1:]
+				if value == "" {
+					index++
+					if index == len(args) {
+						fmt.Fprint(os.Stderr, dumpUsage)
+						return cliError(os.Stderr, "dump", fmt.Errorf("option -%c needs a value", option))
+					}
+					value = args[index]
+				}
+				var err error
+				switch option {
+				case 'e':
+					formats = append(formats, value)
+				case 'f':
+					var lines []string
+					lines, err = readDumpFormatFile(value)
+					formats = append(formats, lines...)
+				case 'n':
+					length, err = strconv.ParseInt(value, 0, 64)
+					if err == nil && length < 0 {
+						err = fmt.Errorf("negative length %s", value)
+					}
+				case 's':
+					skip, err = parseDumpOffset(value)
+				}
+				if err != nil {
+					return cliError(os.Stderr, "dump", err)
+				}
+				at = len(arg)
+			default:
+				fmt.Fprint(os.Stderr, dumpUsage)
+				return cliError(os.Stderr, "dump", fmt.Errorf("unknown option -%c", option))
+			}
+		}
+	}
+	if len(formats) == 0 {
+		formats = dumpFormatDefault
+	}
+	formatter, err := newDumpFormatter(formats, verbose)
+	if err != nil {
+		return cliError(os.Stderr, "dump", err)
+	}
+
+	// The files are read as one stream, as hexdump(1) does
+	exitCode := 0
+	var data []byte
+	if files := args[index:]; len(files) == 0 {
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return cliError(os.Stderr, "dump", err)
+		}
+	} else {
+		for _, name := range files {
+			fileData, err := os.ReadFile(name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "hexdump dump: %v\n", err)
+				exitCode = 1
+				continue
+			}
+			data = append(data, fileData...)
+		}
+	}
+	data = data[min(skip, int64(len(data))):]
+	if length >= 0 {
+		data = data[:min(length, int64(len(data)))]
+	}
+	if err := formatter.write(os.Stdout, data, skip); err != nil {
+		return cliError(os.Stderr, "dump", err)
+	}
+	return exitCode
+}
+
+// parseDumpOffset parses the offset of -s: decimal, hex with 0x, or octal with a
+// leading 0, optionally followed by b, k, or m for units of 512, 1024, or 1048576 bytes
+func parseDumpOffset(text string) (int64, error) {
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(text, "b") && !strings.HasPrefix(strings.ToLower(text), "0x"):
+		unit, text = 512, strings.TrimSuffix(text, "b")
+	case strings.HasSuffix(text, "k"):
+		unit, text = 1024, strings.TrimSuffix(text, "k")
+	case strings.HasSuffix(text, "m"):
+		unit, text = 1<<20, strings.TrimSuffix(text, "m")
+	}
+	offset, err := strconv.ParseInt(text, 0, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid offset %q", text)
+	}
+	return offset * unit, nil
+}
+
+// readDumpFormatFile reads the format strings of -f, one per line. Blank lines and
+// lines starting with # are skipped.
+func readDumpFormatFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var formats []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			formats = append(formats, line)
+		}
+	}
+	return formats, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestParseDumpOffset(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    int64
+		wantErr bool
+	}{
+		{"100", 100, false},
+		{"0x100", 256, false},
+		{"0X1b", 27, false},
+		{"010", 8, false},
+		{"2b", 1024, false},
+		{"0x2b", 43, false},
+		{"3k", 3072, false},
+		{"0x10k", 16384, false},
+		{"1m", 1 << 20, false},
+		{"", 0, true},
+		{"-5", 0, true},
+		{"12q", 0, true},
+		{"09", 0, true},
+	}
+	for _, test := range tests {
+		got, err := parseDumpOffset(test.text)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("parseDumpOffset(%q) = %d, %v; want %d, error %v", test.text, got, err, test.want, test.wantErr)
+		}
+	}
+}
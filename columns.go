@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// columnValue is a field extracted from one record
+type columnValue struct {
+	record int
+	offset int
+	data   []byte
+	value  string
+}
+
+// extractColumn extracts the field of the given template type at fieldOffset within
+// every record of the file, stopping at the first record too short to hold the field
+func (h *HexDumpApp) extractColumn(fieldOffset int, typeName string) ([]columnValue, error) {
+	size, err := fieldSize(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if fieldOffset < 0 || fieldOffset+size > h.recordSize {
+		return nil, fmt.Errorf("the field must lie within the %d-byte record", h.recordSize)
+	}
+
+	var values []columnValue
+	for record := 0; ; record++ {
+		offset := record*h.recordSize + fieldOffset
+		if offset+size > len(h.fileData) {
+			break
+		}
+		data := h.fileData[offset : offset+size]
+		values = append(values, columnValue{record: record, offset: offset, data: data,
+			value: formatFieldValue(typeName, data, h.byteOrder())})
+	}
+	return values, nil
+}
+
+// writeColumnCSV writes extracted values to w as CSV with a header row
+func writeColumnCSV(w io.Writer, values []columnValue) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"record", "offset", "bytes", "value"})
+	for _, value := range values {
+		writer.Write([]string{strconv.Itoa(value.record), fmt.Sprintf("0x%X", value.offset),
+			strings.ToUpper(hex.EncodeToString(value.data)), value.value})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// showExtractColumn extracts a field at a fixed offset from every record in record mode
+// into a table, which can be exported to CSV or saved as a new file and opened
+func (h *HexDumpApp) showExtractColumn() {
+	if h.recordSize == 0 {
+		dialog.ShowInformation(lang.L("Extract Column"), lang.L("Turn on record mode first (View → Record Mode...)."), h.window)
+		return
+	}
+
+	var values []columnValue
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetText(fmt.Sprintf("0x%X", h.caret%h.recordSize))
+	typeEntry := widget.NewSelectEntry([]string{"u8", "u16", "u32", "u64", "i8", "i16", "i32", "i64",
+		"f32", "f64", "char[8]", "bytes[4]"})
+	typeEntry.SetText("u32")
+	summaryLabel := widget.NewLabel("")
+
+	list := widget.NewList(
+		func() int { return len(values) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			value := values[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("#%-6d %08X  %s", value.record, value.offset, value.value))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		value := values[id]
+		h.setSelection(value.offset, value.offset+len(value.data))
+		h.goToOffset(value.offset)
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("Extract Column - %s", h.fileName))
+	extractBtn := widget.NewButton(lang.L("Extract"), func() {
+		fieldOffset, err := parseOffset(offsetEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		extracted, err := h.extractColumn(fieldOffset, strings.TrimSpace(typeEntry.Text))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		values = extracted
+		list.UnselectAll()
+		list.Refresh()
+		summaryLabel.SetText(fmt.Sprintf("%d records of %d bytes", len(values), h.recordSize))
+	})
+	exportBtn := widget.NewButton(lang.L("Export CSV..."), func() {
+		filename, err := nativedialog.File().Filter("CSV files", "csv").Title("Export Column").Save()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, window)
+			}
+			return
+		}
+		var buffer bytes.Buffer
+		err = writeColumnCSV(&buffer, values)
+		if err == nil {
+			err = writeDataFile(filename, buffer.Bytes())
+		}
+		if err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+	newFileBtn := widget.NewButton(lang.L("Open as New File..."), func() {
+		filename, err := nativedialog.File().Filter("All Files", "*").Title("Save Column Bytes").Save()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, window)
+			}
+			return
+		}
+		var buffer bytes.Buffer
+		for _, value := range values {
+			buffer.Write(value.data)
+		}
+		if err := writeDataFile(filename, buffer.Bytes()); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		h.openInNewWindow(filename, 0, 0)
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Field offset"), offsetEntry),
+		widget.NewFormItem(lang.L("Type"), typeEntry),
+	)
+	window.SetContent(container.NewBorder(
+		container.NewVBox(form, container.NewHBox(extractBtn, exportBtn, newFileBtn), summaryLabel),
+		nil, nil, nil,
+		list,
+	))
+	window.Resize(fyne.NewSize(450, 500))
+	window.Show()
+}
@@ -0,0 +1,66 @@
+package main
+
+import "fyne.io/fyne/v2/lang"
+
+// startColumnSelection starts a rectangular selection at offset, as Alt+click does
+func (h *HexDumpApp) startColumnSelection(offset int) {
+	h.selAnchor = offset
+	h.extendColumnSelection(offset)
+}
+
+// extendColumnSelection selects the rectangle between the selection anchor and offset:
+// the same byte columns of every row from the anchor's to offset's. The linear selection
+// is empty while a rectangle is selected.
+func (h *HexDumpApp) extendColumnSelection(offset int) {
+	anchor := h.selAnchor
+	h.setSelection(offset, offset)
+	h.selAnchor, h.caret, h.columnSelect = anchor, offset, true
+	if h.dataList != nil {
+		h.dataList.Refresh()
+	}
+	h.updateStatus()
+}
+
+// columnRect returns the rows and the byte columns, both inclusive, of the rectangular
+// selection
+func (h *HexDumpApp) columnRect() (firstRow, lastRow, firstColumn, lastColumn int) {
+	anchorRow, caretRow := h.rowOf(h.selAnchor), h.rowOf(h.caret)
+	anchorColumn := h.selAnchor - h.rowStart(anchorRow)
+	caretColumn := h.caret - h.rowStart(caretRow)
+	return min(anchorRow, caretRow), max(anchorRow, caretRow), min(anchorColumn, caretColumn),
+		max(anchorColumn, caretColumn)
+}
+
+// columnRowRange returns the bytes of the rectangular selection in the row starting at
+// rowStart, which is in its rows; the range is empty if the row is too short
+func (h *HexDumpApp) columnRowRange(rowStart, firstColumn, lastColumn int) byteRange {
+	if h.collapsedAt(rowStart) != nil {
+		return byteRange{rowStart, rowStart}
+	}
+	end := h.lineEnd(rowStart)
+	return byteRange{min(rowStart+firstColumn, end), min(rowStart+lastColumn+1, end)}
+}
+
+// columnRanges returns the bytes of each row of the rectangular selection, leaving out
+// rows too short to reach its columns
+func (h *HexDumpApp) columnRanges() []byteRange {
+	if !h.columnSelect {
+		return nil
+	}
+	firstRow, lastRow, firstColumn, lastColumn := h.columnRect()
+	var ranges []byteRange
+	for row := firstRow; row <= lastRow; row++ {
+		if r := h.columnRowRange(h.rowStart(row), firstColumn, lastColumn); r.start < r.end {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// columnSelectionStatus describes the rectangular selection for the status bar
+func (h *HexDumpApp) columnSelectionStatus() string {
+	firstRow, lastRow, firstColumn, lastColumn := h.columnRect()
+	return lang.L("Columns: {{.Columns}} × {{.Rows}} rows from {{.Start}} ({{.Length}} bytes)", map[string]any{
+		"Columns": lastColumn - firstColumn + 1, "Rows": lastRow - firstRow + 1,
+		"Start": formatHex(uint64(h.rowStart(firstRow)+firstColumn), 8), "Length": h.selectionLength()})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Copy As formats, as shown in the menus
+const (
+	copyHex       = "Hex"
+	copyHexPacked = "Hex (no spaces)"
+	copyText      = "Text"
+	copyCArray    = "C array"
+	copyBase64    = "Base64"
+)
+
+// copyFormats lists the Copy As formats in display order
+var copyFormats = []string{copyHex, copyHexPacked, copyText, copyCArray, copyBase64}
+
+// formatCopy formats data for the clipboard in one of the Copy As formats
+func (h *HexDumpApp) formatCopy(format string, data []byte) string {
+	switch format {
+	case copyHexPacked:
+		return strings.ToUpper(hex.EncodeToString(data))
+	case copyText:
+		return h.bytesToChars(data)
+	case copyCArray:
+		var buffer bytes.Buffer
+		writeCArrayBytes(&buffer, data, "selection")
+		return buffer.String()
+	case copyBase64:
+		return base64.StdEncoding.EncodeToString(data)
+	}
+
+	parts := make([]string, len(data))
+	for index, b := range data {
+		parts[index] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, " ")
+}
+
+// copySelectionAs copies the selected bytes to the clipboard in the given format
+func (h *HexDumpApp) copySelectionAs(format string) {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("Copy"), lang.L("Select the bytes to copy first."), h.window)
+		return
+	}
+	h.window.Clipboard().SetContent(h.formatCopy(format, h.selectionData()))
+}
+
+// copyAsMenu returns a submenu with an item for each Copy As format, and one copying
+// the hex with the colors of its highlights
+func (h *HexDumpApp) copyAsMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(copyFormats))
+	for index, format := range copyFormats {
+		items[index] = fyne.NewMenuItem(format, func() { h.copySelectionAs(format) })
+	}
+	items = append(items, fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(lang.L("Hex with Colors (HTML)"), h.copySelectionHTML))
+	return fyne.NewMenu(lang.L("Copy As"), items...)
+}
+
+// showContextMenu shows the menu of selection actions at position, a position on the
+// window's canvas. A click outside the selection first selects the byte at offset.
+func (h *HexDumpApp) showContextMenu(offset int, position fyne.Position) {
+	if !h.isSelected(offset) {
+		h.selAnchor = offset
+		h.caret = offset
+		h.setSelection(offset, offset+1)
+	}
+
+	copyItem := fyne.NewMenuItem(lang.L("Copy As"), nil)
+	copyItem.ChildMenu = h.copyAsMenu()
+	codecItem := fyne.NewMenuItem(lang.L("Decode/Encode"), nil)
+	codecItem.ChildMenu = h.codecMenu()
+
+	menu := fyne.NewMenu("",
+		copyItem,
+		fyne.NewMenuItem(lang.L("Copy as Table"), h.copySelectionTable),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(lang.L("Fill..."), h.showFillSelection),
+		fyne.NewMenuItem(lang.L("XOR..."), h.showXORSelection),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(lang.L("Save Selection..."), h.saveSelection),
+		fyne.NewMenuItem(lang.L("Export as CSV..."), h.exportSelectionCSV),
+		fyne.NewMenuItem(lang.L("Play as Audio..."), h.showAudioPreview),
+		fyne.NewMenuItem(lang.L("View as Image..."), h.showRawImage),
+		fyne.NewMenuItem(lang.L("Decrypt..."), h.decryptSelection),
+		codecItem,
+		fyne.NewMenuItem(lang.L("Add Bookmark..."), h.showAddBookmark),
+		fyne.NewMenuItem(lang.L("Apply Template Here"), h.applyTemplateAtCaret),
+		fyne.NewMenuItem(lang.L("Find References..."), h.showFindReferences),
+		fyne.NewMenuItem(lang.L("Mark as Range A"), h.markRangeA),
+		fyne.NewMenuItem(lang.L("Compare with Range A..."), h.showCompareRanges),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(lang.L("Select Block..."), h.showSelectBlock),
+	)
+	widget.ShowPopUpMenuAtPosition(menu, h.window.Canvas(), position)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// crashDirName is the folder in the settings directory where crash reports are saved
+const crashDirName = "crashes"
+
+// openApps lists the application instance of every open window, so that a crash can
+// save the unsaved edits of all of them
+var openApps []*HexDumpApp
+
+// crashShowing is set while the crash dialog is open, so that a callback failing again
+// and again doesn't stack up dialogs
+var crashShowing bool
+
+// guard returns f wrapped so that a panic in it is reported in the crash dialog instead
+// of ending the application
+func (h *HexDumpApp) guard(f func()) func() {
+	return func() {
+		defer h.recoverPanic()
+		f()
+	}
+}
+
+// recoverPanic, deferred at the start of a UI callback or background goroutine, stops a
+// panic there, saves a crash report and the unsaved edits of every window, and shows the
+// crash dialog
+func (h *HexDumpApp) recoverPanic() {
+	value := recover()
+	if value == nil {
+		return
+	}
+	stack := string(debug.Stack())
+	fyne.Do(func() {
+		report := crashReport(value, stack)
+		dir, err := saveCrashReport(report)
+		slog.Error("recovered from a panic", "panic", value, "report", dir, "stack", stack)
+		if crashShowing {
+			return
+		}
+		h.showCrashDialog(report, dir, err)
+	})
+}
+
+// crashReport describes a panic for the crash dialog and the saved report: its value,
+// the files open in each window with their unsaved edits, and the stack trace
+func crashReport(value any, stack string) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "Hex Dump Utility crash report, %s\n\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&report, "panic: %v\n\n", value)
+	fmt.Fprintf(&report, "Open files:\n")
+	for _, app := range openApps {
+		switch {
+		case app.fileName == "":
+			fmt.Fprintf(&report, "  (none)\n")
+		case app.isModified():
+			fmt.Fprintf(&report, "  %s (%d unsaved edit(s))\n", app.fileName, len(app.journal))
+		default:
+			fmt.Fprintf(&report, "  %s\n", app.fileName)
+		}
+	}
+	fmt.Fprintf(&report, "\n%s", stack)
+	return report.String()
+}
+
+// saveCrashReport writes the crash report to a new folder under the settings directory,
+// along with a patch list of the unsaved edits of each window, which Edit → Apply Patch
+// List... restores. It returns the folder.
+func saveCrashReport(report string) (dir string, err error) {
+	// The state being saved may be what is broken, so a second panic only loses the
+	// patch lists
+	defer func() {
+		if value := recover(); value != nil {
+			err = fmt.Errorf("saving the unsaved edits failed: %v", value)
+		}
+	}()
+
+	base, err := settingsDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(base, crashDirName, time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.txt"), []byte(report), 0o644); err != nil {
+		return "", err
+	}
+	for index, app := range openApps {
+		if !app.isModified() {
+			continue
+		}
+		name := fmt.Sprintf("%d-%s.patch", index+1, filepath.Base(app.fileName))
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return dir, err
+		}
+		err = writePatch(file, app.fileName, app.changedEntries())
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+// showCrashDialog shows the crash report with a button to copy it, and where the report
+// and the unsaved edits were saved
+func (h *HexDumpApp) showCrashDialog(report, dir string, saveErr error) {
+	message := lang.L("Something went wrong, but the application is still running. The crash report was saved in {{.Folder}}.",
+		map[string]any{"Folder": dir})
+	if slices.ContainsFunc(openApps, (*HexDumpApp).isModified) {
+		message = lang.L("Something went wrong, but the application is still running. Unsaved edits were saved as patch lists in {{.Folder}}; Edit → Apply Patch List... restores them.",
+			map[string]any{"Folder": dir})
+	}
+	if saveErr != nil {
+		message = lang.L("Something went wrong, but the application is still running. The crash report could not be saved: {{.Error}}",
+			map[string]any{"Error": saveErr.Error()})
+	}
+	intro := widget.NewLabel(message)
+	intro.Wrapping = fyne.TextWrapWord
+
+	details := widget.NewMultiLineEntry()
+	details.SetText(report)
+	details.TextStyle.Monospace = true
+	details.Wrapping = fyne.TextWrapOff
+
+	copyButton := widget.NewButton(lang.L("Copy Report"), func() {
+		h.window.Clipboard().SetContent(report)
+	})
+	content := container.NewBorder(intro, container.NewHBox(copyButton), nil, nil, details)
+
+	crashShowing = true
+	crash := dialog.NewCustom(lang.L("Unexpected Error"), lang.L("Close"), content, h.window)
+	crash.SetOnClosed(func() { crashShowing = false })
+	crash.Resize(fyne.NewSize(700, 500))
+	crash.Show()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// backgroundScanSize is the data size from which the whole-data scans behind the
+// highlights and the status bar run in the background, so that a large file opens, and
+// takes keystrokes, without waiting for them
+const backgroundScanSize = 16 << 20
+
+// rescanDelay is how long a background scan waits after an edit, so that a run of
+// typing is scanned once
+const rescanDelay = 300 * time.Millisecond
+
+// scanInputs are the settings the data scans depend on, captured so that the scans can
+// run in the background and their results be dropped if the settings change meanwhile
+type scanInputs struct {
+	snapshot       []byte
+	diffMasks      []maskRegion
+	showSignatures bool
+	paddingMin     int
+	filter         *lineFilter
+	reference      *referencePattern
+}
+
+// dataScan is the result of scanning the whole data
+type dataScan struct {
+	inputs         scanInputs
+	snapshotDiffs  []byteRange
+	textKind       string
+	signatures     []signatureMatch
+	padding        []paddingRegion
+	overlay        *overlayRegion
+	filterMatches  []int
+	referenceDiffs []byteRange
+	sqlite         *sqliteDatabase
+}
+
+// backgroundScan is a scan running in the background. It reads fileData, so changing
+// the data stops it first; see stopScan.
+type backgroundScan struct {
+	cancelled atomic.Bool
+	done      chan struct{} // Closed when the scan no longer reads the data
+}
+
+// scanInputs captures the current settings of the data scans
+func (h *HexDumpApp) scanInputs() scanInputs {
+	return scanInputs{snapshot: h.snapshot, diffMasks: slices.Clone(h.diffMasks), showSignatures: h.showSignatures,
+		paddingMin: h.paddingMin, filter: h.filter, reference: h.reference}
+}
+
+// scan scans data with the settings in inputs. It only reads data and inputs, so it may
+// run in the background. Between the individual scans it checks cancelled, if given, and
+// returns nil once it is set.
+func (inputs scanInputs) scan(data []byte, cancelled *atomic.Bool) *dataScan {
+	scan := &dataScan{inputs: inputs}
+	steps := []func(){
+		func() { scan.textKind = textSummary(data) },
+		func() { scan.overlay = findOverlay(data) },
+		func() { scan.sqlite = parseSQLite(data) },
+	}
+	if inputs.snapshot != nil {
+		steps = append(steps, func() {
+			scan.snapshotDiffs = maskDiffRanges(diffRanges(inputs.snapshot, data), inputs.diffMasks)
+		})
+	}
+	if inputs.showSignatures {
+		steps = append(steps, func() { scan.signatures = findSignatures(data) })
+	}
+	if inputs.paddingMin > 0 {
+		steps = append(steps, func() { scan.padding = findPadding(data, inputs.paddingMin) })
+	}
+	if inputs.filter != nil {
+		steps = append(steps, func() { scan.filterMatches = inputs.filter.pattern.findAll(data, 0) })
+	}
+	if inputs.reference != nil {
+		steps = append(steps, func() { scan.referenceDiffs = referenceDiffRanges(data, inputs.reference) })
+	}
+	for _, step := range steps {
+		if cancelled != nil && cancelled.Load() {
+			return nil
+		}
+		step()
+	}
+	return scan
+}
+
+// applyScan takes over the results of a scan. Results whose settings were changed while
+// the scan ran are skipped, since changing them already updated those results.
+func (h *HexDumpApp) applyScan(scan *dataScan) {
+	current := h.scanInputs()
+	h.textKind, h.overlay, h.sqlite = scan.textKind, scan.overlay, scan.sqlite
+	if sameBacking(current.snapshot, scan.inputs.snapshot) && slices.Equal(current.diffMasks, scan.inputs.diffMasks) {
+		h.snapshotDiffs = scan.snapshotDiffs
+	}
+	if current.showSignatures == scan.inputs.showSignatures {
+		h.signatures = scan.signatures
+	}
+	if current.paddingMin == scan.inputs.paddingMin {
+		h.padding = scan.padding
+	}
+	if h.filter != nil && h.filter == scan.inputs.filter {
+		h.filter.matches = scan.filterMatches
+	}
+	if h.reference != nil && h.reference == scan.inputs.reference {
+		h.reference.diffs = scan.referenceDiffs
+	}
+}
+
+// sameBacking reports whether two slices are the same bytes in memory
+func sameBacking(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// rescanData updates the results of the whole-data scans after the data changed. Large
+// data is scanned in the background, after delay, and the display and status bar are
+// updated when the scan is done; the results from before stay meanwhile.
+func (h *HexDumpApp) rescanData(delay time.Duration) {
+	h.scanGeneration++
+	if len(h.fileData) < backgroundScanSize {
+		h.applyScan(h.scanInputs().scan(h.fileData, nil))
+		return
+	}
+
+	generation := h.scanGeneration
+	start := func() {
+		if generation != h.scanGeneration {
+			return // Superseded by a later change
+		}
+		h.stopScan()
+		inputs, data := h.scanInputs(), h.fileData
+		running := &backgroundScan{done: make(chan struct{})}
+		h.runningScan = running
+		go func() {
+			defer h.recoverPanic()
+			defer close(running.done)
+			scan := inputs.scan(data, &running.cancelled)
+			fyne.Do(func() {
+				if h.runningScan != running {
+					return // Stopped by a change to the data
+				}
+				h.runningScan = nil
+				h.unmapRetired()
+				if scan == nil || generation != h.scanGeneration {
+					return
+				}
+				h.applyScan(scan)
+				h.updateDisplay()
+				h.updateStatus()
+			})
+		}()
+	}
+	if delay == 0 {
+		start()
+		return
+	}
+	time.AfterFunc(delay, func() { fyne.Do(start) })
+}
+
+// stopScan cancels the background scan, if one is running, and waits until it no longer
+// reads fileData, so that the data can be changed under it. The change rescans the data
+// afterwards.
+func (h *HexDumpApp) stopScan() {
+	if h.runningScan == nil {
+		return
+	}
+	h.runningScan.cancelled.Store(true)
+	<-h.runningScan.done
+	h.runningScan = nil
+	h.unmapRetired()
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// Decryption scheme names, as shown in the GUI
+const (
+	schemeAESCBC   = "AES-CBC"
+	schemeAESGCM   = "AES-GCM"
+	schemeChaCha20 = "ChaCha20"
+)
+
+// decryptSchemes lists the supported decryption schemes in display order
+var decryptSchemes = []string{schemeAESCBC, schemeAESGCM, schemeChaCha20}
+
+// ivSize returns the size of the IV or nonce the scheme uses
+func ivSize(scheme string) int {
+	if scheme == schemeAESCBC {
+		return aes.BlockSize
+	}
+	return 12
+}
+
+// decrypt decrypts data with the given scheme, key and IV or nonce. If iv is empty it
+// is taken from the start of data, where many formats store it. AES-CBC padding is
+// removed when it is valid PKCS#7, the AES-GCM tag is expected at the end of data, and
+// ChaCha20 starts at the given block counter.
+func decrypt(scheme string, key, iv, data []byte, counter uint32) ([]byte, error) {
+	if len(iv) == 0 {
+		if len(data) < ivSize(scheme) {
+			return nil, fmt.Errorf("the data is too short to start with a %d-byte IV", ivSize(scheme))
+		}
+		iv, data = data[:ivSize(scheme)], data[ivSize(scheme):]
+	}
+	if len(iv) != ivSize(scheme) {
+		return nil, fmt.Errorf("%s needs a %d-byte IV, not %d bytes", scheme, ivSize(scheme), len(iv))
+	}
+
+	if scheme == schemeChaCha20 {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("ChaCha20 needs a 32-byte key, not %d bytes", len(key))
+		}
+		return chacha20XOR(key, iv, counter, data), nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("AES needs a 16, 24 or 32-byte key, not %d bytes", len(key))
+	}
+	if scheme == schemeAESGCM {
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		plain, err := gcm.Open(nil, iv, data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("AES-GCM authentication failed; the key, nonce or data is wrong")
+		}
+		return plain, nil
+	}
+
+	if len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("AES-CBC data must be a multiple of %d bytes, not %d bytes", aes.BlockSize, len(data))
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+	return trimPKCS7(plain), nil
+}
+
+// trimPKCS7 removes PKCS#7 padding from data, leaving data unchanged if the padding
+// is not valid
+func trimPKCS7(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(data) {
+		return data
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return data
+		}
+	}
+	return data[:len(data)-padding]
+}
+
+// chacha20XOR XORs data with the ChaCha20 key stream of RFC 8439 for the 32-byte key
+// and 12-byte nonce, starting at the given block counter
+func chacha20XOR(key, nonce []byte, counter uint32, data []byte) []byte {
+	var state [16]uint32
+	state[0], state[1], state[2], state[3] = 0x61707865, 0x3320646e, 0x79622d32, 0x6b206574
+	for i := 0; i < 8; i++ {
+		state[4+i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+	for i := 0; i < 3; i++ {
+		state[13+i] = binary.LittleEndian.Uint32(nonce[i*4:])
+	}
+
+	result := make([]byte, len(data))
+	var stream [64]byte
+	for offset := 0; offset < len(data); offset += 64 {
+		state[12] = counter
+		chachaBlock(&state, &stream)
+		for i := offset; i < min(offset+64, len(data)); i++ {
+			result[i] = data[i] ^ stream[i-offset]
+		}
+		counter++
+	}
+	return result
+}
+
+// chachaBlock computes the ChaCha20 key stream block for state
+func chachaBlock(state *[16]uint32, stream *[64]byte) {
+	x := *state
+	quarterRound := func(a, b, c, d int) {
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+	}
+	for round := 0; round < 10; round++ {
+		quarterRound(0, 4, 8, 12)
+		quarterRound(1, 5, 9, 13)
+		quarterRound(2, 6, 10, 14)
+		quarterRound(3, 7, 11, 15)
+		quarterRound(0, 5, 10, 15)
+		quarterRound(1, 6, 11, 12)
+		quarterRound(2, 7, 8, 13)
+		quarterRound(3, 4, 9, 14)
+	}
+	for i := range x {
+		binary.LittleEndian.PutUint32(stream[i*4:], x[i]+state[i])
+	}
+}
+
+// openEncryptedFile asks for an encrypted file and then for the key to decrypt it with
+func (h *HexDumpApp) openEncryptedFile() {
+	filename, err := nativedialog.File().Filter("All Files", "*").Title("Open Encrypted").Load()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	h.showDecrypt(filename, data)
+}
+
+// decryptSelection decrypts the selected bytes, for encrypted blobs inside a file
+func (h *HexDumpApp) decryptSelection() {
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("Decrypt"), lang.L("Select the encrypted bytes first."), h.window)
+		return
+	}
+	h.showDecrypt(fmt.Sprintf("%s@%X", h.fileName, h.selStart), h.selectedBytes())
+}
+
+// showDecrypt asks for the scheme, key and IV to decrypt data read from name with, and
+// shows the plaintext in a new window. The plaintext is only written to disk if it is
+// saved there.
+func (h *HexDumpApp) showDecrypt(name string, data []byte) {
+	schemeSelect := widget.NewSelect(decryptSchemes, nil)
+	schemeSelect.SetSelected(schemeAESCBC)
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder(lang.L("Hex, e.g. 00112233..."))
+	ivEntry := widget.NewEntry()
+	ivEntry.SetPlaceHolder(lang.L("Hex; empty to read it from the start of the data"))
+	counterEntry := widget.NewEntry()
+	counterEntry.SetText("0")
+
+	counterItem := widget.NewFormItem(lang.L("Counter"), counterEntry)
+	counterItem.HintText = "ChaCha20 only; the first block's counter"
+	form := dialog.NewForm("Decrypt "+filepath.Base(name), "Decrypt", "Cancel", []*widget.FormItem{
+		widget.NewFormItem(lang.L("Scheme"), schemeSelect),
+		widget.NewFormItem(lang.L("Key"), keyEntry),
+		widget.NewFormItem(lang.L("IV / nonce"), ivEntry),
+		counterItem,
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		key, err := parseHexBytes(keyEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		var iv []byte
+		if strings.TrimSpace(ivEntry.Text) != "" {
+			if iv, err = parseHexBytes(ivEntry.Text); err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+		}
+		counter, err := strconv.ParseUint(strings.TrimSpace(counterEntry.Text), 0, 32)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid counter %q", counterEntry.Text), h.window)
+			return
+		}
+
+		plain, err := decrypt(schemeSelect.Selected, key, iv, data, uint32(counter))
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		h.openDataInNewWindow(name+".decrypted", plain)
+	}, h.window)
+	form.Resize(fyne.NewSize(480, 320))
+	form.Show()
+	h.window.Canvas().Focus(keyEntry)
+}
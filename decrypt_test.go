@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestChacha20XOR(t *testing.T) {
+	// Test vectors from RFC 8439 sections 2.3.2 and 2.4.2
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	tests := []struct {
+		name      string
+		nonce     string
+		counter   uint32
+		plaintext []byte
+		want      string
+	}{
+		{
+			name:      "block function",
+			nonce:     "000000090000004a00000000",
+			counter:   1,
+			plaintext: make([]byte, 64),
+			want: "10f1e7e4d13b5915500fdd1fa32071c4c7d1f4c733c068030422aa9ac3d46c4e" +
+				"d2826446079faa0914c2d705d98b02a2b5129cd1de164eb9cbd083e8a2503c4e",
+		},
+		{
+			name:    "encryption",
+			nonce:   "000000000000004a00000000",
+			counter: 1,
+			plaintext: []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip " +
+				"for the future, sunscreen would be it."),
+			want: "6e2e359a2568f98041ba0728dd0d6981e97e7aec1d4360c20a27afccfd9fae0b" +
+				"f91b65c5524733ab8f593dabcd62b3571639d624e65152ab8f530c359f0861d8" +
+				"07ca0dbf500d6a6156a38e088a22b65e52bc514d16ccf806818ce91ab7793736" +
+				"5af90bbf74a35be6b40b8eedf2785e42874d",
+		},
+	}
+	for _, test := range tests {
+		nonce, _ := hex.DecodeString(test.nonce)
+		want, _ := hex.DecodeString(test.want)
+		got := chacha20XOR(key, nonce, test.counter, test.plaintext)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got %x, want %x", test.name, got, want)
+		}
+		if back := chacha20XOR(key, nonce, test.counter, got); !bytes.Equal(back, test.plaintext) {
+			t.Errorf("%s: decrypting gave %x", test.name, back)
+		}
+	}
+}
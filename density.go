@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+// Row densities of the data list
+const (
+	densityNormal      = ""
+	densityCompact     = "compact"
+	densityComfortable = "comfortable"
+)
+
+// densityNames maps the row densities to their names in the Preferences dialog
+var densityNames = map[string]string{
+	densityCompact:     "Compact",
+	densityNormal:      "Normal",
+	densityComfortable: "Comfortable",
+}
+
+// densitySpacing returns the space a density adds to the height of the row text, as a
+// fraction of the font size so that it grows with the text
+func densitySpacing(density string) float32 {
+	switch density {
+	case densityCompact:
+		return 0
+	case densityComfortable:
+		return 0.6
+	default:
+		return 0.3 // 18 pixels at the default size
+	}
+}
+
+// defaultDataFontSize is the font size of the data list unless the preferences change it
+const defaultDataFontSize = 12
+
+// dataFontSizes lists the font sizes offered for the data list
+var dataFontSizes = []float32{9, 10, 11, 12, 13, 14, 16, 18, 20, 24}
+
+// rowTextSize returns the font size of the hex and character text
+func rowTextSize() float32 {
+	if size := appSettings.DataFontSize; size >= dataFontSizes[0] && size <= dataFontSizes[len(dataFontSizes)-1] {
+		return size
+	}
+	return defaultDataFontSize
+}
+
+// rowHeight returns the height of a line of the data list: the line height of the
+// monospace font, which covers its ascenders and descenders, plus the density's spacing.
+// It is rounded up to whole pixels so that rows tile without gaps.
+func rowHeight() float32 {
+	size := rowTextSize()
+	text := fyne.MeasureText("Mg|", size, fyne.TextStyle{Monospace: true}).Height
+	return float32(math.Ceil(float64(text + size*densitySpacing(appSettings.Density))))
+}
+
+// rebuildDataList replaces the data list with one whose rows use the current font size
+// and density, keeping the caret in view
+func (h *HexDumpApp) rebuildDataList() {
+	h.accessibleRows = nil
+	h.dataList = h.newDataList()
+	h.layoutPanels()
+	h.goToOffset(h.caret)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maskRegion is a range of offsets whose content is expected to vary between versions of
+// a file, such as a timestamp or a build ID, and which comparisons ignore
+type maskRegion struct {
+	byteRange
+	label string
+}
+
+// parseMaskRegions parses mask regions, one per line: "START-END" with an inclusive end,
+// or "START+LENGTH", followed by an optional label. Offsets are written as for
+// parseOffset, and empty lines and lines starting with # are skipped.
+func parseMaskRegions(text string) ([]maskRegion, error) {
+	var regions []maskRegion
+	for number, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rangeText, label, _ := strings.Cut(line, " ")
+		region, err := parseMaskRange(rangeText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", number+1, err)
+		}
+		region.label = strings.TrimSpace(label)
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+// parseMaskRange parses the range of a mask region, "START-END" or "START+LENGTH"
+func parseMaskRange(text string) (maskRegion, error) {
+	separator := strings.IndexAny(text, "-+")
+	if separator < 0 {
+		return maskRegion{}, fmt.Errorf("%q is not START-END or START+LENGTH", text)
+	}
+	start, err := parseOffset(text[:separator])
+	if err != nil {
+		return maskRegion{}, err
+	}
+	value, err := parseOffset(text[separator+1:])
+	if err != nil {
+		return maskRegion{}, err
+	}
+	end := value + 1
+	if text[separator] == '+' {
+		end = start + value
+	}
+	if start < 0 || end <= start {
+		return maskRegion{}, fmt.Errorf("%q is an empty range", text)
+	}
+	return maskRegion{byteRange: byteRange{start, end}}, nil
+}
+
+// formatMaskRegions formats mask regions as parseMaskRegions reads them
+func formatMaskRegions(regions []maskRegion) string {
+	var builder strings.Builder
+	for _, region := range regions {
+		fmt.Fprintf(&builder, "0x%X-0x%X", region.start, region.end-1)
+		if region.label != "" {
+			builder.WriteString(" " + region.label)
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// maskDiffRanges removes the bytes of the mask regions from differing ranges, which are
+// in order, so that only meaningful changes remain
+func maskDiffRanges(ranges []byteRange, masks []maskRegion) []byteRange {
+	if len(masks) == 0 {
+		return ranges
+	}
+	sorted := slices.Clone(masks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+	var kept []byteRange
+	for _, r := range ranges {
+		for _, mask := range sorted {
+			if mask.end <= r.start || mask.start >= r.end {
+				continue
+			}
+			if mask.start > r.start {
+				kept = append(kept, byteRange{r.start, mask.start})
+			}
+			r.start = mask.end
+			if r.start >= r.end {
+				break
+			}
+		}
+		if r.start < r.end {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// maskedBytes counts the bytes of the differing ranges, which are in order, that the mask
+// regions hide
+func maskedBytes(ranges []byteRange, masks []maskRegion) int {
+	count := 0
+	for _, r := range ranges {
+		count += r.end - r.start
+	}
+	for _, r := range maskDiffRanges(ranges, masks) {
+		count -= r.end - r.start
+	}
+	return count
+}
+
+// compareWithSnapshot returns the ranges of data that differ from the snapshot, leaving
+// out the mask regions
+func (h *HexDumpApp) compareWithSnapshot(data []byte) []byteRange {
+	return maskDiffRanges(diffRanges(h.snapshot, data), h.diffMasks)
+}
+
+// showMaskSelection adds the selection as a mask region, with a label such as "build ID"
+func (h *HexDumpApp) showMaskSelection() {
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("Mask Selection"), lang.L("Select the bytes to ignore first."), h.window)
+		return
+	}
+	labelEntry := widget.NewEntry()
+	labelEntry.SetPlaceHolder(lang.L("e.g. timestamp, build ID"))
+	dialog.ShowForm(fmt.Sprintf("Ignore %08X-%08X in Comparisons", h.selStart, h.selEnd-1), lang.L("Mask"),
+		lang.L("Cancel"), []*widget.FormItem{widget.NewFormItem(lang.L("Label"), labelEntry)}, func(ok bool) {
+			if ok {
+				h.diffMasks = append(h.diffMasks, maskRegion{byteRange{h.selStart, h.selEnd},
+					strings.TrimSpace(labelEntry.Text)})
+				h.snapshotChanged()
+			}
+		}, h.window)
+	h.window.Canvas().Focus(labelEntry)
+}
+
+// showMaskRegions edits the mask regions as text, one per line, in the form hexdump diff
+// reads with -mask-file, so that they can be shared with scripts
+func (h *HexDumpApp) showMaskRegions() {
+	regionsEntry := widget.NewMultiLineEntry()
+	regionsEntry.SetMinRowsVisible(6)
+	regionsEntry.SetText(formatMaskRegions(h.diffMasks))
+	regionsEntry.SetPlaceHolder("0x40-0x47 timestamp\n0x1F0+20 build ID")
+	regionsEntry.Validator = func(text string) error {
+		_, err := parseMaskRegions(text)
+		return err
+	}
+	regionsItem := widget.NewFormItem(lang.L("Regions"), regionsEntry)
+	regionsItem.HintText = "START-END or START+LENGTH, then a label; ignored when comparing"
+	form := dialog.NewForm(lang.L("Mask Regions"), lang.L("Save"), lang.L("Cancel"),
+		[]*widget.FormItem{regionsItem}, func(ok bool) {
+			if !ok {
+				return
+			}
+			regions, err := parseMaskRegions(regionsEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			h.diffMasks = regions
+			h.snapshotChanged()
+		}, h.window)
+	form.Resize(fyne.NewSize(460, 320))
+	form.Show()
+	h.window.Canvas().Focus(regionsEntry)
+}
+
+// maskSpans returns highlight spans for the mask regions intersecting [lineStart, lineEnd)
+// while the file is compared with a snapshot
+func (h *HexDumpApp) maskSpans(lineStart, lineEnd int) []highlightSpan {
+	if h.snapshot == nil {
+		return nil
+	}
+	var spans []highlightSpan
+	for _, mask := range h.diffMasks {
+		if mask.start < lineEnd && mask.end > lineStart {
+			spans = append(spans, highlightSpan{start: max(mask.start, lineStart), end: min(mask.end, lineEnd),
+				color: theme.Color(colorNameMasked)})
+		}
+	}
+	return spans
+}
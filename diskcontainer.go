@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxExpandedImage limits the virtual size of a VHD or qcow2 image that is expanded
+// into memory, since images are often sparse and much larger than their files
+const maxExpandedImage = 4 << 30
+
+// diskContainer describes a virtual machine disk image format that stores a disk in
+// some other layout than a raw copy of its sectors
+type diskContainer struct {
+	name   string
+	detect func(data []byte) bool
+	expand func(data []byte) ([]byte, error) // Returns the raw disk
+}
+
+// diskContainers lists the supported disk image formats
+var diskContainers = []diskContainer{
+	{"qcow2", isQCOW2, expandQCOW2},
+	{"VHD", isVHD, expandVHD},
+}
+
+// detectDiskContainer returns the format of a disk image stored as data, if it is one
+// of diskContainers
+func detectDiskContainer(data []byte) (diskContainer, bool) {
+	for _, container := range diskContainers {
+		if container.detect(data) {
+			return container, true
+		}
+	}
+	return diskContainer{}, false
+}
+
+// checkExpandedSize returns an error if a disk of size bytes is too large to expand
+func checkExpandedSize(size uint64) error {
+	if size > maxExpandedImage {
+		return fmt.Errorf("the disk is %s, more than the %s that can be expanded", formatDiskSize(size),
+			formatDiskSize(maxExpandedImage))
+	}
+	return nil
+}
+
+// vhdFooterSize is the size of the footer at the end of every VHD file
+const vhdFooterSize = 512
+
+// VHD disk types
+const (
+	vhdFixed        = 2
+	vhdDynamic      = 3
+	vhdDifferencing = 4
+)
+
+// isVHD reports whether data ends with a VHD footer
+func isVHD(data []byte) bool {
+	return len(data) >= vhdFooterSize && string(data[len(data)-vhdFooterSize:][:8]) == "conectix"
+}
+
+// expandVHD returns the disk stored in a fixed or dynamic VHD file. A fixed VHD is the
+// raw disk followed by the footer; a dynamic one stores the blocks of the disk that
+// were written, located by its block allocation table.
+func expandVHD(data []byte) ([]byte, error) {
+	footer := data[len(data)-vhdFooterSize:]
+	size := binary.BigEndian.Uint64(footer[48:])
+	if err := checkExpandedSize(size); err != nil {
+		return nil, err
+	}
+
+	switch diskType := binary.BigEndian.Uint32(footer[60:]); diskType {
+	case vhdFixed:
+		if size > uint64(len(data)-vhdFooterSize) {
+			return nil, fmt.Errorf("the fixed VHD is shorter than its disk size, %s", formatDiskSize(size))
+		}
+		return data[:size], nil
+	case vhdDynamic:
+	case vhdDifferencing:
+		return nil, fmt.Errorf("a differencing VHD can't be expanded without its parent")
+	default:
+		return nil, fmt.Errorf("unknown VHD disk type %d", diskType)
+	}
+
+	headerOffset := binary.BigEndian.Uint64(footer[16:])
+	if headerOffset > uint64(len(data)-1024) || string(data[headerOffset:headerOffset+8]) != "cxsparse" {
+		return nil, fmt.Errorf("the dynamic VHD header is missing")
+	}
+	header := data[headerOffset : headerOffset+1024]
+	tableOffset := binary.BigEndian.Uint64(header[16:])
+	entries := uint64(binary.BigEndian.Uint32(header[28:]))
+	blockSize := uint64(binary.BigEndian.Uint32(header[32:]))
+	if blockSize == 0 || blockSize%512 != 0 || tableOffset+4*entries > uint64(len(data)) {
+		return nil, fmt.Errorf("the dynamic VHD header is invalid")
+	}
+
+	// Each block starts with a bitmap of its sectors, padded to a whole sector
+	bitmapSize := (blockSize/512/8 + 511) / 512 * 512
+	disk := make([]byte, size)
+	for index := range entries {
+		sector := binary.BigEndian.Uint32(data[tableOffset+4*index:])
+		start := index * blockSize
+		if sector == 0xFFFFFFFF || start >= size {
+			continue // Never written, so zeros
+		}
+		blockStart := uint64(sector)*512 + bitmapSize
+		length := min(blockSize, size-start)
+		if blockStart+length > uint64(len(data)) {
+			return nil, fmt.Errorf("block %d of the VHD is past the end of the file", index)
+		}
+		copy(disk[start:start+length], data[blockStart:blockStart+length])
+	}
+	return disk, nil
+}
+
+// qcow2 L1 and L2 table entry bits
+const (
+	qcow2OffsetMask = 0x00FFFFFFFFFFFE00
+	qcow2Compressed = 1 << 62
+	qcow2ZeroFlag   = 1
+)
+
+// isQCOW2 reports whether data starts with a qcow2 header
+func isQCOW2(data []byte) bool {
+	return len(data) >= 72 && string(data[:4]) == "QFI\xfb" && binary.BigEndian.Uint32(data[4:]) >= 2
+}
+
+// expandQCOW2 returns the disk stored in a qcow2 image, reading its clusters through
+// the two-level table that maps them. Compressed clusters are inflated; images with a
+// backing file, encryption, external data, or zstd compression are not supported.
+func expandQCOW2(data []byte) ([]byte, error) {
+	version := binary.BigEndian.Uint32(data[4:])
+	if binary.BigEndian.Uint64(data[8:]) != 0 {
+		return nil, fmt.Errorf("the qcow2 image can't be expanded without its backing file")
+	}
+	clusterBits := binary.BigEndian.Uint32(data[20:])
+	size := binary.BigEndian.Uint64(data[24:])
+	if binary.BigEndian.Uint32(data[32:]) != 0 {
+		return nil, fmt.Errorf("the qcow2 image is encrypted")
+	}
+	if version >= 3 && len(data) >= 104 {
+		// Bit 0 only marks the image dirty; the others change how it is read
+		if features := binary.BigEndian.Uint64(data[72:]); features&^1 != 0 {
+			return nil, fmt.Errorf("the qcow2 image uses unsupported features (0x%X)", features)
+		}
+	}
+	if clusterBits < 9 || clusterBits > 21 {
+		return nil, fmt.Errorf("invalid qcow2 cluster size 2^%d", clusterBits)
+	}
+	if err := checkExpandedSize(size); err != nil {
+		return nil, err
+	}
+
+	clusterSize := uint64(1) << clusterBits
+	l2Entries := clusterSize / 8
+	l1Size := uint64(binary.BigEndian.Uint32(data[36:]))
+	l1Offset := binary.BigEndian.Uint64(data[40:])
+	if l1Offset+8*l1Size > uint64(len(data)) {
+		return nil, fmt.Errorf("the qcow2 L1 table is past the end of the file")
+	}
+
+	disk := make([]byte, size)
+	for l1Index := range l1Size {
+		l2Offset := binary.BigEndian.Uint64(data[l1Offset+8*l1Index:]) & qcow2OffsetMask
+		if l2Offset == 0 {
+			continue
+		}
+		if l2Offset+clusterSize > uint64(len(data)) {
+			return nil, fmt.Errorf("a qcow2 L2 table is past the end of the file")
+		}
+		for l2Index := range l2Entries {
+			start := (l1Index*l2Entries + l2Index) * clusterSize
+			if start >= size {
+				break
+			}
+			cluster := disk[start:min(start+clusterSize, size)]
+			entry := binary.BigEndian.Uint64(data[l2Offset+8*l2Index:])
+			if err := readQCOW2Cluster(data, entry, clusterBits, cluster); err != nil {
+				return nil, fmt.Errorf("cluster at 0x%X: %w", start, err)
+			}
+		}
+	}
+	return disk, nil
+}
+
+// readQCOW2Cluster reads the cluster an L2 table entry points at into cluster, which is
+// left zero if the cluster was never written
+func readQCOW2Cluster(data []byte, entry uint64, clusterBits uint32, cluster []byte) error {
+	if entry&qcow2Compressed != 0 {
+		// The offset and the number of extra 512-byte sectors share the low 62 bits
+		offsetBits := 62 - (clusterBits - 8)
+		offset := entry & (1<<offsetBits - 1)
+		sectors := (entry>>offsetBits)&(1<<(clusterBits-8)-1) + 1
+		end := min((offset&^511)+sectors*512, uint64(len(data)))
+		if offset >= end {
+			return fmt.Errorf("compressed data past the end of the file")
+		}
+		reader := flate.NewReader(bytes.NewReader(data[offset:end]))
+		defer reader.Close()
+		if _, err := io.ReadFull(reader, cluster); err != nil {
+			return fmt.Errorf("inflating: %w", err)
+		}
+		return nil
+	}
+	offset := entry & qcow2OffsetMask
+	if offset == 0 || entry&qcow2ZeroFlag != 0 {
+		return nil
+	}
+	if offset+uint64(len(cluster)) > uint64(len(data)) {
+		return fmt.Errorf("data past the end of the file")
+	}
+	copy(cluster, data[offset:])
+	return nil
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// diskSectorSize is the sector size assumed for MBR partition tables
+const diskSectorSize = 512
+
+// maxLogicalPartitions limits how far the chain of extended boot records is followed
+const maxLogicalPartitions = 64
+
+// diskEntry is one structure found in a disk image: a partition table, a partition, or
+// a filesystem header, shown indented under the structure it belongs to
+type diskEntry struct {
+	offset    int
+	size      int
+	depth     int
+	text      string
+	partition bool // Whether the entry is a partition, which can be opened on its own
+}
+
+// mbrTypes names the common MBR partition type bytes
+var mbrTypes = map[byte]string{
+	0x01: "FAT12", 0x04: "FAT16 (small)", 0x05: "Extended", 0x06: "FAT16", 0x07: "NTFS/exFAT",
+	0x0B: "FAT32", 0x0C: "FAT32 (LBA)", 0x0E: "FAT16 (LBA)", 0x0F: "Extended (LBA)", 0x82: "Linux swap",
+	0x83: "Linux", 0x8E: "Linux LVM", 0xA5: "FreeBSD", 0xAF: "HFS+", 0xEE: "GPT protective", 0xEF: "EFI System",
+}
+
+// gptTypes names the common GPT partition type GUIDs
+var gptTypes = map[string]string{
+	"C12A7328-F81F-11D2-BA4B-00A0C93EC93B": "EFI System",
+	"21686148-6449-6E6F-744E-656564454649": "BIOS boot",
+	"E3C9E316-0B5C-4DB8-817D-F92DF00215AE": "Microsoft reserved",
+	"EBD0A0A2-B9E5-4433-87C0-68B6B72699C7": "Microsoft basic data",
+	"DE94BBA4-06D1-4D40-A16A-BFD50179D6AC": "Windows recovery",
+	"0FC63DAF-8483-4772-8E79-3D69D8477DE4": "Linux filesystem",
+	"0657FD6D-A4AB-43C4-84E5-0933C84B4F4F": "Linux swap",
+	"E6D6D379-F507-44C2-A23C-238F2A3DF928": "Linux LVM",
+	"7C3457EF-0000-11AA-AA11-00306543ECAC": "Apple APFS",
+}
+
+// formatDiskSize formats a size in bytes in the largest binary unit that keeps it at
+// least 1, such as "512 MiB"
+func formatDiskSize(size uint64) string {
+	units := []string{"bytes", "KiB", "MiB", "GiB", "TiB"}
+	value, unit := float64(size), 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d bytes", size)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+// formatGUID formats the 16 bytes of a GUID as stored on disk, with its first three
+// fields little-endian
+func formatGUID(data []byte) string {
+	return fmt.Sprintf("%08X-%04X-%04X-%X-%X", binary.LittleEndian.Uint32(data), binary.LittleEndian.Uint16(data[4:]),
+		binary.LittleEndian.Uint16(data[6:]), data[8:10], data[10:16])
+}
+
+// trimLabel returns a space- or zero-padded volume label without its padding
+func trimLabel(data []byte) string {
+	return strings.TrimRight(string(bytes.TrimRight(data, "\x00")), " ")
+}
+
+// analyzeDisk finds the partition tables and filesystem headers of a disk image: an MBR
+// with its logical partitions, or a GPT, and the FAT, NTFS, and ext2/3/4 filesystems at
+// the start of each partition. An image of a single filesystem is recognized too.
+func analyzeDisk(data []byte) []diskEntry {
+	if entries := analyzeFilesystem(data, 0, 0); len(entries) > 0 {
+		return entries
+	}
+	if len(data) < diskSectorSize || data[510] != 0x55 || data[511] != 0xAA {
+		return nil
+	}
+
+	// The four primary entries must have valid boot flags
+	for index := 0; index < 4; index++ {
+		if flag := data[446+16*index]; flag != 0x00 && flag != 0x80 {
+			return nil
+		}
+	}
+	entries := []diskEntry{{offset: 446, size: 66, text: "MBR partition table"}}
+	for index := 0; index < 4; index++ {
+		entry := data[446+16*index : 446+16*(index+1)]
+		partType := entry[4]
+		if partType == 0 {
+			continue
+		}
+		start := int(binary.LittleEndian.Uint32(entry[8:])) * diskSectorSize
+		size := uint64(binary.LittleEndian.Uint32(entry[12:])) * diskSectorSize
+		entries = append(entries, mbrPartition(fmt.Sprintf("Partition %d", index+1), partType, entry[0] == 0x80,
+			start, size, 1))
+
+		switch partType {
+		case 0xEE:
+			entries = append(entries, analyzeGPT(data)...)
+		case 0x05, 0x0F:
+			entries = append(entries, analyzeExtended(data, start)...)
+		default:
+			entries = append(entries, analyzeFilesystem(data, start, 2)...)
+		}
+	}
+	return entries
+}
+
+// mbrPartition describes an MBR partition entry
+func mbrPartition(name string, partType byte, active bool, start int, size uint64, depth int) diskEntry {
+	typeName := mbrTypes[partType]
+	if typeName == "" {
+		typeName = "unknown type"
+	}
+	text := fmt.Sprintf("%s: type %02X %s, %s", name, partType, typeName, formatDiskSize(size))
+	if active {
+		text += ", active"
+	}
+	return diskEntry{offset: start, size: int(min(size, 1<<31)), depth: depth, text: text,
+		partition: partType != 0x05 && partType != 0x0F && partType != 0xEE}
+}
+
+// analyzeExtended follows the chain of extended boot records of the extended partition
+// starting at offset, listing the logical partitions
+func analyzeExtended(data []byte, extendedStart int) []diskEntry {
+	var entries []diskEntry
+	ebr := extendedStart
+	for number := 5; number < 5+maxLogicalPartitions; number++ {
+		if ebr+diskSectorSize > len(data) || data[ebr+510] != 0x55 || data[ebr+511] != 0xAA {
+			break
+		}
+		entry := data[ebr+446 : ebr+462]
+		if entry[4] != 0 {
+			start := ebr + int(binary.LittleEndian.Uint32(entry[8:]))*diskSectorSize
+			size := uint64(binary.LittleEndian.Uint32(entry[12:])) * diskSectorSize
+			entries = append(entries, mbrPartition(fmt.Sprintf("Logical partition %d", number), entry[4], false,
+				start, size, 2))
+			entries = append(entries, analyzeFilesystem(data, start, 3)...)
+		}
+
+		// The second entry links to the next record, relative to the extended partition
+		next := data[ebr+462 : ebr+478]
+		if next[4] != 0x05 && next[4] != 0x0F {
+			break
+		}
+		ebr = extendedStart + int(binary.LittleEndian.Uint32(next[8:]))*diskSectorSize
+	}
+	return entries
+}
+
+// analyzeGPT lists the partitions of the GUID partition table whose header follows the
+// protective MBR, trying 512- and 4096-byte sectors
+func analyzeGPT(data []byte) []diskEntry {
+	for _, sectorSize := range []int{512, 4096} {
+		header := sectorSize
+		if header+92 > len(data) || string(data[header:header+8]) != "EFI PART" {
+			continue
+		}
+		entries := []diskEntry{{offset: header, size: int(binary.LittleEndian.Uint32(data[header+12:])), depth: 2,
+			text: fmt.Sprintf("GPT header: disk %s, %d-byte sectors", formatGUID(data[header+56:]), sectorSize)}}
+
+		tableStart := int(binary.LittleEndian.Uint64(data[header+72:])) * sectorSize
+		count := int(binary.LittleEndian.Uint32(data[header+80:]))
+		entrySize := int(binary.LittleEndian.Uint32(data[header+84:]))
+		if entrySize < 128 {
+			return entries
+		}
+		for index := 0; index < min(count, 1024); index++ {
+			offset := tableStart + index*entrySize
+			if offset+128 > len(data) {
+				break
+			}
+			entry := data[offset : offset+128]
+			if bytes.Equal(entry[:16], make([]byte, 16)) {
+				continue // Unused entry
+			}
+			typeGUID := formatGUID(entry)
+			typeName := gptTypes[typeGUID]
+			if typeName == "" {
+				typeName = typeGUID
+			}
+			first, last := binary.LittleEndian.Uint64(entry[32:]), binary.LittleEndian.Uint64(entry[40:])
+			units := make([]uint16, 36)
+			for unit := range units {
+				units[unit] = binary.LittleEndian.Uint16(entry[56+2*unit:])
+			}
+			name := strings.TrimRight(string(utf16.Decode(units)), "\x00")
+			size := (last - first + 1) * uint64(sectorSize)
+			start := int(first) * sectorSize
+			entries = append(entries, diskEntry{offset: start, size: int(min(size, 1<<31)), depth: 2, partition: true,
+				text: fmt.Sprintf("GPT partition %d: %s %q, %s", index+1, typeName, name, formatDiskSize(size))})
+			entries = append(entries, analyzeFilesystem(data, start, 3)...)
+		}
+		return entries
+	}
+	return nil
+}
+
+// analyzeFilesystem recognizes the boot sector or superblock of a FAT, NTFS, or
+// ext2/3/4 filesystem starting at offset
+func analyzeFilesystem(data []byte, offset, depth int) []diskEntry {
+	if offset < 0 || offset+diskSectorSize > len(data) {
+		return nil
+	}
+	sector := data[offset : offset+diskSectorSize]
+
+	if string(sector[3:11]) == "NTFS    " {
+		bytesPerSector := uint64(binary.LittleEndian.Uint16(sector[11:]))
+		clusterSize := bytesPerSector * uint64(sector[13])
+		total := binary.LittleEndian.Uint64(sector[40:]) * bytesPerSector
+		mft := binary.LittleEndian.Uint64(sector[48:]) * clusterSize
+		return []diskEntry{
+			{offset: offset, size: diskSectorSize, depth: depth,
+				text: fmt.Sprintf("NTFS boot sector: %s, %d-byte clusters", formatDiskSize(total), clusterSize)},
+			{offset: offset + int(mft), size: 1024, depth: depth + 1, text: "NTFS $MFT"},
+		}
+	}
+
+	if entry, ok := analyzeFAT(sector, offset, depth); ok {
+		return []diskEntry{entry}
+	}
+
+	// ext2/3/4 keep their superblock 1024 bytes into the filesystem
+	if superblock := offset + 1024; superblock+1024 <= len(data) && binary.LittleEndian.Uint16(data[superblock+56:]) == 0xEF53 {
+		block := data[superblock : superblock+1024]
+		blockSize := uint64(1024) << binary.LittleEndian.Uint32(block[24:])
+		blocks := uint64(binary.LittleEndian.Uint32(block[4:]))
+		compat, incompat := binary.LittleEndian.Uint32(block[92:]), binary.LittleEndian.Uint32(block[96:])
+		version := "ext2"
+		switch {
+		case incompat&0x2C0 != 0: // Extents, 64-bit, or flexible block groups
+			version = "ext4"
+		case compat&0x4 != 0: // Journal
+			version = "ext3"
+		}
+		text := fmt.Sprintf("%s superblock: %s, %d-byte blocks", version, formatDiskSize(blocks*blockSize), blockSize)
+		if label := trimLabel(block[120:136]); label != "" {
+			text += fmt.Sprintf(", label %q", label)
+		}
+		return []diskEntry{{offset: superblock, size: 1024, depth: depth, text: text}}
+	}
+	return nil
+}
+
+// analyzeFAT recognizes the BIOS parameter block of a FAT12, FAT16, or FAT32 boot sector
+func analyzeFAT(sector []byte, offset, depth int) (diskEntry, bool) {
+	bytesPerSector := int(binary.LittleEndian.Uint16(sector[11:]))
+	sectorsPerCluster := int(sector[13])
+	reserved := int(binary.LittleEndian.Uint16(sector[14:]))
+	fats := int(sector[16])
+	if (sector[0] != 0xEB && sector[0] != 0xE9) || sector[510] != 0x55 || sector[511] != 0xAA ||
+		bytesPerSector < 512 || bytesPerSector > 4096 || bytesPerSector&(bytesPerSector-1) != 0 ||
+		sectorsPerCluster == 0 || sectorsPerCluster&(sectorsPerCluster-1) != 0 || reserved == 0 || fats == 0 {
+		return diskEntry{}, false
+	}
+
+	total := uint64(binary.LittleEndian.Uint16(sector[19:]))
+	if total == 0 {
+		total = uint64(binary.LittleEndian.Uint32(sector[32:]))
+	}
+	version, label := "FAT12/16", trimLabel(sector[43:54])
+	if binary.LittleEndian.Uint16(sector[22:]) == 0 {
+		version, label = "FAT32", trimLabel(sector[71:82])
+	} else if fsType := trimLabel(sector[54:62]); strings.HasPrefix(fsType, "FAT1") {
+		version = fsType
+	}
+	text := fmt.Sprintf("%s boot sector: %s, %d-byte clusters", version,
+		formatDiskSize(total*uint64(bytesPerSector)), bytesPerSector*sectorsPerCluster)
+	if label != "" && label != "NO NAME" {
+		text += fmt.Sprintf(", label %q", label)
+	}
+	return diskEntry{offset: offset, size: diskSectorSize, depth: depth, text: text}, true
+}
+
+// createDiskPanel creates the Disk Layout side panel, which lists the partitions and
+// filesystems found in a disk image. Clicking one selects its header and scrolls to it.
+// A partition can be opened in a window of its own, and a VHD or qcow2 image expanded
+// into the raw disk it stores.
+func (h *HexDumpApp) createDiskPanel() panelContent {
+	var entries []diskEntry
+	var format diskContainer
+	selected := -1
+	analyzed := false
+	summaryLabel := widget.NewLabel("")
+	summaryLabel.Wrapping = fyne.TextWrapWord
+	openBtn := widget.NewButton(lang.L("Open Partition"), func() {
+		if selected >= 0 && selected < len(entries) {
+			h.openPartition(entries[selected])
+		}
+	})
+	openBtn.Disable()
+	expandBtn := widget.NewButton(lang.L("Expand Image"), func() { h.expandDiskImage(format) })
+	expandBtn.Hide()
+
+	list := widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := entries[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %s%s", entry.offset, strings.Repeat("   ", entry.depth),
+				entry.text))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		entry := entries[id]
+		selected = id
+		if entry.partition && entry.offset < len(h.fileData) {
+			openBtn.Enable()
+		} else {
+			openBtn.Disable()
+		}
+		if entry.offset < len(h.fileData) {
+			h.setSelection(entry.offset, min(entry.offset+max(entry.size, 1), len(h.fileData)))
+			h.goToOffset(entry.offset)
+		}
+	}
+
+	analyze := func() {
+		entries = analyzeDisk(h.fileData)
+		analyzed = true
+		selected = -1
+		openBtn.Disable()
+		list.UnselectAll()
+		list.Refresh()
+		var isContainer bool
+		format, isContainer = detectDiskContainer(h.fileData)
+		if isContainer {
+			expandBtn.Show()
+		} else {
+			expandBtn.Hide()
+		}
+		switch {
+		case len(h.fileData) == 0:
+			summaryLabel.SetText(lang.L("No file loaded"))
+		case isContainer && len(entries) == 0:
+			summaryLabel.SetText(lang.L("{{.Format}} disk image; expand it to see its partitions",
+				map[string]any{"Format": format.name}))
+		case len(entries) == 0:
+			summaryLabel.SetText(lang.L("No partition table or filesystem header found"))
+		default:
+			summaryLabel.SetText(fmt.Sprintf("%d structures found; click one to go to it", len(entries)))
+		}
+	}
+	rescanBtn := widget.NewButton(lang.L("Rescan"), analyze)
+
+	return panelContent{
+		object: container.NewBorder(container.NewVBox(summaryLabel, container.NewHBox(rescanBtn, openBtn, expandBtn)),
+			nil, nil, nil, list),
+		refresh: func() {
+			if !analyzed {
+				analyze()
+			}
+		},
+		reset: func() { analyzed = false },
+	}
+}
+
+// openPartition opens a copy of a partition in a new window, with the address column
+// showing the offsets the bytes have in the disk image
+func (h *HexDumpApp) openPartition(entry diskEntry) {
+	end := min(entry.offset+entry.size, len(h.fileData))
+	data := bytes.Clone(h.fileData[entry.offset:end])
+	other := h.openDataInNewWindow(fmt.Sprintf("%s@%X", h.fileName, entry.offset), data)
+	other.setSegments([]addressSegment{{name: entry.text, virtualAddress: uint64(entry.offset), length: len(data)}})
+	other.showVirtual = true
+	other.addressMapChanged()
+}
+
+// expandDiskImage expands a VHD or qcow2 image into the raw disk it stores as a
+// background task, and opens the disk in a new window
+func (h *HexDumpApp) expandDiskImage(format diskContainer) {
+	data, name := h.fileData, h.fileName
+	task := h.startTask(lang.L("Expand Image"))
+	go func() {
+		defer h.recoverPanic()
+		disk, err := format.expand(data)
+		fyne.Do(func() {
+			result := formatDiskSize(uint64(len(disk)))
+			if err != nil {
+				result = err.Error()
+			}
+			if !h.finishTask(task, result, nil) {
+				return
+			}
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			h.openDataInNewWindow(strings.TrimSuffix(name, filepath.Ext(name))+".raw", disk)
+		})
+	}()
+}
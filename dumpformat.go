@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formats of the hexdump(1) display options, each a list of format strings in the
+// format language of hexdump(1)
+var (
+	dumpFormatDefault   = []string{`"%07.7_Ax\n"`, `"%07.7_ax " 8/2 "%04x " "\n"`}
+	dumpFormatCanonical = []string{`"%08.8_Ax\n"`, `"%08.8_ax  " 8/1 "%02x " "  " 8/1 "%02x "`, `"  |" 16/1 "%_p" "|\n"`}
+	dumpFormatOctal1    = []string{`"%07.7_Ax\n"`, `"%07.7_ax " 16/1 "%03o " "\n"`}
+	dumpFormatChar      = []string{`"%07.7_Ax\n"`, `"%07.7_ax " 16/1 "%3_c " "\n"`}
+	dumpFormatDecimal2  = []string{`"%07.7_Ax\n"`, `"%07.7_ax " 8/2 "  %05u " "\n"`}
+	dumpFormatOctal2    = []string{`"%07.7_Ax\n"`, `"%07.7_ax " 8/2 " %06o " "\n"`}
+	dumpFormatHex2      = []string{`"%07.7_Ax\n"`, `"%07.7_ax " 8/2 "   %04x " "\n"`}
+)
+
+// asciiNames are the names the _u conversion gives the control characters
+var asciiNames = []string{"nul", "soh", "stx", "etx", "eot", "enq", "ack", "bel", "bs", "ht", "lf", "vt", "ff",
+	"cr", "so", "si", "dle", "dc1", "dc2", "dc3", "dc4", "nak", "syn", "etb", "can", "em", "sub", "esc", "fs",
+	"gs", "rs", "us"}
+
+// dumpItem is a piece of a format unit: literal text, or a conversion of the bytes at the
+// current position (or of the current address)
+type dumpItem struct {
+	text  string // Literal text, if conv is empty
+	conv  string // Conversion: a C printf conversion letter, or "_a", "_A", "_c", "_p", "_u"
+	flags string // Flags, width and precision, such as "07.7"
+	base  byte   // Base of an address conversion: 'd', 'o', or 'x'
+	size  int    // Bytes the conversion consumes
+}
+
+// dumpUnit is a format unit: its items applied reps times, each time consuming the
+// bytes of its conversions
+type dumpUnit struct {
+	reps         int
+	explicitReps bool
+	byteCount    int
+	items        []dumpItem
+	endAddress   bool // Whether the unit has an _A conversion, so only runs at the end
+}
+
+// bytes returns the number of bytes one iteration of the unit consumes
+func (u *dumpUnit) bytes() int {
+	total := 0
+	for _, item := range u.items {
+		total += item.size
+	}
+	return total
+}
+
+// dumpFormat is a parsed format string: format units applied in turn to each block
+type dumpFormat []*dumpUnit
+
+// bytes returns the number of bytes the format consumes from each block, up to a unit
+// that only runs at the end
+func (f dumpFormat) bytes() int {
+	total := 0
+	for _, unit := range f {
+		if unit.endAddress {
+			break
+		}
+		total += unit.reps * unit.bytes()
+	}
+	return total
+}
+
+// parseDumpFormat parses a format string of hexdump(1): format units separated by
+// whitespace, each an optional iteration count, an optional byte count following a
+// slash, and a quoted printf-style format, such as `16/1 "%02x "`
+func parseDumpFormat(text string) (dumpFormat, error) {
+	var format dumpFormat
+	rest := strings.TrimSpace(text)
+	for rest != "" {
+		unit := &dumpUnit{reps: 1}
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits > 0 {
+			unit.reps, _ = strconv.Atoi(rest[:digits])
+			unit.explicitReps = true
+			rest = strings.TrimSpace(rest[digits:])
+		}
+		if strings.HasPrefix(rest, "/") {
+			rest = strings.TrimSpace(rest[1:])
+			digits = len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+			unit.byteCount, _ = strconv.Atoi(rest[:digits])
+			if unit.byteCount < 1 {
+				return nil, fmt.Errorf("byte count missing in %q", text)
+			}
+			rest = strings.TrimSpace(rest[digits:])
+		}
+		if unit.reps < 1 {
+			return nil, fmt.Errorf("iteration count must be positive in %q", text)
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, fmt.Errorf("expected a quoted format in %q", text)
+		}
+		quoted, remaining, err := cutQuoted(rest)
+		if err != nil {
+			return nil, err
+		}
+		if err := unit.parseItems(quoted); err != nil {
+			return nil, err
+		}
+		format = append(format, unit)
+		rest = strings.TrimSpace(remaining)
+	}
+	return format, nil
+}
+
+// cutQuoted splits a double-quoted string off the start of text, with its backslash
+// escapes resolved
+func cutQuoted(text string) (string, string, error) {
+	var unquoted strings.Builder
+	for index := 1; index < len(text); index++ {
+		switch c := text[index]; c {
+		case '"':
+			return unquoted.String(), text[index+1:], nil
+		case '\\':
+			index++
+			if index == len(text) {
+				break
+			}
+			switch e := text[index]; e {
+			case 'a':
+				unquoted.WriteByte('\a')
+			case 'b':
+				unquoted.WriteByte('\b')
+			case 'f':
+				unquoted.WriteByte('\f')
+			case 'n':
+				unquoted.WriteByte('\n')
+			case 'r':
+				unquoted.WriteByte('\r')
+			case 't':
+				unquoted.WriteByte('\t')
+			case 'v':
+				unquoted.WriteByte('\v')
+			default:
+				unquoted.WriteByte(e)
+			}
+		default:
+			unquoted.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated format %s", text)
+}
+
+// parseItems splits a format into literal text and conversions, and works out how many
+// bytes each conversion consumes
+func (u *dumpUnit) parseItems(format string) error {
+	var text strings.Builder
+	conversions := 0
+	for index := 0; index < len(format); index++ {
+		if format[index] != '%' {
+			text.WriteByte(format[index])
+			continue
+		}
+		if strings.HasPrefix(format[index:], "%%") {
+			text.WriteByte('%')
+			index++
+			continue
+		}
+		start := index + 1
+		end := start
+		for end < len(format) && strings.IndexByte("-+ #0123456789.", format[end]) >= 0 {
+			end++
+		}
+		if end == len(format) {
+			return fmt.Errorf("conversion missing in %q", format)
+		}
+		item := dumpItem{flags: format[start:end]}
+		switch c := format[end]; {
+		case c == '_' && end+1 < len(format):
+			end++
+			item.conv = "_" + string(format[end])
+			switch format[end] {
+			case 'a', 'A':
+				if end+1 == len(format) || strings.IndexByte("dox", format[end+1]) < 0 {
+					return fmt.Errorf("%%%s needs a base of d, o, or x in %q", item.conv, format)
+				}
+				end++
+				item.base = format[end]
+				u.endAddress = u.endAddress || item.conv == "_A"
+			case 'c', 'p', 'u':
+				item.size = 1
+			default:
+				return fmt.Errorf("unknown conversion %%%s in %q", item.conv, format)
+			}
+		case strings.IndexByte("diouxX", c) >= 0:
+			item.conv, item.size = string(c), 4
+		case c == 'c':
+			item.conv, item.size = "c", 1
+		case strings.IndexByte("eEfgG", c) >= 0:
+			item.conv, item.size = string(c), 8
+		case c == 's':
+			item.conv = "s"
+			if _, precision, found := strings.Cut(item.flags, "."); found {
+				item.size, _ = strconv.Atoi(precision)
+			}
+		default:
+			return fmt.Errorf("unknown conversion %%%c in %q", c, format)
+		}
+		index = end
+
+		if item.conv != "_a" && item.conv != "_A" {
+			conversions++
+			if u.byteCount > 0 {
+				item.size = u.byteCount
+			}
+		}
+		if err := item.checkSize(); err != nil {
+			return fmt.Errorf("%v in %q", err, format)
+		}
+		if text.Len() > 0 {
+			u.items = append(u.items, dumpItem{text: text.String()})
+			text.Reset()
+		}
+		u.items = append(u.items, item)
+	}
+	if text.Len() > 0 {
+		u.items = append(u.items, dumpItem{text: text.String()})
+	}
+	if u.byteCount > 0 && conversions > 1 {
+		return fmt.Errorf("a format with a byte count can have only one conversion: %q", format)
+	}
+	return nil
+}
+
+// checkSize reports an error if a conversion can't consume its number of bytes
+func (item dumpItem) checkSize() error {
+	switch item.conv {
+	case "d", "i", "o", "u", "x", "X":
+		if item.size != 1 && item.size != 2 && item.size != 4 && item.size != 8 {
+			return fmt.Errorf("%%%s takes 1, 2, 4, or 8 bytes", item.conv)
+		}
+	case "e", "E", "f", "g", "G":
+		if item.size != 4 && item.size != 8 {
+			return fmt.Errorf("%%%s takes 4 or 8 bytes", item.conv)
+		}
+	case "c", "_c", "_p", "_u":
+		if item.size != 1 {
+			return fmt.Errorf("%%%s takes 1 byte", item.conv)
+		}
+	case "s":
+		if item.size < 1 {
+			return fmt.Errorf("%%s needs a byte count or precision")
+		}
+	}
+	return nil
+}
+
+// width returns the field width of a conversion, for the blanks shown past the end of
+// the data
+func (item dumpItem) width() int {
+	width := strings.TrimLeft(item.flags, "-+ #0")
+	width, _, _ = strings.Cut(width, ".")
+	n, _ := strconv.Atoi(width)
+	return n
+}
+
+// format formats a conversion of data, or of address for _a and _A
+func (item dumpItem) format(data []byte, address int64) string {
+	verb := func(v byte) string { return "%" + item.flags + string(v) }
+	unsigned := func() uint64 {
+		var padded [8]byte
+		copy(padded[:], data)
+		return binary.LittleEndian.Uint64(padded[:])
+	}
+	switch item.conv {
+	case "_a", "_A":
+		return fmt.Sprintf(verb(item.base), address)
+	case "d", "i":
+		value := unsigned()
+		switch item.size {
+		case 1:
+			return fmt.Sprintf(verb('d'), int8(value))
+		case 2:
+			return fmt.Sprintf(verb('d'), int16(value))
+		case 4:
+			return fmt.Sprintf(verb('d'), int32(value))
+		}
+		return fmt.Sprintf(verb('d'), int64(value))
+	case "u":
+		return fmt.Sprintf(verb('d'), unsigned())
+	case "o", "x", "X":
+		return fmt.Sprintf(verb(item.conv[0]), unsigned())
+	case "e", "E", "f", "g", "G":
+		value := math.Float64frombits(unsigned())
+		if item.size == 4 {
+			value = float64(math.Float32frombits(uint32(unsigned())))
+		}
+		return fmt.Sprintf(verb(item.conv[0]), value)
+	case "c":
+		return fmt.Sprintf(verb('s'), data[:1])
+	case "s":
+		text, _, _ := bytes.Cut(data, []byte{0})
+		return fmt.Sprintf(verb('s'), text)
+	case "_p":
+		b := data[0]
+		if b < 0x20 || b > 0x7E {
+			b = '.'
+		}
+		return fmt.Sprintf(verb('s'), []byte{b})
+	case "_c":
+		return fmt.Sprintf(verb('s'), escapeDumpChar(data[0]))
+	case "_u":
+		b := data[0]
+		switch {
+		case int(b) < len(asciiNames):
+			return fmt.Sprintf(verb('s'), asciiNames[b])
+		case b == 0x7F:
+			return fmt.Sprintf(verb('s'), "del")
+		case b > 0x7F:
+			return fmt.Sprintf(verb('s'), fmt.Sprintf("%02x", b))
+		}
+		return fmt.Sprintf(verb('s'), []byte{b})
+	}
+	return ""
+}
+
+// escapeDumpChar shows a byte as the _c conversion does: printable characters as
+// themselves, common control characters as C escapes, and others in octal
+func escapeDumpChar(b byte) string {
+	switch b {
+	case 0:
+		return `\0`
+	case '\a':
+		return `\a`
+	case '\b':
+		return `\b`
+	case '\f':
+		return `\f`
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	case '\v':
+		return `\v`
+	}
+	if b >= 0x20 && b <= 0x7E {
+		return string(rune(b))
+	}
+	return fmt.Sprintf("%03o", b)
+}
+
+// dumpFormatter writes data in the layout given by hexdump(1) format strings
+type dumpFormatter struct {
+	formats   []dumpFormat
+	blockSize int
+	verbose   bool // Whether to show repeated blocks instead of a single "*"
+}
+
+// newDumpFormatter parses format strings into a formatter. As in hexdump(1), every
+// format is applied to the same block of input, as large as the most any format
+// consumes, and the last unit consuming bytes in a shorter format is repeated to fill
+// the block if no iteration count was given for it.
+func newDumpFormatter(formatStrings []string, verbose bool) (*dumpFormatter, error) {
+	f := &dumpFormatter{verbose: verbose}
+	for _, text := range formatStrings {
+		format, err := parseDumpFormat(text)
+		if err != nil {
+			return nil, err
+		}
+		f.formats = append(f.formats, format)
+		f.blockSize = max(f.blockSize, format.bytes())
+	}
+	if f.blockSize == 0 {
+		return nil, fmt.Errorf("the format doesn't display any data")
+	}
+	for _, format := range f.formats {
+		var last *dumpUnit
+		for _, unit := range format {
+			if unit.endAddress {
+				break
+			}
+			if unit.bytes() > 0 {
+				last = unit
+			}
+		}
+		if short := f.blockSize - format.bytes(); last != nil && !last.explicitReps && short > 0 {
+			last.reps += short / last.bytes()
+		}
+	}
+	return f, nil
+}
+
+// write writes data, whose first byte is at address, in the formatter's layout
+func (f *dumpFormatter) write(w io.Writer, data []byte, address int64) error {
+	out := bufio.NewWriter(w)
+	var previous []byte
+	starred := false
+	for start := 0; start < len(data); start += f.blockSize {
+		block := data[start:min(start+f.blockSize, len(data))]
+		if !f.verbose && previous != nil && len(block) == f.blockSize && bytes.Equal(block, previous) {
+			if !starred {
+				out.WriteString("*\n")
+				starred = true
+			}
+			continue
+		}
+		previous, starred = block, false
+		for _, format := range f.formats {
+			f.writeBlock(out, format, block, address+int64(start))
+		}
+	}
+
+	// Units with an _A conversion show the address past the end of the data, once
+	if len(data) > 0 {
+		end := address + int64(len(data))
+		for _, format := range f.formats {
+			for _, unit := range format {
+				if unit.endAddress {
+					for _, item := range unit.items {
+						out.WriteString(item.text + item.format(nil, end))
+					}
+					break
+				}
+			}
+		}
+	}
+	return out.Flush()
+}
+
+// writeBlock applies one format to a block of data. Conversions past the end of the data
+// show as blanks of their width, so that the columns after them still line up.
+func (f *dumpFormatter) writeBlock(out *bufio.Writer, format dumpFormat, block []byte, address int64) {
+	offset := 0
+	for _, unit := range format {
+		if unit.endAddress {
+			break
+		}
+		for rep := range unit.reps {
+			for index, item := range unit.items {
+				switch {
+				case item.conv == "":
+					text := item.text
+					// hexdump(1) drops trailing whitespace on the last of several iterations
+					if unit.reps > 1 && rep == unit.reps-1 && index == len(unit.items)-1 {
+						text = strings.TrimRight(text, " \t")
+					}
+					out.WriteString(text)
+				case item.conv == "_a":
+					out.WriteString(item.format(nil, address+int64(offset)))
+				case offset >= len(block):
+					out.WriteString(strings.Repeat(" ", item.width()))
+				default:
+					out.WriteString(item.format(block[offset:min(offset+item.size, len(block))], 0))
+				}
+				offset += item.size
+			}
+		}
+	}
+}
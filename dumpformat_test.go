@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDumpFormatter(t *testing.T) {
+	zeros := make([]byte, 48)
+	zeros[47] = 1
+	tests := []struct {
+		name    string
+		formats []string
+		verbose bool
+		data    []byte
+		address int64
+		want    string
+	}{
+		{
+			// Conversions past the end of the data are blanks, as in hexdump(1)
+			name:    "default",
+			formats: dumpFormatDefault,
+			data:    []byte("hello world\n"),
+			want:    "0000000 6568 6c6c 206f 6f77 6c72 0a64          \n000000c\n",
+		},
+		{
+			name:    "canonical",
+			formats: dumpFormatCanonical,
+			data:    []byte("hello world\n"),
+			want:    "00000000  68 65 6c 6c 6f 20 77 6f  72 6c 64 0a              |hello world.|\n0000000c\n",
+		},
+		{
+			name:    "canonical repeated lines",
+			formats: dumpFormatCanonical,
+			data:    zeros,
+			want: "00000000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00  |................|\n" +
+				"*\n" +
+				"00000020  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 01  |................|\n" +
+				"00000030\n",
+		},
+		{
+			name:    "verbose",
+			formats: []string{`"%04_ax " 16/1 "%02x" "\n"`},
+			verbose: true,
+			data:    zeros[:32],
+			want:    "0000 00000000000000000000000000000000\n0010 00000000000000000000000000000000\n",
+		},
+		{
+			name:    "start address",
+			formats: dumpFormatOctal1,
+			data:    []byte{0, 8, 255},
+			address: 16,
+			want:    "0000010 000 010 377" + strings.Repeat(" ", 52) + "\n0000013\n",
+		},
+		{
+			name:    "characters",
+			formats: dumpFormatChar,
+			data:    []byte("a\t\x00\x80"),
+			want:    "0000000   a  \\t  \\0 200" + strings.Repeat(" ", 48) + "\n0000004\n",
+		},
+		{
+			name:    "unsigned decimal",
+			formats: dumpFormatDecimal2,
+			data:    []byte{0xFF, 0xFF, 1, 0},
+			want:    "0000000   65535   00001" + strings.Repeat(" ", 48) + "\n0000004\n",
+		},
+		{
+			name:    "signed and names",
+			formats: []string{`1/1 "%d " 1/2 "%d " 2/1 "%_u "`},
+			data:    []byte{0xFF, 0xFE, 0xFF, 0x0A, 0x41},
+			want:    "-1 -2 lf A",
+		},
+	}
+	for _, test := range tests {
+		formatter, err := newDumpFormatter(test.formats, test.verbose)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		var out bytes.Buffer
+		if err := formatter.write(&out, test.data, test.address); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: got\n%q\nwant\n%q", test.name, out.String(), test.want)
+		}
+	}
+}
+
+func TestParseDumpFormatErrors(t *testing.T) {
+	tests := []string{
+		`16/1 %02x`,
+		`"%02x`,
+		`/ "%x"`,
+		`0 "%x"`,
+		`4/1 "%x %x"`,
+		`"%_a"`,
+		`"%k"`,
+		`1/3 "%x"`,
+		`"%s"`,
+	}
+	for _, text := range tests {
+		if _, err := parseDumpFormat(text); err == nil {
+			t.Errorf("%s: want an error", text)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Limits for the duplicate-region detector
+const (
+	minDuplicateLength = 4    // Smallest minimum length accepted from the user
+	maxDuplicateGroups = 1000 // Largest number of clusters reported
+	duplicateHashBase  = 257  // Base of the polynomial rolling hash
+)
+
+// duplicateRegion is one copy of a repeated byte sequence
+type duplicateRegion struct {
+	offset int
+	length int
+}
+
+// duplicateCluster is a byte sequence at source together with its later copies
+type duplicateCluster struct {
+	source int
+	copies []duplicateRegion
+}
+
+// duplicatedBytes returns the total number of bytes in the copies of the cluster
+func (c *duplicateCluster) duplicatedBytes() int {
+	total := 0
+	for _, region := range c.copies {
+		total += region.length
+	}
+	return total
+}
+
+// findDuplicates finds byte sequences of at least minLength bytes that occur more than
+// once in data. Candidate matches are located with a rolling hash over minLength-byte
+// windows, verified, and then extended as far as the copy continues to match without
+// overlapping its source. Clusters are returned largest first. progress, if not nil, is
+// told how far the scan has got, and stops it by returning false.
+func findDuplicates(data []byte, minLength int, progress func(done, total int) bool) []duplicateCluster {
+	if minLength < 1 || len(data) < 2*minLength {
+		return nil
+	}
+
+	// power is duplicateHashBase^(minLength-1), used to remove the outgoing byte
+	power := uint64(1)
+	for index := 1; index < minLength; index++ {
+		power *= duplicateHashBase
+	}
+	windowHash := func(start int) uint64 {
+		var hash uint64
+		for _, b := range data[start : start+minLength] {
+			hash = hash*duplicateHashBase + uint64(b)
+		}
+		return hash
+	}
+
+	firstSeen := make(map[uint64]int)
+	clusters := make(map[int]*duplicateCluster)
+
+	position := 0
+	hash := windowHash(0)
+	for {
+		source, seen := firstSeen[hash]
+		if seen && source+minLength <= position && bytes.Equal(data[source:source+minLength], data[position:position+minLength]) {
+			// Extend the match, without letting the copy run into its source
+			length := minLength
+			for position+length < len(data) && length < position-source && data[source+length] == data[position+length] {
+				length++
+			}
+
+			cluster := clusters[source]
+			if cluster == nil {
+				cluster = &duplicateCluster{source: source}
+				clusters[source] = cluster
+			}
+			cluster.copies = append(cluster.copies, duplicateRegion{offset: position, length: length})
+
+			// Skip past the copy and restart the rolling hash
+			position += length
+			if position+minLength > len(data) {
+				break
+			}
+			hash = windowHash(position)
+			continue
+		}
+
+		if !seen {
+			firstSeen[hash] = position
+		}
+		if position%progressInterval == 0 && progress != nil && !progress(position, len(data)) {
+			return nil
+		}
+		if position+minLength >= len(data) {
+			break
+		}
+		hash = (hash-uint64(data[position])*power)*duplicateHashBase + uint64(data[position+minLength])
+		position++
+	}
+
+	result := make([]duplicateCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		result = append(result, *cluster)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].duplicatedBytes() != result[j].duplicatedBytes() {
+			return result[i].duplicatedBytes() > result[j].duplicatedBytes()
+		}
+		return result[i].source < result[j].source
+	})
+	if len(result) > maxDuplicateGroups {
+		result = result[:maxDuplicateGroups]
+	}
+	return result
+}
+
+// showDuplicatesDialog asks for the minimum sequence length and runs the detector
+func (h *HexDumpApp) showDuplicatesDialog() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Find Duplicate Regions"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	lengthEntry := widget.NewEntry()
+	lengthEntry.SetText("32")
+
+	dialog.ShowForm(lang.L("Find Duplicate Regions"), lang.L("Find"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Minimum length (bytes)"), lengthEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		minLength, err := strconv.Atoi(strings.TrimSpace(lengthEntry.Text))
+		if err != nil || minLength < minDuplicateLength {
+			dialog.ShowError(fmt.Errorf("minimum length must be a number of at least %d", minDuplicateLength), h.window)
+			return
+		}
+
+		task := h.startTask(lang.L("Find Duplicate Regions"))
+		data := h.fileData
+		go func() {
+			defer h.recoverPanic()
+			clusters := findDuplicates(data, minLength, task.report)
+			fyne.Do(func() {
+				show := func() { h.showDuplicateResults(clusters, minLength) }
+				if h.finishTask(task, fmt.Sprintf("%d repeated sequence(s)", len(clusters)), show) {
+					show()
+				}
+			})
+		}()
+	}, h.window)
+}
+
+// showDuplicateResults lists duplicate clusters in a tool window. Tapping a region
+// selects it in the data view.
+func (h *HexDumpApp) showDuplicateResults(clusters []duplicateCluster, minLength int) {
+	if len(clusters) == 0 {
+		dialog.ShowInformation(lang.L("Find Duplicate Regions"),
+			fmt.Sprintf("No repeated sequences of %d bytes or more were found.", minLength), h.window)
+		return
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("Duplicate Regions - %s", h.fileName))
+	window.Resize(fyne.NewSize(450, 500))
+
+	// Node IDs are "c<cluster>" for clusters and "r<cluster>:<copy>" for copies,
+	// where copy -1 is the source sequence
+	tree := widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			if uid == "" {
+				ids := make([]widget.TreeNodeID, len(clusters))
+				for index := range clusters {
+					ids[index] = "c" + strconv.Itoa(index)
+				}
+				return ids
+			}
+			clusterIndex, err := strconv.Atoi(strings.TrimPrefix(uid, "c"))
+			if err != nil {
+				return nil
+			}
+			ids := []widget.TreeNodeID{fmt.Sprintf("r%d:-1", clusterIndex)}
+			for index := range clusters[clusterIndex].copies {
+				ids = append(ids, fmt.Sprintf("r%d:%d", clusterIndex, index))
+			}
+			return ids
+		},
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || strings.HasPrefix(uid, "c")
+		},
+		func(branch bool) fyne.CanvasObject {
+			return newTappableLabel("")
+		},
+		nil,
+	)
+
+	tree.UpdateNode = func(uid widget.TreeNodeID, branch bool, object fyne.CanvasObject) {
+		label := object.(*tappableLabel)
+		if branch {
+			clusterIndex, _ := strconv.Atoi(strings.TrimPrefix(uid, "c"))
+			cluster := clusters[clusterIndex]
+			label.SetText(fmt.Sprintf("%08X: %d copies, %d bytes duplicated",
+				cluster.source, len(cluster.copies)+1, cluster.duplicatedBytes()))
+			label.onTapped = func() { tree.ToggleBranch(uid) }
+			return
+		}
+
+		var clusterIndex, copyIndex int
+		fmt.Sscanf(uid, "r%d:%d", &clusterIndex, &copyIndex)
+		cluster := clusters[clusterIndex]
+		region := duplicateRegion{offset: cluster.source, length: minLength}
+		description := "source"
+		if copyIndex >= 0 {
+			region = cluster.copies[copyIndex]
+			description = "copy"
+		} else {
+			// The source extends as far as its longest copy
+			for _, other := range cluster.copies {
+				region.length = max(region.length, other.length)
+			}
+		}
+		label.SetText(fmt.Sprintf("%08X-%08X (%d bytes, %s)", region.offset, region.offset+region.length-1,
+			region.length, description))
+		label.onTapped = func() {
+			tree.Select(uid)
+			h.setSelection(region.offset, region.offset+region.length)
+			h.goToOffset(region.offset)
+		}
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("%d clusters of repeated sequences of %d bytes or more",
+		len(clusters), minLength))
+	window.SetContent(container.NewBorder(summary, nil, nil, nil, tree))
+	window.Show()
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// edit is one change to the file data, recorded in the edit journal so that it can be
+// undone. The new bytes replace the old bytes at offset.
+type edit struct {
+	offset   int
+	oldBytes []byte
+	newBytes []byte
+	label    string
+}
+
+// applyEdit overwrites the bytes at offset with data, recording the change under label
+func (h *HexDumpApp) applyEdit(label string, offset int, data []byte) {
+	if h.changeBytes(label, offset, data) {
+		h.editsChanged()
+	}
+}
+
+// changeBytes overwrites and records as applyEdit does, without updating the display,
+// and reports whether anything changed
+func (h *HexDumpApp) changeBytes(label string, offset int, data []byte) bool {
+	if offset < 0 || offset >= len(h.fileData) || len(data) == 0 {
+		return false
+	}
+	data = data[:min(len(data), len(h.fileData)-offset)]
+	h.stopScan()
+
+	change := edit{
+		offset:   offset,
+		oldBytes: append([]byte(nil), h.fileData[offset:offset+len(data)]...),
+		newBytes: append([]byte(nil), data...),
+		label:    label,
+	}
+	copy(h.fileData[offset:], change.newBytes)
+	h.recordEdit(change)
+	return true
+}
+
+// undo reverts the most recent step of the edit history
+func (h *HexDumpApp) undo() {
+	if h.historyAt == 0 {
+		return
+	}
+	change := h.history[h.historyAt].edits[0]
+	h.undoStep()
+	h.setSelection(change.offset, change.offset+len(change.oldBytes))
+	h.editsChanged()
+}
+
+// redo reapplies the most recently undone step of the edit history
+func (h *HexDumpApp) redo() {
+	if !h.redoStep() {
+		return
+	}
+	change := h.history[h.historyAt].edits[0]
+	h.setSelection(change.offset, change.offset+len(change.newBytes))
+	h.editsChanged()
+}
+
+// isModified reports whether the data differs from the file as last loaded or saved
+func (h *HexDumpApp) isModified() bool {
+	return h.historyAt != h.savedStep
+}
+
+// editsChanged redraws everything that depends on the file data after an edit
+func (h *HexDumpApp) editsChanged() {
+	h.hashLookup = "" // The data no longer has the hash that was looked up
+	h.rescanData(rescanDelay)
+	h.updateStructure()
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// modifiedSpans returns highlight spans for the edited bytes intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) modifiedSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	for _, change := range h.journal {
+		start := max(change.offset, lineStart)
+		end := min(change.offset+len(change.newBytes), lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(colorNameModified)})
+		}
+	}
+	return spans
+}
+
+// writeDataFile writes data to path byte for byte, as every save and export does. Files
+// are always written in binary: nothing translates line endings or encodings, unlike the
+// text mode of other tools. The size written is checked, so that a file cut short, as
+// on a full disk, is reported instead of silently corrupting it.
+func writeDataFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() != int64(len(data)) {
+		return fmt.Errorf("%s was written as %d bytes instead of %d", path, info.Size(), len(data))
+	}
+	return nil
+}
+
+// saveFile writes the data back to the file it was loaded from, after the user confirms
+// the changes it makes. A file downloaded from a server is saved with Save As instead.
+func (h *HexDumpApp) saveFile() {
+	if h.fileName == "" {
+		return
+	}
+	if isRemoteName(h.fileName) {
+		h.saveFileAs()
+		return
+	}
+	h.confirmSave(h.fileName, func() {
+		if err := h.writeFileData(h.fileName); err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		h.savedStep = h.historyAt
+		h.updateStatus()
+	})
+}
+
+// confirmDiscardEdits calls proceed at once if there are no unsaved edits, and
+// otherwise only if the user agrees to discard them
+func (h *HexDumpApp) confirmDiscardEdits(proceed func()) {
+	if !h.isModified() {
+		proceed()
+		return
+	}
+	dialog.ShowConfirm(lang.L("Unsaved Changes"), lang.L("The file has unsaved changes. Discard them?"),
+		func(ok bool) {
+			if ok {
+				proceed()
+			}
+		}, h.window)
+}
+
+// showFillSelection fills the selection with a repeating hex pattern, which continues
+// from one selected range to the next
+func (h *HexDumpApp) showFillSelection() {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("Fill"), lang.L("Select the bytes to fill first."), h.window)
+		return
+	}
+
+	patternEntry := widget.NewEntry()
+	patternEntry.SetText("00")
+	dialog.ShowForm(lang.L("Fill {{.Count}} bytes", map[string]any{"Count": h.selectionLength()}), lang.L("Fill"), lang.L("Cancel"),
+		[]*widget.FormItem{widget.NewFormItem(lang.L("Hex pattern"), patternEntry)},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			pattern, err := parseHexBytes(patternEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			data := make([]byte, h.selectionLength())
+			for index := range data {
+				data[index] = pattern[index%len(pattern)]
+			}
+			h.applyToSelection(lang.L("Fill"), data)
+		}, h.window)
+}
+
+// showXORSelection XORs the selection with a repeating hex key, which continues from
+// one selected range to the next
+func (h *HexDumpApp) showXORSelection() {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("XOR"), lang.L("Select the bytes to XOR first."), h.window)
+		return
+	}
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder(lang.L("e.g. 5A or DE AD BE EF"))
+	dialog.ShowForm(lang.L("XOR {{.Count}} bytes", map[string]any{"Count": h.selectionLength()}), lang.L("XOR"), lang.L("Cancel"),
+		[]*widget.FormItem{widget.NewFormItem(lang.L("Hex key"), keyEntry)},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			key, err := parseHexBytes(keyEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			h.applyToSelection(lang.L("XOR"), xorBytes(h.selectionData(), key))
+		}, h.window)
+}
+
+// saveSelection writes the selected bytes to a file chosen by the user, several selected
+// ranges or the rows of a rectangle one after another
+func (h *HexDumpApp) saveSelection() {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("Save Selection"), lang.L("Select the bytes to save first."), h.window)
+		return
+	}
+	filename, err := nativedialog.File().Filter("All Files", "*").Title("Save Selection").Save()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	h.exportInBackground(lang.L("Save Selection"), filename, writeBytesExport(h.selectionData()))
+}
+
+// exportSelectionCSV writes the selection to a CSV file chosen by the user, as numbers
+// decoded with the current grouping and byte order; several selected ranges or the rows
+// of a rectangle each start a new line
+func (h *HexDumpApp) exportSelectionCSV() {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("Export CSV"), lang.L("Select the bytes to export first."), h.window)
+		return
+	}
+	filename, err := nativedialog.File().Filter("CSV files", "csv").Title("Export Selection as CSV").Save()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	h.exportInBackground(lang.L("Export CSV"), filename, h.writeRangesCSVExport(h.selectedRanges()))
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+)
+
+// endiannessEvidence counts the words of data that look like values in each byte order
+type endiannessEvidence struct {
+	name        string
+	little, big int
+}
+
+// guessEndianness looks for signs of the byte order of the integers in data: small
+// 16- and 32-bit values, whose high bytes are zero, and 32-bit values that are
+// plausible offsets into a file of fileSize bytes. It returns the evidence and the
+// byte order it favors, or "" if neither order clearly wins.
+func guessEndianness(data []byte, fileSize int) ([]endiannessEvidence, string) {
+	small16 := endiannessEvidence{name: "Small 16-bit values"}
+	for offset := 0; offset+2 <= len(data); offset += 2 {
+		switch low, high := data[offset], data[offset+1]; {
+		case low != 0 && high == 0:
+			small16.little++
+		case low == 0 && high != 0:
+			small16.big++
+		}
+	}
+
+	small32 := endiannessEvidence{name: "Small 32-bit values"}
+	offsets := endiannessEvidence{name: "32-bit file offsets"}
+	for offset := 0; offset+4 <= len(data); offset += 4 {
+		word := data[offset : offset+4]
+		switch {
+		case word[0] != 0 && word[2] == 0 && word[3] == 0:
+			small32.little++
+		case word[0] == 0 && word[1] == 0 && word[3] != 0:
+			small32.big++
+		}
+		// Values below 256 are already counted as small
+		little, big := int(binary.LittleEndian.Uint32(word)), int(binary.BigEndian.Uint32(word))
+		littleOK, bigOK := little >= 256 && little < fileSize, big >= 256 && big < fileSize
+		if littleOK && !bigOK {
+			offsets.little++
+		} else if bigOK && !littleOK {
+			offsets.big++
+		}
+	}
+
+	evidence := []endiannessEvidence{small16, small32, offsets}
+	little, big := 0, 0
+	for _, item := range evidence {
+		little += item.little
+		big += item.big
+	}
+	// One order must have half as much evidence again as the other
+	switch {
+	case little > 0 && 2*little >= 3*big:
+		return evidence, "Little-endian"
+	case big > 0 && 2*big >= 3*little:
+		return evidence, "Big-endian"
+	}
+	return evidence, ""
+}
+
+// showEndiannessGuess guesses the byte order of the selection, or of the whole file
+// when nothing is selected, and offers to choose it in the byte order selector
+func (h *HexDumpApp) showEndiannessGuess() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Guess Endianness"), lang.L("No file is loaded."), h.window)
+		return
+	}
+	data, scope := h.fileData, "the whole file"
+	if h.hasSelection() {
+		data, scope = h.selectedBytes(), fmt.Sprintf("the %d selected bytes", h.selEnd-h.selStart)
+	}
+
+	evidence, guess := guessEndianness(data, len(h.fileData))
+	lines := []string{fmt.Sprintf("Words in %s that look like values in each byte order:", scope), ""}
+	for _, item := range evidence {
+		lines = append(lines, fmt.Sprintf("%-22s little-endian %6d   big-endian %6d", item.name+":", item.little, item.big))
+	}
+	lines = append(lines, "")
+
+	if guess == "" {
+		lines = append(lines, "Neither byte order is clearly more likely.")
+		dialog.ShowInformation(lang.L("Guess Endianness"), strings.Join(lines, "\n"), h.window)
+		return
+	}
+	if (guess == "Big-endian") == h.bigEndian {
+		lines = append(lines, fmt.Sprintf("The data is probably %s, the byte order already chosen.", strings.ToLower(guess)))
+		dialog.ShowInformation(lang.L("Guess Endianness"), strings.Join(lines, "\n"), h.window)
+		return
+	}
+	lines = append(lines, fmt.Sprintf("The data is probably %s. Choose %s?", strings.ToLower(guess), guess))
+	dialog.ShowConfirm(lang.L("Guess Endianness"), strings.Join(lines, "\n"), func(ok bool) {
+		if ok {
+			h.byteOrderSelect.SetSelected(guess)
+		}
+	}, h.window)
+}
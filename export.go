@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// Export format names, as shown in the GUI and accepted by the CLI
+const (
+	exportFormatText   = "Text"
+	exportFormatHTML   = "HTML"
+	exportFormatCArray = "C array"
+)
+
+// exportFormats lists the supported export formats in display order
+var exportFormats = []string{exportFormatText, exportFormatHTML, exportFormatCArray}
+
+// exportExtension returns the file name extension used for the given export format
+func exportExtension(format string) string {
+	switch format {
+	case exportFormatHTML:
+		return ".html"
+	case exportFormatCArray:
+		return ".c"
+	default:
+		return ".txt"
+	}
+}
+
+// writeExport writes the file data to w in the given export format. The name is used
+// as the title of HTML output and to derive the variable name of C array output.
+func (h *HexDumpApp) writeExport(w io.Writer, format string, name string) error {
+	switch format {
+	case exportFormatText:
+		return h.writeTextDump(w)
+	case exportFormatHTML:
+		return h.writeHTMLDump(w, name)
+	case exportFormatCArray:
+		return h.writeCArray(w, name)
+	default:
+		return fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// writeTextDump writes the file data to w as plain text, laid out like the display
+func (h *HexDumpApp) writeTextDump(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	for offset := 0; offset < len(h.fileData); offset = h.lineEnd(offset) {
+		fmt.Fprintf(writer, "%-*s  %s\n", h.hexColumns(), h.generateHexLine(offset), h.generateCharLine(offset))
+	}
+	return writer.Flush()
+}
+
+// writeHTMLDump writes the file data to w as a standalone HTML document
+func (h *HexDumpApp) writeHTMLDump(w io.Writer, name string) error {
+	writer := bufio.NewWriter(w)
+	title := html.EscapeString(filepath.Base(name))
+
+	fmt.Fprintf(writer, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+	writer.WriteString("<style>body { background: #202020; color: #ffffff; } pre { font-family: monospace; }</style>\n")
+	fmt.Fprintf(writer, "</head>\n<body>\n<h1>%s</h1>\n<pre>\n", title)
+	for offset := 0; offset < len(h.fileData); offset = h.lineEnd(offset) {
+		line := fmt.Sprintf("%-*s  %s", h.hexColumns(), h.generateHexLine(offset), h.generateCharLine(offset))
+		writer.WriteString(html.EscapeString(line))
+		writer.WriteString("\n")
+	}
+	writer.WriteString("</pre>\n</body>\n</html>\n")
+	return writer.Flush()
+}
+
+// writeCArray writes the file data to w as a C array definition, in the style of "xxd -i"
+func (h *HexDumpApp) writeCArray(w io.Writer, name string) error {
+	return writeCArrayBytes(w, h.fileData, cIdentifier(filepath.Base(name)))
+}
+
+// writeCArrayBytes writes data to w as a C array definition with the given identifier
+func writeCArrayBytes(w io.Writer, data []byte, identifier string) error {
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintf(writer, "unsigned char %s[] = {", identifier)
+	for index, b := range data {
+		if index%12 == 0 {
+			writer.WriteString("\n  ")
+		} else {
+			writer.WriteString(" ")
+		}
+		fmt.Fprintf(writer, "0x%02x", b)
+		if index < len(data)-1 {
+			writer.WriteString(",")
+		}
+	}
+	fmt.Fprintf(writer, "\n};\nunsigned int %s_len = %d;\n", identifier, len(data))
+	return writer.Flush()
+}
+
+// writeGroupsCSV writes the bytes [start, end) to w as CSV, with one cell per group of
+// bytes decoded in the chosen byte order and one row per line of the display, so rows
+// follow records in record mode. Values are unsigned, or signed when signed values are
+// shown; 16-byte groups and incomplete groups are written in hex.
+func (h *HexDumpApp) writeGroupsCSV(w io.Writer, start, end int) error {
+	writer := csv.NewWriter(w)
+	order := h.byteOrder()
+	for lineStart := h.lineStart(h.lineOf(start)); lineStart < end; lineStart = h.lineEnd(lineStart) {
+		var row []string
+		rowEnd := min(h.lineEnd(lineStart), end)
+		for offset := max(lineStart, start); offset < rowEnd; offset += h.bytesPerGroup {
+			group := h.fileData[offset:min(offset+h.bytesPerGroup, rowEnd)]
+			var value uint64
+			switch len(group) {
+			case 1:
+				value = uint64(group[0])
+			case 2:
+				value = uint64(order.Uint16(group))
+			case 4:
+				value = uint64(order.Uint32(group))
+			case 8:
+				value = order.Uint64(group)
+			}
+			switch {
+			case len(group) != h.bytesPerGroup || len(group) > 8:
+				row = append(row, strings.ToUpper(hex.EncodeToString(group)))
+			case h.signedValues:
+				shift := 64 - 8*len(group)
+				row = append(row, strconv.FormatInt(int64(value<<shift)>>shift, 10))
+			default:
+				row = append(row, strconv.FormatUint(value, 10))
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvChunkLines is how many lines of CSV exports are written between progress reports
+const csvChunkLines = 1 << 14
+
+// writeGroupsCSVExport returns an exportWriter writing the groups of [start, end) as
+// writeGroupsCSV does, a block of lines at a time to report its progress
+func (h *HexDumpApp) writeGroupsCSVExport(start, end int) exportWriter {
+	return func(w io.Writer, progress func(done, total int) bool) error {
+		for chunkStart := start; chunkStart < end; {
+			if !progress(chunkStart-start, end-start) {
+				return errExportCancelled
+			}
+			chunkEnd := min(h.lineStart(h.lineOf(chunkStart)+csvChunkLines), end)
+			if err := h.writeGroupsCSV(w, chunkStart, chunkEnd); err != nil {
+				return err
+			}
+			chunkStart = chunkEnd
+		}
+		return nil
+	}
+}
+
+// Ways Export Decoded Text writes characters that are not printable and invalid bytes
+const (
+	nonPrintableDots   = "Replace with fallback character"
+	nonPrintableEscape = "Escape as \\xNN"
+	nonPrintableDrop   = "Drop"
+)
+
+// writeDecodedText writes data to w decoded under encoding as UTF-8 text. Line breaks
+// and tabs are kept, with CRLF and CR line breaks converted to LF if toLF is set, and
+// other control characters and invalid bytes are replaced with the fallback character of
+// the encoding, escaped as the \xNN of their bytes, or dropped. Escaping also doubles backslashes.
+func writeDecodedText(w io.Writer, data []byte, encoding, nonPrintable string, toLF bool) error {
+	writer := bufio.NewWriter(w)
+	for index := 0; index < len(data); {
+		r, size := decodeRune(data[index:], encoding)
+		source := data[index : index+size]
+		index += size
+		switch {
+		case r == '\r' && toLF:
+			// A CR is written as LF unless the LF follows
+			if index < len(data) {
+				if next, _ := decodeRune(data[index:], encoding); next == '\n' {
+					continue
+				}
+			}
+			writer.WriteByte('\n')
+		case r == '\n' || r == '\r' || r == '\t':
+			writer.WriteRune(r)
+		case r == '\\' && nonPrintable == nonPrintableEscape:
+			writer.WriteString(`\\`)
+		case r == utf8.RuneError || !unicode.IsPrint(r):
+			switch nonPrintable {
+			case nonPrintableEscape:
+				for _, b := range source {
+					fmt.Fprintf(writer, "\\x%02X", b)
+				}
+			case nonPrintableDots:
+				writer.WriteString(fallbackText(encoding, r))
+			}
+		default:
+			writer.WriteRune(r)
+		}
+	}
+	return writer.Flush()
+}
+
+// exportDecodedText writes the whole file, decoded in an encoding chosen by the user, to
+// a text file for text-analysis tools
+func (h *HexDumpApp) exportDecodedText() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Export Decoded Text"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	encodingSelect := widget.NewSelect(encodingNames, nil)
+	encodingSelect.SetSelected(h.encoding)
+	nonPrintableSelect := widget.NewSelect([]string{nonPrintableDots, nonPrintableEscape, nonPrintableDrop}, nil)
+	nonPrintableSelect.SetSelected(nonPrintableDots)
+	lfCheck := widget.NewCheck(lang.L("Convert line endings to LF"), nil)
+
+	nonPrintableItem := widget.NewFormItem(lang.L("Non-printables"), nonPrintableSelect)
+	nonPrintableItem.HintText = "Control characters and invalid bytes; tabs and line breaks are kept"
+	lfItem := widget.NewFormItem("", lfCheck)
+	lfItem.HintText = "Otherwise line breaks are written exactly as in the file"
+	dialog.ShowForm(lang.L("Export Decoded Text"), lang.L("Export"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Encoding"), encodingSelect),
+		nonPrintableItem,
+		lfItem,
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		filename, err := nativedialog.File().Filter("Text files", "txt").Title("Export Decoded Text").Save()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, h.window)
+			}
+			return
+		}
+		data, encoding, nonPrintable, toLF := h.fileData, encodingSelect.Selected, nonPrintableSelect.Selected, lfCheck.Checked
+		h.exportInBackground(lang.L("Export Decoded Text"), filename,
+			func(w io.Writer, progress func(done, total int) bool) error {
+				// The text is about as long as the data, so its length shows the progress
+				counter := &outputProgressWriter{w: w, total: len(data), progress: progress}
+				return writeDecodedText(counter, data, encoding, nonPrintable, toLF)
+			})
+	}, h.window)
+}
+
+// hexColumns returns the width of the address and hex columns of one full line
+func (h *HexDumpApp) hexColumns() int {
+	return h.addressColumns() + h.bytesPerLine*h.cellsPerByte() + h.separatorsBefore(h.bytesPerLine) - 1
+}
+
+// cIdentifier converts a file name into a valid C identifier
+func cIdentifier(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteString("_")
+		}
+	}
+	identifier := builder.String()
+	if identifier == "" || unicode.IsDigit(rune(identifier[0])) {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+)
+
+// partialSuffix is added to the name of a file while an export writes it. If the export
+// is cancelled or fails, the partial file is kept, and exporting to the same file again
+// resumes from it.
+const partialSuffix = ".partial"
+
+// exportChunkSize is how many bytes of data exports write between progress reports
+const exportChunkSize = 1 << 20
+
+// errExportCancelled is returned by exports the user cancelled
+var errExportCancelled = errors.New("export cancelled")
+
+// exportWriter writes the output of an export to w, reporting its progress as a
+// background task does, and stops with errExportCancelled if progress returns false.
+// It must write the same output each time it is called with the same data, so that an
+// interrupted export can be resumed.
+type exportWriter func(w io.Writer, progress func(done, total int) bool) error
+
+// writeBytesExport returns an exportWriter writing data as it is
+func writeBytesExport(data []byte) exportWriter {
+	return func(w io.Writer, progress func(done, total int) bool) error {
+		for offset := 0; offset < len(data); offset += exportChunkSize {
+			if !progress(offset, len(data)) {
+				return errExportCancelled
+			}
+			if _, err := w.Write(data[offset:min(offset+exportChunkSize, len(data))]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// outputProgressWriter reports the bytes written through it as the progress of an
+// export whose output is expected to be about total bytes, for exports that can't tell
+// how far through their data they are
+type outputProgressWriter struct {
+	w        io.Writer
+	done     int
+	total    int
+	progress func(done, total int) bool
+}
+
+// Write writes p, failing with errExportCancelled once the export is cancelled
+func (w *outputProgressWriter) Write(p []byte) (int, error) {
+	if !w.progress(min(w.done, w.total), w.total) {
+		return 0, errExportCancelled
+	}
+	n, err := w.w.Write(p)
+	w.done += n
+	return n, err
+}
+
+// resumeWriter writes to a partial file that may already hold the start of the output,
+// left by an interrupted export. While the output matches what the file holds, it is
+// only compared; from the first difference on, the file is overwritten.
+type resumeWriter struct {
+	file     *os.File
+	existing int64 // Bytes at the start of the file still to be compared
+	offset   int64 // Bytes of output so far
+	matched  int64 // Bytes of output the file already held
+	buffer   []byte
+}
+
+// Write compares or writes p at the current offset
+func (w *resumeWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	if w.offset < w.existing {
+		n := int(min(int64(len(p)), w.existing-w.offset))
+		if cap(w.buffer) < n {
+			w.buffer = make([]byte, n)
+		}
+		held := w.buffer[:n]
+		if _, err := w.file.ReadAt(held, w.offset); err != nil {
+			return 0, err
+		}
+		same := 0
+		for same < n && held[same] == p[same] {
+			same++
+		}
+		w.offset += int64(same)
+		w.matched += int64(same)
+		p = p[same:]
+		if same < n {
+			w.existing = w.offset // Stop comparing at the first difference
+		}
+	}
+	if len(p) > 0 {
+		if _, err := w.file.WriteAt(p, w.offset); err != nil {
+			return 0, err
+		}
+		w.offset += int64(len(p))
+	}
+	return written, nil
+}
+
+// streamExport writes the output of write to path through a partial file, which is
+// renamed to path once it is complete. An existing partial file is resumed: the output
+// it already holds is checked rather than written again. It returns the number of
+// bytes written and how many of them were resumed.
+func streamExport(path string, write exportWriter, progress func(done, total int) bool) (int64, int64, error) {
+	partial := path + partialSuffix
+	file, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return 0, 0, err
+	}
+	resume := &resumeWriter{file: file, existing: info.Size()}
+	buffered := bufio.NewWriterSize(resume, exportChunkSize)
+	err = write(buffered, progress)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err == nil {
+		err = file.Truncate(resume.offset)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return resume.offset, resume.matched, err
+	}
+
+	// As writeDataFile does, check that the whole output reached the disk
+	if info, err = os.Stat(partial); err != nil {
+		return resume.offset, resume.matched, err
+	}
+	if info.Size() != resume.offset {
+		return resume.offset, resume.matched, fmt.Errorf("%s was written as %d bytes instead of %d", partial,
+			info.Size(), resume.offset)
+	}
+	return resume.offset, resume.matched, os.Rename(partial, path)
+}
+
+// exportInBackground writes an export to path as a background task with its progress,
+// which can be cancelled. A cancelled or failed export resumes when it is exported to
+// the same file again.
+func (h *HexDumpApp) exportInBackground(name, path string, write exportWriter) {
+	task := h.startTask(name)
+	go func() {
+		defer h.recoverPanic()
+		written, resumed, err := streamExport(path, write, task.report)
+		fyne.Do(func() {
+			result := fmt.Sprintf("%d bytes", written)
+			if resumed > 0 {
+				result = lang.L("{{.Written}} bytes, resumed after {{.Resumed}}",
+					map[string]any{"Written": written, "Resumed": resumed})
+			}
+			if err != nil {
+				result = err.Error()
+			}
+			if !h.finishTask(task, result, nil) || err == nil {
+				return
+			}
+			hint := lang.L("The part written is kept in {{.Path}}; export to the same file again to resume.",
+				map[string]any{"Path": path + partialSuffix})
+			dialog.ShowError(fmt.Errorf("%w\n\n%s", err, hint), h.window)
+		})
+	}()
+}
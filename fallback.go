@@ -0,0 +1,67 @@
+package main
+
+import (
+	"unicode/utf8"
+)
+
+// Fallback characters, shown in place of characters that aren't printable and of bytes
+// that don't decode. The setting is the character itself, except for the dot, which is
+// the default, and control pictures.
+const (
+	fallbackDot             = ""
+	fallbackMiddleDot       = "·"
+	fallbackControlPictures = "pictures"
+	fallbackSpace           = " "
+	fallbackReplacement     = "�"
+)
+
+// fallbackKinds lists the fallback characters in the order the Preferences dialog offers
+// them
+var fallbackKinds = []string{fallbackDot, fallbackMiddleDot, fallbackControlPictures, fallbackSpace,
+	fallbackReplacement}
+
+// fallbackNames maps the fallback characters to their names in the Preferences dialog
+var fallbackNames = map[string]string{
+	fallbackDot:             "Dot (.)",
+	fallbackMiddleDot:       "Middle dot (·)",
+	fallbackControlPictures: "Control pictures",
+	fallbackSpace:           "Space",
+	fallbackReplacement:     "Replacement character (�)",
+}
+
+// fallbackKind returns the fallback character used for the encoding: its own if one
+// is set, or else the one for all encodings
+func fallbackKind(encoding string) string {
+	if kind, ok := appSettings.EncodingFallbacks[encoding]; ok {
+		return kind
+	}
+	return appSettings.Fallback
+}
+
+// fallbackText returns what the character pane and text exports show in place of r, a
+// character of the encoding that isn't printable, or utf8.RuneError for bytes that
+// don't decode. Control pictures stand for the C0 controls and DEL, and a dot for
+// anything else.
+func fallbackText(encoding string, r rune) string {
+	switch kind := fallbackKind(encoding); kind {
+	case fallbackDot:
+		return "."
+	case fallbackControlPictures:
+		switch {
+		case r >= 0 && r < 0x20:
+			return string(0x2400 + r)
+		case r == 0x7F:
+			return "␡"
+		}
+		return "."
+	default:
+		return kind
+	}
+}
+
+// firstRune returns the first character of data, or utf8.RuneError if it doesn't start
+// with valid UTF-8
+func firstRune(data []byte) rune {
+	r, _ := utf8.DecodeRune(data)
+	return r
+}
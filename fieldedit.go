@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// isIntegerType reports whether a template field type is an integer type
+func isIntegerType(typeName string) bool {
+	return fieldSizes[typeName] > 0 && (strings.HasPrefix(typeName, "u") || strings.HasPrefix(typeName, "i"))
+}
+
+// encodeFieldValue converts a value typed for a field of a non-struct type to its bytes:
+// a number in decimal or with a 0x prefix, text for char[N], which is padded with NULs,
+// or hex for bytes[N]
+func encodeFieldValue(typeName string, text string, order binary.ByteOrder) ([]byte, error) {
+	size, err := fieldSize(typeName)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	put := func(value uint64) {
+		switch size {
+		case 1:
+			data[0] = byte(value)
+		case 2:
+			order.PutUint16(data, uint16(value))
+		case 4:
+			order.PutUint32(data, uint32(value))
+		case 8:
+			order.PutUint64(data, value)
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(typeName, "u"):
+		value, err := strconv.ParseUint(text, 0, 8*size)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a %s, from 0 to %d", strconv.Quote(text), typeName,
+				uint64(math.MaxUint64)>>(64-8*size))
+		}
+		put(value)
+	case strings.HasPrefix(typeName, "i"):
+		value, err := strconv.ParseInt(text, 0, 8*size)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an %s, from %d to %d", strconv.Quote(text), typeName,
+				-1<<(8*size-1), 1<<(8*size-1)-1)
+		}
+		put(uint64(value))
+	case typeName == "f32":
+		value, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", strconv.Quote(text))
+		}
+		put(uint64(math.Float32bits(float32(value))))
+	case typeName == "f64":
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", strconv.Quote(text))
+		}
+		put(math.Float64bits(value))
+	case strings.HasPrefix(typeName, "char["):
+		if len(text) > size {
+			return nil, fmt.Errorf("the text is %d characters long, but the field holds %d", len(text), size)
+		}
+		for index := range len(text) {
+			if !isPrintableASCII(text[index]) {
+				return nil, fmt.Errorf("the text can only have printable ASCII characters")
+			}
+		}
+		copy(data, text)
+	default:
+		value, err := parseHexBytes(text)
+		if err != nil {
+			return nil, err
+		}
+		if len(value) != size {
+			return nil, fmt.Errorf("%d bytes are needed, not %d", size, len(value))
+		}
+		copy(data, value)
+	}
+	return data, nil
+}
+
+// editableFieldValue returns the value of a field as it is typed to edit it: text
+// without quotes for char[N] fields, and the shown value for others
+func editableFieldValue(field *parsedField) string {
+	if strings.HasPrefix(field.typeName, "char[") {
+		if text, err := strconv.Unquote(field.value); err == nil {
+			return text
+		}
+	}
+	return field.value
+}
+
+// fieldDataLength returns the length a length field holds for data, the bytes of a
+// field: the text up to the first NUL for char[N] fields, and the whole size for others
+func fieldDataLength(typeName string, data []byte) int {
+	if strings.HasPrefix(typeName, "char[") {
+		if end := strings.IndexByte(string(data), 0); end >= 0 {
+			return end
+		}
+	}
+	return len(data)
+}
+
+// lengthFields returns the fields holding the length of the field with the given tree
+// node ID, which are among its siblings
+func (h *HexDumpApp) lengthFields(uid widget.TreeNodeID) []*parsedField {
+	parentID := ""
+	if dot := strings.LastIndexByte(uid, '.'); dot >= 0 {
+		parentID = uid[:dot]
+	}
+	field, parent := h.templateFields.fieldAt(uid), h.templateFields.fieldAt(parentID)
+	if field == nil || parent == nil {
+		return nil
+	}
+	var fields []*parsedField
+	for _, sibling := range parent.children {
+		if sibling.lengthOf == field.name && sibling.size > 0 {
+			fields = append(fields, sibling)
+		}
+	}
+	return fields
+}
+
+// showEditField asks for a new value of the field with the given tree node ID and writes
+// its bytes in the template's byte order, as one step of the edit history. The fields
+// holding its length can be updated with it.
+func (h *HexDumpApp) showEditField(uid widget.TreeNodeID) {
+	field := h.templateFields.fieldAt(uid)
+	if field == nil || field.typeName == "struct" || field.size == 0 {
+		dialog.ShowInformation(lang.L("Edit Value"), lang.L("Select a field in the Structure panel first."), h.window)
+		return
+	}
+	order := h.template.byteOrder()
+	lengthFields := h.lengthFields(uid)
+
+	valueEntry := widget.NewEntry()
+	valueEntry.SetText(editableFieldValue(field))
+	valueEntry.Validator = func(text string) error {
+		_, err := encodeFieldValue(field.typeName, text, order)
+		return err
+	}
+	valueItem := widget.NewFormItem(field.typeName, valueEntry)
+	if isIntegerType(field.typeName) {
+		valueItem.HintText = "Decimal, or hex with 0x"
+	} else if strings.HasPrefix(field.typeName, "bytes[") {
+		valueItem.HintText = "Hex bytes"
+	}
+	items := []*widget.FormItem{valueItem}
+
+	var names []string
+	for _, lengthField := range lengthFields {
+		names = append(names, lengthField.name)
+	}
+	updateCheck := widget.NewCheck(fmt.Sprintf("Update %s", strings.Join(names, ", ")), nil)
+	updateCheck.SetChecked(true)
+	if len(lengthFields) > 0 {
+		updateItem := widget.NewFormItem("", updateCheck)
+		updateItem.HintText = "Set to the new length of the value"
+		items = append(items, updateItem)
+	}
+
+	form := dialog.NewForm(fmt.Sprintf("Edit %s at %08X", field.name, field.offset), lang.L("Set"), lang.L("Cancel"),
+		items, func(ok bool) {
+			if !ok {
+				return
+			}
+			data, err := encodeFieldValue(field.typeName, valueEntry.Text, order)
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			var lengths [][]byte
+			if updateCheck.Checked {
+				length := strconv.Itoa(fieldDataLength(field.typeName, data))
+				for _, lengthField := range lengthFields {
+					value, err := encodeFieldValue(lengthField.typeName, length, order)
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("%s can't hold the length %s", lengthField.name, length), h.window)
+						return
+					}
+					lengths = append(lengths, value)
+				}
+			}
+			label := "Edit " + field.name
+			h.groupEdits(label, func() {
+				h.changeBytes(label, field.offset, data)
+				for index, value := range lengths {
+					h.changeBytes(label, lengthFields[index].offset, value)
+				}
+			})
+			h.editsChanged()
+		}, h.window)
+	form.Resize(fyne.NewSize(400, 220))
+	form.Show()
+	h.window.Canvas().Focus(valueEntry)
+}
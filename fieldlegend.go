@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fieldHighlight is a decoded template field highlighted in the data view
+type fieldHighlight struct {
+	name   string // Path of the field, such as "header.width"
+	offset int
+	size   int
+	color  fyne.ThemeColorName
+}
+
+// updateFieldHighlights assigns colors from the bookmark palette to the fields decoded
+// by the structure template, in order, for the data view and the Fields legend
+func (h *HexDumpApp) updateFieldHighlights() {
+	h.fieldHighlights = nil
+	var collect func(field *parsedField, prefix string)
+	collect = func(field *parsedField, prefix string) {
+		for _, child := range field.children {
+			name := prefix + child.name
+			if child.typeName == "struct" {
+				collect(child, name+".")
+			} else if child.size > 0 {
+				h.fieldHighlights = append(h.fieldHighlights, fieldHighlight{name: name, offset: child.offset,
+					size: child.size, color: bookmarkColors[len(h.fieldHighlights)%len(bookmarkColors)]})
+			}
+		}
+	}
+	if h.templateFields != nil {
+		collect(h.templateFields, "")
+	}
+	sort.SliceStable(h.fieldHighlights, func(i, j int) bool {
+		return h.fieldHighlights[i].offset < h.fieldHighlights[j].offset
+	})
+	if h.fieldList != nil {
+		h.fieldList.Refresh()
+	}
+}
+
+// fieldSpans returns highlight spans for the template fields intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) fieldSpans(lineStart, lineEnd int) []highlightSpan {
+	if !h.showFieldColors {
+		return nil
+	}
+	var spans []highlightSpan
+	for _, field := range h.fieldHighlights {
+		if field.offset >= lineEnd {
+			break
+		}
+		start, end := max(field.offset, lineStart), min(field.offset+field.size, lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(field.color)})
+		}
+	}
+	return spans
+}
+
+// createFieldsPanel creates the Fields side panel, a legend of the colors of the fields
+// decoded by the structure template. Clicking a field selects its bytes.
+func (h *HexDumpApp) createFieldsPanel() panelContent {
+	h.fieldList = widget.NewList(
+		func() int { return len(h.fieldHighlights) },
+		func() fyne.CanvasObject {
+			swatch := canvas.NewRectangle(color.Transparent)
+			swatch.SetMinSize(fyne.NewSize(16, 16))
+			return container.NewBorder(nil, nil, container.NewCenter(swatch), nil, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			field := h.fieldHighlights[id]
+			row := item.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%08X  %s (%d bytes)", field.offset, field.name, field.size))
+			swatch := row.Objects[1].(*fyne.Container).Objects[0].(*canvas.Rectangle)
+			swatch.FillColor = theme.Color(field.color)
+			swatch.Refresh()
+		},
+	)
+	h.fieldList.OnSelected = func(id widget.ListItemID) {
+		field := h.fieldHighlights[id]
+		h.setSelection(field.offset, field.offset+field.size)
+		h.goToOffset(field.offset)
+	}
+
+	colorsCheck := widget.NewCheck(lang.L("Color fields in the data view"), func(checked bool) {
+		h.showFieldColors = checked
+		if h.dataList != nil {
+			h.dataList.Refresh()
+		}
+	})
+	colorsCheck.SetChecked(h.showFieldColors)
+	bookmarkBtn := widget.NewButton(lang.L("Add as Bookmarks"), func() {
+		var bookmarks []bookmark
+		for _, field := range h.fieldHighlights {
+			bookmarks = append(bookmarks, bookmark{offset: field.offset, length: field.size, label: field.name,
+				color: field.color})
+		}
+		h.addBookmarks(bookmarks...)
+	})
+
+	return panelContent{
+		object: container.NewBorder(
+			container.NewVBox(colorsCheck, widget.NewLabel(lang.L("Fields of the applied template"))),
+			bookmarkBtn,
+			nil, nil,
+			h.fieldList,
+		),
+		reset: func() { h.fieldList.UnselectAll() },
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// cutOptions selects the range of a file that "hexdump cut" extracts
+type cutOptions struct {
+	offset    int
+	length    int            // Bytes to extract, or -1 for the rest of the file
+	until     *searchPattern // The pattern ending the range, if any
+	inclusive bool           // Whether the range includes the pattern it ends at
+}
+
+// cutRange returns the range of data that options select. A length past the end of the
+// data is cut short, as dd(1) does, but an offset past it is an error, and so is a
+// pattern that isn't found.
+func cutRange(data []byte, options cutOptions) (byteRange, error) {
+	if options.offset > len(data) {
+		return byteRange{}, fmt.Errorf("offset 0x%x is past the end of the file (0x%x bytes)", options.offset, len(data))
+	}
+	end := len(data)
+	if options.length >= 0 && options.length < len(data)-options.offset {
+		end = options.offset + options.length // Compared first, as the sum may overflow
+	}
+	if options.until != nil {
+		found := options.until.findNext(data[:end], options.offset)
+		if found < 0 {
+			return byteRange{}, fmt.Errorf("pattern not found after offset 0x%x", options.offset)
+		}
+		end = found
+		if options.inclusive {
+			end += len(options.until.data)
+		}
+	}
+	return byteRange{options.offset, end}, nil
+}
+
+// runCutCommand implements "hexdump cut", which copies a range of a file to another
+// file or to stdout, so that carving found in the GUI can be scripted
+func runCutCommand(args []string) int {
+	flags := newFlagSet("cut", "[options] IN [OUT]")
+	offsetText := flags.String("offset", "0", "`offset` of the first byte: decimal, hex with 0x, or octal with 0")
+	lengthText := flags.String("length", "", "number of `bytes` to copy; the rest of the file if not given")
+	untilText := flags.String("until-pattern", "", "end the range before the first match of `PATTERN`, hex bytes such as \"FF D9\"")
+	inclusive := flags.Bool("inclusive", false, "include the match of -until-pattern in the range")
+	text := flags.Bool("text", false, "-until-pattern is text instead of hex bytes")
+	encoding := flags.String("encoding", "ISO Latin-1", "character `encoding` of a text pattern")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return 2
+	}
+
+	options := cutOptions{length: -1, inclusive: *inclusive}
+	offset, err := parseDumpOffset(*offsetText)
+	if err != nil {
+		return cliError(os.Stderr, "cut", err)
+	}
+	options.offset = int(offset)
+	if *lengthText != "" {
+		length, err := parseDumpOffset(*lengthText)
+		if err != nil {
+			return cliError(os.Stderr, "cut", fmt.Errorf("invalid length %q", *lengthText))
+		}
+		options.length = int(length)
+	}
+	if *untilText != "" {
+		kind := searchKindHex
+		if *text {
+			kind = searchKindText
+			if !slices.Contains(encodingNames, *encoding) {
+				return cliError(os.Stderr, "cut", fmt.Errorf("unknown encoding: %s (use %s)", *encoding,
+					strings.Join(encodingNames, ", ")))
+			}
+		}
+		pattern, err := parseSearchPattern(kind, *untilText, *encoding)
+		if err != nil {
+			return cliError(os.Stderr, "cut", err)
+		}
+		options.until = &pattern
+	}
+
+	var data []byte
+	if name := flags.Arg(0); name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return cliError(os.Stderr, "cut", err)
+	}
+	r, err := cutRange(data, options)
+	if err != nil {
+		return cliError(os.Stderr, "cut", err)
+	}
+
+	if out := flags.Arg(1); out != "" && out != "-" {
+		err = os.WriteFile(out, data[r.start:r.end], 0644)
+	} else {
+		_, err = os.Stdout.Write(data[r.start:r.end])
+	}
+	if err != nil {
+		return cliError(os.Stderr, "cut", err)
+	}
+	return 0
+}
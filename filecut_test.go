@@ -0,0 +1,36 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCutRange(t *testing.T) {
+	data := []byte("\xFF\xD8header\xFF\xD9trailer")
+	marker, err := parseHexPattern("FF D9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		options cutOptions
+		want    byteRange
+		wantErr bool
+	}{
+		{"rest of file", cutOptions{offset: 2, length: -1}, byteRange{2, len(data)}, false},
+		{"length", cutOptions{offset: 2, length: 6}, byteRange{2, 8}, false},
+		{"length past end", cutOptions{offset: 2, length: 100}, byteRange{2, len(data)}, false},
+		{"huge length", cutOptions{offset: 1, length: math.MaxInt}, byteRange{1, len(data)}, false},
+		{"offset at end", cutOptions{offset: len(data), length: -1}, byteRange{len(data), len(data)}, false},
+		{"offset past end", cutOptions{offset: len(data) + 1, length: -1}, byteRange{}, true},
+		{"until", cutOptions{offset: 0, length: -1, until: &marker}, byteRange{0, 8}, false},
+		{"until inclusive", cutOptions{offset: 0, length: -1, until: &marker, inclusive: true}, byteRange{0, 10}, false},
+		{"until past length", cutOptions{offset: 0, length: 9, until: &marker}, byteRange{}, true},
+	}
+	for _, test := range tests {
+		got, err := cutRange(data, test.options)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("%s: got %v, %v; want %v, error %v", test.name, got, err, test.want, test.wantErr)
+		}
+	}
+}
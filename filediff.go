@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// fileDiffRanges returns the ranges where two files differ, using the same comparison
+// as snapshots. Bytes only one file has count as differing.
+func fileDiffRanges(a, b []byte) []byteRange {
+	ranges := diffRanges(a, b)
+	if len(a) > len(b) {
+		if last := len(ranges) - 1; last >= 0 && ranges[last].end == len(b) {
+			ranges[last].end = len(a)
+		} else {
+			ranges = append(ranges, byteRange{len(b), len(a)})
+		}
+	}
+	return ranges
+}
+
+// diffHunk is a run of rows of a file comparison: the rows that differ, and up to
+// context rows around them
+type diffHunk struct {
+	rows   []compareRow
+	ranges []byteRange // The differing ranges within the rows
+}
+
+// diffHunks groups the rows of a comparison, width bytes each, into hunks of differing
+// rows with context rows before and after them. Hunks whose context would overlap are
+// joined. Rows differ when they have bytes of ranges, so masked bytes don't count.
+func diffHunks(a, b []byte, ranges []byteRange, width, context int) []diffHunk {
+	rows := compareRows(a, b, width)
+	for index := range rows {
+		start, end := rows[index].offset, rows[index].offset+width
+		rows[index].differ = slices.ContainsFunc(ranges, func(r byteRange) bool { return r.start < end && r.end > start })
+	}
+	var hunks []diffHunk
+	for index := 0; index < len(rows); index++ {
+		if !rows[index].differ {
+			continue
+		}
+		first := max(0, index-context)
+		last := index
+		for next := index + 1; next < len(rows) && next <= last+2*context+1; next++ {
+			if rows[next].differ {
+				last = next
+			}
+		}
+		last = min(len(rows)-1, last+context)
+
+		hunk := diffHunk{rows: rows[first : last+1]}
+		start, end := rows[first].offset, rows[last].offset+width
+		for _, r := range ranges {
+			if r.start < end && r.end > start {
+				hunk.ranges = append(hunk.ranges, r)
+			}
+		}
+		hunks = append(hunks, hunk)
+		index = last
+	}
+	return hunks
+}
+
+// formatDiffRow formats the bytes of one file in a row of a comparison as hex and
+// printable ASCII, padded to width bytes
+func formatDiffRow(offset int, data []byte, width int) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%08x ", offset)
+	for index := range width {
+		if index < len(data) {
+			fmt.Fprintf(&builder, " %02x", data[index])
+		} else {
+			builder.WriteString("   ")
+		}
+	}
+	builder.WriteString("  |")
+	for _, b := range data {
+		if b < 0x20 || b > 0x7E {
+			b = '.'
+		}
+		builder.WriteByte(b)
+	}
+	builder.WriteString("|")
+	return builder.String()
+}
+
+// formatByteRange formats a range of offsets in hex, such as "00000010-00000013"
+func formatByteRange(r byteRange) string {
+	if r.end-r.start == 1 {
+		return fmt.Sprintf("%08x", r.start)
+	}
+	return fmt.Sprintf("%08x-%08x", r.start, r.end-1)
+}
+
+// writeTextDiff writes a comparison of files a and b in the style of a unified diff:
+// each hunk starts with its differing ranges, followed by its rows, where "-" rows show
+// the bytes of a, "+" rows those of b, and " " rows bytes both have. Differences in the
+// mask regions are left out.
+func writeTextDiff(w io.Writer, nameA, nameB string, a, b []byte, width, context int, masks []maskRegion) error {
+	out := bufio.NewWriter(w)
+	allRanges := fileDiffRanges(a, b)
+	ranges := maskDiffRanges(allRanges, masks)
+	fmt.Fprintf(out, "--- %s\t%d bytes\n+++ %s\t%d bytes\n", nameA, len(a), nameB, len(b))
+	for _, hunk := range diffHunks(a, b, ranges, width, context) {
+		var changed []string
+		for _, r := range hunk.ranges {
+			changed = append(changed, formatByteRange(r))
+		}
+		fmt.Fprintf(out, "@@ %s @@\n", strings.Join(changed, ", "))
+		for _, row := range hunk.rows {
+			switch {
+			case !row.differ:
+				fmt.Fprintf(out, " %s\n", formatDiffRow(row.offset, row.a, width))
+			default:
+				if len(row.a) > 0 {
+					fmt.Fprintf(out, "-%s\n", formatDiffRow(row.offset, row.a, width))
+				}
+				if len(row.b) > 0 {
+					fmt.Fprintf(out, "+%s\n", formatDiffRow(row.offset, row.b, width))
+				}
+			}
+		}
+	}
+	differing := 0
+	for _, r := range ranges {
+		differing += r.end - r.start
+	}
+	fmt.Fprintf(out, "%d range(s), %d byte(s) differ", len(ranges), differing)
+	if len(masks) > 0 {
+		fmt.Fprintf(out, ", %d byte(s) in masked regions ignored", maskedBytes(allRanges, masks))
+	}
+	fmt.Fprintln(out)
+	return out.Flush()
+}
+
+// diffRecord is one line of the NDJSON output of "hexdump diff -json": a "range" that
+// differs, with the bytes of each file in hex, or the closing "summary"
+type diffRecord struct {
+	Type   string `json:"type"`
+	Offset *int   `json:"offset,omitempty"`
+	Length int    `json:"length,omitempty"`
+	A      string `json:"a"`
+	B      string `json:"b"`
+	SizeA  *int   `json:"sizeA,omitempty"`
+	SizeB  *int   `json:"sizeB,omitempty"`
+	Ranges *int   `json:"ranges,omitempty"`
+	Bytes  *int   `json:"bytes,omitempty"`
+	Masked *int   `json:"masked,omitempty"`
+}
+
+// writeJSONDiff writes the ranges where files a and b differ outside the mask regions as
+// NDJSON, followed by a summary naming the files
+func writeJSONDiff(w io.Writer, nameA, nameB string, a, b []byte, masks []maskRegion) error {
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	allRanges := fileDiffRanges(a, b)
+	ranges := maskDiffRanges(allRanges, masks)
+	differing := 0
+	for _, r := range ranges {
+		offset := r.start
+		record := diffRecord{Type: "range", Offset: &offset, Length: r.end - r.start,
+			A: hex.EncodeToString(a[min(r.start, len(a)):min(r.end, len(a))]),
+			B: hex.EncodeToString(b[min(r.start, len(b)):min(r.end, len(b))])}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		differing += r.end - r.start
+	}
+	sizeA, sizeB, count := len(a), len(b), len(ranges)
+	summary := diffRecord{Type: "summary", A: nameA, B: nameB, SizeA: &sizeA, SizeB: &sizeB, Ranges: &count,
+		Bytes: &differing}
+	if len(masks) > 0 {
+		masked := maskedBytes(allRanges, masks)
+		summary.Masked = &masked
+	}
+	if err := encoder.Encode(summary); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// runDiffCommand implements "hexdump diff", which lists the ranges where two files
+// differ. As with diff(1), the exit code is 0 if the files are the same, 1 if they
+// differ, and 2 on errors.
+func runDiffCommand(args []string) int {
+	flags := newFlagSet("diff", "[options] FILE1 FILE2")
+	context := flags.Int("context", 1, "`lines` of context around differing lines")
+	width := flags.Int("width", 16, "bytes per line")
+	asJSON := flags.Bool("json", false, "write the differing ranges as NDJSON")
+	var masks []maskRegion
+	flags.Func("mask", "ignore differences in a `range`, START-END or START+LENGTH (repeatable)", func(text string) error {
+		region, err := parseMaskRange(text)
+		masks = append(masks, region)
+		return err
+	})
+	maskFile := flags.String("mask-file", "", "ignore differences in the regions listed in `file`, one per line")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	if *width < 1 || *context < 0 {
+		return cliError(os.Stderr, "diff", fmt.Errorf("width must be positive and context not negative"))
+	}
+
+	if *maskFile != "" {
+		text, err := os.ReadFile(*maskFile)
+		if err != nil {
+			return cliError(os.Stderr, "diff", err)
+		}
+		regions, err := parseMaskRegions(string(text))
+		if err != nil {
+			return cliError(os.Stderr, "diff", fmt.Errorf("%s: %v", *maskFile, err))
+		}
+		masks = append(masks, regions...)
+	}
+
+	nameA, nameB := flags.Arg(0), flags.Arg(1)
+	a, err := os.ReadFile(nameA)
+	if err != nil {
+		return cliError(os.Stderr, "diff", err)
+	}
+	b, err := os.ReadFile(nameB)
+	if err != nil {
+		return cliError(os.Stderr, "diff", err)
+	}
+
+	if *asJSON {
+		err = writeJSONDiff(os.Stdout, nameA, nameB, a, b, masks)
+	} else {
+		err = writeTextDiff(os.Stdout, nameA, nameB, a, b, *width, *context, masks)
+	}
+	if err != nil {
+		return cliError(os.Stderr, "diff", err)
+	}
+	if len(maskDiffRanges(fileDiffRanges(a, b), masks)) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// grepOptions selects what "hexdump grep" prints about the matches in each file
+type grepOptions struct {
+	count    bool // Print the number of matches instead of their offsets
+	list     bool // Print only the names of files with a match
+	quiet    bool // Print nothing
+	decimal  bool // Print offsets in decimal instead of hex
+	names    bool // Prefix each line with the file name
+	maxCount int  // Stop after this many matches in a file, if positive
+}
+
+// writeGrepMatches writes the matches of pattern in the data of the named file to w as
+// options select, and reports whether there were any
+func writeGrepMatches(w io.Writer, name string, data []byte, pattern searchPattern, options grepOptions) (bool, error) {
+	limit := options.maxCount
+	if options.list || options.quiet {
+		limit = 1
+	}
+	offsets := pattern.findAll(data, limit)
+
+	out := bufio.NewWriter(w)
+	prefix := ""
+	if options.names {
+		prefix = name + ":"
+	}
+	switch {
+	case options.quiet:
+	case options.list:
+		if len(offsets) > 0 {
+			fmt.Fprintln(out, name)
+		}
+	case options.count:
+		fmt.Fprintf(out, "%s%d\n", prefix, len(offsets))
+	default:
+		for _, offset := range offsets {
+			if options.decimal {
+				fmt.Fprintf(out, "%s%d\n", prefix, offset)
+			} else {
+				fmt.Fprintf(out, "%s0x%08x\n", prefix, offset)
+			}
+		}
+	}
+	return len(offsets) > 0, out.Flush()
+}
+
+// runGrepCommand implements "hexdump grep", which prints the offsets at which a hex or
+// text pattern occurs in files. As with grep(1), the exit code is 0 if a file matched,
+// 1 if none did, and 2 on errors, so that build scripts can check their output.
+func runGrepCommand(args []string) int {
+	flags := newFlagSet("grep", "[options] PATTERN [file ...]")
+	text := flags.Bool("text", false, "PATTERN is text instead of hex bytes such as \"4D 5A ?? 00\"")
+	encoding := flags.String("encoding", "ISO Latin-1", "character `encoding` of a text PATTERN")
+	var options grepOptions
+	flags.BoolVar(&options.count, "c", false, "print the number of matches in each file")
+	flags.BoolVar(&options.list, "l", false, "print only the names of files with a match")
+	flags.BoolVar(&options.quiet, "q", false, "print nothing; only set the exit code")
+	flags.BoolVar(&options.decimal, "d", false, "print offsets in decimal")
+	flags.IntVar(&options.maxCount, "m", 0, "stop after `count` matches in each file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	kind := searchKindHex
+	if *text {
+		kind = searchKindText
+		if !slices.Contains(encodingNames, *encoding) {
+			return cliError(os.Stderr, "grep", fmt.Errorf("unknown encoding: %s (use %s)", *encoding,
+				strings.Join(encodingNames, ", ")))
+		}
+	}
+	pattern, err := parseSearchPattern(kind, flags.Arg(0), *encoding)
+	if err != nil {
+		return cliError(os.Stderr, "grep", err)
+	}
+
+	files := flags.Args()[1:]
+	if len(files) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return cliError(os.Stderr, "grep", err)
+		}
+		matched, err := writeGrepMatches(os.Stdout, "-", data, pattern, options)
+		if err != nil {
+			return cliError(os.Stderr, "grep", err)
+		}
+		if !matched {
+			return 1
+		}
+		return 0
+	}
+
+	options.names = len(files) > 1
+	exitCode := 1
+	failed := false
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		var matched bool
+		if err == nil {
+			matched, err = writeGrepMatches(os.Stdout, name, data, pattern, options)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "hexdump grep: %v\n", err)
+			failed = true
+			continue
+		}
+		if matched {
+			exitCode = 0
+			if options.quiet {
+				break
+			}
+		}
+	}
+	// As with grep(1), an error only decides the exit code if nothing matched
+	if failed && exitCode != 0 {
+		return 2
+	}
+	return exitCode
+}
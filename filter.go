@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// lineFilter is a pattern that lines must contain to be shown: the data list collapses
+// each run of lines without a match into one row counting them
+type lineFilter struct {
+	kind     string
+	text     string
+	encoding string
+	pattern  searchPattern
+	matches  []int // Offsets of the matches in the data
+}
+
+// showFilterLines asks for the pattern lines must contain to stay visible. An empty
+// pattern shows all lines again.
+func (h *HexDumpApp) showFilterLines() {
+	if len(h.fileData) == 0 {
+		return
+	}
+	kindSelect := widget.NewSelect(searchKinds, nil)
+	kindSelect.SetSelected(searchKindHex)
+	encodingSelect := widget.NewSelect(encodingNames, nil)
+	encodingSelect.SetSelected(h.encoding)
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder(lang.L("e.g. 4D 5A ?? 00, or text"))
+	if h.filter != nil {
+		kindSelect.SetSelected(h.filter.kind)
+		encodingSelect.SetSelected(h.filter.encoding)
+		patternEntry.SetText(h.filter.text)
+	}
+	warningLabel := newEncodingWarning(kindSelect, encodingSelect, patternEntry)
+
+	patternItem := widget.NewFormItem(lang.L("Pattern"), patternEntry)
+	patternItem.HintText = "Lines without a match are hidden; leave empty to show all lines"
+	form := dialog.NewForm(lang.L("Filter Lines"), lang.L("Filter"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Type"), kindSelect),
+		widget.NewFormItem(lang.L("Encoding"), encodingSelect),
+		patternItem,
+		widget.NewFormItem("", warningLabel),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if strings.TrimSpace(patternEntry.Text) == "" {
+			h.clearFilter()
+			return
+		}
+		pattern, err := parseSearchPattern(kindSelect.Selected, patternEntry.Text, encodingSelect.Selected)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		h.filter = &lineFilter{kind: kindSelect.Selected, text: patternEntry.Text, encoding: encodingSelect.Selected,
+			pattern: pattern}
+		h.updateFilter()
+		h.updateDisplay()
+		h.updateStatus()
+		h.goToOffset(h.caret)
+	}, h.window)
+	form.Resize(fyne.NewSize(420, 260))
+	form.Show()
+	h.window.Canvas().Focus(patternEntry)
+}
+
+// clearFilter shows all lines again
+func (h *HexDumpApp) clearFilter() {
+	if h.filter == nil {
+		return
+	}
+	h.filter = nil
+	h.updateDisplay()
+	h.updateStatus()
+	h.goToOffset(h.caret)
+}
+
+// updateFilter finds the matches of the filter again after the data changed
+func (h *HexDumpApp) updateFilter() {
+	if h.filter != nil {
+		h.filter.matches = h.filter.pattern.findAll(h.fileData, 0)
+	}
+}
+
+// filteredLines returns the runs of lines without a match of the filter, which the data
+// list collapses. Unlike padding, a single hidden line is collapsed too, so that every
+// line shown contains a match.
+func (h *HexDumpApp) filteredLines() []collapsedLines {
+	var hidden []collapsedLines
+	next := 0 // First line not yet known to be shown
+	for _, offset := range h.filter.matches {
+		first := h.lineOf(offset)
+		last := h.lineOf(offset + len(h.filter.pattern.data) - 1)
+		if first > next {
+			hidden = append(hidden, collapsedLines{first: next, last: first - 1, hidden: true})
+		}
+		next = max(next, last+1)
+	}
+	if lines := h.lineCount(); next < lines {
+		hidden = append(hidden, collapsedLines{first: next, last: lines - 1, hidden: true})
+	}
+	return hidden
+}
+
+// filterStatus describes the filter for the status bar
+func (h *HexDumpApp) filterStatus() string {
+	if h.filter == nil {
+		return ""
+	}
+	return lang.L("Filter: {{.Shown}} of {{.Lines}} lines match", map[string]any{
+		"Shown": h.rowCount() - len(h.collapsed), "Lines": h.lineCount()})
+}
+
+// filteredRowText returns the text of the row standing for lines hidden by the filter
+func (h *HexDumpApp) filteredRowText(offset int, lines *collapsedLines) string {
+	return fmt.Sprintf("%s: ··· %d lines hidden by the filter ···", h.formatAddress(offset),
+		lines.last-lines.first+1)
+}
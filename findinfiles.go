@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxHitsPerFile limits the number of hits recorded for any one file
+const maxHitsPerFile = 1000
+
+// fileHits records the offsets at which a pattern was found in one file
+type fileHits struct {
+	path    string
+	offsets []int
+}
+
+// findInFiles walks the directory tree rooted at root and searches every regular file
+// whose name matches namePattern. The found callback is called for each file with at
+// least one hit, and scanning stops early when stop returns true. It returns the
+// number of files searched.
+func findInFiles(root string, namePattern string, pattern searchPattern,
+	found func(fileHits), stop func() bool) (int, error) {
+	if _, err := filepath.Match(namePattern, ""); err != nil {
+		return 0, fmt.Errorf("invalid file pattern %q: %w", namePattern, err)
+	}
+
+	searched := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if stop() {
+			return filepath.SkipAll
+		}
+		if err != nil || !entry.Type().IsRegular() {
+			return nil // Skip unreadable entries, directories, and special files
+		}
+		if matched, _ := filepath.Match(namePattern, entry.Name()); !matched {
+			return nil
+		}
+
+		// Files are read a chunk at a time, so that large images aren't loaded whole
+		file, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+		offsets, err := pattern.findInReader(file, maxHitsPerFile)
+		if err != nil {
+			return nil
+		}
+		searched++
+
+		if len(offsets) > 0 {
+			found(fileHits{path: path, offsets: offsets})
+		}
+		return nil
+	})
+	return searched, err
+}
+
+// showFindInFiles opens the Find in Files tool window
+func (h *HexDumpApp) showFindInFiles() {
+	window := h.app.NewWindow(lang.L("Find in Files"))
+	window.Resize(fyne.NewSize(600, 500))
+
+	folderEntry := widget.NewEntry()
+	folderEntry.SetPlaceHolder(lang.L("Folder to search"))
+	if h.fileName != "" && !isRemoteName(h.fileName) {
+		folderEntry.SetText(filepath.Dir(h.fileName))
+	}
+	namePatternEntry := widget.NewEntry()
+	namePatternEntry.SetText("*")
+	kindSelect := widget.NewSelect(searchKinds, nil)
+	kindSelect.SetSelected(searchKindHex)
+	encodingSelect := widget.NewSelect(encodingNames, nil)
+	encodingSelect.SetSelected(h.encoding)
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder(lang.L("e.g. 4D 5A ?? 00, or text"))
+
+	// Results, indexed by the tree's node IDs: "f<file>" for files, "h<file>:<hit>" for hits
+	var results []fileHits
+	var patternLength int
+	statusLabel := widget.NewLabel("")
+
+	var tree *widget.Tree
+	openHit := func(uid widget.TreeNodeID) {
+		fileIndex, hitIndex, ok := parseHitUID(uid)
+		if !ok || fileIndex >= len(results) {
+			return
+		}
+		hit := results[fileIndex]
+		h.openInNewTab(hit.path, hit.offsets[hitIndex], patternLength)
+	}
+
+	tree = widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			if uid == "" {
+				ids := make([]widget.TreeNodeID, len(results))
+				for index := range results {
+					ids[index] = "f" + strconv.Itoa(index)
+				}
+				return ids
+			}
+			fileIndex, err := strconv.Atoi(strings.TrimPrefix(uid, "f"))
+			if !strings.HasPrefix(uid, "f") || err != nil || fileIndex >= len(results) {
+				return nil
+			}
+			ids := make([]widget.TreeNodeID, len(results[fileIndex].offsets))
+			for index := range ids {
+				ids[index] = fmt.Sprintf("h%d:%d", fileIndex, index)
+			}
+			return ids
+		},
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || strings.HasPrefix(uid, "f")
+		},
+		func(branch bool) fyne.CanvasObject {
+			return newTappableLabel("")
+		},
+		func(uid widget.TreeNodeID, branch bool, object fyne.CanvasObject) {
+			label := object.(*tappableLabel)
+			if branch {
+				fileIndex, _ := strconv.Atoi(strings.TrimPrefix(uid, "f"))
+				hit := results[fileIndex]
+				label.SetText(fmt.Sprintf("%s (%d hits)", hit.path, len(hit.offsets)))
+				label.onTapped = func() { tree.ToggleBranch(uid) }
+				label.onDoubleTapped = nil
+				return
+			}
+			fileIndex, hitIndex, _ := parseHitUID(uid)
+			label.SetText(fmt.Sprintf("%08X", results[fileIndex].offsets[hitIndex]))
+			label.onTapped = func() { tree.Select(uid) }
+			label.onDoubleTapped = func() { openHit(uid) }
+		},
+	)
+
+	var stopFlag atomic.Bool
+	var findBtn, stopBtn *widget.Button
+	stopBtn = widget.NewButton(lang.L("Stop"), func() { stopFlag.Store(true) })
+	stopBtn.Disable()
+
+	findBtn = widget.NewButton(lang.L("Find"), func() {
+		pattern, err := parseSearchPattern(kindSelect.Selected, patternEntry.Text, encodingSelect.Selected)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if folderEntry.Text == "" {
+			dialog.ShowError(fmt.Errorf("a folder to search is required"), window)
+			return
+		}
+
+		results = nil
+		patternLength = len(pattern.data)
+		tree.Refresh()
+		stopFlag.Store(false)
+		findBtn.Disable()
+		stopBtn.Enable()
+		statusLabel.SetText(lang.L("Searching..."))
+
+		root := folderEntry.Text
+		namePattern := namePatternEntry.Text
+		task := h.startTask(lang.L("Find in Files"))
+		go func() {
+			defer h.recoverPanic()
+			searched, err := findInFiles(root, namePattern, pattern, func(hit fileHits) {
+				fyne.Do(func() {
+					results = append(results, hit)
+					tree.Refresh()
+				})
+			}, func() bool { return stopFlag.Load() || task.cancelled() })
+
+			fyne.Do(func() {
+				findBtn.Enable()
+				stopBtn.Disable()
+				if err != nil {
+					h.finishTask(task, err.Error(), nil)
+					statusLabel.SetText("")
+					dialog.ShowError(err, window)
+					return
+				}
+				summary := fmt.Sprintf("%d file(s) searched, %d with hits.", searched, len(results))
+				h.finishTask(task, summary, nil)
+				statusLabel.SetText(summary + " " + lang.L("Double-click a hit to open it."))
+			})
+		}()
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(lang.L("Folder"), h.folderField(folderEntry)),
+		widget.NewFormItem(lang.L("File pattern"), namePatternEntry),
+		widget.NewFormItem(lang.L("Search for"), container.NewBorder(nil, nil, kindSelect, encodingSelect, patternEntry)),
+		widget.NewFormItem("", newEncodingWarning(kindSelect, encodingSelect, patternEntry)),
+	)
+	top := container.NewVBox(form, container.NewHBox(findBtn, stopBtn, statusLabel))
+
+	window.SetContent(container.NewBorder(top, nil, nil, nil, tree))
+	window.SetOnClosed(func() { stopFlag.Store(true) })
+	window.Show()
+}
+
+// parseHitUID parses a Find in Files tree node ID of the form "h<file>:<hit>"
+func parseHitUID(uid string) (int, int, bool) {
+	fileText, hitText, found := strings.Cut(strings.TrimPrefix(uid, "h"), ":")
+	if !strings.HasPrefix(uid, "h") || !found {
+		return 0, 0, false
+	}
+	fileIndex, err1 := strconv.Atoi(fileText)
+	hitIndex, err2 := strconv.Atoi(hitText)
+	return fileIndex, hitIndex, err1 == nil && err2 == nil
+}
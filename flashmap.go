@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// States of a flash sector
+const (
+	sectorErased = "erased" // All FF, as left by erasing
+	sectorBlank  = "blank"  // All 00
+	sectorData   = "data"
+)
+
+// flashSector is one sector of a flash image
+type flashSector struct {
+	index  int
+	offset int
+	size   int
+	state  string
+	crc    uint32
+}
+
+// mapSectors divides data into sectors of sectorSize bytes, the last possibly shorter,
+// and works out the state and CRC-32 of each
+func mapSectors(data []byte, sectorSize int) []flashSector {
+	var sectors []flashSector
+	for offset := 0; offset < len(data); offset += sectorSize {
+		sector := data[offset:min(offset+sectorSize, len(data))]
+		state := sectorData
+		switch {
+		case len(bytes.Trim(sector, "\xFF")) == 0:
+			state = sectorErased
+		case len(bytes.Trim(sector, "\x00")) == 0:
+			state = sectorBlank
+		}
+		sectors = append(sectors, flashSector{index: len(sectors), offset: offset, size: len(sector), state: state,
+			crc: crc32.ChecksumIEEE(sector)})
+	}
+	return sectors
+}
+
+// writeSectorCSV writes a sector table to w as CSV with a header row
+func writeSectorCSV(w io.Writer, sectors []flashSector) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"sector", "offset", "size", "state", "crc32"})
+	for _, sector := range sectors {
+		writer.Write([]string{strconv.Itoa(sector.index), fmt.Sprintf("0x%X", sector.offset), strconv.Itoa(sector.size),
+			sector.state, fmt.Sprintf("%08X", sector.crc)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// showSectorMap divides a flash image into sectors of a given size and lists each
+// sector's offset, state (erased, blank, or data) and CRC-32 in a table, which can be
+// exported to CSV
+func (h *HexDumpApp) showSectorMap() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Flash Sector Map"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	var sectors []flashSector
+	sizeEntry := widget.NewSelectEntry([]string{"0x200", "0x1000", "0x8000", "0x10000", "0x20000", "0x40000"})
+	sizeEntry.SetText("0x1000")
+	summaryLabel := widget.NewLabel("")
+
+	list := widget.NewList(
+		func() int { return len(sectors) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			sector := sectors[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("#%-5d %08X  %-6s  CRC %08X", sector.index, sector.offset,
+				sector.state, sector.crc))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		sector := sectors[id]
+		h.setSelection(sector.offset, sector.offset+sector.size)
+		h.goToOffset(sector.offset)
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("Flash Sector Map - %s", h.fileName))
+	mapBtn := widget.NewButton(lang.L("Map"), func() {
+		sectorSize, err := parseOffset(sizeEntry.Text)
+		if err != nil || sectorSize < 1 {
+			dialog.ShowError(fmt.Errorf("the sector size must be a positive number"), window)
+			return
+		}
+		sectors = mapSectors(h.fileData, sectorSize)
+		counts := map[string]int{}
+		for _, sector := range sectors {
+			counts[sector.state]++
+		}
+		list.UnselectAll()
+		list.Refresh()
+		summaryLabel.SetText(fmt.Sprintf("%d sectors: %d erased, %d blank, %d with data", len(sectors),
+			counts[sectorErased], counts[sectorBlank], counts[sectorData]))
+	})
+	exportBtn := widget.NewButton(lang.L("Export CSV..."), func() {
+		filename, err := nativedialog.File().Filter("CSV files", "csv").Title("Export Sector Map").Save()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, window)
+			}
+			return
+		}
+		var buffer bytes.Buffer
+		err = writeSectorCSV(&buffer, sectors)
+		if err == nil {
+			err = writeDataFile(filename, buffer.Bytes())
+		}
+		if err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+
+	sizeItem := widget.NewFormItem(lang.L("Sector size"), sizeEntry)
+	sizeItem.HintText = "The erase unit of the flash, in bytes"
+	form := widget.NewForm(sizeItem)
+	window.SetContent(container.NewBorder(
+		container.NewVBox(form, container.NewHBox(mapBtn, exportBtn), summaryLabel),
+		nil, nil, nil,
+		list,
+	))
+	window.Resize(fyne.NewSize(450, 500))
+	window.Show()
+	mapBtn.OnTapped()
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// graphTypes lists the value types the Graph panel can plot, as template field types
+var graphTypes = []string{"i8", "u8", "i16", "u16", "i32", "u32", "f32", "f64"}
+
+// Graph image dimensions
+const (
+	graphWidth  = 400
+	graphHeight = 200
+	maxGraphed  = 1000000 // Values beyond this are not plotted
+)
+
+// decodeSeries decodes the values of the given template field type found every stride
+// bytes in data, in the given byte order
+func decodeSeries(data []byte, typeName string, stride int, order binary.ByteOrder) []float64 {
+	size := fieldSizes[typeName]
+	var values []float64
+	for offset := 0; offset+size <= len(data) && len(values) < maxGraphed; offset += stride {
+		value := data[offset : offset+size]
+		switch typeName {
+		case "i8":
+			values = append(values, float64(int8(value[0])))
+		case "u8":
+			values = append(values, float64(value[0]))
+		case "i16":
+			values = append(values, float64(int16(order.Uint16(value))))
+		case "u16":
+			values = append(values, float64(order.Uint16(value)))
+		case "i32":
+			values = append(values, float64(int32(order.Uint32(value))))
+		case "u32":
+			values = append(values, float64(order.Uint32(value)))
+		case "f32":
+			values = append(values, float64(math.Float32frombits(order.Uint32(value))))
+		case "f64":
+			values = append(values, math.Float64frombits(order.Uint64(value)))
+		}
+	}
+	return values
+}
+
+// seriesRange returns the lowest and highest finite values
+func seriesRange(values []float64) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			low, high = math.Min(low, value), math.Max(high, value)
+		}
+	}
+	return low, high
+}
+
+// renderGraph plots values as a line chart scaled to fill the image between low and
+// high, with a zero axis when zero is in range. Each column spans the values it
+// represents and the first value of the next column, so the points are joined.
+func renderGraph(values []float64, low, high float64) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, graphWidth, graphHeight))
+	lineColor, axisColor := paletteRGBA(colorNameGraphLine), paletteRGBA(colorNameGraphAxis)
+	if len(values) == 0 || low > high {
+		return img
+	}
+	if low == high {
+		low, high = low-1, high+1
+	}
+	yOf := func(value float64) int {
+		return int((high - value) / (high - low) * (graphHeight - 1))
+	}
+
+	if low < 0 && high > 0 {
+		for x := 0; x < graphWidth; x++ {
+			img.SetRGBA(x, yOf(0), axisColor)
+		}
+	}
+
+	columns := min(graphWidth, len(values))
+	for x := 0; x < columns; x++ {
+		first := x * len(values) / columns
+		last := min(max(first+1, (x+1)*len(values)/columns)+1, len(values))
+		top, bottom := graphHeight, -1
+		for _, value := range values[first:last] {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			y := yOf(value)
+			top, bottom = min(top, y), max(bottom, y)
+		}
+		// Spread the columns of a short series across the width of the image
+		left, right := x*graphWidth/columns, (x+1)*graphWidth/columns
+		for px := left; px < right; px++ {
+			for y := top; y <= bottom; y++ {
+				img.SetRGBA(px, y, lineColor)
+			}
+		}
+	}
+	return img
+}
+
+// createGraphPanel creates the Graph side panel, which plots the selection decoded as
+// a numeric series. Clicking the graph selects the value under the pointer.
+func (h *HexDumpApp) createGraphPanel() panelContent {
+	typeSelect := widget.NewSelect(graphTypes, nil)
+	typeSelect.SetSelected("i16")
+	orderSelect := widget.NewSelect(append([]string{"Chosen"}, byteOrderNames...), nil)
+	orderSelect.SetSelected("Chosen")
+	strideEntry := widget.NewEntry()
+	strideEntry.SetPlaceHolder(lang.L("auto"))
+	rangeLabel := widget.NewLabel("")
+	hoverLabel := widget.NewLabel("")
+	view := newPixelView(renderGraph(nil, 0, 0), fyne.NewSize(graphWidth, graphHeight))
+
+	var values []float64
+	start, stride, size := 0, 0, 0
+	plotBtn := widget.NewButton(lang.L("Plot Selection"), func() {
+		if !h.hasSelection() {
+			dialog.ShowInformation(lang.L("Graph"), lang.L("Select the bytes to plot first."), h.window)
+			return
+		}
+		stride = fieldSizes[typeSelect.Selected]
+		if text := strings.TrimSpace(strideEntry.Text); text != "" {
+			var err error
+			if stride, err = parseOffset(text); err != nil || stride <= 0 {
+				dialog.ShowError(fmt.Errorf("stride must be a positive number of bytes"), h.window)
+				return
+			}
+		}
+		order := h.byteOrder()
+		switch orderSelect.Selected {
+		case "Little-endian":
+			order = binary.LittleEndian
+		case "Big-endian":
+			order = binary.BigEndian
+		}
+
+		start, size = h.selStart, fieldSizes[typeSelect.Selected]
+		values = decodeSeries(h.selectedBytes(), typeSelect.Selected, stride, order)
+		low, high := seriesRange(values)
+		view.setImage(renderGraph(values, low, high))
+		if low <= high {
+			rangeLabel.SetText(fmt.Sprintf("%d values from %08X, %g to %g", len(values), start, low, high))
+		} else {
+			rangeLabel.SetText(fmt.Sprintf("%d values from %08X", len(values), start))
+		}
+	})
+
+	valueAt := func(x int) int {
+		if len(values) == 0 {
+			return -1
+		}
+		columns := min(graphWidth, len(values))
+		return x * columns / graphWidth * len(values) / columns
+	}
+	view.onHover = func(x, _ int) {
+		if index := valueAt(x); index >= 0 {
+			hoverLabel.SetText(fmt.Sprintf("Value %d at %08X: %g", index, start+index*stride, values[index]))
+		}
+	}
+	view.onTapped = func(x, _ int) {
+		if index := valueAt(x); index >= 0 {
+			offset := start + index*stride
+			h.setSelection(offset, offset+size)
+			h.goToOffset(offset)
+		}
+	}
+
+	reset := func() {
+		values = nil
+		view.setImage(renderGraph(nil, 0, 0))
+		rangeLabel.SetText(lang.L("Select bytes and press Plot Selection"))
+		hoverLabel.SetText("")
+	}
+	reset()
+
+	controls := container.NewVBox(
+		container.NewHBox(widget.NewLabel(lang.L("Type:")), typeSelect, widget.NewLabel(lang.L("Order:")), orderSelect),
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Stride:")), plotBtn, strideEntry),
+		rangeLabel,
+	)
+	return panelContent{
+		object: container.NewBorder(controls, hoverLabel, nil, nil, view),
+		reset:  reset,
+	}
+}
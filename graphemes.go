@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxClusterBytes is the longest grapheme cluster the character pane combines, and so
+// the furthest it looks before a line for a cluster that continues onto it
+const maxClusterBytes = 64
+
+// charCell is a grapheme cluster decoded from the bytes [start, end) and shown as text
+// in width columns of the character pane
+type charCell struct {
+	start, end int
+	text       string
+	width      int
+}
+
+// continuedText is shown for the bytes at the start of a line that belong to a grapheme
+// cluster shown on the previous line
+const continuedText = "…"
+
+// isGraphemeExtender reports whether r joins the grapheme cluster before it: combining
+// marks and variation selectors, emoji skin tone modifiers, and emoji tag characters
+func isGraphemeExtender(r rune) bool {
+	return unicode.Is(unicode.M, r) || (r >= 0x1F3FB && r <= 0x1F3FF) || (r >= 0xE0020 && r <= 0xE007F)
+}
+
+// isRegionalIndicator reports whether r is one of the letters that form flags in pairs
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isWideRune reports whether r is drawn about two columns wide: East Asian wide and
+// fullwidth characters, and emoji
+func isWideRune(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, r >= 0x2E80 && r <= 0xA4CF && r != 0x303F, r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF, r >= 0xFE30 && r <= 0xFE4F, r >= 0xFF00 && r <= 0xFF60, r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F, r >= 0x1F900 && r <= 0x1F9FF, r >= 0x20000 && r <= 0x3FFFD:
+		return true
+	}
+	return false
+}
+
+// nextGrapheme returns the length of the grapheme cluster at the start of data, a
+// simplified form of the Unicode rules: a character followed by its extenders, zero
+// width joiner sequences, and pairs of regional indicators. Invalid bytes and control
+// characters are clusters of their own.
+func nextGrapheme(data []byte) int {
+	r, size := utf8.DecodeRune(data)
+	if r == utf8.RuneError || unicode.IsControl(r) {
+		return size
+	}
+	pairedIndicator := !isRegionalIndicator(r)
+	for size < len(data) {
+		next, nextSize := utf8.DecodeRune(data[size:])
+		switch {
+		case next == utf8.RuneError:
+			return size
+		case isGraphemeExtender(next):
+			size += nextSize
+		case next == 0x200D:
+			// A joiner also takes the character after it
+			size += nextSize
+			if joined, joinedSize := utf8.DecodeRune(data[size:]); size < len(data) && joined != utf8.RuneError &&
+				!unicode.IsControl(joined) {
+				size += joinedSize
+			}
+		case isRegionalIndicator(next) && !pairedIndicator:
+			pairedIndicator = true
+			size += nextSize
+		default:
+			return size
+		}
+	}
+	return size
+}
+
+// graphemeText returns how the character pane shows a grapheme cluster: its printable
+// characters, with a dotted circle under marks that have no character to combine with,
+// and the fallback character if nothing in it is printable
+func graphemeText(cluster []byte) (string, int) {
+	var builder strings.Builder
+	width := 1
+	for index, r := range string(cluster) {
+		if index == 0 && isGraphemeExtender(r) {
+			builder.WriteRune('◌')
+		}
+		if r == 0xFE0F || isWideRune(r) {
+			width = 2 // Emoji presentation, or a wide character
+		}
+		if unicode.IsPrint(r) || isGraphemeExtender(r) || r == 0x200D {
+			builder.WriteRune(r)
+		}
+	}
+	text := builder.String()
+	if text == "" || text == "\u200D" {
+		return fallbackText("UTF-8", firstRune(cluster)), 1
+	}
+	return text, width
+}
+
+// utf8Cells splits data[start:end] into the cells of the character pane, looking for
+// clusters from the character boundary from. A cluster crossing a line is shown whole
+// on its first line, and its bytes on the next line as one continuedText cell.
+func utf8Cells(data []byte, from, start, end int) []charCell {
+	var cells []charCell
+	for offset := from; offset < end; {
+		size := nextGrapheme(data[offset:min(offset+maxClusterBytes, len(data))])
+		switch {
+		case offset+size <= start:
+			// Shown on an earlier line
+		case offset < start:
+			cells = append(cells, charCell{start: start, end: offset + size, text: continuedText, width: 1})
+		default:
+			text, width := graphemeText(data[offset : offset+size])
+			cells = append(cells, charCell{start: offset, end: offset + size, text: text, width: width})
+		}
+		offset += size
+	}
+	return cells
+}
+
+// lineCells returns the UTF-8 cells of the character pane for the bytes [lineStart, lineEnd)
+func (h *HexDumpApp) lineCells(lineStart, lineEnd int) []charCell {
+	// Start looking for clusters at a character boundary before the line
+	from := max(lineStart-maxClusterBytes, 0)
+	for from < lineStart && !utf8.RuneStart(h.fileData[from]) {
+		from++
+	}
+	return utf8Cells(h.fileData, from, lineStart, lineEnd)
+}
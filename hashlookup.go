@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Hash lookup sources, as shown in the GUI
+const (
+	lookupVirusTotal = "VirusTotal"
+	lookupHashSet    = "Hash set file (e.g. NSRL)"
+)
+
+// virusTotalURL is the VirusTotal API v3 endpoint for file reports, followed by the
+// file's SHA-256
+const virusTotalURL = "https://www.virustotal.com/api/v3/files/"
+
+// virusTotalKey is the VirusTotal API key last entered in Look Up Hash. It is kept for
+// the session only and never saved.
+var virusTotalKey string
+
+// lookupVirusTotalReport asks VirusTotal for its report on the file with the given
+// SHA-256 and summarizes how many engines flag it
+func lookupVirusTotalReport(sha256Hex, apiKey string) (string, error) {
+	request, err := http.NewRequest(http.MethodGet, virusTotalURL+sha256Hex, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("x-apikey", apiKey)
+	client := &http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "VirusTotal: unknown file", nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("VirusTotal rejected the API key")
+	default:
+		return "", fmt.Errorf("VirusTotal answered %s", response.Status)
+	}
+
+	var report struct {
+		Data struct {
+			Attributes struct {
+				Stats map[string]int `json:"last_analysis_stats"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&report); err != nil {
+		return "", fmt.Errorf("reading the VirusTotal report: %w", err)
+	}
+	stats := report.Data.Attributes.Stats
+	engines := 0
+	for _, count := range stats {
+		engines += count
+	}
+	if flagged := stats["malicious"] + stats["suspicious"]; flagged > 0 {
+		return fmt.Sprintf("VirusTotal: known bad, flagged by %d of %d engines", flagged, engines), nil
+	}
+	return fmt.Sprintf("VirusTotal: known, not flagged by %d engines", engines), nil
+}
+
+// fileDigests returns the upper-case hex SHA-256, SHA-1 and MD5 digests of data,
+// computed in a single pass
+func fileDigests(data []byte) []string {
+	hashes := []hash.Hash{sha256.New(), sha1.New(), md5.New()}
+	writers := make([]io.Writer, len(hashes))
+	for i, digest := range hashes {
+		writers[i] = digest
+	}
+	io.MultiWriter(writers...).Write(data)
+	digests := make([]string, len(hashes))
+	for i, digest := range hashes {
+		digests[i] = strings.ToUpper(hex.EncodeToString(digest.Sum(nil)))
+	}
+	return digests
+}
+
+// lookupHashSetFile reports whether any of the hex digests appears in the hash set file
+// at path. Any text file listing digests works, such as the NSRLFile.txt of the legacy
+// NSRL reference data set, which lists SHA-1 and MD5 digests of known software. Digests
+// are compared with whole runs of hex digits, so that one doesn't match inside a longer
+// digest or another field.
+func lookupHashSetFile(path string, digests []string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	notHex := func(r rune) bool { return !strings.ContainsRune("0123456789ABCDEFabcdef", r) }
+	for scanner.Scan() {
+		for _, token := range strings.FieldsFunc(scanner.Text(), notHex) {
+			if slices.Contains(digests, strings.ToUpper(token)) {
+				return "Hash set: known file", nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "Hash set: unknown file", nil
+}
+
+// showHashLookup looks the file's hashes up online at VirusTotal or in a local hash set,
+// and shows the result in the status bar. Nothing is sent anywhere until the user
+// starts a lookup here.
+func (h *HexDumpApp) showHashLookup() {
+	if h.fileName == "" {
+		dialog.ShowInformation(lang.L("Look Up Hash"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	sourceSelect := widget.NewRadioGroup([]string{lookupVirusTotal, lookupHashSet}, nil)
+	sourceSelect.SetSelected(lookupVirusTotal)
+	keyEntry := widget.NewPasswordEntry()
+	keyEntry.SetText(virusTotalKey)
+	keyEntry.SetPlaceHolder(lang.L("Your VirusTotal API key"))
+	pathEntry := widget.NewEntry()
+	pathEntry.SetText(appSettings.HashSetPath)
+	pathEntry.SetPlaceHolder(lang.L("Path of a text file listing digests"))
+
+	keyItem := widget.NewFormItem(lang.L("API key"), keyEntry)
+	keyItem.HintText = lang.L("Only the SHA-256 is sent, never the file. The key is not saved.")
+	form := dialog.NewForm(lang.L("Look Up Hash"), lang.L("Look Up"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Source"), sourceSelect),
+		keyItem,
+		widget.NewFormItem(lang.L("Hash set"), pathEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		source, apiKey, path := sourceSelect.Selected, strings.TrimSpace(keyEntry.Text), strings.TrimSpace(pathEntry.Text)
+		if source == lookupVirusTotal && apiKey == "" {
+			dialog.ShowError(fmt.Errorf("enter a VirusTotal API key"), h.window)
+			return
+		}
+		if source == lookupHashSet && path == "" {
+			dialog.ShowError(fmt.Errorf("enter the path of a hash set file"), h.window)
+			return
+		}
+		virusTotalKey, appSettings.HashSetPath = apiKey, path
+		saveSettings()
+
+		task := h.startTask(lang.L("Look Up Hash"))
+		fileName, data := h.fileName, h.fileData
+		go func() {
+			defer h.recoverPanic()
+			digests := fileDigests(data)
+			var result string
+			var err error
+			if source == lookupVirusTotal {
+				result, err = lookupVirusTotalReport(strings.ToLower(digests[0]), apiKey)
+			} else {
+				result, err = lookupHashSetFile(path, digests)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					if h.finishTask(task, err.Error(), nil) {
+						dialog.ShowError(err, h.window)
+					}
+					return
+				}
+				if !h.finishTask(task, result, nil) {
+					return
+				}
+				if h.fileName == fileName {
+					h.hashLookup = result
+					h.updateStatus()
+				}
+			})
+		}()
+	}, h.window)
+	form.Resize(fyne.NewSize(560, 340))
+	form.Show()
+}
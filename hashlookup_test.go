@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookupHashSetFile(t *testing.T) {
+	const md5 = "0123456789ABCDEF0123456789ABCDEF"
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"bare digest", md5, "Hash set: known file"},
+		{"lower case", "0123456789abcdef0123456789abcdef", "Hash set: known file"},
+		{"NSRL record", `"DA39A3EE5E6B4B0D3255BFEF95601890AFD80709","` + md5 + `","00000000","a.exe",0,1,"WIN",""`, "Hash set: known file"},
+		{"inside a longer digest", "FF" + md5 + "FF", "Hash set: unknown file"},
+		{"prefix of the digest", md5[:20], "Hash set: unknown file"},
+		{"other digest", "FEDCBA9876543210FEDCBA9876543210", "Hash set: unknown file"},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "hashes.txt")
+		if err := os.WriteFile(path, []byte("header line\n"+test.line+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := lookupHashSetFile(path, []string{md5})
+		if err != nil || got != test.want {
+			t.Errorf("%s: got %q, %v; want %q", test.name, got, err, test.want)
+		}
+	}
+}
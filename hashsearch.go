@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxHashMatches is the largest number of matching blocks reported
+const maxHashMatches = 1000
+
+// findHashBlocks returns the offsets of the blockSize-byte blocks of data whose digest
+// with newHash equals digest. Blocks start every step bytes, so a step of blockSize
+// checks aligned blocks and a step of 1 checks every offset. progress, if not nil, is
+// told how far the search has got, and stops it by returning false.
+func findHashBlocks(data []byte, newHash func() hash.Hash, digest []byte, blockSize, step int,
+	progress func(done, total int) bool) []int {
+	var matches []int
+	hasher := newHash()
+	sum := make([]byte, 0, hasher.Size())
+	for offset := 0; offset+blockSize <= len(data) && len(matches) < maxHashMatches; offset += step {
+		if offset/step%progressInterval == 0 && progress != nil && !progress(offset, len(data)) {
+			return nil
+		}
+		hasher.Reset()
+		hasher.Write(data[offset : offset+blockSize])
+		if bytes.Equal(hasher.Sum(sum[:0]), digest) {
+			matches = append(matches, offset)
+		}
+	}
+	return matches
+}
+
+// showHashSearch asks for a digest and block size and finds the blocks of the file with
+// that digest, to locate known content inside disk images and other containers
+func (h *HexDumpApp) showHashSearch() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Find Block by Hash"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	var names []string
+	for _, algorithm := range checksumAlgorithms {
+		names = append(names, algorithm.name)
+	}
+	algorithmSelect := widget.NewSelect(names, nil)
+	algorithmSelect.SetSelected("SHA-256")
+	digestEntry := widget.NewEntry()
+	digestEntry.SetPlaceHolder(lang.L("Hex digest"))
+	sizeEntry := widget.NewEntry()
+	sizeEntry.SetText("512")
+	slidingCheck := widget.NewCheck(lang.L("Check every offset, not just aligned blocks"), nil)
+
+	dialog.ShowForm(lang.L("Find Block by Hash"), lang.L("Find"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Algorithm"), algorithmSelect),
+		widget.NewFormItem(lang.L("Digest"), digestEntry),
+		widget.NewFormItem(lang.L("Block size"), sizeEntry),
+		widget.NewFormItem("", slidingCheck),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		algorithm := checksumAlgorithms[algorithmSelect.SelectedIndex()]
+		digest, err := parseHexBytes(digestEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		if size := algorithm.new().Size(); len(digest) != size {
+			dialog.ShowError(fmt.Errorf("a %s digest has %d bytes, not %d", algorithm.name, size, len(digest)), h.window)
+			return
+		}
+		blockSize, err := parseOffset(sizeEntry.Text)
+		if err != nil || blockSize <= 0 {
+			dialog.ShowError(fmt.Errorf("block size must be a positive number of bytes"), h.window)
+			return
+		}
+		step := blockSize
+		if slidingCheck.Checked {
+			step = 1
+		}
+
+		task := h.startTask(lang.L("Find Block by Hash"))
+		data := h.fileData
+		go func() {
+			defer h.recoverPanic()
+			matches := findHashBlocks(data, algorithm.new, digest, blockSize, step, task.report)
+			fyne.Do(func() {
+				show := func() { h.showHashMatches(matches, blockSize, algorithm.name) }
+				if h.finishTask(task, fmt.Sprintf("%d matching block(s)", len(matches)), show) {
+					show()
+				}
+			})
+		}()
+	}, h.window)
+}
+
+// showHashMatches lists the blocks found by showHashSearch. Clicking one selects it.
+func (h *HexDumpApp) showHashMatches(matches []int, blockSize int, algorithmName string) {
+	if len(matches) == 0 {
+		dialog.ShowInformation(lang.L("Find Block by Hash"),
+			fmt.Sprintf("No %d-byte block has the given %s digest.", blockSize, algorithmName), h.window)
+		return
+	}
+	h.setSelection(matches[0], matches[0]+blockSize)
+	h.goToOffset(matches[0])
+
+	list := widget.NewList(
+		func() int { return len(matches) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X-%08X", matches[id], matches[id]+blockSize-1))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		h.setSelection(matches[id], matches[id]+blockSize)
+		h.goToOffset(matches[id])
+	}
+
+	summary := fmt.Sprintf("%d matching blocks", len(matches))
+	if len(matches) == maxHashMatches {
+		summary = fmt.Sprintf("The first %d matching blocks", maxHashMatches)
+	}
+	window := h.app.NewWindow(fmt.Sprintf("Blocks by %s - %s", algorithmName, h.fileName))
+	window.SetContent(container.NewBorder(widget.NewLabel(summary), nil, nil, nil, list))
+	window.Resize(fyne.NewSize(350, 400))
+	window.Show()
+}
@@ -2,34 +2,27 @@ package main
 
 import (
 	"fmt"
-	"image/color"
-	"os"
+	"log/slog"
+	"slices"
 	"strings"
 	"unicode"
 	"unicode/utf16"
 	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 	nativedialog "github.com/sqweek/dialog"
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
 )
 
-// Package-scope variable to cache the debug setting at startup.
-var debugEnabled bool
-
-// init function runs once when the package is initialized.
-func init() {
-	debugEnabled = os.Getenv("HEXDUMP_DEBUG") != ""
-
-	if debugEnabled {
-		fmt.Println("DEBUG: HexDumpApp initialized with debug mode enabled")
-	}
-}
+// encodingNames lists the supported character encodings in display order
+var encodingNames = []string{"ISO Latin-1", "UTF-8", "UTF-16LE", "GB 18030"}
 
 // HexDumpApp represents the main application structure
 type HexDumpApp struct {
@@ -39,20 +32,208 @@ type HexDumpApp struct {
 	// File data
 	fileData []byte
 	fileName string
+	mapped   *mappedData   // Mapping of a large file, which fileData may be
+	retired  []*mappedData // Mappings of files loaded before, unmapped when no longer read
+
+	// Whole-data scans: the number of requests to rescan, so that the results of a
+	// superseded scan are dropped, and the scan running in the background
+	scanGeneration int
+	runningScan    *backgroundScan
 
 	// GUI components
 	// hexDisplay      *widget.Label // Removed
 	// charDisplay     *widget.Label // Removed
 	byteGroupSelect *widget.Select
 	encodingSelect  *widget.Select
+	byteOrderSelect *widget.Select
+	toolbarBox      *fyne.Container
+	toolbarGroups   []*toolbarGroup
+	toolbarHidden   []*toolbarGroup // Groups moved to the overflow menu
+	toolbarOverflow *widget.Button
+	positionSlider  *widget.Slider
 	statusLabel     *widget.Label
+	taskChips       *fyne.Container // Chips of the running background tasks
+	statusContent   *fyne.Container
+	taskTrayButton  *widget.Button
+	finishedTasks   []*backgroundTask // Newest first
+
+	// Welcome screen shown while no file is open, and the File → Open Recent item
+	welcome          *fyne.Container
+	welcomeRecent    *fyne.Container
+	welcomeShortcuts *fyne.Container
+	recentItem       *fyne.MenuItem
 	// scrollContainer *container.Scroll // Removed
 	dataList *widget.List // Added
 
+	// Animated jump of the data list in progress, or nil
+	scrollAnimation *fyne.Animation
+
+	// Rows created for the data list in accessible mode, which the keyboard moves
+	// the focus between
+	accessibleRows []*accessibleRow
+
 	// Settings
 	bytesPerGroup int
 	encoding      string
 	bytesPerLine  int
+	bigEndian     bool
+	recordSize    int  // Size of the records lines are aligned to, or 0 outside record mode
+	recordRuler   bool // Whether a rule marks the start of each record in record mode
+	signedValues  bool // Show groups as signed decimal values instead of hex bytes
+
+	// Selected byte range [selStart, selEnd), empty when the two are equal, the
+	// offset at which the selection was started with the mouse, and the caret offset
+	// at its moving end. With columnSelect, the selection is instead the rectangle of
+	// rows and byte columns between selAnchor and caret.
+	selStart     int
+	selEnd       int
+	selAnchor    int
+	caret        int
+	columnSelect bool
+
+	// While the caret is on the low nibble of its byte in the hex pane, lowNibbleCaret
+	// is 2*caret+1, so that moving the caret any other way puts it on the high nibble
+	lowNibbleCaret int
+
+	// Ranges selected with Ctrl+click besides the current selection, and whether a range
+	// is being added to them
+	extraRanges []byteRange
+	addingRange bool
+
+	// Bookmarks, sorted by offset, and their list in the side panel
+	bookmarks    []bookmark
+	bookmarkList *widget.List
+	tagFilter    string // Tag of the bookmarks shown, or "" for all
+
+	// Edit journal of the changes to fileData from the data as loaded, most recent last,
+	// the history tree it is a branch of, the step of the tree the data is at, the step
+	// it was at when last saved, and the edit group collecting edits into one step
+	journal   []edit
+	history   []historyStep
+	historyAt int
+	savedStep int
+	editGroup *editGroup
+
+	// While monitoring, closing monitorStop stops re-reading the file. changeCounts
+	// counts the changes to each byte seen while monitoring, or is nil.
+	monitorStop   chan struct{}
+	changeCounts  []int
+	monitorReads  int
+	hottestChange int // Largest count in changeCounts
+
+	// File data remembered by Take Snapshot, or nil, with its mapping if it is large, the
+	// ranges changed since, and the regions left out of the comparison
+	snapshot       []byte
+	snapshotMapped *mappedData
+	snapshotDiffs  []byteRange
+	diffMasks      []maskRegion
+
+	// Minimum length of detected padding, or 0 when detection is off, the padding found,
+	// whether it is collapsed, and the lines collapsed
+	paddingMin      int
+	padding         []paddingRegion
+	collapsePadding bool
+	collapsed       []collapsedLines
+
+	// Data after the end of the file's declared structure, or nil
+	overlay *overlayRegion
+
+	// Content the data is expected to have, whose differences are highlighted, or nil
+	reference *referencePattern
+
+	// Pattern lines must contain to be shown, or nil to show all lines
+	filter *lineFilter
+
+	// Whether file signatures are highlighted, and the signatures found
+	showSignatures bool
+	signatures     []signatureMatch
+
+	// Range marked by Mark Selection as Range A, or nil
+	rangeA *byteRange
+
+	// Page structure of the file if it is a SQLite database, or nil, and whether page
+	// boundaries and headers are shown
+	sqlite          *sqliteDatabase
+	showSQLitePages bool
+
+	// Pointers found by the last pointer scan, in order of offset
+	pointers []pointerHit
+
+	// Entries found by the last TLV walk, in order of offset with nested entries after
+	// their parents
+	tlvEntries []tlvEntry
+
+	// Matches of the last YARA scan, in order of offset
+	yaraMatches []yaraMatch
+
+	// Result of the last hash lookup of the file, or ""
+	hashLookup string
+
+	// Whether the file looks like text, and its line endings, for the status bar
+	textKind string
+
+	// Structure template, the fields it decoded at the caret or nil, any error from
+	// decoding, and the tree showing the fields in the Structure panel
+	template       *structTemplate
+	templateFields *parsedField
+	templateError  string
+	structureTree  *widget.Tree
+
+	// Record shown of each array of records in the decoded fields, by tree node ID, and
+	// the array the record navigator of the Structure panel steps through
+	recordIndexes map[widget.TreeNodeID]int
+	recordArray   widget.TreeNodeID
+
+	// Colored highlights of the decoded fields, whether they are drawn, and the legend
+	// listing them in the Fields panel
+	fieldHighlights []fieldHighlight
+	showFieldColors bool
+	fieldList       *widget.List
+
+	// Templates in the template library and the problems found while scanning it
+	templateLibrary  []libraryTemplate
+	templateProblems []string
+
+	// Symbols loaded from a linker map or ELF file, sorted by offset
+	symbols []symbol
+
+	// Address translation segments, whether the address column shows virtual
+	// addresses, and the address map window if it is open
+	segments      []addressSegment
+	showVirtual   bool
+	segmentWindow fyne.Window
+	segmentList   *widget.List
+
+	// Side panel tabs, the split holding the data list and the side panel, and the
+	// container showing either the split or the data list alone
+	panelTabs   *container.AppTabs
+	panels      []*sidePanel
+	panelSplit  *container.Split
+	contentHost *fyne.Container
+
+	// Hover tooltip, drawn on a layer above the window content
+	tooltipLayer *fyne.Container
+	tooltipBox   *fyne.Container
+	tooltipLabel *widget.Label
+
+	// Whether a Shift key is held down, for key combinations the driver does not
+	// report as shortcuts
+	shiftDown bool
+
+	// Keyboard shortcuts in effect by command ID, the shortcuts registered on the canvas,
+	// and the actions of those the driver reports as keys, by shortcut
+	shortcuts   map[string]string
+	registered  []fyne.Shortcut
+	keyCommands map[string]func()
+
+	// Set while the window is closing, so that closing detached panels does not
+	// forget that they were detached
+	closing bool
+
+	// Everything the window shows for this file, and the tabs of the window when it
+	// has more than one file open, or nil
+	content fyne.CanvasObject
+	tabs    *fileTabs
 
 	// Display metrics
 	totalLines int
@@ -66,6 +247,10 @@ func NewHexDumpApp(app fyne.App, window fyne.Window) *HexDumpApp {
 		bytesPerGroup: 1,
 		encoding:      "ISO Latin-1",
 		bytesPerLine:  16,
+		history:       newHistory(),
+
+		showFieldColors: true,
+		showSQLitePages: true,
 	}
 }
 
@@ -74,8 +259,9 @@ func (h *HexDumpApp) setupGUI() {
 	// Create menu
 	h.createMenu()
 
-	// Create main content area first (this initializes the display widgets)
-	content := h.createMainContent()
+	// Create main content area first (this initializes the display widgets), covered
+	// by the welcome screen until a file is open
+	content := container.NewStack(h.createMainContent(), h.createWelcome())
 
 	// Create toolbar (this can now safely set default values)
 	toolbar := h.createToolbar()
@@ -83,131 +269,428 @@ func (h *HexDumpApp) setupGUI() {
 	// Create status bar
 	statusBar := h.createStatusBar()
 
-	// Combine all components
-	mainContainer := container.NewBorder(
-		container.NewVBox(toolbar),
-		statusBar,
-		nil,
-		nil,
-		content,
-	)
+	// Combine all components, in the order Tab moves the focus through them
+	top := container.NewVBox(toolbar, h.createPositionSlider())
+	mainContainer := container.New(layout.NewBorderLayout(top, statusBar, nil, nil), top, content, statusBar)
+
+	h.content = container.NewStack(mainContainer, h.createTooltipLayer())
+	h.bindKeyboard()
+	openApps = append(openApps, h)
+	if h.tabs != nil {
+		return // The window is set up already, and the tabs show the content
+	}
+	h.window.SetContent(h.content)
+
+	// Ask before closing the window discards unsaved edits in any of its files
+	h.window.SetCloseIntercept(func() {
+		confirmDiscardAll(h.windowApps(), h.window.Close)
+	})
+	h.window.SetOnClosed(func() {
+		for _, app := range h.windowApps() {
+			app.closed()
+		}
+	})
+}
+
+// bindKeyboard sends the keys typed while no widget has the focus to this file
+func (h *HexDumpApp) bindKeyboard() {
+	if deskCanvas, ok := h.window.Canvas().(desktop.Canvas); ok {
+		deskCanvas.SetOnKeyDown(h.onKeyDown)
+		deskCanvas.SetOnKeyUp(h.onKeyUp)
+	}
+	h.window.Canvas().SetOnTypedRune(h.onTypedRune)
+	h.window.Canvas().SetOnTypedKey(h.onTypedKey)
+}
 
-	h.window.SetContent(mainContainer)
+// closed cleans up after the window or tab showing the file closed: it remembers where
+// the side panel split was dragged to, and closes detached panels
+func (h *HexDumpApp) closed() {
+	openApps = slices.DeleteFunc(openApps, func(app *HexDumpApp) bool { return app == h })
+	h.closing = true
+	h.stopMonitoring()
+	h.rememberPanelLayout()
+	saveSettings()
+	h.dockAllPanels()
+	h.fileData = nil
+	h.releaseMapping()
 }
 
-// createMenu creates the application menu
+// onKeyDown handles keys pressed while no widget has the focus, including the shortcuts
+// the driver reports as keys
+func (h *HexDumpApp) onKeyDown(event *fyne.KeyEvent) {
+	defer h.recoverPanic()
+	var modifier fyne.KeyModifier
+	if h.shiftDown {
+		modifier = fyne.KeyModifierShift
+	}
+	if action := h.keyCommands[formatShortcut(modifier, event.Name)]; action != nil {
+		action()
+		return
+	}
+
+	switch event.Name {
+	case desktop.KeyShiftLeft, desktop.KeyShiftRight:
+		h.shiftDown = true
+	case fyne.KeyPageDown:
+		h.moveByRecords(1)
+	case fyne.KeyPageUp:
+		h.moveByRecords(-1)
+	}
+}
+
+// onKeyUp handles keys released while no widget has the focus
+func (h *HexDumpApp) onKeyUp(event *fyne.KeyEvent) {
+	if event.Name == desktop.KeyShiftLeft || event.Name == desktop.KeyShiftRight {
+		h.shiftDown = false
+	}
+}
+
+// createMenu creates the application menu, with the keyboard shortcuts in the settings
 func (h *HexDumpApp) createMenu() {
-	fileMenu := fyne.NewMenu("File",
-		fyne.NewMenuItem("Open file...", h.openFile),
+	h.shortcuts = currentShortcuts()
+	exportItem := fyne.NewMenuItem(lang.L("Export"), nil)
+	exportItem.ChildMenu = fyne.NewMenu("",
+		h.commandItem("exportCSV"),
+		h.commandItem("exportText"),
+		h.commandItem("exportPatch"),
+		h.commandItem("exportViewPNG"),
+		h.commandItem("extractOverlay"),
+	)
+	h.recentItem = fyne.NewMenuItem(lang.L("Open Recent"), nil)
+	h.recentItem.ChildMenu = h.recentMenu()
+	fileMenu := fyne.NewMenu(lang.L("File"),
+		h.commandItem("open"),
+		h.recentItem,
+		h.commandItem("openClipboard"),
+		h.commandItem("openSample"),
+		h.commandItem("openRemote"),
+		h.commandItem("openEncrypted"),
+		h.commandItem("save"),
+		h.commandItem("saveAs"),
+		h.commandItem("reload"),
+		h.commandItem("saveSelection"),
+		exportItem,
 		fyne.NewMenuItemSeparator(),
-		fyne.NewMenuItem("Quit", func() {
-			h.app.Quit()
-		}),
+		h.commandItem("quit"),
 	)
 
-	optionsMenu := fyne.NewMenu("Options",
-		fyne.NewMenuItem("About", h.showAbout),
+	copyItem := fyne.NewMenuItem(lang.L("Copy As"), nil)
+	copyItem.ChildMenu = h.copyAsMenu()
+	codecItem := fyne.NewMenuItem(lang.L("Decode/Encode"), nil)
+	codecItem.ChildMenu = h.codecMenu()
+	editMenu := fyne.NewMenu(lang.L("Edit"),
+		h.commandItem("undo"),
+		h.commandItem("redo"),
+		h.commandItem("addCheckpoint"),
+		h.commandItem("revertCheckpoint"),
+		fyne.NewMenuItemSeparator(),
+		copyItem,
+		h.commandItem("copyViewImage"),
+		h.commandItem("fill"),
+		h.commandItem("xor"),
+		codecItem,
+		h.commandItem("applyPatch"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("selectAll"),
+		h.commandItem("selectLineEnd"),
+		h.commandItem("selectFileEnd"),
+		h.commandItem("selectBlock"),
+		h.commandItem("selectOverlay"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("goTo"),
 	)
 
-	mainMenu := fyne.NewMainMenu(fileMenu, optionsMenu)
-	h.window.SetMainMenu(mainMenu)
-}
-
-// createToolbar creates the toolbar with controls
-func (h *HexDumpApp) createToolbar() *fyne.Container {
-	// Open file button
-	openBtn := widget.NewButton("Open File...", h.openFile)
-
-	// Byte grouping selector
-	h.byteGroupSelect = widget.NewSelect(
-		[]string{"1 byte", "2 bytes", "4 bytes", "8 bytes", "16 bytes"},
-		h.onByteGroupChanged,
+	bookmarksMenu := fyne.NewMenu(lang.L("Bookmarks"),
+		h.commandItem("addBookmark"),
+		h.commandItem("showBookmarks"),
+		h.commandItem("nextBookmark"),
+		h.commandItem("previousBookmark"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("importBookmarks"),
+		h.commandItem("importOffsets"),
+		h.commandItem("clearMarkers"),
 	)
-	h.byteGroupSelect.SetSelected("1 byte")
 
-	// Encoding selector
-	h.encodingSelect = widget.NewSelect(
-		[]string{"ISO Latin-1", "UTF-8", "UTF-16LE", "GB 18030"},
-		h.onEncodingChanged,
+	toolsMenu := fyne.NewMenu(lang.L("Tools"),
+		h.commandItem("findInFiles"),
+		h.commandItem("findDuplicates"),
+		h.commandItem("hashSearch"),
+		h.commandItem("detectPadding"),
+		h.commandItem("sectorMap"),
+		h.commandItem("sqlitePages"),
+		h.commandItem("metadata"),
+		h.commandItem("endianness"),
+		h.commandItem("pointerScan"),
+		h.commandItem("clearPointers"),
+		h.commandItem("tlvWalker"),
+		h.commandItem("clearTLV"),
+		h.commandItem("findReferences"),
+		h.commandItem("hashLookup"),
+		h.commandItem("yaraScan"),
+		h.commandItem("clearYARA"),
+		h.commandItem("bytePairs"),
+		h.commandItem("visualization"),
+		h.commandItem("audioPreview"),
+		h.commandItem("rawImage"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("loadSymbols"),
+		h.commandItem("symbolList"),
+		h.commandItem("addressMap"),
+		h.commandItem("templateManager"),
+		h.commandItem("nextRecord"),
+		h.commandItem("previousRecord"),
+		h.commandItem("goToRecord"),
+		h.commandItem("extractColumn"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("takeSnapshot"),
+		h.commandItem("snapshotChanges"),
+		h.commandItem("clearSnapshot"),
+		h.commandItem("maskSelection"),
+		h.commandItem("maskRegions"),
+		h.commandItem("markRangeA"),
+		h.commandItem("compareRanges"),
+		h.commandItem("comparePattern"),
+		h.commandItem("clearPatternComparison"),
+		h.commandItem("monitor"),
+		h.commandItem("stopMonitoring"),
+		h.commandItem("batchConvert"),
 	)
-	h.encodingSelect.SetSelected("ISO Latin-1")
-
-	// Create toolbar content
-	toolbarContent := container.NewHBox(
-		openBtn,
-		widget.NewSeparator(),
-		widget.NewLabel("Byte Grouping:"),
-		h.byteGroupSelect,
-		widget.NewSeparator(),
-		widget.NewLabel("Encoding:"),
-		h.encodingSelect,
+
+	tooltipsItem := h.commandItem("encodingTooltips")
+	tooltipsItem.Checked = appSettings.EncodingTooltips
+	toggleTooltips := tooltipsItem.Action
+	tooltipsItem.Action = func() {
+		toggleTooltips()
+		tooltipsItem.Checked = appSettings.EncodingTooltips
+		h.window.MainMenu().Refresh()
+	}
+	signaturesItem := h.commandItem("signatures")
+	signaturesItem.Action = func() {
+		h.toggleSignatures()
+		signaturesItem.Checked = h.showSignatures
+		h.window.MainMenu().Refresh()
+	}
+	collapseItem := h.commandItem("collapsePadding")
+	collapseItem.Action = func() {
+		h.toggleCollapsePadding()
+		collapseItem.Checked = h.collapsePadding
+		h.window.MainMenu().Refresh()
+	}
+	sqliteItem := h.commandItem("sqliteOverlay")
+	sqliteItem.Checked = h.showSQLitePages
+	sqliteItem.Action = func() {
+		h.toggleSQLiteOverlay()
+		sqliteItem.Checked = h.showSQLitePages
+		h.window.MainMenu().Refresh()
+	}
+	signedItem := h.commandItem("signedValues")
+	signedItem.Action = func() {
+		h.toggleSignedValues()
+		signedItem.Checked = h.signedValues
+		h.window.MainMenu().Refresh()
+	}
+	accessibleItem := h.commandItem("accessibleMode")
+	accessibleItem.Checked = appSettings.AccessibleMode
+	accessibleItem.Action = func() {
+		h.toggleAccessibleMode()
+		accessibleItem.Checked = appSettings.AccessibleMode
+		h.window.MainMenu().Refresh()
+	}
+	viewMenu := fyne.NewMenu(lang.L("View"),
+		h.commandItem("sidePanel"),
+		h.commandItem("detachPanel"),
+		h.commandItem("dockPanels"),
+		fyne.NewMenuItemSeparator(),
+		signedItem,
+		h.commandItem("recordMode"),
+		signaturesItem,
+		collapseItem,
+		h.commandItem("filterLines"),
+		h.commandItem("showAllLines"),
+		sqliteItem,
+		tooltipsItem,
+		h.commandItem("customizeToolbar"),
+		accessibleItem,
+		h.commandItem("nextPane"),
+		h.commandItem("centerCaret"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("inspectorPanel"),
+		h.commandItem("stringsPanel"),
+		h.commandItem("bookmarksPanel"),
+		h.commandItem("searchPanel"),
+		h.commandItem("structurePanel"),
+		h.commandItem("checksumsPanel"),
+		h.commandItem("textPanel"),
+		h.commandItem("watchesPanel"),
+		h.commandItem("graphPanel"),
+		h.commandItem("fieldsPanel"),
+		h.commandItem("diskPanel"),
+		h.commandItem("bitsPanel"),
+		h.commandItem("historyPanel"),
 	)
 
-	// Create light background for toolbar
-	lightGray := color.RGBA{R: 45, G: 45, B: 45, A: 255}
-	background := canvas.NewRectangle(lightGray)
+	optionsMenu := fyne.NewMenu(lang.L("Options"),
+		h.commandItem("preferences"),
+		fyne.NewMenuItemSeparator(),
+		h.commandItem("showLog"),
+		h.commandItem("checkUpdates"),
+		h.commandItem("about"),
+	)
 
-	return container.NewStack(background, toolbarContent)
+	mainMenu := fyne.NewMainMenu(fileMenu, editMenu, viewMenu, bookmarksMenu, toolsMenu, optionsMenu)
+	h.window.SetMainMenu(mainMenu)
+	h.registerShortcuts(mainMenu)
+	h.refreshWelcome()
 }
 
 // createMainContent creates the main content area using widget.List.
 func (h *HexDumpApp) createMainContent() fyne.CanvasObject {
-	h.dataList = widget.NewList(
-		h.listLength,
-		h.listCreateItem,
-		h.listUpdateItem,
-	)
-	// Hide separators to eliminate space between line rectangles
-	h.dataList.HideSeparators = true
-	return h.dataList
+	h.dataList = h.newDataList()
+	h.panelSplit = container.NewHSplit(h.dataList, h.createSidePanels())
+	h.contentHost = container.NewStack()
+	h.layoutPanels()
+	return h.contentHost
 }
 
 // createStatusBar creates the status bar
 func (h *HexDumpApp) createStatusBar() *fyne.Container {
-	h.statusLabel = widget.NewLabel("Ready")
+	h.statusLabel = widget.NewLabel(lang.L("Ready"))
+	h.statusLabel.Truncation = fyne.TextTruncateEllipsis
 
-	// Create status bar content
-	statusContent := container.NewHBox(h.statusLabel)
+	// Create status bar content, with the running background tasks at the right
+	h.statusContent = container.NewBorder(nil, nil, nil, h.createTaskTray(), h.statusLabel)
 
-	// Create light background for status bar
-	lightGray := color.RGBA{R: 45, G: 45, B: 45, A: 255}
-	background := canvas.NewRectangle(lightGray)
-
-	return container.NewStack(background, statusContent)
+	return container.NewStack(newThemedRectangle(colorNameBar), h.statusContent)
 }
 
 // synchronizeScrolling function REMOVED
 
 // openFile opens a native Windows file dialog and loads the selected file
 func (h *HexDumpApp) openFile() {
-	filename, err := nativedialog.File().Filter("All Files", "*").Load()
+	h.confirmDiscardEdits(func() {
+		filename, err := nativedialog.File().Filter("All Files", "*").Load()
+		if err != nil {
+			// Check if user cancelled the dialog
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, h.window)
+			}
+			return
+		}
+
+		h.loadFileFromPath(filename)
+	})
+}
+
+// saveFileAs writes the file's data to a file chosen by the user
+func (h *HexDumpApp) saveFileAs() {
+	if h.fileName == "" {
+		return
+	}
+	filename, err := nativedialog.File().Filter("All Files", "*").Title("Save As").Save()
 	if err != nil {
-		// Check if user cancelled the dialog
 		if err.Error() != "Cancelled" {
 			dialog.ShowError(err, h.window)
 		}
 		return
 	}
 
-	h.loadFileFromPath(filename)
+	if err := h.writeFileData(filename); err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	h.fileName = filename
+	h.savedStep = h.historyAt
+	h.updateStatus()
 }
 
 // loadFileFromPath loads a file from the given file path
 func (h *HexDumpApp) loadFileFromPath(filePath string) {
-	// Read the entire file at once
-	fileData, err := os.ReadFile(filePath)
+	// Large files are mapped, so that only the pages shown are read
+	fileData, mapped, err := readFileData(filePath)
 	if err != nil {
+		slog.Warn("opening file", "path", filePath, "error", err)
 		dialog.ShowError(err, h.window)
 		return
 	}
+	slog.Info("opened file", "path", filePath, "size", len(fileData), "mapped", mapped != nil)
+	h.loadData(filePath, fileData)
+	h.mapped = mapped
+	addRecentFile(filePath)
+	h.refreshRecentFiles()
+}
+
+// loadData shows data as the contents of the file at filePath, which need not exist
+// until the data is saved
+func (h *HexDumpApp) loadData(filePath string, fileData []byte) {
+	h.stopMonitoring()
+	h.hideWelcome()
+	h.changeCounts = nil
+	h.hashLookup = ""
+	h.yaraMatches = nil
+	h.pointers = nil
+	h.tlvEntries = nil
+	h.rangeA = nil
+
+	// A snapshot is kept when the same file is reloaded
+	if filePath != h.fileName {
+		h.setSnapshot(nil, nil)
+	}
+	h.snapshotDiffs = nil
 
 	// Set file data and name
 	h.fileData = fileData
 	h.fileName = filePath
+	h.releaseMapping()
+	h.selStart, h.selEnd, h.selAnchor, h.caret = 0, 0, 0, 0
+	h.columnSelect, h.extraRanges = false, nil
+	h.bookmarks = nil
+	h.tagFilter = ""
+	h.symbols = nil
+	h.segments = nil
+	h.showVirtual = false
+	h.resetHistory()
+
+	// The results for the file loaded before mustn't show while a large file is scanned
+	h.applyScan(&dataScan{inputs: h.scanInputs()})
+	h.rescanData(0)
+	h.bookmarksChanged()
+	h.resetPanels()
 
 	// Update display and status
 	h.updateDisplay()
 	h.updateStatus()
+	h.syncPositionSlider(0)
+	if h.tabs != nil {
+		h.tabs.retitle(h)
+	}
+}
+
+// openInNewWindow opens a file in a new window, selecting length bytes at offset
+func (h *HexDumpApp) openInNewWindow(filePath string, offset, length int) {
+	window := h.app.NewWindow(lang.L("Hex Dump Utility"))
+	window.Resize(initialWindowSize())
+
+	other := NewHexDumpApp(h.app, window)
+	other.setupGUI()
+	other.loadFileFromPath(filePath)
+	other.setSelection(offset, offset+length)
+	other.goToOffset(offset)
+
+	window.Show()
+}
+
+// openDataInNewWindow shows data in a new window as the contents of filePath, which is
+// only written if the data is saved, and returns the new window's application
+func (h *HexDumpApp) openDataInNewWindow(filePath string, data []byte) *HexDumpApp {
+	window := h.app.NewWindow(lang.L("Hex Dump Utility"))
+	window.Resize(initialWindowSize())
+
+	other := NewHexDumpApp(h.app, window)
+	other.setupGUI()
+	other.loadData(filePath, data)
+
+	window.Show()
+	return other
 }
 
 // onByteGroupChanged handles byte grouping selection changes
@@ -231,6 +714,7 @@ func (h *HexDumpApp) onByteGroupChanged(value string) {
 func (h *HexDumpApp) onEncodingChanged(value string) {
 	h.encoding = value
 	h.updateDisplay()
+	h.refreshPanels()
 }
 
 // updateDisplay updates the dataList
@@ -245,13 +729,17 @@ func (h *HexDumpApp) updateDisplay() {
 		return
 	}
 
-	// Calculate total lines needed
-	h.totalLines = (len(h.fileData) + h.bytesPerLine - 1) / h.bytesPerLine
+	// Calculate total lines needed. Collapsing padding changes which row each byte is
+	// in, so keep the byte at the top of the list where it is.
+	anchor := h.scrollAnchor()
+	h.updateCollapsed()
+	h.totalLines = h.rowCount()
 
 	// The actual updating of list items will be handled by widget.List's
 	// UpdateItem callback, which will use generateHexLine and generateCharLine.
 	// For now, just refresh the list.
 	h.dataList.Refresh()
+	h.restoreScrollAnchor(anchor)
 }
 
 // listLength returns the number of items in the list (number of lines).
@@ -259,28 +747,29 @@ func (h *HexDumpApp) listLength() int {
 	if h.fileData == nil || h.bytesPerLine == 0 {
 		return 0
 	}
-	return (len(h.fileData) + h.bytesPerLine - 1) / h.bytesPerLine
+	return h.rowCount()
+}
+
+// newDataList creates the list showing the file's lines
+func (h *HexDumpApp) newDataList() *widget.List {
+	list := widget.NewList(
+		h.listLength,
+		h.listCreateItem,
+		h.listUpdateItem,
+	)
+	// Hide separators to eliminate space between line rectangles
+	list.HideSeparators = true
+	return list
 }
 
 // listCreateItem creates a new template item for the list.
 func (h *HexDumpApp) listCreateItem() fyne.CanvasObject {
-	// Use canvas.Text for better control over text positioning and size
-	hexText := canvas.NewText("HEX_PLACEHOLDER", color.White)
-	hexText.TextStyle.Monospace = true
-	hexText.TextSize = 12 // Smaller font size to fit in reduced height
-
-	charText := canvas.NewText("CHAR_PLACEHOLDER", color.White)
-	charText.TextStyle.Monospace = true
-	charText.TextSize = 12 // Smaller font size to fit in reduced height
-
-	// Create a spacer to separate hex data from character data for better readability
-	spacer := canvas.NewText("          ", color.Transparent) // Invisible spacer text
-	spacer.TextStyle.Monospace = true
-	// Set the same font size as hex and char text for alignment
-	spacer.TextSize = 12
-
-	// Use HBox with spacer between hex and character data
-	return container.NewHBox(hexText, spacer, charText)
+	if appSettings.AccessibleMode {
+		row := newAccessibleRow(h)
+		h.accessibleRows = append(h.accessibleRows, row)
+		return row
+	}
+	return newHexRow(h)
 }
 
 // listUpdateItem updates the content of a list item.
@@ -288,94 +777,112 @@ func (h *HexDumpApp) listUpdateItem(id widget.ListItemID, item fyne.CanvasObject
 	if h.fileData == nil {
 		return // No data to display
 	}
-	// The item is now an HBox container with hex, spacer, and char text objects
-	hbox := item.(*fyne.Container)
-	hexText := hbox.Objects[0].(*canvas.Text)
-	// spacer is at index 1, skip it
-	charText := hbox.Objects[2].(*canvas.Text)
-
-	offset := id * h.bytesPerLine
-	if offset >= len(h.fileData) {
-		// This case should ideally not be reached if listLength is correct
-		hexText.Text = ""
-		charText.Text = ""
-		hexText.Refresh()
-		charText.Refresh()
+	if row, ok := item.(*accessibleRow); ok {
+		row.setLine(id)
 		return
 	}
+	item.(*hexRow).setLine(id)
+}
 
-	hexAndAddrStr := h.generateHexLine(offset) // This includes address
-	charStr := h.generateCharLine(offset)
+// formatHex formats value as at least digits hex digits, in the case chosen in the
+// preferences
+func formatHex(value uint64, digits int) string {
+	if appSettings.LowercaseHex {
+		return fmt.Sprintf("%0*x", digits, value)
+	}
+	return fmt.Sprintf("%0*X", digits, value)
+}
 
-	hexText.Text = strings.TrimSpace(hexAndAddrStr)
+// valueSize returns the number of bytes shown as one value in the hex pane: a whole
+// group, up to 8 bytes, in signed mode, and a single byte otherwise
+func (h *HexDumpApp) valueSize() int {
+	if !h.signedValues {
+		return 1
+	}
+	return min(h.bytesPerGroup, 8)
+}
 
-    // Maybe pad hexText.Text with spaces to align the character text with the previous line.  Here,
-    // 10 is the width of the hex address column (including the trailing space), 32 is the maximum
-    // number of hex digits in one line, and `(16/h.bytesPerGroup) - 1` is the maximum number of
-    // spaces between the hex digits in one line.
-    hexAndAddrColumns := 10 + 32 + (16/h.bytesPerGroup) - 1
-    hexTextLength := len(hexText.Text)
-    paddingLength := hexAndAddrColumns - hexTextLength
+// cellsPerByte returns the number of text columns used by each byte in the hex pane.
+// Signed values need 4 columns for a byte ("-128") and fit in 3 per byte for larger ones.
+func (h *HexDumpApp) cellsPerByte() int {
+	switch {
+	case !h.signedValues:
+		return 2
+	case h.valueSize() == 1:
+		return 4
+	default:
+		return 3
+	}
+}
 
-    if paddingLength > 0 {
-        hexText.Text += strings.Repeat(" ", paddingLength)
-    }
+// formatSignedValue formats the value starting at offset as a signed decimal integer
+// in the chosen byte order, right-aligned in the columns of its bytes. The bytes of an
+// incomplete value before lineEnd are shown in hex instead.
+func (h *HexDumpApp) formatSignedValue(offset, lineEnd int) string {
+	size := h.valueSize()
+	width := size * h.cellsPerByte()
+	if offset+size > lineEnd {
+		var builder strings.Builder
+		for index := offset; index < lineEnd; index++ {
+			builder.WriteString(fmt.Sprintf("%*s", h.cellsPerByte(), formatHex(uint64(h.fileData[index]), 2)))
+		}
+		return builder.String()
+	}
 
-	charText.Text = strings.TrimSpace(charStr)
-	hexText.Refresh()
-	charText.Refresh()
+	data := h.fileData[offset : offset+size]
+	var value int64
+	switch size {
+	case 1:
+		value = int64(int8(data[0]))
+	case 2:
+		value = int64(int16(h.byteOrder().Uint16(data)))
+	case 4:
+		value = int64(int32(h.byteOrder().Uint32(data)))
+	default:
+		value = int64(h.byteOrder().Uint64(data))
+	}
+	return fmt.Sprintf("%*d", width, value)
+}
 
-	// Set a custom height for this list item to reduce vertical padding
-	// Use 18 pixels to accommodate the smaller 12pt font with minimal padding
-	h.dataList.SetItemHeight(id, 18) // Slightly increased to prevent text clipping
+// toggleSignedValues switches the hex pane between hex bytes and signed decimal values
+func (h *HexDumpApp) toggleSignedValues() {
+	h.signedValues = !h.signedValues
+	h.updateDisplay()
 }
 
 // generateHexLine generates a single hex line
 func (h *HexDumpApp) generateHexLine(offset int) string {
 	var builder strings.Builder
-	dataLen := len(h.fileData)
 
 	// Write address
-	builder.WriteString(fmt.Sprintf("%08X: ", offset))
+	builder.WriteString(h.formatAddress(offset) + ": ")
 
 	// Write hex bytes
-	lineEnd := offset + h.bytesPerLine
-	if lineEnd > dataLen {
-		lineEnd = dataLen
-	}
-
-	for index := offset; index < lineEnd; index += h.bytesPerGroup {
-		groupEnd := index + h.bytesPerGroup
-		if groupEnd > lineEnd {
-			groupEnd = lineEnd
-		}
-
-		// Write bytes in group
-		for byteIndex := index; byteIndex < groupEnd; byteIndex++ {
-			builder.WriteString(fmt.Sprintf("%02X", h.fileData[byteIndex]))
+	lineEnd := h.lineEnd(offset)
+
+	separator := h.groupSeparator()
+	for index := 0; index < h.bytesPerLine; index++ {
+		// Write the separators before the byte; a missing byte is padded with spaces
+		if index > 0 && index%h.bytesPerGroup == 0 {
+			if offset+index < lineEnd {
+				builder.WriteString(separator)
+			} else {
+				builder.WriteString(" ")
+			}
 		}
-
-		// Add space after group (except for last group on line)
-		if groupEnd < lineEnd {
+		if h.hasMidLineGap(index) {
 			builder.WriteString(" ")
 		}
-	}
 
-	// Pad line if necessary
-	bytesOnLine := lineEnd - offset
-	if bytesOnLine < h.bytesPerLine {
-		// Calculate padding needed
-		groupsOnLine := (bytesOnLine + h.bytesPerGroup - 1) / h.bytesPerGroup
-		totalGroups := (h.bytesPerLine + h.bytesPerGroup - 1) / h.bytesPerGroup
-		missingGroups := totalGroups - groupsOnLine
-
-		// Add padding for missing bytes
-		for index := bytesOnLine; index < h.bytesPerLine; index++ {
-			builder.WriteString("  ")
-		}
-		// Add padding for missing group separators
-		for index := 0; index < missingGroups; index++ {
-			builder.WriteString(" ")
+		switch {
+		case offset+index >= lineEnd:
+			builder.WriteString(strings.Repeat(" ", h.cellsPerByte()))
+		case h.signedValues:
+			if index%h.valueSize() == 0 {
+				builder.WriteString(h.formatSignedValue(offset+index, lineEnd))
+			}
+		default:
+			builder.WriteString(formatHex(uint64(h.fileData[offset+index]), 2))
 		}
 	}
 
@@ -389,32 +896,25 @@ func (h *HexDumpApp) generateHexDisplay() string {
 	var builder strings.Builder
 	dataLen := len(h.fileData)
 
-	for offset := 0; offset < dataLen; offset += h.bytesPerLine {
+	for offset := 0; offset < dataLen; offset = h.lineEnd(offset) {
 		builder.WriteString(h.generateHexLine(offset))
 	}
 
 	return builder.String()
 }
 
-// generateCharLine generates a single character line
+// generateCharLine generates a single character line. In UTF-8, grapheme clusters are
+// combined into one character, and may continue past the end of the line.
 func (h *HexDumpApp) generateCharLine(offset int) string {
-	dataLen := len(h.fileData)
-	lineEnd := offset + h.bytesPerLine
-	if lineEnd > dataLen {
-		lineEnd = dataLen
+	lineEnd := h.lineEnd(offset)
+	if h.encoding == "UTF-8" {
+		var builder strings.Builder
+		for _, cell := range h.lineCells(offset, lineEnd) {
+			builder.WriteString(cell.text)
+		}
+		return builder.String()
 	}
-
-	lineData := h.fileData[offset:lineEnd]
-	chars := h.bytesToChars(lineData)
-
-//	// Pad the character string with spaces to align the last line.
-//	numRunes := utf8.RuneCountInString(chars)
-//	if numRunes < h.bytesPerLine {
-//		padding := strings.Repeat(" ", h.bytesPerLine-numRunes)
-//		chars += padding
-//	}
-
-	return chars // Newline might not be needed for List items
+	return h.bytesToChars(h.fileData[offset:lineEnd])
 }
 
 // generateCharDisplay generates the character display content (legacy method for compatibility)
@@ -423,7 +923,7 @@ func (h *HexDumpApp) generateCharDisplay() string {
 	var builder strings.Builder
 	dataLen := len(h.fileData)
 
-	for offset := 0; offset < dataLen; offset += h.bytesPerLine {
+	for offset := 0; offset < dataLen; offset = h.lineEnd(offset) {
 		builder.WriteString(h.generateCharLine(offset))
 		builder.WriteString("\n") // Add newline if generating full display text
 	}
@@ -458,29 +958,17 @@ func (h *HexDumpApp) bytesToLatin1(data []byte) string {
 			builder.WriteRune(rune(b))
 		} else {
 			// Non-printable
-			builder.WriteString(".")
+			builder.WriteString(fallbackText("ISO Latin-1", rune(b)))
 		}
 	}
 	return builder.String()
 }
 
-// bytesToUTF8 converts bytes to UTF-8 characters
+// bytesToUTF8 converts bytes to UTF-8 characters, combining grapheme clusters
 func (h *HexDumpApp) bytesToUTF8(data []byte) string {
 	var builder strings.Builder
-	for len(data) > 0 {
-		r, size := utf8.DecodeRune(data)
-		if r == utf8.RuneError && size == 1 {
-			// Invalid UTF-8
-			builder.WriteString(".")
-			data = data[1:]
-		} else {
-			if unicode.IsPrint(r) {
-				builder.WriteRune(r)
-			} else {
-				builder.WriteString(".")
-			}
-			data = data[size:]
-		}
+	for _, cell := range utf8Cells(data, 0, 0, len(data)) {
+		builder.WriteString(cell.text)
 	}
 	return builder.String()
 }
@@ -492,8 +980,8 @@ func (h *HexDumpApp) bytesToUTF16LE(data []byte) string {
 	// Ensure we have pairs of bytes
 	for index := 0; index < len(data); index += 2 {
 		if index+1 >= len(data) {
-			// Odd number of bytes, show as dot
-			builder.WriteString(".")
+			// Odd number of bytes, which don't decode
+			builder.WriteString(fallbackText("UTF-16LE", utf8.RuneError))
 			break
 		}
 
@@ -504,10 +992,10 @@ func (h *HexDumpApp) bytesToUTF16LE(data []byte) string {
 
 		// Convert to rune
 		runes := utf16.Decode([]uint16{codeUnit})
-		if len(runes) > 0 && unicode.IsPrint(runes[0]) {
+		if unicode.IsPrint(runes[0]) {
 			builder.WriteRune(runes[0])
 		} else {
-			builder.WriteString(".")
+			builder.WriteString(fallbackText("UTF-16LE", runes[0]))
 		}
 	}
 	return builder.String()
@@ -518,10 +1006,10 @@ func (h *HexDumpApp) bytesToGB18030(data []byte) string {
 	decoder := simplifiedchinese.GB18030.NewDecoder()
 	result, _, err := transform.Bytes(decoder, data)
 	if err != nil {
-		// Fallback to showing dots for invalid sequences
+		// Show invalid sequences as a fallback character for each byte
 		var builder strings.Builder
 		for range data {
-			builder.WriteString(".")
+			builder.WriteString(fallbackText("GB 18030", utf8.RuneError))
 		}
 		return builder.String()
 	}
@@ -532,7 +1020,7 @@ func (h *HexDumpApp) bytesToGB18030(data []byte) string {
 		if unicode.IsPrint(r) {
 			builder.WriteRune(r)
 		} else {
-			builder.WriteString(".")
+			builder.WriteString(fallbackText("GB 18030", r))
 		}
 	}
 	return builder.String()
@@ -540,14 +1028,76 @@ func (h *HexDumpApp) bytesToGB18030(data []byte) string {
 
 // updateStatus updates the status bar
 func (h *HexDumpApp) updateStatus() {
+	if h.statusLabel == nil {
+		return
+	}
+	h.refreshPanels()
+
 	if h.fileName == "" {
-		h.statusLabel.SetText("Ready")
-	} else {
-		h.statusLabel.SetText(fmt.Sprintf("File: %s | Size: %d bytes", h.fileName, len(h.fileData)))
+		h.statusLabel.SetText(lang.L("Ready"))
+		return
+	}
+
+	status := lang.L("File: {{.Name}} | Size: {{.Size}} bytes", map[string]any{"Name": h.fileName, "Size": len(h.fileData)})
+	if h.textKind != "" {
+		status += " | " + h.textKind
+	}
+	if selection := h.selectionStatus(); selection != "" {
+		status += " | " + selection
+		if label := h.bookmarkAt(h.selStart); label != "" {
+			status += " | " + lang.L("Bookmark:") + " " + label
+		}
+	}
+	if h.hashLookup != "" {
+		status += " | " + h.hashLookup
+	}
+	if rangeA := h.rangeAStatus(); rangeA != "" {
+		status += " | " + rangeA
+	}
+	if monitor := h.monitorStatus(); monitor != "" {
+		status += " | " + monitor
+	}
+	if overlay := h.overlayStatus(); overlay != "" {
+		status += " | " + overlay
+	}
+	if snapshot := h.snapshotStatus(); snapshot != "" {
+		status += " | " + snapshot
+	}
+	if reference := h.referenceStatus(); reference != "" {
+		status += " | " + reference
+	}
+	if record := h.recordStatus(); record != "" {
+		status += " | " + record
+	}
+	if padding := h.paddingStatus(); padding != "" {
+		status += " | " + padding
+	}
+	if filter := h.filterStatus(); filter != "" {
+		status += " | " + filter
+	}
+	if page := h.sqliteStatus(); page != "" {
+		status += " | " + page
+	}
+	if pointer := h.pointerStatus(); pointer != "" {
+		status += " | " + pointer
+	}
+	if tlv := h.tlvStatus(); tlv != "" {
+		status += " | " + tlv
+	}
+	if rules := h.yaraAt(h.caret); rules != "" {
+		status += " | " + lang.L("YARA:") + " " + rules
+	}
+	if name := h.symbolAt(h.caret); name != "" {
+		status += " | " + lang.L("Symbol:") + " " + name
+	}
+	if h.isModified() {
+		status += " | " + lang.L("Modified")
 	}
+	h.statusLabel.SetText(status)
 }
 
 // showAbout shows the about dialog
 func (h *HexDumpApp) showAbout() {
-	dialog.ShowInformation("About", "Hex Dump Utility\n\nA graphical hex dump tool built with Fyne.\nSupports multiple byte groupings and character encodings.", h.window)
+	dialog.ShowInformation(lang.L("About"), lang.L("Hex Dump Utility {{.Version}}\n\nA graphical hex dump tool built with Fyne.\nSupports multiple byte groupings and character encodings.",
+		map[string]any{"Version": appVersion}), h.window)
 }
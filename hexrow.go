@@ -0,0 +1,522 @@
+package main
+
+import (
+	"image/color"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Layout constants for the rows of the data list
+const (
+	charPaneGap   = 4  // Number of blank columns between the hex and character panes
+	maxHighlights = 64 // Upper bound on highlight rectangles drawn in one row
+)
+
+// hexRow is one line of the data list, showing the address, hex bytes, and decoded
+// characters of bytesPerLine bytes, with highlighted byte ranges drawn behind the text
+type hexRow struct {
+	widget.BaseWidget
+	h    *HexDumpApp
+	line int
+}
+
+// newHexRow creates a row widget for the given application
+func newHexRow(h *HexDumpApp) *hexRow {
+	row := &hexRow{h: h, line: -1}
+	row.ExtendBaseWidget(row)
+	return row
+}
+
+// setLine changes the line displayed by the row
+func (r *hexRow) setLine(line int) {
+	r.line = line
+	r.Refresh()
+}
+
+// offsetAt returns the file offset of the byte displayed at pos, which is relative to
+// the row and may lie above or below it when dragging, or -1 if there is no data
+func (r *hexRow) offsetAt(pos fyne.Position) int {
+	h := r.h
+	if len(h.fileData) == 0 || r.line < 0 {
+		return -1
+	}
+
+	line := r.line
+	if height := r.Size().Height; height > 0 {
+		line += int(pos.Y / height)
+		if pos.Y < 0 {
+			line--
+		}
+	}
+	if line < 0 {
+		return 0
+	}
+
+	lineStart := h.rowStart(line)
+	if h.collapsedAt(lineStart) != nil {
+		return min(lineStart, len(h.fileData)-1)
+	}
+	column := int(pos.X / charCellWidth())
+	index := 0
+	if column >= h.charPaneColumn() {
+		// Character pane: find the first byte decoded into the column
+		lineEnd := h.lineEnd(lineStart)
+		if lineStart < lineEnd {
+			charColumns := h.charColumns(lineStart, lineEnd)
+			for index < lineEnd-lineStart-1 && charColumns[index+1] <= column-h.charPaneColumn() {
+				index++
+			}
+		}
+	} else {
+		// Hex pane: find the last byte starting at or before the column
+		for index < h.bytesPerLine-1 && h.hexColumnOf(index+1) <= column {
+			index++
+		}
+	}
+
+	return min(lineStart+index, h.lineEnd(lineStart)-1, len(h.fileData)-1)
+}
+
+// lowNibbleAt reports whether pos, relative to the row, is on the second hex digit of
+// the byte at offset in the hex pane
+func (r *hexRow) lowNibbleAt(pos fyne.Position, offset int) bool {
+	h := r.h
+	if h.signedValues || h.collapsedAt(h.rowStart(r.line)) != nil {
+		return false
+	}
+	column := int(pos.X / charCellWidth())
+	return column < h.charPaneColumn() && column > h.hexColumnOf(offset-h.rowStart(r.line))
+}
+
+// MouseDown implements desktop.Mouseable. A click starts a new selection at the byte
+// under the pointer, a shift-click extends the current selection to it, a Ctrl+click
+// follows a pointer found by the pointer scan, and a right-click opens the context menu.
+func (r *hexRow) MouseDown(event *desktop.MouseEvent) {
+	offset := r.offsetAt(event.Position)
+	if offset < 0 {
+		return
+	}
+	if event.Button == desktop.MouseButtonSecondary {
+		r.h.showContextMenu(offset, event.AbsolutePosition)
+		return
+	}
+	if event.Button != desktop.MouseButtonPrimary {
+		return
+	}
+	if event.Modifier&fyne.KeyModifierShortcutDefault != 0 && r.h.followPointer(offset) {
+		return
+	}
+	r.h.endAddingRange()
+	if event.Modifier&fyne.KeyModifierShortcutDefault != 0 {
+		r.h.addSelectionRange(offset)
+	} else if event.Modifier&fyne.KeyModifierAlt != 0 {
+		r.h.startColumnSelection(offset)
+	} else if event.Modifier&fyne.KeyModifierShift != 0 && r.h.hasSelection() {
+		r.h.extendSelection(offset)
+	} else {
+		r.h.selAnchor = offset
+		r.h.caret = offset
+		r.h.lowNibbleCaret = 0
+		if r.lowNibbleAt(event.Position, offset) {
+			r.h.lowNibbleCaret = 2*offset + 1
+		}
+		r.h.setSelection(offset, offset+1)
+	}
+}
+
+// MouseIn implements desktop.Hoverable
+func (r *hexRow) MouseIn(event *desktop.MouseEvent) {
+	r.MouseMoved(event)
+}
+
+// MouseMoved implements desktop.Hoverable, showing the tooltip for the byte under the pointer
+func (r *hexRow) MouseMoved(event *desktop.MouseEvent) {
+	r.h.hoverByte(r.offsetAt(event.Position), event.AbsolutePosition)
+}
+
+// MouseOut implements desktop.Hoverable
+func (r *hexRow) MouseOut() {
+	r.h.hideTooltip()
+}
+
+// MouseUp implements desktop.Mouseable, ending a range added with Ctrl+click
+func (r *hexRow) MouseUp(*desktop.MouseEvent) {
+	r.h.endAddingRange()
+}
+
+// Tapped implements fyne.Tappable. Taps are handled by MouseDown, and consuming them
+// here stops the list from selecting the whole row.
+func (r *hexRow) Tapped(*fyne.PointEvent) {}
+
+// Dragged implements fyne.Draggable, extending the selection to the byte under the pointer
+func (r *hexRow) Dragged(event *fyne.DragEvent) {
+	if offset := r.offsetAt(event.Position); offset >= 0 && r.h.columnSelect {
+		r.h.extendColumnSelection(offset)
+	} else if offset >= 0 {
+		r.h.extendSelection(offset)
+	}
+}
+
+// DragEnd implements fyne.Draggable
+func (r *hexRow) DragEnd() {
+	r.h.endAddingRange()
+}
+
+// CreateRenderer implements fyne.Widget
+func (r *hexRow) CreateRenderer() fyne.WidgetRenderer {
+	hexText := canvas.NewText("", color.White)
+	hexText.TextStyle.Monospace = true
+	hexText.TextSize = rowTextSize()
+
+	charText := canvas.NewText("", color.White)
+	charText.TextStyle.Monospace = true
+	charText.TextSize = rowTextSize()
+
+	checkText := canvas.NewText("", theme.Color(colorNameLineChecksum))
+	checkText.TextStyle.Monospace = true
+	checkText.TextSize = rowTextSize()
+
+	boundary := canvas.NewRectangle(theme.Color(colorNameRecordBoundary))
+	boundary.Hide()
+
+	caret := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	caret.Hide()
+
+	renderer := &hexRowRenderer{row: r, hexText: hexText, charText: charText, checkText: checkText,
+		boundary: boundary, caret: caret}
+	renderer.Refresh()
+	return renderer
+}
+
+// charCellWidth returns the width of one monospace character cell in a row
+func charCellWidth() float32 {
+	return fyne.MeasureText("0", rowTextSize(), fyne.TextStyle{Monospace: true}).Width
+}
+
+// hexColumnOf returns the text column at which byte number index of a line starts
+// in the hex pane, counting the address column
+func (h *HexDumpApp) hexColumnOf(index int) int {
+	return h.addressColumns() + index*h.cellsPerByte() + h.separatorsBefore(index)
+}
+
+// separatorsBefore returns the number of separator columns before byte number index
+// of a line, counting a separator after the last byte of a full line
+func (h *HexDumpApp) separatorsBefore(index int) int {
+	separators := index / h.bytesPerGroup
+	if index >= 8 && h.hasMidLineGap(8) {
+		separators++
+	}
+	return separators
+}
+
+// hasMidLineGap reports whether the extra gap after the first 8 bytes of a line comes
+// before byte number index. The gap is only inserted between groups.
+func (h *HexDumpApp) hasMidLineGap(index int) bool {
+	return appSettings.MidLineGap && index == 8 && h.bytesPerLine > 8 && 8%h.bytesPerGroup == 0
+}
+
+// groupSeparator returns the text between groups of bytes in the hex pane
+func (h *HexDumpApp) groupSeparator() string {
+	if appSettings.GroupSeparator == separatorDash {
+		return "-"
+	}
+	return " "
+}
+
+// charPaneColumn returns the text column at which the character pane starts
+func (h *HexDumpApp) charPaneColumn() int {
+	return h.hexColumns() + charPaneGap
+}
+
+// hexRowRenderer draws a hexRow
+type hexRowRenderer struct {
+	row        *hexRow
+	hexText    *canvas.Text
+	charText   *canvas.Text
+	checkText  *canvas.Text // The line checksum, when shown
+	highlights []*canvas.Rectangle
+	boundary   *canvas.Rectangle // Marks the start of a record in record mode, or of a SQLite page
+	caret      *canvas.Rectangle // Underlines the hex digit that typing replaces
+	size       fyne.Size
+}
+
+// Layout implements fyne.WidgetRenderer
+func (r *hexRowRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.Refresh() // Highlight rectangles span the full row height
+}
+
+// layoutText positions the hex and character text within the row
+func (r *hexRowRenderer) layoutText() {
+	cellWidth := charCellWidth()
+	textHeight := r.hexText.MinSize().Height
+	top := (r.size.Height - textHeight) / 2
+
+	r.hexText.Move(fyne.NewPos(0, top))
+	r.hexText.Resize(r.hexText.MinSize())
+	r.charText.Move(fyne.NewPos(float32(r.row.h.charPaneColumn())*cellWidth, top))
+	r.charText.Resize(r.charText.MinSize())
+	r.checkText.Move(fyne.NewPos(float32(r.row.h.checksumColumn())*cellWidth, top))
+	r.checkText.Resize(r.checkText.MinSize())
+}
+
+// MinSize implements fyne.WidgetRenderer
+func (r *hexRowRenderer) MinSize() fyne.Size {
+	cellWidth := charCellWidth()
+	width := float32(r.row.h.charPaneColumn()+r.row.h.bytesPerLine) * cellWidth
+	if showsLineChecksum() {
+		width = float32(r.row.h.checksumColumn()+2) * cellWidth
+	}
+	return fyne.NewSize(width, rowHeight())
+}
+
+// Objects implements fyne.WidgetRenderer
+func (r *hexRowRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, 0, len(r.highlights)+4)
+	for _, rect := range r.highlights {
+		objects = append(objects, rect)
+	}
+	return append(objects, r.hexText, r.charText, r.checkText, r.boundary, r.caret)
+}
+
+// Refresh implements fyne.WidgetRenderer
+func (r *hexRowRenderer) Refresh() {
+	h := r.row.h
+	offset := h.rowStart(r.row.line)
+	r.checkText.Color = theme.Color(colorNameLineChecksum)
+	r.boundary.FillColor = theme.Color(colorNameRecordBoundary)
+
+	if r.row.line < 0 || offset >= len(h.fileData) {
+		r.hexText.Text = ""
+		r.charText.Text = ""
+		r.checkText.Text = ""
+		r.highlights = r.highlights[:0]
+	} else if lines := h.collapsedAt(offset); lines != nil {
+		r.hexText.Text = h.collapsedRowText(offset, lines)
+		r.charText.Text = ""
+		r.checkText.Text = ""
+		r.updateCollapsedHighlights(offset, lines)
+	} else {
+		r.hexText.Text = h.generateHexLine(offset)
+		r.charText.Text = h.generateCharLine(offset)
+		r.checkText.Text = h.generateChecksumLine(offset)
+		r.updateHighlights(offset)
+	}
+
+	if r.row.line >= 0 && (h.startsRecord(offset) || h.startsSQLitePage(offset)) {
+		r.boundary.Move(fyne.NewPos(0, 0))
+		r.boundary.Resize(fyne.NewSize(r.size.Width, 1))
+		r.boundary.Show()
+	} else {
+		r.boundary.Hide()
+	}
+	r.layoutCaret(offset)
+
+	r.layoutText()
+	r.hexText.Refresh()
+	r.charText.Refresh()
+	r.checkText.Refresh()
+	r.boundary.Refresh()
+	r.caret.Refresh()
+	for _, rect := range r.highlights {
+		rect.Refresh()
+	}
+}
+
+// layoutCaret underlines the nibble at the caret if it is in the line starting at offset
+func (r *hexRowRenderer) layoutCaret(offset int) {
+	h := r.row.h
+	r.caret.FillColor = theme.Color(theme.ColorNamePrimary)
+	if r.row.line < 0 || h.caret < offset || h.caret >= h.lineEnd(offset) || !h.editsNibbles() ||
+		h.collapsedAt(offset) != nil {
+		r.caret.Hide()
+		return
+	}
+	cellWidth := charCellWidth()
+	column := h.hexColumnOf(h.caret-offset) + h.caretNibble()
+	r.caret.Move(fyne.NewPos(float32(column)*cellWidth, r.size.Height-2))
+	r.caret.Resize(fyne.NewSize(cellWidth, 2))
+	r.caret.Show()
+}
+
+// Destroy implements fyne.WidgetRenderer
+func (r *hexRowRenderer) Destroy() {}
+
+// updateHighlights rebuilds the highlight rectangles for the line starting at offset
+func (r *hexRowRenderer) updateHighlights(offset int) {
+	h := r.row.h
+	lineEnd := h.lineEnd(offset)
+	cellWidth := charCellWidth()
+	charColumns := h.charColumns(offset, lineEnd)
+
+	used := 0
+	addRect := func(fill color.Color, column, width int) {
+		if used >= maxHighlights {
+			return
+		}
+		if used == len(r.highlights) {
+			r.highlights = append(r.highlights, canvas.NewRectangle(fill))
+		}
+		rect := r.highlights[used]
+		rect.FillColor = fill
+		rect.Move(fyne.NewPos(float32(column)*cellWidth, 0))
+		rect.Resize(fyne.NewSize(float32(width)*cellWidth, r.size.Height))
+		used++
+	}
+
+	for _, span := range h.highlightSpans(offset, lineEnd) {
+		first := span.start - offset
+		last := span.end - offset - 1
+
+		// Hex pane: cover the digits of every byte in the span, plus the separators between them
+		startColumn := h.hexColumnOf(first)
+		endColumn := h.hexColumnOf(last) + h.cellsPerByte()
+		addRect(span.color, startColumn, endColumn-startColumn)
+
+		// Character pane: cover the characters decoded from the bytes in the span
+		charStart := charColumns[first]
+		next := last + 1
+		for charColumns[next] == charColumns[last] {
+			next++
+		}
+		charEnd := charColumns[next]
+		addRect(span.color, h.charPaneColumn()+charStart, charEnd-charStart)
+	}
+
+	r.highlights = r.highlights[:used]
+}
+
+// updateCollapsedHighlights rebuilds the highlight rectangles for a row of collapsed
+// padding starting at offset: its text is drawn on the padding color, or on the
+// selection color when the selection reaches into it
+func (r *hexRowRenderer) updateCollapsedHighlights(offset int, lines *collapsedLines) {
+	h := r.row.h
+	var fill color.Color = theme.Color(colorNamePadding)
+	if lines.hidden {
+		fill = color.Transparent
+	}
+	if h.selStart < h.rowEnd(offset) && h.selEnd > offset {
+		fill = theme.Color(colorNameSelection)
+	}
+	if len(r.highlights) == 0 {
+		r.highlights = append(r.highlights, canvas.NewRectangle(fill))
+	}
+	r.highlights = r.highlights[:1]
+	cellWidth := charCellWidth()
+	start := h.addressColumns()
+	r.highlights[0].FillColor = fill
+	r.highlights[0].Move(fyne.NewPos(float32(start)*cellWidth, 0))
+	r.highlights[0].Resize(fyne.NewSize(float32(utf8.RuneCountInString(r.hexText.Text)-start)*cellWidth, r.size.Height))
+}
+
+// highlightSpan is a range of bytes [start, end) drawn with a background color
+type highlightSpan struct {
+	start, end int
+	color      color.Color
+}
+
+// highlightSpans returns the highlighted byte ranges intersecting [lineStart, lineEnd),
+// clipped to that range and in drawing order
+func (h *HexDumpApp) highlightSpans(lineStart, lineEnd int) []highlightSpan {
+	spans := h.annotationSpans(lineStart, lineEnd)
+
+	if h.selEnd > h.selStart {
+		start := max(h.selStart, lineStart)
+		end := min(h.selEnd, lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(colorNameSelection)})
+		}
+	}
+	for _, r := range h.extraRanges {
+		if start, end := max(r.start, lineStart), min(r.end, lineEnd); start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(colorNameSelection)})
+		}
+	}
+	if h.columnSelect {
+		firstRow, lastRow, firstColumn, lastColumn := h.columnRect()
+		if row := h.rowOf(lineStart); row >= firstRow && row <= lastRow {
+			if r := h.columnRowRange(lineStart, firstColumn, lastColumn); r.start < r.end {
+				spans = append(spans, highlightSpan{start: r.start, end: r.end, color: theme.Color(colorNameSelection)})
+			}
+		}
+	}
+
+	return spans
+}
+
+// annotationSpans returns the highlight spans of [lineStart, lineEnd) that annotate the
+// data, which are all but the selection, in drawing order
+func (h *HexDumpApp) annotationSpans(lineStart, lineEnd int) []highlightSpan {
+	spans := h.paddingSpans(lineStart, lineEnd)
+	spans = append(spans, h.overlaySpans(lineStart, lineEnd)...)
+	spans = append(spans, h.signatureSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.sqliteSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.heatSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.maskSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.snapshotSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.referenceSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.yaraSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.pointerSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.tlvSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.modifiedSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.fieldSpans(lineStart, lineEnd)...)
+	spans = append(spans, h.bookmarkSpans(lineStart, lineEnd)...)
+	return spans
+}
+
+// charColumns returns, for each byte of [lineStart, lineEnd), the column of the
+// character pane holding the character decoded from that byte under the selected
+// encoding, followed by the number of columns of the line
+func (h *HexDumpApp) charColumns(lineStart, lineEnd int) []int {
+	data := h.fileData[lineStart:lineEnd]
+	columns := make([]int, len(data)+1)
+
+	switch h.encoding {
+	case "UTF-8":
+		column := 0
+		for _, cell := range h.lineCells(lineStart, lineEnd) {
+			for offset := cell.start; offset < min(cell.end, lineEnd); offset++ {
+				columns[offset-lineStart] = column
+			}
+			column += cell.width
+		}
+		columns[len(data)] = column
+	case "UTF-16LE":
+		for index := range data {
+			columns[index] = index / 2
+		}
+		columns[len(data)] = (len(data) + 1) / 2
+	case "GB 18030":
+		column := 0
+		for index := 0; index < len(data); {
+			size := gb18030SequenceLength(data[index:])
+			for byteIndex := index; byteIndex < index+size && byteIndex < len(data); byteIndex++ {
+				columns[byteIndex] = column
+			}
+			index += size
+			column++
+		}
+		columns[len(data)] = column
+	default:
+		for index := range columns {
+			columns[index] = index
+		}
+	}
+	return columns
+}
+
+// gb18030SequenceLength returns the length of the GB 18030 byte sequence at the start of data
+func gb18030SequenceLength(data []byte) int {
+	if data[0] < 0x81 || data[0] == 0xFF || len(data) < 2 {
+		return 1
+	}
+	if data[1] >= 0x30 && data[1] <= 0x39 {
+		return 4
+	}
+	return 2
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// historyStep is a node of the edit history tree: the edits made by one command, which
+// are undone and redone together. Undoing and then editing starts a new branch, so no
+// state is ever lost. Step 0 is the data as loaded and has no edits.
+type historyStep struct {
+	parent     int
+	label      string
+	edits      []edit
+	redoChild  int    // The child redo goes to: the newest, or the one last undone
+	checkpoint string // Name given to the state after the step, if any
+}
+
+// editGroup collects the edits applied while it is open into a single history step
+type editGroup struct {
+	label string
+	step  int // The step the edits go into, or -1 until the first one
+}
+
+// newHistory returns an edit history holding only the data as loaded
+func newHistory() []historyStep {
+	return []historyStep{{}}
+}
+
+// resetHistory forgets the edit history, after other data is loaded
+func (h *HexDumpApp) resetHistory() {
+	h.history, h.historyAt, h.savedStep, h.journal = newHistory(), 0, 0, nil
+}
+
+// groupEdits calls f, making the edits it applies one step of the history
+func (h *HexDumpApp) groupEdits(label string, f func()) {
+	h.editGroup = &editGroup{label: label, step: -1}
+	defer func() { h.editGroup = nil }()
+	f()
+}
+
+// recordEdit adds an edit just applied to the history, as a new step after the
+// current one unless an edit group has started one already
+func (h *HexDumpApp) recordEdit(change edit) {
+	h.journal = append(h.journal, change)
+	if h.editGroup != nil && h.editGroup.step >= 0 {
+		step := &h.history[h.editGroup.step]
+		step.edits = append(step.edits, change)
+		return
+	}
+	label := change.label
+	if h.editGroup != nil {
+		label = h.editGroup.label
+	}
+	h.history = append(h.history, historyStep{parent: h.historyAt, label: label, edits: []edit{change}})
+	h.historyAt = len(h.history) - 1
+	h.history[h.history[h.historyAt].parent].redoChild = h.historyAt
+	if h.editGroup != nil {
+		h.editGroup.step = h.historyAt
+	}
+}
+
+// undoStep reverts the current step without redrawing, and reports whether there was one
+func (h *HexDumpApp) undoStep() bool {
+	if h.historyAt == 0 {
+		return false
+	}
+	step := h.history[h.historyAt]
+	h.stopScan()
+	for index := len(step.edits) - 1; index >= 0; index-- {
+		copy(h.fileData[step.edits[index].offset:], step.edits[index].oldBytes)
+	}
+	h.journal = h.journal[:len(h.journal)-len(step.edits)]
+	h.history[step.parent].redoChild = h.historyAt
+	h.historyAt = step.parent
+	return true
+}
+
+// redoStep reapplies the step redo goes to without redrawing, and reports whether there
+// was one
+func (h *HexDumpApp) redoStep() bool {
+	child := h.history[h.historyAt].redoChild
+	if child == 0 {
+		return false
+	}
+	h.stopScan()
+	for _, change := range h.history[child].edits {
+		copy(h.fileData[change.offset:], change.newBytes)
+	}
+	h.journal = append(h.journal, h.history[child].edits...)
+	h.historyAt = child
+	return true
+}
+
+// isHistoryAncestor reports whether step ancestor is step or comes before it in its branch
+func (h *HexDumpApp) isHistoryAncestor(ancestor, step int) bool {
+	for ; step != 0; step = h.history[step].parent {
+		if step == ancestor {
+			return true
+		}
+	}
+	return ancestor == 0
+}
+
+// goToHistoryStep changes the data to its state after step, in whichever branch of the
+// history it is, by undoing back to where the branches meet and redoing from there
+func (h *HexDumpApp) goToHistoryStep(target int) {
+	if target < 0 || target >= len(h.history) || target == h.historyAt {
+		return
+	}
+	for !h.isHistoryAncestor(h.historyAt, target) {
+		h.undoStep()
+	}
+	var path []int
+	for step := target; step != h.historyAt; step = h.history[step].parent {
+		path = append(path, step)
+	}
+	for index := len(path) - 1; index >= 0; index-- {
+		h.history[h.historyAt].redoChild = path[index]
+		h.redoStep()
+	}
+	h.editsChanged()
+}
+
+// stepText describes a history step for the History panel
+func (h *HexDumpApp) stepText(index int) string {
+	step := h.history[index]
+	var text string
+	switch {
+	case index == 0:
+		text = lang.L("Opened")
+	case len(step.edits) == 1:
+		text = fmt.Sprintf("%s at %s, %d byte(s)", step.label, formatHex(uint64(step.edits[0].offset), 8),
+			len(step.edits[0].newBytes))
+	default:
+		text = fmt.Sprintf("%s, %d changes", step.label, len(step.edits))
+	}
+	if step.checkpoint != "" {
+		text += fmt.Sprintf("  ★ %s", step.checkpoint)
+	}
+	if index == h.savedStep {
+		text += "  " + lang.L("(saved)")
+	}
+	return text
+}
+
+// historyRow is a row of the History panel: a step, indented by how many branches off
+// the main line it is
+type historyRow struct {
+	step  int
+	depth int
+}
+
+// historyRows lists the steps of the history tree for the History panel. The newest
+// child of a step continues its line, and older branches are listed before it, indented.
+func (h *HexDumpApp) historyRows() []historyRow {
+	children := make([][]int, len(h.history))
+	for index := 1; index < len(h.history); index++ {
+		parent := h.history[index].parent
+		children[parent] = append(children[parent], index)
+	}
+	var rows []historyRow
+	var visit func(step, depth int)
+	visit = func(step, depth int) {
+		for {
+			rows = append(rows, historyRow{step, depth})
+			kids := children[step]
+			if len(kids) == 0 {
+				return
+			}
+			for _, branch := range kids[:len(kids)-1] {
+				visit(branch, depth+1)
+			}
+			step = kids[len(kids)-1]
+		}
+	}
+	visit(0, 0)
+	return rows
+}
+
+// checkpointSteps returns the steps that have been named as checkpoints
+func (h *HexDumpApp) checkpointSteps() []int {
+	var steps []int
+	for index, step := range h.history {
+		if step.checkpoint != "" {
+			steps = append(steps, index)
+		}
+	}
+	return steps
+}
+
+// showAddCheckpoint names the current state of the data as a checkpoint, such as
+// "before checksum fix", that can be returned to later
+func (h *HexDumpApp) showAddCheckpoint() {
+	h.showNameCheckpoint(h.historyAt)
+}
+
+// showNameCheckpoint asks for the name of the checkpoint at step; an empty name
+// removes it
+func (h *HexDumpApp) showNameCheckpoint(step int) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(h.history[step].checkpoint)
+	nameEntry.SetPlaceHolder(lang.L("e.g. before checksum fix"))
+	dialog.ShowForm(lang.L("Add Checkpoint"), lang.L("OK"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Name"), nameEntry),
+	}, func(ok bool) {
+		if ok && step < len(h.history) {
+			h.history[step].checkpoint = strings.TrimSpace(nameEntry.Text)
+			h.refreshPanels()
+		}
+	}, h.window)
+}
+
+// showRevertToCheckpoint lists the checkpoints to change the data back to one of them
+func (h *HexDumpApp) showRevertToCheckpoint() {
+	steps := h.checkpointSteps()
+	if len(steps) == 0 {
+		dialog.ShowInformation(lang.L("Revert to Checkpoint"),
+			lang.L("There are no checkpoints yet. Add one with Edit → Add Checkpoint."), h.window)
+		return
+	}
+	var names []string
+	for _, step := range steps {
+		names = append(names, h.history[step].checkpoint)
+	}
+	checkpointSelect := widget.NewSelect(names, nil)
+	checkpointSelect.SetSelectedIndex(len(names) - 1)
+	dialog.ShowForm(lang.L("Revert to Checkpoint"), lang.L("Revert"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Checkpoint"), checkpointSelect),
+	}, func(ok bool) {
+		if index := checkpointSelect.SelectedIndex(); ok && index >= 0 {
+			h.goToHistoryStep(steps[index])
+		}
+	}, h.window)
+}
+
+// createHistoryPanel creates the History side panel, which shows the tree of edits with
+// its checkpoints. Go To changes the data to its state after the selected step, in any
+// branch.
+func (h *HexDumpApp) createHistoryPanel() panelContent {
+	var rows []historyRow
+	selected := -1
+	summaryLabel := widget.NewLabel("")
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	historyList := widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := rows[id]
+			marker := "  "
+			if row.step == h.historyAt {
+				marker = "▶ "
+			}
+			item.(*widget.Label).SetText(marker + strings.Repeat("    ", row.depth) + h.stepText(row.step))
+		},
+	)
+	historyList.OnSelected = func(id widget.ListItemID) { selected = rows[id].step }
+	historyList.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	goToBtn := widget.NewButton(lang.L("Go To"), func() {
+		if selected >= 0 {
+			h.goToHistoryStep(selected)
+		}
+	})
+	checkpointBtn := widget.NewButton(lang.L("Checkpoint..."), func() {
+		if selected >= 0 {
+			h.showNameCheckpoint(selected)
+		} else {
+			h.showAddCheckpoint()
+		}
+	})
+
+	refresh := func() {
+		rows = h.historyRows()
+		if selected >= 0 && !slices.ContainsFunc(rows, func(row historyRow) bool { return row.step == selected }) {
+			selected = -1
+		}
+		branches := 0
+		for _, row := range rows {
+			branches = max(branches, row.depth)
+		}
+		summaryLabel.SetText(lang.L("{{.Steps}} step(s) in {{.Branches}} branch(es); Go To returns to the selected one",
+			map[string]any{"Steps": len(rows) - 1, "Branches": branches + 1}))
+		historyList.Refresh()
+	}
+
+	return panelContent{
+		object: container.NewBorder(container.NewVBox(summaryLabel, container.NewHBox(goToBtn, checkpointBtn)),
+			nil, nil, nil, historyList),
+		refresh: refresh,
+		reset: func() {
+			selected = -1
+			historyList.UnselectAll()
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"embed"
+	"log/slog"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// translations holds the message catalogs, one hexdump.<locale>.json file per language,
+// each mapping the English text of a message to its translation
+//
+//go:embed translations
+var translations embed.FS
+
+// loadTranslations registers the message catalogs with fyne, which picks the one for the
+// system language and falls back to English
+func loadTranslations() {
+	if err := lang.AddTranslationsFS(translations, "translations"); err != nil {
+		slog.Warn("loading translations", "error", err)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/text/unicode/runenames"
+)
+
+// inspectorType is one interpretation of the bytes at the caret shown by the inspector
+type inspectorType struct {
+	name   string
+	size   int
+	format func(data []byte, order binary.ByteOrder) string
+}
+
+// inspectorTypes lists the interpretations shown by the inspector, in display order
+var inspectorTypes = []inspectorType{
+	{"Binary", 1, func(data []byte, _ binary.ByteOrder) string { return fmt.Sprintf("%08b", data[0]) }},
+	{"Int8", 1, func(data []byte, _ binary.ByteOrder) string { return fmt.Sprint(int8(data[0])) }},
+	{"UInt8", 1, func(data []byte, _ binary.ByteOrder) string { return fmt.Sprint(data[0]) }},
+	{"Int16", 2, func(data []byte, order binary.ByteOrder) string { return fmt.Sprint(int16(order.Uint16(data))) }},
+	{"UInt16", 2, func(data []byte, order binary.ByteOrder) string { return fmt.Sprint(order.Uint16(data)) }},
+	{"Int32", 4, func(data []byte, order binary.ByteOrder) string { return fmt.Sprint(int32(order.Uint32(data))) }},
+	{"UInt32", 4, func(data []byte, order binary.ByteOrder) string { return fmt.Sprint(order.Uint32(data)) }},
+	{"Int64", 8, func(data []byte, order binary.ByteOrder) string { return fmt.Sprint(int64(order.Uint64(data))) }},
+	{"UInt64", 8, func(data []byte, order binary.ByteOrder) string { return fmt.Sprint(order.Uint64(data)) }},
+	{"Float32", 4, func(data []byte, order binary.ByteOrder) string {
+		return fmt.Sprint(math.Float32frombits(order.Uint32(data)))
+	}},
+	{"Float64", 8, func(data []byte, order binary.ByteOrder) string {
+		return fmt.Sprint(math.Float64frombits(order.Uint64(data)))
+	}},
+}
+
+// createInspectorPanel creates the Inspector side panel, which shows the bytes at the
+// caret interpreted as integers and floating-point numbers of both byte orders, and the
+// bits of the byte at the caret
+func (h *HexDumpApp) createInspectorPanel() panelContent {
+	offsetLabel := widget.NewLabel("")
+	littleHeader := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	bigHeader := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	grid := container.NewGridWithColumns(3,
+		widget.NewLabelWithStyle(lang.L("Type"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		littleHeader,
+		bigHeader,
+	)
+
+	littleLabels := make([]*widget.Label, len(inspectorTypes))
+	bigLabels := make([]*widget.Label, len(inspectorTypes))
+	for index, inspected := range inspectorTypes {
+		littleLabels[index] = widget.NewLabel("")
+		littleLabels[index].Selectable = true
+		littleLabels[index].Truncation = fyne.TextTruncateEllipsis // Keep long values from widening the panel
+		bigLabels[index] = widget.NewLabel("")
+		bigLabels[index].Selectable = true
+		bigLabels[index].Truncation = fyne.TextTruncateEllipsis
+		grid.Add(widget.NewLabel(inspected.name))
+		grid.Add(littleLabels[index])
+		grid.Add(bigLabels[index])
+	}
+
+	// Bit breakdown of the byte at the caret. Toggling a bit edits the byte.
+	updatingBits := false
+	bitChecks := make([]*widget.Check, 8)
+	bitRow := container.NewGridWithColumns(4)
+	for index := range bitChecks {
+		bit := 7 - index
+		bitChecks[index] = widget.NewCheck(fmt.Sprintf("b%d", bit), func(checked bool) {
+			if updatingBits || h.caret >= len(h.fileData) {
+				return
+			}
+			value := h.fileData[h.caret] &^ (1 << bit)
+			if checked {
+				value |= 1 << bit
+			}
+			h.applyEdit(fmt.Sprintf("Set bit %d", bit), h.caret, []byte{value})
+		})
+		bitRow.Add(bitChecks[index])
+	}
+	bitsItem := widget.NewAccordionItem(lang.L("Bits"), bitRow)
+	bits := widget.NewAccordion(bitsItem)
+	bits.Open(0)
+
+	// The character decoded at the caret under the chosen encoding
+	charLabels := make([]*widget.Label, 5)
+	charGrid := container.NewGridWithColumns(2)
+	for index, name := range []string{"Character", "Code points", "Names", "UTF-8", "UTF-16"} {
+		charLabels[index] = widget.NewLabel("")
+		charLabels[index].Selectable = true
+		charLabels[index].Wrapping = fyne.TextWrapWord
+		charGrid.Add(widget.NewLabel(name))
+		charGrid.Add(charLabels[index])
+	}
+	charItem := widget.NewAccordionItem(lang.L("Character"), charGrid)
+	characters := widget.NewAccordion(charItem)
+	characters.Open(0)
+
+	refresh := func() {
+		updatingBits = true
+		for index, check := range bitChecks {
+			if h.caret < len(h.fileData) {
+				check.Enable()
+				check.SetChecked(h.fileData[h.caret]&(1<<(7-index)) != 0)
+			} else {
+				check.SetChecked(false)
+				check.Disable()
+			}
+		}
+		updatingBits = false
+
+		if len(h.fileData) == 0 {
+			offsetLabel.SetText(lang.L("No file loaded"))
+		} else {
+			offsetLabel.SetText(fmt.Sprintf("Offset: %08X (%d)", h.caret, h.caret))
+		}
+
+		// Mark the byte order chosen in the toolbar
+		if h.bigEndian {
+			littleHeader.SetText("Little-endian")
+			bigHeader.SetText("Big-endian *")
+		} else {
+			littleHeader.SetText("Little-endian *")
+			bigHeader.SetText("Big-endian")
+		}
+
+		for index, inspected := range inspectorTypes {
+			little, big := "-", "-"
+			if h.caret+inspected.size <= len(h.fileData) {
+				data := h.fileData[h.caret : h.caret+inspected.size]
+				little = inspected.format(data, binary.LittleEndian)
+				big = inspected.format(data, binary.BigEndian)
+			}
+			littleLabels[index].SetText(little)
+			bigLabels[index].SetText(big)
+		}
+
+		charItem.Title = "Character (" + h.encoding + ")"
+		characters.Refresh()
+		for index, text := range describeCharacter(h.fileData, h.caret, h.encoding) {
+			charLabels[index].SetText(text)
+		}
+	}
+
+	return panelContent{
+		object:  container.NewVScroll(container.NewVBox(offsetLabel, grid, bits, characters)),
+		refresh: refresh,
+	}
+}
+
+// characterAt decodes the character at offset under encoding, returning its code points,
+// the base character followed by any combining marks, and the offsets [start, end) of
+// its bytes. In UTF-8, an offset inside a multibyte sequence is moved to its first byte.
+func characterAt(data []byte, offset int, encoding string) ([]rune, int, int) {
+	if encoding == "UTF-8" {
+		for back := 0; back < utf8.UTFMax-1 && offset > 0 && !utf8.RuneStart(data[offset]); back++ {
+			offset--
+		}
+	}
+
+	var runes []rune
+	start, end := offset, offset
+	for end < len(data) && len(runes) < 8 {
+		r, size := decodeRune(data[end:min(end+4, len(data))], encoding)
+		if len(runes) > 0 && (r == utf8.RuneError || !unicode.Is(unicode.M, r)) {
+			break
+		}
+		runes = append(runes, r)
+		end += size
+		if r == utf8.RuneError {
+			break
+		}
+	}
+	return runes, start, end
+}
+
+// describeCharacter returns the inspector's description of the character at offset: the
+// character, its code points and names, and its UTF-8 and UTF-16 encodings
+func describeCharacter(data []byte, offset int, encoding string) []string {
+	if offset >= len(data) {
+		return []string{"-", "-", "-", "-", "-"}
+	}
+	runes, start, end := characterAt(data, offset, encoding)
+	if runes[0] == utf8.RuneError {
+		return []string{fmt.Sprintf("invalid (% X)", data[start:end]), "-", "-", "-", "-"}
+	}
+
+	var points, names, utf16Units []string
+	for _, r := range runes {
+		points = append(points, fmt.Sprintf("U+%04X", r))
+		name := runenames.Name(r)
+		if name == "" {
+			name = "<unassigned>"
+		}
+		names = append(names, name)
+		for _, unit := range utf16.Encode([]rune{r}) {
+			utf16Units = append(utf16Units, fmt.Sprintf("%04X", unit))
+		}
+	}
+	shown := string(runes)
+	if !unicode.IsPrint(runes[0]) {
+		shown = "."
+	}
+	return []string{
+		fmt.Sprintf("%s  (% X at %08X)", shown, data[start:end], start),
+		strings.Join(points, " "),
+		strings.Join(names, ", "),
+		fmt.Sprintf("% X", []byte(string(runes))),
+		strings.Join(utf16Units, " "),
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of record written by "hexdump json", as given to -what
+var jsonRecordKinds = []string{"lines", "strings", "hashes", "magic"}
+
+// jsonRecord is one line of the NDJSON output of "hexdump json". Type is "line",
+// "string", "hash", or "magic", and only the fields of that type are written.
+type jsonRecord struct {
+	Type      string `json:"type"`
+	File      string `json:"file"`
+	Offset    *int   `json:"offset,omitempty"`
+	Length    int    `json:"length,omitempty"`
+	Bytes     []int  `json:"bytes,omitempty"`
+	Hex       string `json:"hex,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Digest    string `json:"digest,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// jsonOptions selects what "hexdump json" writes about each file
+type jsonOptions struct {
+	kinds      []string // Elements of jsonRecordKinds
+	width      int      // Bytes per line
+	encoding   string   // Encoding of the text of lines
+	minLength  int      // Shortest string written
+	maxStrings int
+}
+
+// writeJSONRecords writes the records selected by options about the data of the named
+// file to w, one JSON object per line
+func writeJSONRecords(w io.Writer, name string, data []byte, options jsonOptions) error {
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	at := func(offset int) *int { return &offset }
+
+	if slices.Contains(options.kinds, "lines") {
+		for offset := 0; offset < len(data); offset += options.width {
+			line := data[offset:min(offset+options.width, len(data))]
+			values := make([]int, len(line))
+			for index, b := range line {
+				values[index] = int(b)
+			}
+			record := jsonRecord{Type: "line", File: name, Offset: at(offset), Bytes: values,
+				Hex: fmt.Sprintf("%x", line), Text: lineText(line, options.encoding)}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	if slices.Contains(options.kinds, "strings") {
+		for _, found := range extractStrings(data, options.minLength, options.maxStrings) {
+			encoding := "ascii"
+			if found.wide {
+				encoding = "utf-16le"
+			}
+			record := jsonRecord{Type: "string", File: name, Offset: at(found.offset), Length: found.length,
+				Encoding: encoding, Text: found.text}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	if slices.Contains(options.kinds, "hashes") {
+		for _, algorithm := range checksumAlgorithms {
+			record := jsonRecord{Type: "hash", File: name, Algorithm: algorithm.name,
+				Digest: computeChecksum(algorithm, data)}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	if slices.Contains(options.kinds, "magic") {
+		for _, match := range findSignatures(data) {
+			record := jsonRecord{Type: "magic", File: name, Offset: at(match.offset), Length: match.length,
+				Name: match.name}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	return out.Flush()
+}
+
+// lineText decodes a line of data as the character pane shows it, with control
+// characters and invalid bytes as dots
+func lineText(data []byte, encoding string) string {
+	var builder strings.Builder
+	for index := 0; index < len(data); {
+		r, size := decodeRune(data[index:], encoding)
+		index += size
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '.'
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// runJSONCommand implements "hexdump json", which writes the lines of files, or
+// analysis results about them, as NDJSON for other programs to read
+func runJSONCommand(args []string) int {
+	flags := newFlagSet("json", "[options] [file ...]")
+	what := flags.String("what", "lines", "comma-separated `kinds` of record: "+strings.Join(jsonRecordKinds, ", ")+", or all")
+	width := flags.Int("width", 16, "bytes per line")
+	encoding := flags.String("encoding", "ISO Latin-1", "character `encoding` of the text of lines")
+	minLength := flags.Int("min", 4, "shortest string `length` in characters")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	options := jsonOptions{width: *width, encoding: *encoding, minLength: *minLength, maxStrings: maxStringsFound}
+	for _, kind := range strings.Split(*what, ",") {
+		switch kind = strings.TrimSpace(kind); {
+		case kind == "all":
+			options.kinds = jsonRecordKinds
+		case slices.Contains(jsonRecordKinds, kind):
+			options.kinds = append(options.kinds, kind)
+		default:
+			return cliError(os.Stderr, "json", fmt.Errorf("unknown kind of record: %s", kind))
+		}
+	}
+	if options.width < 1 {
+		return cliError(os.Stderr, "json", fmt.Errorf("width must be positive"))
+	}
+	if !slices.Contains(encodingNames, options.encoding) {
+		return cliError(os.Stderr, "json", fmt.Errorf("unknown encoding: %s (use %s)", options.encoding,
+			strings.Join(encodingNames, ", ")))
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return cliError(os.Stderr, "json", err)
+		}
+		if err := writeJSONRecords(os.Stdout, "-", data, options); err != nil {
+			return cliError(os.Stderr, "json", err)
+		}
+		return 0
+	}
+	exitCode := 0
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err == nil {
+			err = writeJSONRecords(os.Stdout, name, data, options)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "hexdump json: %v\n", err)
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,68 @@
+package main
+
+// lineChecksumNames maps the kinds of line checksum to their names in the Preferences dialog
+var lineChecksumNames = map[string]string{
+	lineChecksumNone: "None",
+	lineChecksumSum:  "Sum",
+	lineChecksumXOR:  "XOR",
+	lineChecksumCRC8: "CRC-8",
+}
+
+// crc8 returns the CRC-8 of data with the polynomial x^8+x^2+x+1 (0x07), as used by
+// SMBus and ATM headers
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// lineChecksum returns the checksum of a line's bytes of the given kind: their sum
+// modulo 256, their XOR, or their CRC-8
+func lineChecksum(data []byte, kind string) byte {
+	switch kind {
+	case lineChecksumSum:
+		var sum byte
+		for _, b := range data {
+			sum += b
+		}
+		return sum
+	case lineChecksumXOR:
+		var xor byte
+		for _, b := range data {
+			xor ^= b
+		}
+		return xor
+	case lineChecksumCRC8:
+		return crc8(data)
+	}
+	return 0
+}
+
+// showsLineChecksum reports whether a checksum column follows the character pane
+func showsLineChecksum() bool {
+	return appSettings.LineChecksum != lineChecksumNone
+}
+
+// checksumColumn returns the text column of the checksum column, after the widest
+// character pane a line can have
+func (h *HexDumpApp) checksumColumn() int {
+	return h.charPaneColumn() + h.bytesPerLine + charPaneGap
+}
+
+// generateChecksumLine returns the checksum column of the line starting at offset, or ""
+// when the column is off
+func (h *HexDumpApp) generateChecksumLine(offset int) string {
+	if !showsLineChecksum() {
+		return ""
+	}
+	return formatHex(uint64(lineChecksum(h.fileData[offset:h.lineEnd(offset)], appSettings.LineChecksum)), 2)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Names of the log levels, as given to --log-level and shown in the log viewer
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+// Log file rotation: the file is renamed to hexdump.log.1 once it reaches maxLogSize,
+// keeping logFileCount files in all
+const (
+	logDirName   = "logs"
+	logFileName  = "hexdump.log"
+	maxLogSize   = 1 << 20
+	logFileCount = 3
+)
+
+// logFilePath is the file log records are written to, or "" if they go to stderr
+var logFilePath string
+
+// maxLogLines is the number of recent log lines kept for the log viewer
+const maxLogLines = 2000
+
+// parseLogLevel parses the name of a log level
+func parseLogLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (use %s)", name, strings.Join(logLevelNames, ", "))
+	}
+	return level, nil
+}
+
+// setupLogging makes slog write records of the given level and above to the log file in
+// the settings directory, and keeps the recent ones for the log viewer. If the log file
+// can't be opened, the records go to stderr instead.
+func setupLogging(level slog.Level) {
+	var out io.Writer = os.Stderr
+	if dir, err := settingsDir(); err == nil {
+		if file, err := openLogFile(filepath.Join(dir, logDirName)); err == nil {
+			out, logFilePath = file, file.path
+		} else {
+			fmt.Fprintf(os.Stderr, "hexdump: logging to stderr: %v\n", err)
+		}
+	}
+	handler := slog.NewTextHandler(io.MultiWriter(out, recentLog), &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+}
+
+// rotatingFile is a log file that is rotated when it grows past maxLogSize
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+// openLogFile opens the log file in dir for appending, rotating it first if it is full
+func openLogFile(dir string) (*rotatingFile, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: filepath.Join(dir, logFileName)}
+	if info, err := os.Stat(r.path); err == nil && info.Size() >= maxLogSize {
+		r.rotate()
+	}
+	return r, r.open()
+}
+
+// open opens the current log file for appending
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts hexdump.log to hexdump.log.1, hexdump.log.1 to hexdump.log.2, and so on,
+// dropping the oldest
+func (r *rotatingFile) rotate() {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+	for index := logFileCount - 1; index > 0; index-- {
+		older := fmt.Sprintf("%s.%d", r.path, index)
+		newer := r.path
+		if index > 1 {
+			newer = fmt.Sprintf("%s.%d", r.path, index-1)
+		}
+		os.Rename(newer, older)
+	}
+}
+
+// Write implements io.Writer, rotating the file first if the record would take it past
+// maxLogSize
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil || r.size+int64(len(p)) > maxLogSize {
+		r.rotate()
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// logLine is a record kept for the log viewer
+type logLine struct {
+	level slog.Level
+	text  string
+}
+
+// logMemory keeps the most recent log records, one per Write, as the text handler
+// writes them
+type logMemory struct {
+	mu       sync.Mutex
+	lines    []logLine
+	onChange func() // Called after each record, from the goroutine that logged it
+}
+
+// recentLog holds the records shown by the log viewer
+var recentLog = &logMemory{}
+
+// Write implements io.Writer, taking the level from the record's level= field
+func (m *logMemory) Write(p []byte) (int, error) {
+	text := strings.TrimRight(string(p), "\n")
+	line := logLine{level: slog.LevelInfo, text: text}
+	if _, rest, found := strings.Cut(text, " level="); found {
+		name, _, _ := strings.Cut(rest, " ")
+		line.level, _ = parseLogLevel(name)
+	}
+
+	m.mu.Lock()
+	if len(m.lines) == maxLogLines {
+		m.lines = m.lines[1:]
+	}
+	m.lines = append(m.lines, line)
+	onChange := m.onChange
+	m.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+	return len(p), nil
+}
+
+// linesAtLevel returns the text of the records at level or above
+func (m *logMemory) linesAtLevel(level slog.Level) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var lines []string
+	for _, line := range m.lines {
+		if line.level >= level {
+			lines = append(lines, line.text)
+		}
+	}
+	return lines
+}
+
+// logWindow is the open log viewer, or nil
+var logWindow fyne.Window
+
+// showLogViewer opens a window listing the recent log records, filtered by level, that
+// follows new records as they are logged
+func (h *HexDumpApp) showLogViewer() {
+	if logWindow != nil {
+		logWindow.RequestFocus()
+		return
+	}
+	minimum := slog.LevelDebug
+	var lines []string
+	list := widget.NewList(
+		func() int { return len(lines) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle.Monospace = true
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(lines[id])
+		},
+	)
+	update := func() {
+		lines = recentLog.linesAtLevel(minimum)
+		list.Refresh()
+		list.ScrollToBottom()
+	}
+
+	levelSelect := widget.NewSelect(logLevelNames, func(name string) {
+		minimum, _ = parseLogLevel(name)
+		update()
+	})
+	levelSelect.SetSelected(logLevelNames[0])
+	copyButton := widget.NewButton(lang.L("Copy All"), func() {
+		h.window.Clipboard().SetContent(strings.Join(lines, "\n"))
+	})
+	location := logFilePath
+	if location == "" {
+		location = lang.L("Not saved to a file")
+	}
+	top := container.NewBorder(nil, nil, widget.NewLabel(lang.L("Minimum level")), copyButton, levelSelect)
+	bottom := widget.NewLabel(location)
+	bottom.Truncation = fyne.TextTruncateEllipsis
+
+	logWindow = h.app.NewWindow(lang.L("Log"))
+	logWindow.SetContent(container.NewBorder(top, bottom, nil, nil, list))
+	logWindow.Resize(fyne.NewSize(900, 500))
+	recentLog.mu.Lock()
+	recentLog.onChange = func() { fyne.Do(update) }
+	recentLog.mu.Unlock()
+	logWindow.SetOnClosed(func() {
+		recentLog.mu.Lock()
+		recentLog.onChange = nil
+		recentLog.mu.Unlock()
+		logWindow = nil
+	})
+	logWindow.Show()
+}
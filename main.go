@@ -1,11 +1,15 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"image/color"
+	"log/slog"
 	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/lang"
 	"fyne.io/fyne/v2/theme"
 )
 
@@ -19,8 +23,12 @@ func NewCustomTheme() fyne.Theme {
 	return &CustomTheme{Theme: theme.DefaultTheme()}
 }
 
-// Color returns the color for the given theme color name
+// Color returns the color for the given theme color name, taking the highlight colors
+// from the palette chosen in the preferences
 func (t *CustomTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if c, ok := paletteColor(name); ok {
+		return c
+	}
 	switch name {
 	case theme.ColorNameForeground:
 		return color.RGBA{R: 255, G: 255, B: 255, A: 255} // White text
@@ -31,26 +39,81 @@ func (t *CustomTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant)
 	}
 }
 
+// guiOptions holds the command-line options of the GUI
+type guiOptions struct {
+	logLevel slog.Level
+	portable bool     // Whether to start keeping the settings beside the executable
+	paths    []string // Files to open
+}
+
+// parseGUIArgs parses the command line of the GUI: --log-level, --portable, and the
+// file to open. HEXDUMP_DEBUG in the environment makes the default level debug.
+func parseGUIArgs(args []string) (guiOptions, error) {
+	var options guiOptions
+	defaultLevel := "info"
+	if os.Getenv("HEXDUMP_DEBUG") != "" {
+		defaultLevel = "debug"
+	}
+	flags := flag.NewFlagSet("hexdump", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: hexdump [--log-level LEVEL] [--portable] [FILE]\n       hexdump COMMAND [options]\n")
+		flags.PrintDefaults()
+	}
+	levelName := flags.String("log-level", defaultLevel, "log `level`: debug, info, warn, or error")
+	flags.BoolVar(&options.portable, "portable", false, "keep settings, logs, and templates in "+portableDirName+
+		" beside the executable from now on")
+	if err := flags.Parse(args); err != nil {
+		return options, err
+	}
+	level, err := parseLogLevel(*levelName)
+	options.logLevel, options.paths = level, flags.Args()
+	return options, err
+}
+
 func main() {
+	// Run a command-line subcommand instead of the GUI if one was given
+	if handled, exitCode := runCLI(os.Args[1:]); handled {
+		os.Exit(exitCode)
+	}
+
+	// Parse the options, and start logging before anything can go wrong
+	options, err := parseGUIArgs(os.Args[1:])
+	if err == nil && options.portable {
+		err = makePortable()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hexdump: %v\n", err)
+		os.Exit(2)
+	}
+	setupLogging(options.logLevel)
+	settings, _ := settingsDir()
+	slog.Info("starting", "logLevel", options.logLevel, "args", os.Args[1:], "settings", settings)
+
+	// Load the user's preferences before creating any windows
+	loadSettings()
+	loadTranslations()
+
 	// Create the application
 	myApp := app.New()
 	myApp.Settings().SetTheme(NewCustomTheme())
 	myApp.SetIcon(nil)
 
 	// Create the main window
-	myWindow := myApp.NewWindow("Hex Dump Utility")
-	myWindow.Resize(fyne.NewSize(650, 600))
+	myWindow := myApp.NewWindow(lang.L("Hex Dump Utility"))
+	myWindow.Resize(initialWindowSize())
 
 	// Create the hex dump application instance
 	hexApp := NewHexDumpApp(myApp, myWindow)
 
 	// Set up the GUI
 	hexApp.setupGUI()
+	hexApp.restoreDetachedPanels()
+	hexApp.scanTemplateLibrary()
+	hexApp.checkForUpdatesAtStartup()
 
 	// Check for command-line arguments to load a file
-	if len(os.Args) > 1 {
-		filename := os.Args[1]
-		hexApp.loadFileFromPath(filename)
+	if len(options.paths) > 0 {
+		hexApp.loadFileFromPath(options.paths[0])
 	}
 
 	// Show the window and run the application
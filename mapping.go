@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// mapThreshold is the size from which files are mapped into memory rather than read.
+// Smaller files are read, so that a file shrunk by another program while it is shown
+// can't fault the reads of a mapping.
+const mapThreshold = 256 << 20
+
+// mappedData is a file mapped into memory copy-on-write, so that the operating system
+// reads only the pages that are used, such as those of the lines shown, and edits stay
+// private until saved
+type mappedData struct {
+	path  string
+	data  []byte
+	unmap func() error
+}
+
+// readFileData returns the contents of the file at path, mapped into memory if it is
+// large and the platform supports it, with the mapping or nil
+func readFileData(path string) ([]byte, *mappedData, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Mode().IsRegular() && info.Size() >= mapThreshold {
+		data, unmap, err := mapFile(path, info.Size())
+		if err == nil {
+			return data, &mappedData{path, data, unmap}, nil
+		}
+		slog.Warn("mapping file, reading it instead", "path", path, "error", err)
+	}
+	data, err := os.ReadFile(path)
+	return data, nil, err
+}
+
+// copyData returns a copy of data. Data of mapThreshold or more is copied to a temporary
+// file that is mapped, so that the copy takes disk space rather than memory; the mapping
+// is returned too, or nil.
+func copyData(data []byte) ([]byte, *mappedData, error) {
+	if len(data) < mapThreshold {
+		return append([]byte(nil), data...), nil, nil
+	}
+	temp, err := os.CreateTemp("", "hexdump-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	temp.Close()
+	path := temp.Name()
+	if err := writeDataFile(path, data); err != nil {
+		os.Remove(path)
+		return nil, nil, err
+	}
+	copied, unmap, err := mapFile(path, int64(len(data)))
+	if err != nil {
+		slog.Warn("mapping copy, reading it instead", "path", path, "error", err)
+		copied, err = os.ReadFile(path)
+		os.Remove(path)
+		return copied, nil, err
+	}
+
+	// A mapped file can be removed at once where the platform allows it, and otherwise
+	// once it is unmapped
+	if os.Remove(path) == nil {
+		return copied, &mappedData{path, copied, unmap}, nil
+	}
+	return copied, &mappedData{path, copied, func() error {
+		err := unmap()
+		os.Remove(path)
+		return err
+	}}, nil
+}
+
+// release unmaps the mapping, if there is one, logging a failure
+func (m *mappedData) release() {
+	if m == nil {
+		return
+	}
+	if err := m.unmap(); err != nil {
+		slog.Warn("unmapping file", "path", m.path, "error", err)
+	}
+}
+
+// backs reports whether data is the whole mapping
+func (m *mappedData) backs(data []byte) bool {
+	return m != nil && len(data) == len(m.data) && len(data) > 0 && &data[0] == &m.data[0]
+}
+
+// releaseMapping unmaps the data of the file loaded before. If a background task or scan
+// may still be reading it, it is unmapped once they are done; see unmapRetired.
+func (h *HexDumpApp) releaseMapping() {
+	if h.mapped == nil || h.mapped.backs(h.fileData) {
+		return
+	}
+	h.retired = append(h.retired, h.mapped)
+	h.mapped = nil
+	h.unmapRetired()
+}
+
+// unmapRetired unmaps the mappings of files loaded before, unless a background task or
+// scan may still be reading them. It is called again when a task or scan finishes.
+func (h *HexDumpApp) unmapRetired() {
+	if h.dataInUse() {
+		return
+	}
+	for _, mapping := range h.retired {
+		mapping.release()
+	}
+	h.retired = nil
+}
+
+// dataInUse reports whether a background task or scan may be reading fileData
+func (h *HexDumpApp) dataInUse() bool {
+	return h.runningScan != nil || (h.taskChips != nil && len(h.taskChips.Objects) > 0)
+}
+
+// writeFileData saves the data to path. The mapped file it was loaded from is replaced
+// by a new file rather than overwritten, because truncating it would pull the unread
+// pages out from under the mapping.
+func (h *HexDumpApp) writeFileData(path string) error {
+	if !h.mapped.backs(h.fileData) || !sameFile(path, h.mapped.path) {
+		return writeDataFile(path, h.fileData)
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+"-*")
+	if err != nil {
+		return err
+	}
+	temp.Close()
+	if err := writeDataFile(temp.Name(), h.fileData); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	os.Chmod(temp.Name(), info.Mode().Perm())
+	if replaceUnmapsFirst {
+		return h.replaceMappedFile(temp.Name(), target)
+	}
+	if err := os.Rename(temp.Name(), target); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return nil
+}
+
+// replaceMappedFile renames the saved file temp over target, the file mapped into
+// fileData, for platforms that won't replace a mapped file. The mapping is released
+// first, and the saved file mapped in its place.
+func (h *HexDumpApp) replaceMappedFile(temp, target string) error {
+	if h.dataInUse() {
+		os.Remove(temp)
+		return fmt.Errorf("wait for the background tasks to finish before saving %s", target)
+	}
+	if err := h.mapped.unmap(); err != nil {
+		os.Remove(temp)
+		return err
+	}
+	h.fileData, h.mapped = nil, nil
+
+	// If the rename fails, the changes are only in temp, so it is kept and shown instead
+	path, renameErr := target, os.Rename(temp, target)
+	if renameErr != nil {
+		path = temp
+		renameErr = fmt.Errorf("%w; the changes are kept in %s", renameErr, temp)
+	}
+	data, mapped, err := readFileData(path)
+	if err != nil {
+		h.updateDisplay()
+		return fmt.Errorf("reopening %s after saving: %w", path, err)
+	}
+	h.fileData, h.mapped = data, mapped
+	return renameErr
+}
+
+// sameFile reports whether two paths name the same existing file
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
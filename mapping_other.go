@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package main
+
+import "errors"
+
+// replaceUnmapsFirst is whether a mapped file must be unmapped before it can be replaced
+const replaceUnmapsFirst = false
+
+// mapFile is not supported on this platform, so large files are read whole
+func mapFile(path string, size int64) ([]byte, func() error, error) {
+	return nil, nil, errors.New("mapping files is not supported on this platform")
+}
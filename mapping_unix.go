@@ -0,0 +1,28 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// replaceUnmapsFirst is whether a mapped file must be unmapped before it can be replaced
+const replaceUnmapsFirst = false
+
+// mapFile maps size bytes of the file at path into memory copy-on-write, and returns
+// them with a function that unmaps them
+func mapFile(path string, size int64) ([]byte, func() error, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The mapping keeps the file's pages after it is closed
+	defer file.Close()
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE,
+		syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
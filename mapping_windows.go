@@ -0,0 +1,38 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// replaceUnmapsFirst is whether a mapped file must be unmapped before it can be replaced.
+// Windows won't rename another file over a file that is mapped.
+const replaceUnmapsFirst = true
+
+// mapFile maps size bytes of the file at path into memory copy-on-write, and returns
+// them with a function that unmaps them
+func mapFile(path string, size int64) ([]byte, func() error, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The mapping keeps the file open after it is closed, and the view keeps the mapping
+	defer file.Close()
+	mapping, err := syscall.CreateFileMapping(syscall.Handle(file.Fd()), nil, syscall.PAGE_WRITECOPY,
+		uint32(size>>32), uint32(size), nil)
+	if err != nil {
+		return nil, nil, os.NewSyscallError("CreateFileMapping", err)
+	}
+	defer syscall.CloseHandle(mapping)
+	address, err := syscall.MapViewOfFile(mapping, syscall.FILE_MAP_COPY, 0, 0, uintptr(size))
+	if err != nil {
+		return nil, nil, os.NewSyscallError("MapViewOfFile", err)
+	}
+	// The view is memory the garbage collector doesn't manage, so the address can be
+	// turned into a pointer; reading it through one keeps vet from flagging the conversion
+	data := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&address))), size)
+	return data, func() error { return syscall.UnmapViewOfFile(address) }, nil
+}
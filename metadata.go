@@ -0,0 +1,789 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// metaNode is a chunk, segment, atom, or tag of a media file: the bytes [offset,
+// offset+size) described by text, with the nodes nested inside it
+type metaNode struct {
+	offset, size int
+	text         string
+	children     []*metaNode
+}
+
+// maxMetaDepth limits how deeply nested atoms and IFDs are followed
+const maxMetaDepth = 16
+
+// parseMetadata works out the format of a JPEG, PNG, PDF, or MP4/QuickTime file and
+// returns the tree of its structure and metadata
+func parseMetadata(data []byte) (string, []*metaNode) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "JPEG", parseJPEG(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1A\n")):
+		return "PNG", parsePNG(data)
+	case bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")):
+		return "PDF", parsePDF(data)
+	case len(data) >= 8 && isAtomType(string(data[4:8])):
+		return "MP4/QuickTime", parseAtoms(data, 0, len(data), 0, "")
+	}
+	return "", nil
+}
+
+// JPEG
+
+// jpegMarkerNames names the JPEG markers other than SOFn and APPn
+var jpegMarkerNames = map[byte]string{
+	0xC4: "DHT (Huffman tables)", 0xCC: "DAC (arithmetic coding)", 0xD8: "SOI (start of image)",
+	0xD9: "EOI (end of image)", 0xDA: "SOS (start of scan)", 0xDB: "DQT (quantization tables)",
+	0xDD: "DRI (restart interval)", 0xFE: "COM (comment)",
+}
+
+// parseJPEG lists the segments of a JPEG file, with the tags of its Exif segment
+func parseJPEG(data []byte) []*metaNode {
+	nodes := []*metaNode{{offset: 0, size: 2, text: jpegMarkerNames[0xD8]}}
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			nodes = append(nodes, &metaNode{offset: offset, size: len(data) - offset,
+				text: fmt.Sprintf("Unexpected data (%d bytes)", len(data)-offset)})
+			break
+		}
+		marker := data[offset+1]
+		if marker == 0xFF {
+			offset++ // Fill byte
+			continue
+		}
+		if marker == 0xD9 {
+			nodes = append(nodes, &metaNode{offset: offset, size: 2, text: jpegMarkerNames[marker]})
+			if offset+2 < len(data) {
+				nodes = append(nodes, &metaNode{offset: offset + 2, size: len(data) - offset - 2,
+					text: fmt.Sprintf("Trailing data (%d bytes)", len(data)-offset-2)})
+			}
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		end := min(offset+2+length, len(data))
+		payload := data[min(offset+4, end):end]
+		node := &metaNode{offset: offset, size: end - offset}
+		switch {
+		case marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC:
+			node.text = fmt.Sprintf("SOF%d (start of frame)", marker-0xC0)
+			if len(payload) >= 6 {
+				node.text += fmt.Sprintf(": %dx%d, %d components", binary.BigEndian.Uint16(payload[3:]),
+					binary.BigEndian.Uint16(payload[1:]), payload[5])
+			}
+		case marker >= 0xE0 && marker <= 0xEF:
+			node.text = fmt.Sprintf("APP%d", marker-0xE0)
+			identifier, _, _ := bytes.Cut(payload[:min(len(payload), 32)], []byte{0})
+			switch {
+			case marker == 0xE1 && bytes.HasPrefix(payload, []byte("Exif\x00")):
+				node.text += " Exif"
+				if len(payload) > 6 {
+					node.children = parseTIFF(payload[6:], offset+10)
+				}
+			case marker == 0xE1 && bytes.HasPrefix(payload, []byte("http://ns.adobe.com/xap/1.0/\x00")):
+				node.text += " XMP packet"
+			case marker == 0xE0 && bytes.HasPrefix(payload, []byte("JFIF\x00")) && len(payload) >= 7:
+				node.text += fmt.Sprintf(" JFIF %d.%02d", payload[5], payload[6])
+			case len(identifier) > 0 && allPrintableASCII(identifier):
+				node.text += " " + string(identifier)
+			}
+		case marker == 0xFE:
+			node.text = fmt.Sprintf("%s: %s", jpegMarkerNames[marker], quoteMetaText(payload))
+		default:
+			node.text = jpegMarkerNames[marker]
+			if node.text == "" {
+				node.text = fmt.Sprintf("Marker FF%02X", marker)
+			}
+		}
+		node.text += fmt.Sprintf(" (%d bytes)", node.size)
+		nodes = append(nodes, node)
+		offset = end
+
+		if marker == 0xDA {
+			// Entropy-coded data runs to the next marker other than a restart marker
+			scan := offset
+			for scan+1 < len(data) && (data[scan] != 0xFF || data[scan+1] == 0 || data[scan+1] == 0xFF ||
+				(data[scan+1] >= 0xD0 && data[scan+1] <= 0xD7)) {
+				scan++
+			}
+			if scan+1 >= len(data) {
+				scan = len(data)
+			}
+			nodes = append(nodes, &metaNode{offset: offset, size: scan - offset,
+				text: fmt.Sprintf("Scan data (%d bytes)", scan-offset)})
+			offset = scan
+		}
+	}
+	return nodes
+}
+
+// allPrintableASCII reports whether data is all printable ASCII characters
+func allPrintableASCII(data []byte) bool {
+	for _, b := range data {
+		if !isPrintableASCII(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteMetaText quotes the text of a metadata value, shortened to 80 characters
+func quoteMetaText(data []byte) string {
+	text := strings.TrimRight(string(data), "\x00 ")
+	if runes := []rune(text); len(runes) > 80 {
+		text = string(runes[:80]) + "…"
+	}
+	return strconv.Quote(text)
+}
+
+// Exif
+
+// exifTagNames names the common tags of the primary and Exif IFDs
+var exifTagNames = map[uint16]string{
+	0x0100: "ImageWidth", 0x0101: "ImageLength", 0x0103: "Compression", 0x010E: "ImageDescription",
+	0x010F: "Make", 0x0110: "Model", 0x0112: "Orientation", 0x011A: "XResolution", 0x011B: "YResolution",
+	0x0128: "ResolutionUnit", 0x0131: "Software", 0x0132: "DateTime", 0x013B: "Artist",
+	0x0201: "JPEGInterchangeFormat", 0x0202: "JPEGInterchangeFormatLength", 0x0213: "YCbCrPositioning",
+	0x8298: "Copyright", 0x829A: "ExposureTime", 0x829D: "FNumber", 0x8769: "ExifIFD", 0x8822: "ExposureProgram",
+	0x8825: "GPSIFD", 0x8827: "ISOSpeedRatings", 0x9000: "ExifVersion", 0x9003: "DateTimeOriginal",
+	0x9004: "DateTimeDigitized", 0x9201: "ShutterSpeedValue", 0x9202: "ApertureValue",
+	0x9204: "ExposureBiasValue", 0x9207: "MeteringMode", 0x9209: "Flash", 0x920A: "FocalLength",
+	0x927C: "MakerNote", 0x9286: "UserComment", 0xA000: "FlashpixVersion", 0xA001: "ColorSpace",
+	0xA002: "PixelXDimension", 0xA003: "PixelYDimension", 0xA005: "InteroperabilityIFD",
+	0xA405: "FocalLengthIn35mmFilm", 0xA433: "LensMake", 0xA434: "LensModel",
+}
+
+// gpsTagNames names the tags of the GPS IFD
+var gpsTagNames = map[uint16]string{
+	0x00: "GPSVersionID", 0x01: "GPSLatitudeRef", 0x02: "GPSLatitude", 0x03: "GPSLongitudeRef",
+	0x04: "GPSLongitude", 0x05: "GPSAltitudeRef", 0x06: "GPSAltitude", 0x07: "GPSTimeStamp",
+	0x12: "GPSMapDatum", 0x1D: "GPSDateStamp",
+}
+
+// exifTypeSizes is the size of a value of each TIFF field type
+var exifTypeSizes = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+// parseTIFF lists the IFDs and tags of the TIFF structure in data, as found in an Exif
+// segment or chunk, where base is the offset of data in the file
+func parseTIFF(data []byte, base int) []*metaNode {
+	if len(data) < 8 {
+		return nil
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+	nodes := []*metaNode{{offset: base, size: 8, text: fmt.Sprintf("TIFF header (%s byte order)", data[:2])}}
+	visited := map[int]bool{}
+
+	var parseIFD func(name string, ifdOffset, depth int, names map[uint16]string) *metaNode
+	parseIFD = func(name string, ifdOffset, depth int, names map[uint16]string) *metaNode {
+		if ifdOffset < 8 || ifdOffset+2 > len(data) || visited[ifdOffset] || depth > maxMetaDepth {
+			return nil
+		}
+		visited[ifdOffset] = true
+		count := int(order.Uint16(data[ifdOffset:]))
+		count = min(count, (len(data)-ifdOffset-2)/12)
+		ifd := &metaNode{offset: base + ifdOffset, size: 2 + 12*count + 4,
+			text: fmt.Sprintf("%s (%d entries)", name, count)}
+		for index := 0; index < count; index++ {
+			entry := data[ifdOffset+2+12*index:]
+			tag, fieldType, valueCount := order.Uint16(entry), order.Uint16(entry[2:]), int(order.Uint32(entry[4:]))
+			tagName := names[tag]
+			if tagName == "" {
+				tagName = fmt.Sprintf("Tag 0x%04X", tag)
+			}
+			node := &metaNode{offset: base + ifdOffset + 2 + 12*index, size: 12}
+
+			// Values of more than 4 bytes are stored elsewhere
+			value := entry[8:12]
+			size := exifTypeSizes[fieldType] * valueCount
+			if size > 4 {
+				valueOffset := int(order.Uint32(entry[8:]))
+				if valueOffset < 0 || valueOffset+size > len(data) || size < 0 {
+					node.text = fmt.Sprintf("%s: <out of range>", tagName)
+					ifd.children = append(ifd.children, node)
+					continue
+				}
+				value = data[valueOffset : valueOffset+size]
+				node.offset, node.size = base+valueOffset, size
+			}
+			node.text = fmt.Sprintf("%s: %s", tagName, formatExifValue(value[:min(size, len(value))], fieldType,
+				valueCount, order))
+
+			subIFDs := map[uint16]map[uint16]string{0x8769: exifTagNames, 0x8825: gpsTagNames, 0xA005: exifTagNames}
+			if subNames, ok := subIFDs[tag]; ok && size == 4 {
+				if sub := parseIFD(tagName, int(order.Uint32(value)), depth+1, subNames); sub != nil {
+					node = sub
+				}
+			}
+			ifd.children = append(ifd.children, node)
+		}
+		return ifd
+	}
+
+	// The IFDs after the first one hold thumbnails
+	ifdOffset := int(order.Uint32(data[4:]))
+	for index := 0; index < maxMetaDepth; index++ {
+		ifd := parseIFD(fmt.Sprintf("IFD%d", index), ifdOffset, 0, exifTagNames)
+		if ifd == nil {
+			break
+		}
+		nodes = append(nodes, ifd)
+		if next := ifdOffset + 2 + 12*int(order.Uint16(data[ifdOffset:])); next+4 <= len(data) {
+			ifdOffset = int(order.Uint32(data[next:]))
+		} else {
+			break
+		}
+	}
+	return nodes
+}
+
+// formatExifValue formats count values of a TIFF field type
+func formatExifValue(value []byte, fieldType uint16, count int, order binary.ByteOrder) string {
+	const maxValues = 8
+	var values []string
+	switch fieldType {
+	case 2:
+		return quoteMetaText(value)
+	case 3, 8:
+		for index := 0; index+2 <= len(value) && index < 2*maxValues; index += 2 {
+			number := int64(order.Uint16(value[index:]))
+			if fieldType == 8 {
+				number = int64(int16(number))
+			}
+			values = append(values, strconv.FormatInt(number, 10))
+		}
+	case 4, 9:
+		for index := 0; index+4 <= len(value) && index < 4*maxValues; index += 4 {
+			number := int64(order.Uint32(value[index:]))
+			if fieldType == 9 {
+				number = int64(int32(number))
+			}
+			values = append(values, strconv.FormatInt(number, 10))
+		}
+	case 5, 10:
+		for index := 0; index+8 <= len(value) && index < 8*maxValues; index += 8 {
+			numerator, denominator := int64(order.Uint32(value[index:])), int64(order.Uint32(value[index+4:]))
+			if fieldType == 10 {
+				numerator, denominator = int64(int32(numerator)), int64(int32(denominator))
+			}
+			values = append(values, fmt.Sprintf("%d/%d", numerator, denominator))
+		}
+	default:
+		if len(value) > 0 && allPrintableASCII(bytes.TrimRight(value, "\x00")) {
+			return quoteMetaText(value)
+		}
+		text := strings.ToUpper(hex.EncodeToString(value[:min(len(value), 16)]))
+		if len(value) > 16 {
+			text += fmt.Sprintf("… (%d bytes)", len(value))
+		}
+		return text
+	}
+	text := strings.Join(values, ", ")
+	if count > maxValues {
+		text += fmt.Sprintf(", … (%d values)", count)
+	}
+	return text
+}
+
+// PNG
+
+// parsePNG lists the chunks of a PNG file, with the text of its text chunks and the tags
+// of its Exif chunk
+func parsePNG(data []byte) []*metaNode {
+	nodes := []*metaNode{{offset: 0, size: 8, text: "PNG signature"}}
+	offset := 8
+	for offset+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		chunkType := string(data[offset+4 : offset+8])
+		end := offset + 12 + length
+		if length < 0 || end > len(data) {
+			nodes = append(nodes, &metaNode{offset: offset, size: len(data) - offset,
+				text: fmt.Sprintf("%s (truncated)", chunkType)})
+			break
+		}
+		payload := data[offset+8 : offset+8+length]
+		node := &metaNode{offset: offset, size: end - offset, text: fmt.Sprintf("%s (%d bytes)", chunkType, length)}
+		switch chunkType {
+		case "IHDR":
+			if len(payload) >= 10 {
+				node.text += fmt.Sprintf(": %dx%d, bit depth %d, color type %d", binary.BigEndian.Uint32(payload),
+					binary.BigEndian.Uint32(payload[4:]), payload[8], payload[9])
+			}
+		case "tEXt", "zTXt", "iTXt":
+			if keyword, text, ok := pngText(chunkType, payload); ok {
+				node.text += fmt.Sprintf(": %s = %s", keyword, quoteMetaText([]byte(text)))
+			}
+		case "tIME":
+			if len(payload) >= 7 {
+				node.text += fmt.Sprintf(": %04d-%02d-%02d %02d:%02d:%02d", binary.BigEndian.Uint16(payload),
+					payload[2], payload[3], payload[4], payload[5], payload[6])
+			}
+		case "pHYs":
+			if len(payload) >= 9 {
+				node.text += fmt.Sprintf(": %dx%d pixels per unit, unit %d", binary.BigEndian.Uint32(payload),
+					binary.BigEndian.Uint32(payload[4:]), payload[8])
+			}
+		case "eXIf":
+			node.children = parseTIFF(payload, offset+8)
+		}
+		nodes = append(nodes, node)
+		offset = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+	if offset < len(data) {
+		nodes = append(nodes, &metaNode{offset: offset, size: len(data) - offset,
+			text: fmt.Sprintf("Trailing data (%d bytes)", len(data)-offset)})
+	}
+	return nodes
+}
+
+// pngText returns the keyword and text of a tEXt, zTXt, or iTXt chunk, decompressing
+// the text if needed
+func pngText(chunkType string, payload []byte) (string, string, bool) {
+	keyword, rest, ok := bytes.Cut(payload, []byte{0})
+	if !ok {
+		return "", "", false
+	}
+	compressed := false
+	switch chunkType {
+	case "zTXt":
+		if len(rest) < 1 {
+			return "", "", false
+		}
+		rest, compressed = rest[1:], true
+	case "iTXt":
+		// Compression flag and method, then language tag and translated keyword
+		if len(rest) < 2 {
+			return "", "", false
+		}
+		compressed = rest[0] == 1
+		rest = rest[2:]
+		for range 2 {
+			if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok {
+				return "", "", false
+			}
+		}
+	}
+	if compressed {
+		reader, err := zlib.NewReader(bytes.NewReader(rest))
+		if err != nil {
+			return string(keyword), "<invalid compressed text>", true
+		}
+		rest, err = io.ReadAll(io.LimitReader(reader, 1<<20))
+		if err != nil {
+			return string(keyword), "<invalid compressed text>", true
+		}
+	}
+	return string(keyword), string(rest), true
+}
+
+// PDF
+
+// pdfInfoKeys are the keys of a PDF's document information dictionary
+var pdfInfoKeys = []string{"Title", "Author", "Subject", "Keywords", "Creator", "Producer", "CreationDate", "ModDate"}
+
+var (
+	pdfInfoPattern   = regexp.MustCompile(`/(` + strings.Join(pdfInfoKeys, "|") + `)\s*(\(|<[0-9A-Fa-f\s>])`)
+	pdfObjectPattern = regexp.MustCompile(`(?:^|[\r\n\s])(\d+)\s+(\d+)\s+obj\b`)
+	pdfTypePattern   = regexp.MustCompile(`/Type\s*/(\w+)`)
+	pdfXrefPattern   = regexp.MustCompile(`(?m)^(xref|trailer|startxref|%%EOF)`)
+)
+
+// maxPDFObjects limits the objects listed for a PDF
+const maxPDFObjects = 10000
+
+// parsePDF lists a PDF's header, document information, XMP metadata, objects, and the
+// cross-reference tables and trailers of each revision
+func parsePDF(data []byte) []*metaNode {
+	start := bytes.Index(data, []byte("%PDF-"))
+	header := data[start:]
+	if end := bytes.IndexAny(header, "\r\n"); end >= 0 {
+		header = header[:end]
+	}
+	nodes := []*metaNode{{offset: start, size: len(header), text: "Header: " + string(header)}}
+
+	info := &metaNode{offset: start, text: "Document information"}
+	for _, match := range pdfInfoPattern.FindAllSubmatchIndex(data, -1) {
+		value, end := pdfString(data, match[4])
+		info.children = append(info.children, &metaNode{offset: match[0], size: end - match[0],
+			text: fmt.Sprintf("%s: %s", data[match[2]:match[3]], quoteMetaText(value))})
+	}
+	if len(info.children) > 0 {
+		info.offset, info.size = info.children[0].offset, info.children[0].size
+		nodes = append(nodes, info)
+	}
+
+	for offset := 0; ; {
+		xmpStart := bytes.Index(data[offset:], []byte("<x:xmpmeta"))
+		if xmpStart < 0 {
+			break
+		}
+		xmpStart += offset
+		xmpEnd := bytes.Index(data[xmpStart:], []byte("</x:xmpmeta>"))
+		if xmpEnd < 0 {
+			xmpEnd = len(data) - xmpStart
+		} else {
+			xmpEnd += len("</x:xmpmeta>")
+		}
+		nodes = append(nodes, &metaNode{offset: xmpStart, size: xmpEnd,
+			text: fmt.Sprintf("XMP metadata (%d bytes)", xmpEnd)})
+		offset = xmpStart + xmpEnd
+	}
+
+	objects := &metaNode{offset: start}
+	matches := pdfObjectPattern.FindAllSubmatchIndex(data, maxPDFObjects)
+	for _, match := range matches {
+		objectStart := match[2]
+		end := bytes.Index(data[objectStart:], []byte("endobj"))
+		if end < 0 {
+			end = len(data) - objectStart
+		} else {
+			end += len("endobj")
+		}
+		text := fmt.Sprintf("%s %s obj", data[match[2]:match[3]], data[match[4]:match[5]])
+		if typeMatch := pdfTypePattern.FindSubmatch(data[objectStart:min(objectStart+end, objectStart+1024)]); typeMatch != nil {
+			text += " /" + string(typeMatch[1])
+		}
+		objects.children = append(objects.children, &metaNode{offset: objectStart, size: end,
+			text: fmt.Sprintf("%s (%d bytes)", text, end)})
+	}
+	objects.text = fmt.Sprintf("Objects (%d)", len(objects.children))
+	if len(matches) == maxPDFObjects {
+		objects.text = fmt.Sprintf("Objects (first %d)", maxPDFObjects)
+	}
+	if len(objects.children) > 0 {
+		last := objects.children[len(objects.children)-1]
+		objects.offset = objects.children[0].offset
+		objects.size = last.offset + last.size - objects.offset
+		nodes = append(nodes, objects)
+	}
+
+	// Each revision runs from the end of the one before it to its %%EOF
+	revision, revisions := &metaNode{offset: 0}, 0
+	for _, match := range pdfXrefPattern.FindAllSubmatchIndex(data, -1) {
+		keyword := string(data[match[2]:match[3]])
+		line := data[match[0]:]
+		if end := bytes.IndexAny(line[len(keyword):], "\r\n"); end >= 0 {
+			line = line[:len(keyword)+end]
+		}
+		text := keyword
+		if keyword == "startxref" {
+			if rest := bytes.Fields(data[match[1]:min(match[1]+32, len(data))]); len(rest) > 0 {
+				text += " " + string(rest[0])
+			}
+		}
+		revision.children = append(revision.children, &metaNode{offset: match[0], size: len(line), text: text})
+		if keyword == "%%EOF" {
+			revisions++
+			revision.text = fmt.Sprintf("Revision %d", revisions)
+			revision.size = match[1] - revision.offset
+			nodes = append(nodes, revision)
+			revision = &metaNode{offset: match[1]}
+		}
+	}
+	return nodes
+}
+
+// pdfString decodes the literal or hexadecimal PDF string starting at data[offset] and
+// returns it with the offset just past it. Strings starting with a UTF-16 byte order mark
+// are converted to UTF-8.
+func pdfString(data []byte, offset int) ([]byte, int) {
+	var value []byte
+	end := offset + 1
+	if data[offset] == '<' {
+		closing := bytes.IndexByte(data[end:], '>')
+		if closing < 0 {
+			return nil, end
+		}
+		digits := bytes.Map(func(r rune) rune {
+			if isHexDigit(byte(r)) {
+				return r
+			}
+			return -1
+		}, data[end:end+closing])
+		if len(digits)%2 == 1 {
+			digits = append(digits, '0')
+		}
+		value = make([]byte, len(digits)/2)
+		hex.Decode(value, digits)
+		end += closing + 1
+	} else {
+		// Literal strings may contain balanced parentheses and backslash escapes
+		for depth := 1; end < len(data); end++ {
+			c := data[end]
+			if c == '\\' && end+1 < len(data) {
+				end++
+				switch escaped := data[end]; escaped {
+				case 'n':
+					value = append(value, '\n')
+				case 'r':
+					value = append(value, '\r')
+				case 't':
+					value = append(value, '\t')
+				case '0', '1', '2', '3', '4', '5', '6', '7':
+					code := 0
+					for digits := 0; digits < 3 && end < len(data) && data[end] >= '0' && data[end] <= '7'; digits++ {
+						code = code*8 + int(data[end]-'0')
+						end++
+					}
+					end--
+					value = append(value, byte(code))
+				default:
+					value = append(value, escaped)
+				}
+				continue
+			}
+			if c == '(' {
+				depth++
+			} else if c == ')' {
+				if depth--; depth == 0 {
+					end++
+					break
+				}
+			}
+			value = append(value, c)
+		}
+	}
+	if len(value) >= 2 && value[0] == 0xFE && value[1] == 0xFF {
+		units := make([]uint16, (len(value)-2)/2)
+		for index := range units {
+			units[index] = binary.BigEndian.Uint16(value[2+2*index:])
+		}
+		value = []byte(string(utf16.Decode(units)))
+	}
+	return value, end
+}
+
+// MP4/QuickTime
+
+// containerAtoms are the MP4/QuickTime atoms made up of other atoms
+var containerAtoms = map[string]bool{
+	"moov": true, "trak": true, "mdia": true, "minf": true, "stbl": true, "udta": true, "edts": true, "dinf": true,
+	"mvex": true, "moof": true, "traf": true, "ilst": true, "meta": true, "tref": true, "mfra": true, "clip": true,
+	"matt": true, "sinf": true, "schi": true,
+}
+
+// isAtomType reports whether an MP4/QuickTime file can start with atoms of type name
+func isAtomType(name string) bool {
+	switch name {
+	case "ftyp", "moov", "mdat", "free", "skip", "wide", "pnot":
+		return true
+	}
+	return false
+}
+
+// mp4Epoch is the time MP4 creation and modification times count from
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// parseAtoms lists the atoms in data[start:end], which are inside an atom named parent,
+// and, recursively, the atoms inside container atoms
+func parseAtoms(data []byte, start, end, depth int, parent string) []*metaNode {
+	var nodes []*metaNode
+	for offset := start; offset+8 <= end; {
+		size, headerSize := int(binary.BigEndian.Uint32(data[offset:])), 8
+		name := string(data[offset+4 : offset+8])
+		switch size {
+		case 0:
+			size = end - offset // To the end of the file
+		case 1:
+			if offset+16 > end {
+				return nodes
+			}
+			largeSize := binary.BigEndian.Uint64(data[offset+8:])
+			size, headerSize = int(min(largeSize, uint64(end-offset))), 16
+		}
+		if size < headerSize || offset+size > end {
+			nodes = append(nodes, &metaNode{offset: offset, size: end - offset,
+				text: fmt.Sprintf("%s (truncated or invalid size)", quoteAtomName(name))})
+			return nodes
+		}
+		payload := data[offset+headerSize : offset+size]
+		node := &metaNode{offset: offset, size: size, text: fmt.Sprintf("%s (%d bytes)", quoteAtomName(name), size)}
+		if details := atomDetails(name, payload); details != "" {
+			node.text += ": " + details
+		}
+		if containerAtoms[name] && depth < maxMetaDepth {
+			childStart := offset + headerSize
+			// An MP4 meta atom has a version and flags before its atoms; a QuickTime one doesn't
+			if name == "meta" && len(payload) >= 4 && binary.BigEndian.Uint32(payload) == 0 {
+				childStart += 4
+			}
+			node.children = parseAtoms(data, childStart, offset+size, depth+1, name)
+		} else if parent == "ilst" {
+			// Metadata items hold their value in a data atom
+			node.children = parseAtoms(data, offset+headerSize, offset+size, depth+1, name)
+			if len(payload) >= 16 && string(payload[4:8]) == "data" {
+				node.text += ": " + atomDetails("data", payload[8:min(int(binary.BigEndian.Uint32(payload)), len(payload))])
+			}
+		}
+		nodes = append(nodes, node)
+		offset += size
+	}
+	return nodes
+}
+
+// quoteAtomName returns an atom's name, quoted if it isn't printable
+func quoteAtomName(name string) string {
+	// iTunes metadata items start with a © in Latin-1
+	if len(name) == 4 && name[0] == 0xA9 && allPrintableASCII([]byte(name[1:])) {
+		return "©" + name[1:]
+	}
+	if allPrintableASCII([]byte(name)) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// atomDetails describes the contents of the atoms that carry metadata
+func atomDetails(name string, payload []byte) string {
+	switch name {
+	case "ftyp":
+		if len(payload) < 8 {
+			return ""
+		}
+		var brands []string
+		for index := 8; index+4 <= len(payload); index += 4 {
+			brands = append(brands, string(payload[index:index+4]))
+		}
+		return fmt.Sprintf("brand %s, version %d, compatible %s", payload[:4], binary.BigEndian.Uint32(payload[4:]),
+			strings.Join(brands, " "))
+	case "mvhd", "mdhd":
+		var created, modified, timescale, duration uint64
+		switch {
+		case len(payload) >= 20 && payload[0] == 0:
+			created, modified = uint64(binary.BigEndian.Uint32(payload[4:])), uint64(binary.BigEndian.Uint32(payload[8:]))
+			timescale, duration = uint64(binary.BigEndian.Uint32(payload[12:])), uint64(binary.BigEndian.Uint32(payload[16:]))
+		case len(payload) >= 32 && payload[0] == 1:
+			created, modified = binary.BigEndian.Uint64(payload[4:]), binary.BigEndian.Uint64(payload[12:])
+			timescale, duration = uint64(binary.BigEndian.Uint32(payload[20:])), binary.BigEndian.Uint64(payload[24:])
+		default:
+			return ""
+		}
+		text := fmt.Sprintf("created %s, modified %s", mp4Epoch.Add(time.Duration(created)*time.Second).Format(time.DateTime),
+			mp4Epoch.Add(time.Duration(modified)*time.Second).Format(time.DateTime))
+		if timescale > 0 {
+			text += fmt.Sprintf(", duration %.3fs", float64(duration)/float64(timescale))
+		}
+		return text
+	case "tkhd":
+		if len(payload) < 84 {
+			return ""
+		}
+		width, height := binary.BigEndian.Uint32(payload[len(payload)-8:]), binary.BigEndian.Uint32(payload[len(payload)-4:])
+		return fmt.Sprintf("%gx%g", float64(width)/65536, float64(height)/65536)
+	case "hdlr":
+		if len(payload) < 12 {
+			return ""
+		}
+		return "handler " + quoteAtomName(string(payload[8:12]))
+	case "data":
+		// Type (1 is UTF-8 text) and locale before the value
+		if len(payload) < 8 {
+			return ""
+		}
+		if binary.BigEndian.Uint32(payload)&0xFFFFFF == 1 {
+			return quoteMetaText(payload[8:])
+		}
+		return fmt.Sprintf("%d bytes", len(payload)-8)
+	}
+	return ""
+}
+
+// showMetadata shows the structure and metadata of a JPEG, PNG, PDF, or MP4/QuickTime
+// file in a tree. Clicking a node selects its bytes and scrolls to them.
+func (h *HexDumpApp) showMetadata() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Media Metadata"), lang.L("No file is loaded."), h.window)
+		return
+	}
+	format, nodes := parseMetadata(h.fileData)
+	if format == "" {
+		dialog.ShowInformation(lang.L("Media Metadata"), lang.L("The file isn't a JPEG, PNG, PDF, or MP4/QuickTime file."), h.window)
+		return
+	}
+
+	// Node IDs are the indexes of the nodes on the path from the root, separated by "/"
+	node := func(uid widget.TreeNodeID) *metaNode {
+		var found *metaNode
+		children := nodes
+		for _, part := range strings.Split(uid, "/") {
+			index, err := strconv.Atoi(part)
+			if err != nil || index >= len(children) {
+				return nil
+			}
+			found = children[index]
+			children = found.children
+		}
+		return found
+	}
+
+	var tree *widget.Tree
+	tree = widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			children, prefix := nodes, ""
+			if uid != "" {
+				children, prefix = node(uid).children, uid+"/"
+			}
+			ids := make([]widget.TreeNodeID, len(children))
+			for index := range children {
+				ids[index] = prefix + strconv.Itoa(index)
+			}
+			return ids
+		},
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || len(node(uid).children) > 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			return newTappableLabel("")
+		},
+		func(uid widget.TreeNodeID, branch bool, object fyne.CanvasObject) {
+			label := object.(*tappableLabel)
+			metadata := node(uid)
+			label.SetText(fmt.Sprintf("%08X  %s", metadata.offset, metadata.text))
+			label.onTapped = func() {
+				tree.Select(uid)
+				if branch {
+					tree.ToggleBranch(uid)
+				}
+				if metadata.offset < len(h.fileData) {
+					h.setSelection(metadata.offset, min(metadata.offset+max(metadata.size, 1), len(h.fileData)))
+					h.goToOffset(metadata.offset)
+				}
+			}
+		},
+	)
+
+	window := h.app.NewWindow(fmt.Sprintf("Media Metadata - %s", h.fileName))
+	window.SetContent(container.NewBorder(widget.NewLabel(format+" file; click an item to go to it"), nil, nil, nil,
+		tree))
+	window.Resize(fyne.NewSize(600, 550))
+	window.Show()
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// startMonitoring asks for an interval and then re-reads the file at that interval,
+// counting how often each byte changes. The counts are shown as a heat map over the
+// data, so that the bytes a program writes most often stand out.
+func (h *HexDumpApp) startMonitoring() {
+	if h.fileName == "" {
+		dialog.ShowInformation(lang.L("Monitor"), lang.L("No file is loaded."), h.window)
+		return
+	}
+	if isRemoteName(h.fileName) {
+		dialog.ShowInformation(lang.L("Monitor"), lang.L("Only local files can be monitored."), h.window)
+		return
+	}
+
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText("1")
+	dialog.ShowForm(lang.L("Monitor File"), lang.L("Start"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Re-read every (seconds)"), intervalEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(intervalEntry.Text), 64)
+		if err != nil || seconds < 0.1 {
+			dialog.ShowError(fmt.Errorf("the interval must be a number of at least 0.1 seconds"), h.window)
+			return
+		}
+		h.confirmDiscardEdits(func() {
+			h.stopMonitoring()
+			h.changeCounts = make([]int, len(h.fileData))
+			h.monitorReads, h.hottestChange = 0, 0
+			h.monitorStop = make(chan struct{})
+			go h.monitorFile(h.fileName, time.Duration(seconds*float64(time.Second)), h.monitorStop)
+			h.updateStatus()
+		})
+	}, h.window)
+}
+
+// stopMonitoring stops re-reading the file. The heat map stays until the next file is
+// loaded or monitoring starts again.
+func (h *HexDumpApp) stopMonitoring() {
+	if h.monitorStop != nil {
+		close(h.monitorStop)
+		h.monitorStop = nil
+		h.updateStatus()
+	}
+}
+
+// monitorFile reads path every interval until stop is closed, passing the contents to
+// the GUI
+func (h *HexDumpApp) monitorFile(path string, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			data, mapped, err := readFileData(path)
+			fyne.Do(func() {
+				select {
+				case <-stop:
+					mapped.release()
+					return // Stopped while the file was being read
+				default:
+				}
+				if err == nil {
+					h.monitorRead(data, mapped)
+				}
+			})
+		}
+	}
+}
+
+// monitorRead replaces the file data with data read while monitoring, and mapped, its
+// mapping or nil, counting the bytes that changed. Reads are skipped while there are
+// unsaved edits.
+func (h *HexDumpApp) monitorRead(data []byte, mapped *mappedData) {
+	if h.isModified() {
+		mapped.release()
+		return
+	}
+	h.monitorReads++
+	for len(h.changeCounts) < len(data) {
+		h.changeCounts = append(h.changeCounts, 0)
+	}
+	for offset := range data {
+		if offset >= len(h.fileData) || data[offset] != h.fileData[offset] {
+			h.changeCounts[offset]++
+			h.hottestChange = max(h.hottestChange, h.changeCounts[offset])
+		}
+	}
+	h.fileData = data
+	h.releaseMapping()
+	h.mapped = mapped
+	h.resetHistory()
+	h.rescanData(0)
+	h.setSelection(h.selStart, h.selEnd) // Clamp the selection to the new size
+	h.updateDisplay()
+}
+
+// heatSpans returns highlight spans coloring the bytes intersecting [lineStart, lineEnd)
+// by how often they changed while monitoring, relative to the most changed byte
+func (h *HexDumpApp) heatSpans(lineStart, lineEnd int) []highlightSpan {
+	if h.changeCounts == nil {
+		return nil
+	}
+	var spans []highlightSpan
+	for offset := lineStart; offset < min(lineEnd, len(h.changeCounts)); offset++ {
+		count := h.changeCounts[offset]
+		if count == 0 {
+			continue
+		}
+		// Bytes that changed equally often share one span
+		if last := len(spans) - 1; last >= 0 && spans[last].end == offset && h.changeCounts[offset-1] == count {
+			spans[last].end++
+			continue
+		}
+		spans = append(spans, highlightSpan{start: offset, end: offset + 1,
+			color: heatColor(0.25 + 0.75*float64(count)/float64(h.hottestChange))})
+	}
+	return spans
+}
+
+// monitorStatus describes monitoring for the status bar
+func (h *HexDumpApp) monitorStatus() string {
+	if h.changeCounts == nil {
+		return ""
+	}
+	changed := 0
+	for _, count := range h.changeCounts {
+		if count > 0 {
+			changed++
+		}
+	}
+	status := fmt.Sprintf("%d reads, %d bytes changed", h.monitorReads, changed)
+	if h.monitorStop != nil {
+		return "Monitoring: " + status
+	}
+	return "Monitored: " + status
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+)
+
+// addSelectionRange keeps the current selection and starts another range at offset, as
+// Ctrl+click does, so that disjoint ranges can be selected together. Until the mouse
+// button is released, dragging extends the new range without dropping the others.
+func (h *HexDumpApp) addSelectionRange(offset int) {
+	ranges := h.extraRanges
+	if h.columnSelect {
+		ranges = h.columnRanges()
+	} else if h.hasSelection() {
+		ranges = append(ranges, byteRange{h.selStart, h.selEnd})
+	}
+	h.addingRange = true
+	h.extraRanges = ranges
+	h.selAnchor = offset
+	h.caret = offset
+	h.setSelection(offset, offset+1)
+}
+
+// endAddingRange ends the range started by addSelectionRange, once the mouse button is
+// released; the next selection replaces all the ranges again
+func (h *HexDumpApp) endAddingRange() {
+	h.addingRange = false
+}
+
+// selectedRanges returns the selected bytes as sorted, disjoint ranges: the rows of a
+// rectangular selection, or the ranges added with Ctrl+click and the current selection
+func (h *HexDumpApp) selectedRanges() []byteRange {
+	if h.columnSelect {
+		return h.columnRanges()
+	}
+	ranges := slices.Clone(h.extraRanges)
+	if h.hasSelection() {
+		ranges = append(ranges, byteRange{h.selStart, h.selEnd})
+	}
+	slices.SortFunc(ranges, func(a, b byteRange) int { return a.start - b.start })
+	var merged []byteRange
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && r.start <= merged[last].end {
+			merged[last].end = max(merged[last].end, r.end)
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// hasAnySelection reports whether any byte is selected, in one range or several
+func (h *HexDumpApp) hasAnySelection() bool {
+	return h.hasSelection() || h.columnSelect || len(h.extraRanges) > 0
+}
+
+// selectionLength returns the number of bytes in all the selected ranges
+func (h *HexDumpApp) selectionLength() int {
+	length := 0
+	for _, r := range h.selectedRanges() {
+		length += r.end - r.start
+	}
+	return length
+}
+
+// selectionData returns the bytes of all the selected ranges, one range after another
+func (h *HexDumpApp) selectionData() []byte {
+	ranges := h.selectedRanges()
+	if len(ranges) == 1 {
+		return h.fileData[ranges[0].start:ranges[0].end]
+	}
+	var data []byte
+	for _, r := range ranges {
+		data = append(data, h.fileData[r.start:r.end]...)
+	}
+	return data
+}
+
+// isSelected reports whether offset is in one of the selected ranges
+func (h *HexDumpApp) isSelected(offset int) bool {
+	return slices.ContainsFunc(h.selectedRanges(), func(r byteRange) bool {
+		return offset >= r.start && offset < r.end
+	})
+}
+
+// applyToSelection replaces the bytes of the selected ranges with data, which holds as
+// many bytes as selectionData, as a single step of the edit history
+func (h *HexDumpApp) applyToSelection(label string, data []byte) {
+	h.groupEdits(label, func() {
+		for _, r := range h.selectedRanges() {
+			h.changeBytes(label, r.start, data[:r.end-r.start])
+			data = data[r.end-r.start:]
+		}
+	})
+	h.editsChanged()
+}
+
+// writeRangesCSVExport returns an exportWriter writing the groups of each of ranges as
+// writeGroupsCSVExport does, one range after another
+func (h *HexDumpApp) writeRangesCSVExport(ranges []byteRange) exportWriter {
+	total := 0
+	for _, r := range ranges {
+		total += r.end - r.start
+	}
+	return func(w io.Writer, progress func(done, total int) bool) error {
+		done := 0
+		for _, r := range ranges {
+			write := h.writeGroupsCSVExport(r.start, r.end)
+			if err := write(w, func(written, _ int) bool { return progress(done+written, total) }); err != nil {
+				return err
+			}
+			done += r.end - r.start
+		}
+		return nil
+	}
+}
+
+// selectionTable formats the selection as a table with a line for each selected range
+// or row of a rectangle: the offset of its first byte, a tab, and its bytes in hex
+func (h *HexDumpApp) selectionTable() string {
+	var builder strings.Builder
+	for _, r := range h.selectedRanges() {
+		fmt.Fprintf(&builder, "%s\t%s\n", formatHex(uint64(r.start), 8),
+			h.formatCopy(copyHex, h.fileData[r.start:r.end]))
+	}
+	return builder.String()
+}
+
+// copySelectionTable copies the selection to the clipboard as a table, a line for each
+// selected range or row of a rectangle
+func (h *HexDumpApp) copySelectionTable() {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("Copy"), lang.L("Select the bytes to copy first."), h.window)
+		return
+	}
+	h.window.Clipboard().SetContent(h.selectionTable())
+}
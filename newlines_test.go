@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// binaryWithLineBreaks returns every byte value followed by the line breaks and the
+// Ctrl+Z end-of-file marker that text-mode tools translate
+func binaryWithLineBreaks() []byte {
+	data := make([]byte, 0, 300)
+	for value := 0; value < 256; value++ {
+		data = append(data, byte(value))
+	}
+	return append(data, "\r\n\n\r\n\r\r\x1A\x00\n"...)
+}
+
+func TestWriteDataFileKeepsBytes(t *testing.T) {
+	data := binaryWithLineBreaks()
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := writeDataFile(path, data); err != nil {
+		t.Fatal(err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, data) {
+		t.Errorf("wrote %d bytes that differ from the %d bytes given", len(written), len(data))
+	}
+}
+
+func TestWriteDecodedTextKeepsLineBreaks(t *testing.T) {
+	tests := []struct {
+		encoding string
+		data     []byte
+	}{
+		{"ISO Latin-1", []byte("a\r\nb\nc\rd")},
+		{"UTF-8", []byte("a\r\nb\nc\rd")},
+		{"GB 18030", []byte("a\r\nb\nc\rd")},
+		{"UTF-16LE", []byte("a\x00\r\x00\n\x00b\x00\n\x00c\x00\r\x00d\x00")},
+	}
+	for _, test := range tests {
+		for _, nonPrintable := range []string{nonPrintableDots, nonPrintableEscape, nonPrintableDrop} {
+			var output bytes.Buffer
+			if err := writeDecodedText(&output, test.data, test.encoding, nonPrintable, false); err != nil {
+				t.Fatal(err)
+			}
+			if got := output.String(); got != "a\r\nb\nc\rd" {
+				t.Errorf("%s, %s: got %q, want the line breaks unchanged", test.encoding, nonPrintable, got)
+			}
+		}
+
+		var output bytes.Buffer
+		if err := writeDecodedText(&output, test.data, test.encoding, nonPrintableDots, true); err != nil {
+			t.Fatal(err)
+		}
+		if got := output.String(); got != "a\nb\nc\nd" {
+			t.Errorf("%s converted to LF: got %q", test.encoding, got)
+		}
+	}
+}
+
+func TestTextDumpHasNoRawLineBreaks(t *testing.T) {
+	h := NewHexDumpApp(nil, nil)
+	h.fileData = binaryWithLineBreaks()
+	var output bytes.Buffer
+	if err := h.writeTextDump(&output); err != nil {
+		t.Fatal(err)
+	}
+	text := output.String()
+	if strings.Contains(text, "\r") || strings.Contains(text, "\x1A") {
+		t.Error("the text dump contains a CR or Ctrl+Z from the data")
+	}
+	if lines := strings.Count(text, "\n"); lines != (len(h.fileData)+h.bytesPerLine-1)/h.bytesPerLine {
+		t.Errorf("the text dump has %d lines for %d bytes", lines, len(h.fileData))
+	}
+}
+
+func TestPatchKeepsLineBreakBytes(t *testing.T) {
+	original := binaryWithLineBreaks()
+	current := append([]byte(nil), original...)
+	copy(current[10:], "\r\n\r\n")
+	copy(current[len(current)-4:], "\n\r\x1A\x00")
+	entries := patchEntries(original, current)
+
+	var output bytes.Buffer
+	if err := writePatch(&output, "data.bin", entries); err != nil {
+		t.Fatal(err)
+	}
+	// A patch list edited on Windows may have gained CRLF line endings
+	for _, text := range []string{output.String(), strings.ReplaceAll(output.String(), "\n", "\r\n")} {
+		parsed, err := parsePatch(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patched := append([]byte(nil), original...)
+		for _, entry := range parsed {
+			copy(patched[entry.offset:], entry.newBytes)
+		}
+		if !bytes.Equal(patched, current) {
+			t.Error("applying the patch list didn't reproduce the patched data")
+		}
+	}
+}
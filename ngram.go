@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// topTrigramCount is the number of most frequent trigrams listed
+const topTrigramCount = 32
+
+// ngramStats holds byte-pair and trigram frequencies of a block of data
+type ngramStats struct {
+	pairs       [256 * 256]int // Indexed by first byte * 256 + second byte
+	maxPair     int
+	totalPairs  int
+	usedPairs   int
+	topTrigrams []trigramCount
+}
+
+// trigramCount is the number of occurrences of one three-byte sequence
+type trigramCount struct {
+	bytes [3]byte
+	count int
+}
+
+// computeNgrams counts the byte pairs and trigrams in data
+func computeNgrams(data []byte) *ngramStats {
+	stats := &ngramStats{}
+	for index := 0; index+1 < len(data); index++ {
+		stats.pairs[int(data[index])<<8|int(data[index+1])]++
+	}
+	stats.totalPairs = max(0, len(data)-1)
+
+	for _, count := range stats.pairs {
+		if count > 0 {
+			stats.usedPairs++
+		}
+		stats.maxPair = max(stats.maxPair, count)
+	}
+
+	trigrams := make(map[uint32]int)
+	for index := 0; index+2 < len(data); index++ {
+		trigrams[uint32(data[index])<<16|uint32(data[index+1])<<8|uint32(data[index+2])]++
+	}
+	for key, count := range trigrams {
+		stats.topTrigrams = append(stats.topTrigrams, trigramCount{
+			bytes: [3]byte{byte(key >> 16), byte(key >> 8), byte(key)},
+			count: count,
+		})
+	}
+	sort.Slice(stats.topTrigrams, func(i, j int) bool {
+		if stats.topTrigrams[i].count != stats.topTrigrams[j].count {
+			return stats.topTrigrams[i].count > stats.topTrigrams[j].count
+		}
+		return string(stats.topTrigrams[i].bytes[:]) < string(stats.topTrigrams[j].bytes[:])
+	})
+	if len(stats.topTrigrams) > topTrigramCount {
+		stats.topTrigrams = stats.topTrigrams[:topTrigramCount]
+	}
+	return stats
+}
+
+// heatmap renders the pair frequencies as a 256x256 image, with the first byte of
+// each pair on the vertical axis and the second on the horizontal axis. Counts are
+// scaled logarithmically so that rare pairs remain visible.
+func (s *ngramStats) heatmap() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	scale := math.Log1p(float64(s.maxPair))
+	for first := 0; first < 256; first++ {
+		for second := 0; second < 256; second++ {
+			t := 0.0
+			if scale > 0 {
+				t = math.Log1p(float64(s.pairs[first<<8|second])) / scale
+			}
+			img.SetRGBA(second, first, heatColor(t))
+		}
+	}
+	return img
+}
+
+// showNgramView shows byte-pair and trigram statistics of the selection, or of the
+// whole file if nothing is selected
+func (h *HexDumpApp) showNgramView() {
+	data, scope := h.fileData, "whole file"
+	if h.hasSelection() {
+		data = h.selectedBytes()
+		scope = fmt.Sprintf("selection %08X-%08X", h.selStart, h.selEnd-1)
+	}
+	if len(data) < 2 {
+		dialog.ShowInformation(lang.L("Byte Pair Statistics"), lang.L("At least two bytes of data are needed."), h.window)
+		return
+	}
+
+	task := h.startTask(lang.L("Byte Pair Statistics"))
+	go func() {
+		defer h.recoverPanic()
+		stats := computeNgrams(data)
+		fyne.Do(func() {
+			show := func() { h.showNgramWindow(stats, scope, len(data)) }
+			if h.finishTask(task, fmt.Sprintf("%d byte pair(s)", stats.usedPairs), show) {
+				show()
+			}
+		})
+	}()
+}
+
+// showNgramWindow shows the pair heat map and top trigrams counted over length
+// bytes of scope.
+func (h *HexDumpApp) showNgramWindow(stats *ngramStats, scope string, length int) {
+	hoverLabel := widget.NewLabel(lang.L("Hover over the map to see pair counts"))
+	heatmap := newPixelView(stats.heatmap(), fyne.NewSize(512, 512))
+	heatmap.onHover = func(x, y int) {
+		count := stats.pairs[y<<8|x]
+		hoverLabel.SetText(fmt.Sprintf("Pair %02X %02X: %d (%.3f%%)", y, x, count,
+			100*float64(count)/float64(stats.totalPairs)))
+	}
+
+	trigramList := widget.NewList(
+		func() int { return len(stats.topTrigrams) },
+		func() fyne.CanvasObject { return widget.NewLabel("00 00 00  000000000") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			trigram := stats.topTrigrams[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%02X %02X %02X  %d",
+				trigram.bytes[0], trigram.bytes[1], trigram.bytes[2], trigram.count))
+		},
+	)
+
+	summary := widget.NewLabel(fmt.Sprintf("Scope: %s (%d bytes). %d of 65536 byte pairs occur (%.1f%%).",
+		scope, length, stats.usedPairs, 100*float64(stats.usedPairs)/65536))
+	axes := widget.NewLabel(lang.L("Vertical: first byte (00 at top). Horizontal: second byte (00 at left)."))
+
+	window := h.app.NewWindow(fmt.Sprintf("Byte Pair Statistics - %s", h.fileName))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(summary, axes),
+		hoverLabel,
+		nil,
+		container.NewBorder(widget.NewLabel(lang.L("Top trigrams")), nil, nil, nil, trigramList),
+		heatmap,
+	))
+	window.Show()
+}
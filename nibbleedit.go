@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// caretNibble returns 1 if the caret is on the low nibble of its byte, and 0 if it is
+// on the high nibble
+func (h *HexDumpApp) caretNibble() int {
+	if h.lowNibbleCaret == 2*h.caret+1 {
+		return 1
+	}
+	return 0
+}
+
+// setCaretNibble moves the caret to a nibble, counted from the high nibble of the first
+// byte, and selects its byte
+func (h *HexDumpApp) setCaretNibble(nibble int) {
+	offset, low := nibbleAt(nibble, len(h.fileData))
+	h.lowNibbleCaret = 0
+	if low {
+		h.lowNibbleCaret = 2*offset + 1
+	}
+	h.caret, h.selAnchor = offset, offset
+	h.setSelection(offset, offset+1)
+	h.goToOffset(offset)
+}
+
+// nibbleAt returns the offset of the byte holding a nibble, counted from the high nibble
+// of the first byte and clamped to data of length bytes, and whether it is the low nibble
+func nibbleAt(nibble, length int) (offset int, low bool) {
+	nibble = max(0, min(nibble, 2*length-1))
+	return nibble / 2, nibble%2 == 1
+}
+
+// withNibble returns value with its high or low nibble replaced by digit
+func withNibble(value byte, low bool, digit byte) byte {
+	if low {
+		return value&0xF0 | digit
+	}
+	return value&0x0F | digit<<4
+}
+
+// editsNibbles reports whether typing edits the nibble at the caret: the hex pane shows
+// hex digits, rather than signed values, and there is data
+func (h *HexDumpApp) editsNibbles() bool {
+	return !h.signedValues && h.caret < len(h.fileData)
+}
+
+// onTypedRune edits the data list when no widget has the focus: a hex digit replaces
+// the nibble at the caret, which then moves to the next nibble. The caret stays if the
+// edit is refused.
+func (h *HexDumpApp) onTypedRune(r rune) {
+	defer h.recoverPanic()
+	digit, ok := hexDigitValue(r)
+	if !ok || !h.editsNibbles() {
+		return
+	}
+	nibble := 2*h.caret + h.caretNibble()
+	value := withNibble(h.fileData[h.caret], nibble%2 == 1, digit)
+	if !h.changeBytes("Type", h.caret, []byte{value}) {
+		return
+	}
+	h.setCaretNibble(nibble + 1)
+	h.editsChanged()
+}
+
+// onTypedKey moves the caret when no widget has the focus: Left and Right move it by a
+// nibble, or extend the selection by a byte with Shift
+func (h *HexDumpApp) onTypedKey(event *fyne.KeyEvent) {
+	defer h.recoverPanic()
+	if len(h.fileData) == 0 || (event.Name != fyne.KeyLeft && event.Name != fyne.KeyRight) {
+		return
+	}
+	step := 1
+	if event.Name == fyne.KeyLeft {
+		step = -1
+	}
+	switch {
+	case h.shiftDown:
+		h.extendSelection(max(0, min(h.caret+step, len(h.fileData)-1)))
+		h.goToOffset(h.caret)
+	case h.signedValues:
+		h.setCaretNibble(2 * (h.caret + step))
+	default:
+		h.setCaretNibble(2*h.caret + h.caretNibble() + step)
+	}
+}
+
+// hexDigitValue returns the value of a hex digit
+func hexDigitValue(r rune) (byte, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		return byte(r - '0'), true
+	case r >= 'a' && r <= 'f':
+		return byte(r - 'a' + 10), true
+	case r >= 'A' && r <= 'F':
+		return byte(r - 'A' + 10), true
+	}
+	return 0, false
+}
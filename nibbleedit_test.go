@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestHexDigitValue(t *testing.T) {
+	tests := []struct {
+		r      rune
+		want   byte
+		wantOK bool
+	}{
+		{'0', 0, true},
+		{'9', 9, true},
+		{'a', 10, true},
+		{'f', 15, true},
+		{'A', 10, true},
+		{'F', 15, true},
+		{'g', 0, false},
+		{'G', 0, false},
+		{'/', 0, false},
+		{':', 0, false},
+		{' ', 0, false},
+		{'٣', 0, false},
+	}
+	for _, test := range tests {
+		got, ok := hexDigitValue(test.r)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("hexDigitValue(%q) = %d, %v, want %d, %v", test.r, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestNibbleAt(t *testing.T) {
+	tests := []struct {
+		nibble, length int
+		wantOffset     int
+		wantLow        bool
+	}{
+		{0, 4, 0, false},
+		{1, 4, 0, true},
+		{2, 4, 1, false},
+		{7, 4, 3, true},
+		{8, 4, 3, true}, // Past the end: the last nibble
+		{100, 4, 3, true},
+		{-1, 4, 0, false}, // Before the start: the first nibble
+		{1, 1, 0, true},
+		{2, 1, 0, true},
+	}
+	for _, test := range tests {
+		offset, low := nibbleAt(test.nibble, test.length)
+		if offset != test.wantOffset || low != test.wantLow {
+			t.Errorf("nibbleAt(%d, %d) = %d, %v, want %d, %v", test.nibble, test.length, offset, low,
+				test.wantOffset, test.wantLow)
+		}
+	}
+}
+
+func TestWithNibble(t *testing.T) {
+	tests := []struct {
+		value byte
+		low   bool
+		digit byte
+		want  byte
+	}{
+		{0x00, false, 0xA, 0xA0},
+		{0x00, true, 0xA, 0x0A},
+		{0x5C, false, 0x3, 0x3C},
+		{0x5C, true, 0x3, 0x53},
+		{0xFF, false, 0x0, 0x0F},
+		{0xFF, true, 0x0, 0xF0},
+	}
+	for _, test := range tests {
+		if got := withNibble(test.value, test.low, test.digit); got != test.want {
+			t.Errorf("withNibble(%02X, %v, %X) = %02X, want %02X", test.value, test.low, test.digit, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// offsetNames are the names usable in offset expressions, with what they stand for
+var offsetNames = map[string]func(h *HexDumpApp) int{
+	"end":   func(h *HexDumpApp) int { return len(h.fileData) },
+	"caret": func(h *HexDumpApp) int { return h.caret },
+	"here":  func(h *HexDumpApp) int { return h.caret },
+	"start": func(h *HexDumpApp) int { return h.selStart },
+}
+
+// evaluateOffset evaluates an offset expression typed by the user, such as "end-0x200"
+// or "caret+4*16". Numbers are written as for parseOffset, and "end" (the file size),
+// "caret" or "here" (the caret offset), and "start" (the selection start) may be used
+// with +, -, *, /, and parentheses.
+func (h *HexDumpApp) evaluateOffset(text string) (int, error) {
+	parser := &offsetParser{h: h, text: text}
+	value, err := parser.sum()
+	if err == nil && parser.skipSpaces() < len(text) {
+		err = fmt.Errorf("unexpected %q", text[parser.pos:])
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset expression %q: %v", strings.TrimSpace(text), err)
+	}
+	return value, nil
+}
+
+// offsetParser is a recursive-descent parser for offset expressions
+type offsetParser struct {
+	h    *HexDumpApp
+	text string
+	pos  int
+}
+
+// skipSpaces moves past white space and returns the new position
+func (p *offsetParser) skipSpaces() int {
+	for p.pos < len(p.text) && p.text[p.pos] == ' ' {
+		p.pos++
+	}
+	return p.pos
+}
+
+// next returns the next non-space character, or 0 at the end of the text
+func (p *offsetParser) next() byte {
+	if p.skipSpaces() < len(p.text) {
+		return p.text[p.pos]
+	}
+	return 0
+}
+
+// sum parses terms separated by + and -
+func (p *offsetParser) sum() (int, error) {
+	value, err := p.product()
+	for err == nil {
+		operator := p.next()
+		if operator != '+' && operator != '-' {
+			break
+		}
+		p.pos++
+		var operand int
+		if operand, err = p.product(); operator == '+' {
+			value += operand
+		} else {
+			value -= operand
+		}
+	}
+	return value, err
+}
+
+// product parses factors separated by * and /
+func (p *offsetParser) product() (int, error) {
+	value, err := p.factor()
+	for err == nil {
+		operator := p.next()
+		if operator != '*' && operator != '/' {
+			break
+		}
+		p.pos++
+		var operand int
+		if operand, err = p.factor(); err != nil {
+			break
+		}
+		if operator == '*' {
+			value *= operand
+		} else if operand == 0 {
+			err = fmt.Errorf("division by zero")
+		} else {
+			value /= operand
+		}
+	}
+	return value, err
+}
+
+// factor parses a number, a name, a negated factor, or a parenthesized sum
+func (p *offsetParser) factor() (int, error) {
+	switch p.next() {
+	case 0:
+		return 0, fmt.Errorf("missing value")
+	case '-':
+		p.pos++
+		value, err := p.factor()
+		return -value, err
+	case '(':
+		p.pos++
+		value, err := p.sum()
+		if err == nil && p.next() != ')' {
+			err = fmt.Errorf("missing )")
+		}
+		p.pos++
+		return value, err
+	}
+
+	start := p.pos
+	for p.pos < len(p.text) && (unicode.IsLetter(rune(p.text[p.pos])) || unicode.IsDigit(rune(p.text[p.pos]))) {
+		p.pos++
+	}
+	token := p.text[start:p.pos]
+	if token == "" {
+		return 0, fmt.Errorf("unexpected %q", p.text[start:])
+	}
+	if name, ok := offsetNames[strings.ToLower(token)]; ok {
+		return name(p.h), nil
+	}
+	return parseOffset(token)
+}
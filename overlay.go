@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// overlayRegion is the data after the end of a file's declared structure, such as bytes
+// appended to an executable, where installers keep payloads and malware hides
+type overlayRegion struct {
+	byteRange
+	format string
+}
+
+// pngSignature starts every PNG file
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+
+// findOverlay returns the data after the declared structure of a PE executable, a ZIP
+// archive or a PNG image, or nil if data is none of these or has nothing after it
+func findOverlay(data []byte) *overlayRegion {
+	format, end := "", 0
+	switch {
+	case bytes.HasPrefix(data, []byte("MZ")):
+		format, end = "PE", peEnd(data)
+	case bytes.HasPrefix(data, pngSignature):
+		format, end = "PNG", pngEnd(data)
+	case bytes.HasPrefix(data, []byte("PK")):
+		format, end = "ZIP", zipEnd(data)
+	}
+	if end <= 0 || end >= len(data) {
+		return nil
+	}
+	return &overlayRegion{byteRange{end, len(data)}, format}
+}
+
+// peEnd returns the end of the headers, the raw data of the sections, the COFF symbol
+// table that MinGW builds keep, and the certificate table of a PE executable, which are
+// after the sections, or 0
+func peEnd(data []byte) int {
+	file, err := pe.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return 0
+	}
+	end := 0
+	var security pe.DataDirectory
+	switch header := file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		end = int(header.SizeOfHeaders)
+		if header.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+			security = header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]
+		}
+	case *pe.OptionalHeader64:
+		end = int(header.SizeOfHeaders)
+		if header.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+			security = header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]
+		}
+	}
+	for _, section := range file.Sections {
+		end = max(end, int(section.Offset)+int(section.Size))
+	}
+	if symbols := int(file.PointerToSymbolTable); symbols > 0 {
+		// The string table follows the 18-byte symbols, starting with its own size
+		stringTable := symbols + 18*int(file.NumberOfSymbols)
+		if stringTable+4 <= len(data) {
+			end = max(end, stringTable+int(binary.LittleEndian.Uint32(data[stringTable:])))
+		}
+	}
+	// The certificate table's address is a file offset rather than a virtual address
+	return max(end, int(security.VirtualAddress)+int(security.Size))
+}
+
+// pngEnd returns the end of the IEND chunk of a PNG image, or 0
+func pngEnd(data []byte) int {
+	for offset := len(pngSignature); offset+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		end := offset + 12 + length
+		if length < 0 || end > len(data) {
+			return 0
+		}
+		if string(data[offset+4:offset+8]) == "IEND" {
+			return end
+		}
+		offset = end
+	}
+	return 0
+}
+
+// zipEnd returns the end of the comment of the last end of central directory record of
+// a ZIP archive, or 0
+func zipEnd(data []byte) int {
+	// The record is 22 bytes followed by a comment of at most 65535 bytes
+	searchStart := max(0, len(data)-22-0xFFFF)
+	record := bytes.LastIndex(data[searchStart:], []byte("PK\x05\x06"))
+	if record < 0 {
+		return 0
+	}
+	record += searchStart
+	if record+22 > len(data) {
+		return 0
+	}
+	return min(len(data), record+22+int(binary.LittleEndian.Uint16(data[record+20:])))
+}
+
+// updateOverlay finds the overlay again after the data changed
+func (h *HexDumpApp) updateOverlay() {
+	h.overlay = findOverlay(h.fileData)
+}
+
+// overlaySpans returns highlight spans for the overlay intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) overlaySpans(lineStart, lineEnd int) []highlightSpan {
+	if h.overlay == nil || h.overlay.end <= lineStart || h.overlay.start >= lineEnd {
+		return nil
+	}
+	return []highlightSpan{{start: max(h.overlay.start, lineStart), end: min(h.overlay.end, lineEnd),
+		color: theme.Color(colorNameOverlay)}}
+}
+
+// overlayStatus describes the overlay for the status bar
+func (h *HexDumpApp) overlayStatus() string {
+	if h.overlay == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s overlay: %d bytes at %s", h.overlay.format, h.overlay.end-h.overlay.start,
+		h.formatAddress(h.overlay.start))
+}
+
+// selectOverlay selects the overlay and scrolls to its start
+func (h *HexDumpApp) selectOverlay() {
+	if h.overlay == nil {
+		dialog.ShowInformation(lang.L("Overlay"), lang.L("No data follows the end of the file's PE, ZIP or PNG structure."),
+			h.window)
+		return
+	}
+	h.setSelection(h.overlay.start, h.overlay.end)
+	h.goToOffset(h.overlay.start)
+}
+
+// extractOverlay writes the overlay to a file chosen by the user
+func (h *HexDumpApp) extractOverlay() {
+	if h.overlay == nil {
+		h.selectOverlay()
+		return
+	}
+	name := strings.TrimSuffix(filepath.Base(h.fileName), filepath.Ext(h.fileName)) + ".overlay"
+	filename, err := nativedialog.File().Filter("All Files", "*").Title("Extract Overlay").SetStartFile(name).Save()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	h.exportInBackground(lang.L("Extract Overlay"), filename,
+		writeBytesExport(h.fileData[h.overlay.start:h.overlay.end]))
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// paddingValues lists the byte values whose long runs count as padding: zero fill,
+// erased flash, and the INT3 filler compilers place between functions
+var paddingValues = []byte{0x00, 0xFF, 0xCC}
+
+// paddingRegion is a run [start, end) of a single padding value
+type paddingRegion struct {
+	start, end int
+	value      byte
+}
+
+// collapsedLines is a range of whole lines [first, last] of a padding region, or of
+// lines hidden by the line filter, that the data list shows as one row
+type collapsedLines struct {
+	first, last int
+	value       byte
+	hidden      bool // Whether the lines are hidden by the filter rather than padding
+}
+
+// findPadding returns the runs of at least minLength bytes of one of the paddingValues
+func findPadding(data []byte, minLength int) []paddingRegion {
+	var regions []paddingRegion
+	for start := 0; start < len(data); {
+		end := start + 1
+		for end < len(data) && data[end] == data[start] {
+			end++
+		}
+		if end-start >= minLength && strings.IndexByte(string(paddingValues), data[start]) >= 0 {
+			regions = append(regions, paddingRegion{start: start, end: end, value: data[start]})
+		}
+		start = end
+	}
+	return regions
+}
+
+// showDetectPadding asks for the minimum length of padding and then detects, labels and
+// optionally collapses it. A minimum of 0 turns detection off.
+func (h *HexDumpApp) showDetectPadding() {
+	lengthEntry := widget.NewEntry()
+	lengthEntry.SetText(strconv.Itoa(max(h.paddingMin, 64)))
+	collapseCheck := widget.NewCheck(lang.L("Collapse padding to one line"), nil)
+	collapseCheck.SetChecked(h.collapsePadding)
+
+	lengthItem := widget.NewFormItem(lang.L("Minimum length"), lengthEntry)
+	lengthItem.HintText = "Bytes of 00, FF or CC; 0 turns detection off"
+	dialog.ShowForm(lang.L("Detect Padding"), lang.L("OK"), lang.L("Cancel"), []*widget.FormItem{
+		lengthItem,
+		widget.NewFormItem("", collapseCheck),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		minLength, err := parseOffset(lengthEntry.Text)
+		if err != nil || minLength < 0 || minLength == 1 {
+			dialog.ShowError(fmt.Errorf("the minimum length must be 0 or at least 2 bytes"), h.window)
+			return
+		}
+		h.paddingMin = minLength
+		h.collapsePadding = collapseCheck.Checked
+		h.updatePadding()
+		h.updateDisplay()
+		h.updateStatus()
+		h.goToOffset(h.caret)
+	}, h.window)
+}
+
+// toggleCollapsePadding collapses or expands the detected padding
+func (h *HexDumpApp) toggleCollapsePadding() {
+	if h.paddingMin == 0 {
+		dialog.ShowInformation(lang.L("Collapse Padding"), lang.L("Detect padding first (Tools → Detect Padding...)."), h.window)
+		return
+	}
+	h.collapsePadding = !h.collapsePadding
+	h.updateDisplay()
+	h.goToOffset(h.caret)
+}
+
+// updatePadding detects the padding again after the data changed
+func (h *HexDumpApp) updatePadding() {
+	h.padding = nil
+	if h.paddingMin > 0 {
+		h.padding = findPadding(h.fileData, h.paddingMin)
+	}
+}
+
+// updateCollapsed works out which lines the collapsed padding covers. Only the whole
+// lines of a region are collapsed, and only when there are at least two of them. While
+// lines are filtered, the lines hidden by the filter are collapsed instead.
+func (h *HexDumpApp) updateCollapsed() {
+	h.collapsed = nil
+	if h.filter != nil && h.bytesPerLine > 0 {
+		h.collapsed = h.filteredLines()
+		return
+	}
+	if !h.collapsePadding || h.bytesPerLine == 0 {
+		return
+	}
+	for _, region := range h.padding {
+		first := h.lineOf(region.start)
+		if h.lineStart(first) != region.start {
+			first++
+		}
+		last := h.lineOf(region.end - 1)
+		if h.lineEnd(h.lineStart(last)) != region.end {
+			last--
+		}
+		if last > first {
+			h.collapsed = append(h.collapsed, collapsedLines{first: first, last: last, value: region.value})
+		}
+	}
+}
+
+// paddingAt returns the padding region containing offset, or nil
+func (h *HexDumpApp) paddingAt(offset int) *paddingRegion {
+	index := sort.Search(len(h.padding), func(i int) bool { return h.padding[i].end > offset })
+	if index < len(h.padding) && h.padding[index].start <= offset {
+		return &h.padding[index]
+	}
+	return nil
+}
+
+// collapsedAt returns the collapsed lines shown by the row starting at offset, or nil
+func (h *HexDumpApp) collapsedAt(offset int) *collapsedLines {
+	if len(h.collapsed) == 0 {
+		return nil
+	}
+	line := h.lineOf(offset)
+	index := sort.Search(len(h.collapsed), func(i int) bool { return h.collapsed[i].last >= line })
+	if index < len(h.collapsed) && h.collapsed[index].first == line && h.lineStart(line) == offset {
+		return &h.collapsed[index]
+	}
+	return nil
+}
+
+// rowLine returns the first line shown by row of the data list. Rows are lines, except
+// that the lines of a collapsed padding region share one row.
+func (h *HexDumpApp) rowLine(row int) int {
+	for _, lines := range h.collapsed {
+		if row <= lines.first {
+			break
+		}
+		row += lines.last - lines.first
+	}
+	return row
+}
+
+// rowStart returns the offset of the first byte shown by row of the data list
+func (h *HexDumpApp) rowStart(row int) int {
+	return h.lineStart(h.rowLine(row))
+}
+
+// rowOf returns the row of the data list showing the byte at offset
+func (h *HexDumpApp) rowOf(offset int) int {
+	line := h.lineOf(offset)
+	row := line
+	for _, lines := range h.collapsed {
+		if line <= lines.first {
+			break
+		}
+		row -= min(line, lines.last) - lines.first
+	}
+	return row
+}
+
+// rowEnd returns the offset after the last byte of the row starting at offset
+func (h *HexDumpApp) rowEnd(offset int) int {
+	if lines := h.collapsedAt(offset); lines != nil {
+		return h.lineEnd(h.lineStart(lines.last))
+	}
+	return h.lineEnd(offset)
+}
+
+// rowCount returns the number of rows of the data list
+func (h *HexDumpApp) rowCount() int {
+	count := h.lineCount()
+	for _, lines := range h.collapsed {
+		count -= lines.last - lines.first
+	}
+	return count
+}
+
+// collapsedRowText returns the text of the row showing collapsed lines from offset
+func (h *HexDumpApp) collapsedRowText(offset int, lines *collapsedLines) string {
+	if lines.hidden {
+		return h.filteredRowText(offset, lines)
+	}
+	return fmt.Sprintf("%s: ... %d bytes of %s padding ...", h.formatAddress(offset),
+		h.rowEnd(offset)-offset, formatHex(uint64(lines.value), 2))
+}
+
+// paddingSpans returns highlight spans for the padding intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) paddingSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	first := sort.Search(len(h.padding), func(i int) bool { return h.padding[i].end > lineStart })
+	for _, region := range h.padding[first:] {
+		if region.start >= lineEnd {
+			break
+		}
+		spans = append(spans, highlightSpan{start: max(region.start, lineStart), end: min(region.end, lineEnd),
+			color: theme.Color(colorNamePadding)})
+	}
+	return spans
+}
+
+// paddingStatus describes the padding at the caret for the status bar
+func (h *HexDumpApp) paddingStatus() string {
+	region := h.paddingAt(h.caret)
+	if region == nil {
+		return ""
+	}
+	return fmt.Sprintf("Padding: %d bytes of %s", region.end-region.start, formatHex(uint64(region.value), 2))
+}
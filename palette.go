@@ -0,0 +1,206 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// Theme color names of the colors the data view and the tools draw with. CustomTheme
+// looks them up in the palette chosen in the Preferences dialog.
+const (
+	colorNameSelection      fyne.ThemeColorName = "hexSelection"
+	colorNameModified       fyne.ThemeColorName = "hexModified"
+	colorNameSnapshot       fyne.ThemeColorName = "hexSnapshot"      // Bytes that differ from the snapshot
+	colorNameMasked         fyne.ThemeColorName = "hexMasked"        // Regions comparisons ignore
+	colorNameReferenceDiff  fyne.ThemeColorName = "hexReferenceDiff" // Bytes that differ from the expected pattern
+	colorNamePadding        fyne.ThemeColorName = "hexPadding"
+	colorNameOverlay        fyne.ThemeColorName = "hexOverlay"   // Data after the end of a file's structure
+	colorNameSignature      fyne.ThemeColorName = "hexSignature" // Recognized file signatures
+	colorNamePointer        fyne.ThemeColorName = "hexPointer"   // Values found by the pointer scan
+	colorNameYARA           fyne.ThemeColorName = "hexYARA"
+	colorNameTLVHeader      fyne.ThemeColorName = "hexTLVHeader"
+	colorNameTLVValue       fyne.ThemeColorName = "hexTLVValue"
+	colorNameTLVValueAlt    fyne.ThemeColorName = "hexTLVValueAlt"
+	colorNameLineChecksum   fyne.ThemeColorName = "hexLineChecksum"   // Text of the checksum column
+	colorNameRecordBoundary fyne.ThemeColorName = "hexRecordBoundary" // Rule above records and pages
+	colorNameTooltip        fyne.ThemeColorName = "hexTooltip"
+	colorNameBar            fyne.ThemeColorName = "hexBar" // Toolbar and status bar
+	colorNameGraphLine      fyne.ThemeColorName = "hexGraphLine"
+	colorNameGraphAxis      fyne.ThemeColorName = "hexGraphAxis"
+	colorNameWaveform       fyne.ThemeColorName = "hexWaveform"
+
+	// Byte classes of the binary visualization
+	colorNameClassZero      fyne.ThemeColorName = "hexClassZero"
+	colorNameClassFF        fyne.ThemeColorName = "hexClassFF"
+	colorNameClassPrintable fyne.ThemeColorName = "hexClassPrintable"
+	colorNameClassControl   fyne.ThemeColorName = "hexClassControl"
+	colorNameClassHigh      fyne.ThemeColorName = "hexClassHigh"
+
+	// Headers of each kind of SQLite page
+	colorNameSQLiteTableInterior fyne.ThemeColorName = "hexSQLiteTableInterior"
+	colorNameSQLiteTableLeaf     fyne.ThemeColorName = "hexSQLiteTableLeaf"
+	colorNameSQLiteIndexInterior fyne.ThemeColorName = "hexSQLiteIndexInterior"
+	colorNameSQLiteIndexLeaf     fyne.ThemeColorName = "hexSQLiteIndexLeaf"
+	colorNameSQLiteFreeTrunk     fyne.ThemeColorName = "hexSQLiteFreeTrunk"
+	colorNameSQLiteFreeLeaf      fyne.ThemeColorName = "hexSQLiteFreeLeaf"
+	colorNameSQLiteOverflow      fyne.ThemeColorName = "hexSQLiteOverflow"
+)
+
+// bookmarkColors are the theme colors assigned to bookmarks and template fields in turn
+var bookmarkColors = []fyne.ThemeColorName{
+	"hexBookmark1", "hexBookmark2", "hexBookmark3", "hexBookmark4", "hexBookmark5", "hexBookmark6",
+}
+
+// paletteNames maps the palettes to their names in the Preferences dialog
+var paletteNames = map[string]string{
+	paletteStandard:     "Standard",
+	paletteColorBlind:   "Color-blind safe",
+	paletteHighContrast: "High contrast",
+}
+
+// palettes holds the colors of each palette. The standard palette sets every color;
+// the others fall back to it for the colors they don't set. Backgrounds are dark enough
+// for white text to remain readable on top of them.
+var palettes = map[string]map[fyne.ThemeColorName]color.Color{
+	paletteStandard: {
+		colorNameSelection:      color.RGBA{R: 38, G: 79, B: 120, A: 255},
+		colorNameModified:       color.RGBA{R: 110, G: 40, B: 40, A: 255},
+		colorNameSnapshot:       color.RGBA{R: 120, G: 90, B: 20, A: 255},
+		colorNameMasked:         color.RGBA{R: 62, G: 62, B: 48, A: 255},
+		colorNameReferenceDiff:  color.RGBA{R: 130, G: 60, B: 110, A: 255},
+		colorNamePadding:        color.RGBA{R: 55, G: 55, B: 62, A: 255},
+		colorNameOverlay:        color.RGBA{R: 90, G: 45, B: 70, A: 255},
+		colorNameSignature:      color.RGBA{R: 45, G: 80, B: 70, A: 255},
+		colorNamePointer:        color.RGBA{R: 40, G: 90, B: 130, A: 255},
+		colorNameYARA:           color.RGBA{R: 130, G: 40, B: 110, A: 255},
+		colorNameTLVHeader:      color.RGBA{R: 120, G: 80, B: 30, A: 255},
+		colorNameTLVValue:       color.RGBA{R: 45, G: 70, B: 55, A: 255},
+		colorNameTLVValueAlt:    color.RGBA{R: 45, G: 55, B: 80, A: 255},
+		colorNameLineChecksum:   color.RGBA{R: 150, G: 170, B: 150, A: 255},
+		colorNameRecordBoundary: color.RGBA{R: 110, G: 110, B: 110, A: 255},
+		colorNameTooltip:        color.RGBA{R: 60, G: 60, B: 70, A: 240},
+		colorNameBar:            color.RGBA{R: 45, G: 45, B: 45, A: 255},
+		colorNameGraphLine:      color.RGBA{R: 55, G: 126, B: 184, A: 255},
+		colorNameGraphAxis:      color.RGBA{R: 90, G: 90, B: 90, A: 255},
+		colorNameWaveform:       color.RGBA{R: 77, G: 175, B: 74, A: 255},
+
+		// In the style of popular binary visualizers
+		colorNameClassZero:      color.RGBA{R: 0, G: 0, B: 0, A: 255},
+		colorNameClassFF:        color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		colorNameClassPrintable: color.RGBA{R: 55, G: 126, B: 184, A: 255},
+		colorNameClassControl:   color.RGBA{R: 77, G: 175, B: 74, A: 255},
+		colorNameClassHigh:      color.RGBA{R: 228, G: 26, B: 28, A: 255},
+
+		colorNameSQLiteTableInterior: color.RGBA{R: 30, G: 90, B: 60, A: 255},
+		colorNameSQLiteTableLeaf:     color.RGBA{R: 50, G: 120, B: 50, A: 255},
+		colorNameSQLiteIndexInterior: color.RGBA{R: 40, G: 70, B: 120, A: 255},
+		colorNameSQLiteIndexLeaf:     color.RGBA{R: 60, G: 90, B: 150, A: 255},
+		colorNameSQLiteFreeTrunk:     color.RGBA{R: 120, G: 60, B: 30, A: 255},
+		colorNameSQLiteFreeLeaf:      color.RGBA{R: 90, G: 60, B: 40, A: 255},
+		colorNameSQLiteOverflow:      color.RGBA{R: 100, G: 100, B: 40, A: 255},
+
+		bookmarkColors[0]: color.RGBA{R: 110, G: 85, B: 20, A: 255},
+		bookmarkColors[1]: color.RGBA{R: 30, G: 95, B: 60, A: 255},
+		bookmarkColors[2]: color.RGBA{R: 105, G: 40, B: 95, A: 255},
+		bookmarkColors[3]: color.RGBA{R: 40, G: 80, B: 110, A: 255},
+		bookmarkColors[4]: color.RGBA{R: 115, G: 50, B: 40, A: 255},
+		bookmarkColors[5]: color.RGBA{R: 70, G: 70, B: 110, A: 255},
+	},
+
+	// Darkened Okabe-Ito colors, which stay distinct without telling red from green
+	paletteColorBlind: {
+		colorNameSelection:      color.RGBA{R: 0, G: 80, B: 140, A: 255},
+		colorNameModified:       color.RGBA{R: 140, G: 62, B: 0, A: 255},
+		colorNameSnapshot:       color.RGBA{R: 112, G: 66, B: 92, A: 255},
+		colorNameReferenceDiff:  color.RGBA{R: 150, G: 80, B: 0, A: 255},
+		colorNameOverlay:        color.RGBA{R: 96, G: 48, B: 72, A: 255},
+		colorNameSignature:      color.RGBA{R: 0, G: 84, B: 62, A: 255},
+		colorNamePointer:        color.RGBA{R: 28, G: 90, B: 117, A: 255},
+		colorNameYARA:           color.RGBA{R: 110, G: 102, B: 24, A: 255},
+		colorNameTLVHeader:      color.RGBA{R: 120, G: 83, B: 0, A: 255},
+		colorNameTLVValue:       color.RGBA{R: 0, G: 68, B: 52, A: 255},
+		colorNameTLVValueAlt:    color.RGBA{R: 30, G: 64, B: 92, A: 255},
+		colorNameLineChecksum:   color.RGBA{R: 160, G: 180, B: 210, A: 255},
+		colorNameGraphLine:      color.RGBA{R: 0, G: 114, B: 178, A: 255},
+		colorNameWaveform:       color.RGBA{R: 86, G: 180, B: 233, A: 255},
+		colorNameClassPrintable: color.RGBA{R: 86, G: 180, B: 233, A: 255},
+		colorNameClassControl:   color.RGBA{R: 230, G: 159, B: 0, A: 255},
+		colorNameClassHigh:      color.RGBA{R: 204, G: 121, B: 167, A: 255},
+
+		colorNameSQLiteTableInterior: color.RGBA{R: 0, G: 70, B: 52, A: 255},
+		colorNameSQLiteTableLeaf:     color.RGBA{R: 0, G: 100, B: 74, A: 255},
+		colorNameSQLiteIndexInterior: color.RGBA{R: 0, G: 64, B: 112, A: 255},
+		colorNameSQLiteIndexLeaf:     color.RGBA{R: 36, G: 98, B: 140, A: 255},
+		colorNameSQLiteFreeTrunk:     color.RGBA{R: 130, G: 58, B: 0, A: 255},
+		colorNameSQLiteFreeLeaf:      color.RGBA{R: 110, G: 78, B: 0, A: 255},
+		colorNameSQLiteOverflow:      color.RGBA{R: 104, G: 98, B: 28, A: 255},
+
+		bookmarkColors[0]: color.RGBA{R: 120, G: 83, B: 0, A: 255},
+		bookmarkColors[1]: color.RGBA{R: 28, G: 90, B: 117, A: 255},
+		bookmarkColors[2]: color.RGBA{R: 0, G: 84, B: 62, A: 255},
+		bookmarkColors[3]: color.RGBA{R: 104, G: 98, B: 28, A: 255},
+		bookmarkColors[4]: color.RGBA{R: 112, G: 66, B: 92, A: 255},
+		bookmarkColors[5]: color.RGBA{R: 130, G: 50, B: 0, A: 255},
+	},
+
+	// Saturated highlights on a black background, with bright text and rules
+	paletteHighContrast: {
+		theme.ColorNameBackground: color.RGBA{A: 255},
+
+		colorNameSelection:      color.RGBA{R: 0, G: 60, B: 220, A: 255},
+		colorNameModified:       color.RGBA{R: 190, G: 0, B: 0, A: 255},
+		colorNameSnapshot:       color.RGBA{R: 150, G: 0, B: 150, A: 255},
+		colorNameMasked:         color.RGBA{R: 90, G: 90, B: 90, A: 255},
+		colorNameReferenceDiff:  color.RGBA{R: 200, G: 0, B: 90, A: 255},
+		colorNamePadding:        color.RGBA{R: 70, G: 70, B: 70, A: 255},
+		colorNameOverlay:        color.RGBA{R: 140, G: 0, B: 70, A: 255},
+		colorNameSignature:      color.RGBA{R: 0, G: 120, B: 0, A: 255},
+		colorNamePointer:        color.RGBA{R: 0, G: 110, B: 160, A: 255},
+		colorNameYARA:           color.RGBA{R: 140, G: 90, B: 0, A: 255},
+		colorNameTLVHeader:      color.RGBA{R: 170, G: 90, B: 0, A: 255},
+		colorNameTLVValue:       color.RGBA{R: 0, G: 100, B: 0, A: 255},
+		colorNameTLVValueAlt:    color.RGBA{R: 0, G: 0, B: 150, A: 255},
+		colorNameLineChecksum:   color.RGBA{R: 255, G: 255, B: 0, A: 255},
+		colorNameRecordBoundary: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		colorNameTooltip:        color.RGBA{A: 255},
+		colorNameBar:            color.RGBA{A: 255},
+		colorNameGraphLine:      color.RGBA{R: 0, G: 200, B: 255, A: 255},
+		colorNameGraphAxis:      color.RGBA{R: 200, G: 200, B: 200, A: 255},
+		colorNameWaveform:       color.RGBA{R: 0, G: 255, B: 0, A: 255},
+		colorNameClassPrintable: color.RGBA{R: 0, G: 150, B: 255, A: 255},
+		colorNameClassControl:   color.RGBA{R: 0, G: 220, B: 0, A: 255},
+		colorNameClassHigh:      color.RGBA{R: 255, G: 0, B: 0, A: 255},
+
+		colorNameSQLiteTableInterior: color.RGBA{R: 0, G: 110, B: 50, A: 255},
+		colorNameSQLiteTableLeaf:     color.RGBA{R: 0, G: 150, B: 0, A: 255},
+		colorNameSQLiteIndexInterior: color.RGBA{R: 0, G: 50, B: 170, A: 255},
+		colorNameSQLiteIndexLeaf:     color.RGBA{R: 0, G: 90, B: 210, A: 255},
+		colorNameSQLiteFreeTrunk:     color.RGBA{R: 180, G: 60, B: 0, A: 255},
+		colorNameSQLiteFreeLeaf:      color.RGBA{R: 130, G: 60, B: 0, A: 255},
+		colorNameSQLiteOverflow:      color.RGBA{R: 130, G: 130, B: 0, A: 255},
+
+		bookmarkColors[0]: color.RGBA{R: 170, G: 110, B: 0, A: 255},
+		bookmarkColors[1]: color.RGBA{R: 0, G: 120, B: 40, A: 255},
+		bookmarkColors[2]: color.RGBA{R: 150, G: 0, B: 150, A: 255},
+		bookmarkColors[3]: color.RGBA{R: 0, G: 80, B: 190, A: 255},
+		bookmarkColors[4]: color.RGBA{R: 180, G: 40, B: 0, A: 255},
+		bookmarkColors[5]: color.RGBA{R: 80, G: 80, B: 170, A: 255},
+	},
+}
+
+// paletteColor returns the named color of the palette chosen in the preferences, or
+// false if the palettes don't set it
+func paletteColor(name fyne.ThemeColorName) (color.Color, bool) {
+	if c, ok := palettes[appSettings.Palette][name]; ok {
+		return c, true
+	}
+	c, ok := palettes[paletteStandard][name]
+	return c, ok
+}
+
+// paletteRGBA returns the named theme color as RGBA, for drawing into images
+func paletteRGBA(name fyne.ThemeColorName) color.RGBA {
+	return color.RGBAModel.Convert(theme.Color(name)).(color.RGBA)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Side panel names, which are also their tab titles
+const (
+	panelInspector = "Inspector"
+	panelStrings   = "Strings"
+	panelBookmarks = "Bookmarks"
+	panelSearch    = "Search Results"
+	panelStructure = "Structure"
+	panelChecksums = "Checksums"
+	panelText      = "Text Preview"
+	panelWatches   = "Watches"
+	panelGraph     = "Graph"
+	panelFields    = "Fields"
+	panelDisk      = "Disk Layout"
+	panelBits      = "Bit Stream"
+	panelHistory   = "History"
+)
+
+// minPanelWindowWidth is the window width the side panel needs to be usable
+const minPanelWindowWidth = 1000
+
+// sidePanel is one tab of the side panel. Its refresh function, if set, is called when
+// the tab is shown and whenever the file, selection, or caret changes while it is shown.
+// Its reset function, if set, is called when another file is loaded, to discard results.
+// A detached panel is shown in its own window instead of a tab.
+type sidePanel struct {
+	name    string
+	tab     *container.TabItem
+	object  fyne.CanvasObject
+	refresh func()
+	reset   func()
+	window  fyne.Window
+}
+
+// createSidePanels creates the tabbed side panel host and registers the built-in panels
+func (h *HexDumpApp) createSidePanels() *container.AppTabs {
+	h.panelTabs = container.NewAppTabs()
+
+	h.addPanel(panelInspector, h.createInspectorPanel())
+	h.addPanel(panelStrings, h.createStringsPanel())
+	h.addPanel(panelBookmarks, h.createBookmarksPanel())
+	h.addPanel(panelSearch, h.createSearchPanel())
+	h.addPanel(panelStructure, h.createStructurePanel())
+	h.addPanel(panelChecksums, h.createChecksumsPanel())
+	h.addPanel(panelText, h.createTextPreviewPanel())
+	h.addPanel(panelWatches, h.createWatchPanel())
+	h.addPanel(panelGraph, h.createGraphPanel())
+	h.addPanel(panelFields, h.createFieldsPanel())
+	h.addPanel(panelDisk, h.createDiskPanel())
+	h.addPanel(panelBits, h.createBitStreamPanel())
+	h.addPanel(panelHistory, h.createHistoryPanel())
+
+	if panel := h.panel(appSettings.PanelTab); panel != nil {
+		h.panelTabs.Select(panel.tab)
+	}
+	h.panelTabs.OnSelected = func(tab *container.TabItem) {
+		for _, panel := range h.panels {
+			if panel.tab == tab {
+				appSettings.PanelTab = panel.name
+			}
+		}
+		saveSettings()
+		h.refreshPanels()
+	}
+	return h.panelTabs
+}
+
+// addPanel adds a tab to the side panel. The content is a panel's widgets together
+// with its refresh function.
+func (h *HexDumpApp) addPanel(name string, content panelContent) {
+	panel := &sidePanel{
+		name:    name,
+		tab:     container.NewTabItem(lang.L(name), container.NewBorder(newPanelGrip(h, name), nil, nil, nil, content.object)),
+		object:  content.object,
+		refresh: content.refresh,
+		reset:   content.reset,
+	}
+	h.panels = append(h.panels, panel)
+	h.panelTabs.Append(panel.tab)
+}
+
+// initialWindowSize returns the size of a new main window, wide enough for the side
+// panel if it is shown
+func initialWindowSize() fyne.Size {
+	if appSettings.PanelVisible {
+		return fyne.NewSize(minPanelWindowWidth, 600)
+	}
+	return fyne.NewSize(800, 600)
+}
+
+// panelContent is the widgets and refresh function of a side panel, as returned by the
+// functions creating each panel
+type panelContent struct {
+	object  fyne.CanvasObject
+	refresh func()
+	reset   func()
+}
+
+// panel returns the side panel with the given name, or nil
+func (h *HexDumpApp) panel(name string) *sidePanel {
+	for _, panel := range h.panels {
+		if panel.name == name {
+			return panel
+		}
+	}
+	return nil
+}
+
+// showPanel shows the side panel with the named tab selected, or brings the panel's
+// window to the front if it is detached
+func (h *HexDumpApp) showPanel(name string) {
+	panel := h.panel(name)
+	if panel != nil && panel.window != nil {
+		panel.window.RequestFocus()
+		return
+	}
+	if panel != nil {
+		h.panelTabs.Select(panel.tab)
+	}
+	h.setPanelVisible(true)
+}
+
+// detachSelectedPanel moves the selected side panel tab into its own window
+func (h *HexDumpApp) detachSelectedPanel() {
+	selected := h.panelTabs.Selected()
+	for _, panel := range h.panels {
+		if panel.tab == selected {
+			h.detachPanel(panel.name)
+			return
+		}
+	}
+}
+
+// detachPanel moves the named side panel out of the tabs into its own window. The
+// panel keeps its widgets, and so its results, and is docked again when the window
+// is closed.
+func (h *HexDumpApp) detachPanel(name string) {
+	panel := h.panel(name)
+	if panel == nil || panel.window != nil {
+		return
+	}
+
+	h.panelTabs.Remove(panel.tab)
+	panel.tab.Content.(*fyne.Container).Remove(panel.object)
+	panel.object.Show() // The tabs hide the content of unselected tabs
+
+	window := h.app.NewWindow(lang.L(panel.name))
+	window.SetContent(panel.object)
+	window.Resize(fyne.NewSize(400, 500))
+	window.SetOnClosed(func() {
+		panel.window = nil
+		h.dockPanel(panel)
+	})
+	panel.window = window
+	h.detachedChanged()
+	window.Show()
+
+	if panel.refresh != nil {
+		panel.refresh()
+	}
+	h.refreshPanels()
+}
+
+// dockPanel returns a detached panel to its place among the side panel tabs
+func (h *HexDumpApp) dockPanel(docked *sidePanel) {
+	var tabs []*container.TabItem
+	for _, panel := range h.panels {
+		if panel.window == nil {
+			tabs = append(tabs, panel.tab)
+		}
+	}
+	docked.tab.Content.(*fyne.Container).Add(docked.object)
+	h.panelTabs.SetItems(tabs)
+	h.panelTabs.Select(docked.tab)
+	if !h.closing {
+		h.detachedChanged()
+	}
+}
+
+// dockAllPanels closes the windows of all detached panels, returning them to the tabs
+func (h *HexDumpApp) dockAllPanels() {
+	for _, panel := range h.panels {
+		if panel.window != nil {
+			panel.window.Close()
+		}
+	}
+}
+
+// detachedChanged remembers which panels are detached
+func (h *HexDumpApp) detachedChanged() {
+	appSettings.DetachedPanels = nil
+	for _, panel := range h.panels {
+		if panel.window != nil {
+			appSettings.DetachedPanels = append(appSettings.DetachedPanels, panel.name)
+		}
+	}
+	saveSettings()
+}
+
+// restoreDetachedPanels detaches the panels that were detached in the last session
+func (h *HexDumpApp) restoreDetachedPanels() {
+	for _, name := range append([]string(nil), appSettings.DetachedPanels...) {
+		h.detachPanel(name)
+	}
+}
+
+// togglePanels shows or hides the side panel
+func (h *HexDumpApp) togglePanels() {
+	h.setPanelVisible(!appSettings.PanelVisible)
+}
+
+// setPanelVisible shows or hides the side panel and remembers the choice
+func (h *HexDumpApp) setPanelVisible(visible bool) {
+	h.rememberPanelLayout()
+	appSettings.PanelVisible = visible
+	saveSettings()
+	h.layoutPanels()
+
+	if visible && h.window.Canvas().Size().Width < minPanelWindowWidth {
+		h.window.Resize(fyne.NewSize(minPanelWindowWidth, h.window.Canvas().Size().Height))
+	}
+}
+
+// layoutPanels places the data list alone or beside the side panel, as the settings say
+func (h *HexDumpApp) layoutPanels() {
+	if appSettings.PanelVisible {
+		h.panelSplit.Leading = h.dataList
+		h.panelSplit.Offset = appSettings.PanelSplit
+		h.contentHost.Objects = []fyne.CanvasObject{h.panelSplit}
+		h.refreshPanels()
+	} else {
+		h.contentHost.Objects = []fyne.CanvasObject{h.dataList}
+	}
+	h.contentHost.Refresh()
+}
+
+// rememberPanelLayout records the position of the split between the data list and the
+// side panel, which the user may have dragged
+func (h *HexDumpApp) rememberPanelLayout() {
+	if appSettings.PanelVisible && h.panelSplit != nil {
+		appSettings.PanelSplit = h.panelSplit.Offset
+	}
+}
+
+// refreshPanels refreshes the detached panels and, if the side panel is shown, its
+// selected tab
+func (h *HexDumpApp) refreshPanels() {
+	if h.panelTabs == nil {
+		return
+	}
+	var selected *container.TabItem
+	if appSettings.PanelVisible {
+		selected = h.panelTabs.Selected()
+	}
+	for _, panel := range h.panels {
+		shown := panel.window != nil || (selected != nil && panel.tab == selected)
+		if shown && panel.refresh != nil {
+			panel.refresh()
+		}
+	}
+}
+
+// resetPanels discards the results shown by the side panels, after another file is loaded
+func (h *HexDumpApp) resetPanels() {
+	for _, panel := range h.panels {
+		if panel.reset != nil {
+			panel.reset()
+		}
+	}
+	h.refreshPanels()
+}
+
+// panelGrip is the handle at the top of a docked side panel. Dragging it out of the
+// side panel detaches the panel into its own window.
+type panelGrip struct {
+	widget.BaseWidget
+	h        *HexDumpApp
+	name     string
+	position fyne.Position
+	dragging bool
+}
+
+// newPanelGrip creates the grip of the named panel
+func newPanelGrip(h *HexDumpApp, name string) *panelGrip {
+	g := &panelGrip{h: h, name: name}
+	g.ExtendBaseWidget(g)
+	return g
+}
+
+// CreateRenderer implements fyne.Widget
+func (g *panelGrip) CreateRenderer() fyne.WidgetRenderer {
+	label := widget.NewLabel(lang.L("Drag here to detach"))
+	label.Importance = widget.LowImportance
+	return widget.NewSimpleRenderer(container.NewHBox(widget.NewIcon(theme.MoreHorizontalIcon()), label))
+}
+
+// Cursor implements desktop.Cursorable
+func (g *panelGrip) Cursor() desktop.Cursor {
+	return desktop.PointerCursor
+}
+
+// Dragged implements fyne.Draggable, remembering where the pointer is
+func (g *panelGrip) Dragged(event *fyne.DragEvent) {
+	g.position = event.AbsolutePosition
+	g.dragging = true
+}
+
+// DragEnd implements fyne.Draggable, detaching the panel if the pointer was released
+// outside the side panel
+func (g *panelGrip) DragEnd() {
+	if !g.dragging {
+		return
+	}
+	g.dragging = false
+	tabs := g.h.panelTabs
+	origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(tabs)
+	size := tabs.Size()
+	if g.position.X < origin.X || g.position.Y < origin.Y ||
+		g.position.X > origin.X+size.Width || g.position.Y > origin.Y+size.Height {
+		g.h.detachPanel(g.name)
+	}
+}
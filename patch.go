@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// patchEntry is one change of a patch list: the old bytes at offset are replaced by the
+// new bytes
+type patchEntry struct {
+	offset   int
+	oldBytes []byte
+	newBytes []byte
+}
+
+// changedEntries returns the changes made since the file was loaded as patch entries.
+// Only the ranges the journal touched are compared with the data as it was loaded, so
+// that a large file isn't copied whole.
+func (h *HexDumpApp) changedEntries() []patchEntry {
+	var touched []byteRange
+	for _, change := range h.journal {
+		touched = append(touched, byteRange{change.offset, change.offset + len(change.oldBytes)})
+	}
+	sort.Slice(touched, func(i, j int) bool { return touched[i].start < touched[j].start })
+
+	var entries []patchEntry
+	for index := 0; index < len(touched); {
+		r := touched[index]
+		for index++; index < len(touched) && touched[index].start <= r.end; index++ {
+			r.end = max(r.end, touched[index].end)
+		}
+
+		// Undo the edits within r on a copy of it, latest first
+		original := append([]byte(nil), h.fileData[r.start:r.end]...)
+		for step := len(h.journal) - 1; step >= 0; step-- {
+			change := h.journal[step]
+			start, end := max(change.offset, r.start), min(change.offset+len(change.oldBytes), r.end)
+			if start < end {
+				copy(original[start-r.start:], change.oldBytes[start-change.offset:end-change.offset])
+			}
+		}
+		for _, entry := range patchEntries(original, h.fileData[r.start:r.end]) {
+			entry.offset += r.start
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// patchEntries returns the changed ranges of current from original as patch entries
+func patchEntries(original, current []byte) []patchEntry {
+	var entries []patchEntry
+	for _, r := range diffRanges(original, current) {
+		entries = append(entries, patchEntry{offset: r.start, oldBytes: original[r.start:r.end],
+			newBytes: current[r.start:r.end]})
+	}
+	return entries
+}
+
+// formatPatchBytes formats bytes as space-separated hex pairs
+func formatPatchBytes(data []byte) string {
+	var digits []string
+	for _, b := range data {
+		digits = append(digits, formatHex(uint64(b), 2))
+	}
+	return strings.Join(digits, " ")
+}
+
+// writePatch writes a patch list to w: a comment naming the patched file, then a line
+// "offset: old bytes -> new bytes" for each entry, all in hex
+func writePatch(w io.Writer, name string, entries []patchEntry) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "# Patch list for %s\n", name)
+	fmt.Fprintf(writer, "# offset: old bytes -> new bytes\n")
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%s: %s -> %s\n", formatHex(uint64(entry.offset), 8), formatPatchBytes(entry.oldBytes),
+			formatPatchBytes(entry.newBytes))
+	}
+	return writer.Flush()
+}
+
+// parsePatch parses a patch list as written by writePatch. Blank lines and lines
+// starting with # are skipped, and offsets may have a 0x prefix.
+func parsePatch(text string) ([]patchEntry, error) {
+	var entries []patchEntry
+	for number, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		offsetText, change, found := strings.Cut(line, ":")
+		oldText, newText, arrow := strings.Cut(change, "->")
+		if !found || !arrow {
+			return nil, fmt.Errorf("line %d: expected \"offset: old bytes -> new bytes\"", number+1)
+		}
+		offset, err := parseOffset("0x" + strings.TrimPrefix(strings.ToLower(strings.TrimSpace(offsetText)), "0x"))
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("line %d: invalid offset %q", number+1, strings.TrimSpace(offsetText))
+		}
+		oldBytes, err := parseHexBytes(oldText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+		newBytes, err := parseHexBytes(newText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+		if len(oldBytes) != len(newBytes) {
+			return nil, fmt.Errorf("line %d: the old and new bytes must be the same length", number+1)
+		}
+		entries = append(entries, patchEntry{offset: offset, oldBytes: oldBytes, newBytes: newBytes})
+	}
+	return entries, nil
+}
+
+// exportPatch writes the changes made since the file was loaded to a patch list file
+// chosen by the user
+func (h *HexDumpApp) exportPatch() {
+	entries := h.changedEntries()
+	if len(entries) == 0 {
+		dialog.ShowInformation(lang.L("Export Patch List"), lang.L("The file has no changes."), h.window)
+		return
+	}
+	filename, err := nativedialog.File().Filter("Patch lists", "patch", "txt").Title("Export Patch List").Save()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	var buffer bytes.Buffer
+	err = writePatch(&buffer, filepath.Base(h.fileName), entries)
+	if err == nil {
+		err = writeDataFile(filename, buffer.Bytes())
+	}
+	if err != nil {
+		dialog.ShowError(err, h.window)
+	}
+}
+
+// applyPatch applies a patch list chosen by the user to the data, as edits that can be
+// undone. If some of the bytes to be replaced aren't the old bytes the list expects, as
+// when it was made for another version of the file, the user is asked first.
+func (h *HexDumpApp) applyPatch() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Apply Patch List"), lang.L("Open the file to patch first."), h.window)
+		return
+	}
+	filename, err := nativedialog.File().Filter("Patch lists", "patch", "txt").Title("Apply Patch List").Load()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	text, err := os.ReadFile(filename)
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	entries, err := parsePatch(string(text))
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	if len(entries) == 0 {
+		dialog.ShowInformation(lang.L("Apply Patch List"), lang.L("The patch list has no changes."), h.window)
+		return
+	}
+
+	var mismatches []int
+	for _, entry := range entries {
+		if entry.offset+len(entry.newBytes) > len(h.fileData) {
+			dialog.ShowError(fmt.Errorf("the change at %s is past the end of the file", formatHex(uint64(entry.offset), 8)),
+				h.window)
+			return
+		}
+		if !bytes.Equal(h.fileData[entry.offset:entry.offset+len(entry.oldBytes)], entry.oldBytes) {
+			mismatches = append(mismatches, entry.offset)
+		}
+	}
+
+	apply := func() {
+		h.groupEdits("Patch", func() {
+			for _, entry := range entries {
+				h.applyEdit("Patch", entry.offset, entry.newBytes)
+			}
+		})
+		h.setSelection(entries[0].offset, entries[0].offset+len(entries[0].newBytes))
+		h.goToOffset(entries[0].offset)
+	}
+	if len(mismatches) == 0 {
+		apply()
+		return
+	}
+	dialog.ShowConfirm(lang.L("Apply Patch List"),
+		fmt.Sprintf("%d of the %d changes don't find the old bytes they expect, the first at %s.\n"+
+			"The patch may be for another version of the file. Apply it anyway?",
+			len(mismatches), len(entries), formatHex(uint64(mismatches[0]), 8)),
+		func(ok bool) {
+			if ok {
+				apply()
+			}
+		}, h.window)
+}
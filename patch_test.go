@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestChangedEntries(t *testing.T) {
+	h := NewHexDumpApp(nil, nil)
+	original := make([]byte, 64)
+	for index := range original {
+		original[index] = byte(index)
+	}
+	h.fileData = append([]byte(nil), original...)
+
+	h.changeBytes("a", 4, bytes.Repeat([]byte{0xAA}, 4))
+	h.changeBytes("b", 6, bytes.Repeat([]byte{0xBB}, 4))
+	h.changeBytes("c", 4, original[4:6])    // Restores the start of the first edit
+	h.changeBytes("d", 20, original[20:22]) // Changes nothing
+	h.changeBytes("e", 60, []byte{0xEE, 0xEE, 0xEE, 0xEE, 0xEE})
+	h.changeBytes("f", 10, []byte{0xFF})
+
+	want := patchEntries(original, h.fileData)
+	if got := h.changedEntries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// referencePattern is the content the data is expected to have: a hex pattern placed at
+// an offset, either once, as a header template, or repeated to the end of the file, as
+// the fill of erased flash
+type referencePattern struct {
+	text    string
+	pattern searchPattern
+	offset  int
+	repeat  bool
+	diffs   []byteRange // Ranges of the data that differ from the pattern
+}
+
+// referenceDiffRanges returns the ranges of data that differ from the reference pattern,
+// in order. Wildcard bits of the pattern match anything.
+func referenceDiffRanges(data []byte, reference *referencePattern) []byteRange {
+	pattern := reference.pattern
+	end := min(len(data), reference.offset+len(pattern.data))
+	if reference.repeat {
+		end = len(data)
+	}
+	var ranges []byteRange
+	start := -1
+	for offset := reference.offset; offset <= end; offset++ {
+		differs := false
+		if offset < end {
+			index := (offset - reference.offset) % len(pattern.data)
+			differs = data[offset]&pattern.mask[index] != pattern.data[index]
+		}
+		if differs && start < 0 {
+			start = offset
+		} else if !differs && start >= 0 {
+			ranges = append(ranges, byteRange{start, offset})
+			start = -1
+		}
+	}
+	return ranges
+}
+
+// showCompareWithPattern asks for the content the data is expected to have, such as FF
+// for erased flash or the bytes of a header, and highlights every byte that differs
+// from it. An empty pattern clears the comparison.
+func (h *HexDumpApp) showCompareWithPattern() {
+	if len(h.fileData) == 0 {
+		return
+	}
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder(lang.L("e.g. FF, or 7F 45 4C 46 ?? 01"))
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetText("0")
+	repeatCheck := widget.NewCheck(lang.L("Repeat to the end of the file"), nil)
+	repeatCheck.SetChecked(true)
+	if h.reference != nil {
+		patternEntry.SetText(h.reference.text)
+		offsetEntry.SetText(fmt.Sprintf("0x%X", h.reference.offset))
+		repeatCheck.SetChecked(h.reference.repeat)
+	}
+
+	patternItem := widget.NewFormItem(lang.L("Expected bytes"), patternEntry)
+	patternItem.HintText = "Hex, with ?? for bytes that may be anything"
+	repeatItem := widget.NewFormItem("", repeatCheck)
+	repeatItem.HintText = "Off to check the pattern once, as a header template"
+	form := dialog.NewForm(lang.L("Compare with Pattern"), lang.L("Compare"), lang.L("Cancel"), []*widget.FormItem{
+		patternItem,
+		widget.NewFormItem(lang.L("At offset"), offsetEntry),
+		repeatItem,
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if strings.TrimSpace(patternEntry.Text) == "" {
+			h.clearPatternComparison()
+			return
+		}
+		pattern, err := parseHexPattern(patternEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		offset, err := h.evaluateOffset(offsetEntry.Text)
+		if err != nil || offset < 0 || offset >= len(h.fileData) {
+			dialog.ShowError(fmt.Errorf("offset %q is outside the file", offsetEntry.Text), h.window)
+			return
+		}
+		h.reference = &referencePattern{text: patternEntry.Text, pattern: pattern, offset: offset,
+			repeat: repeatCheck.Checked}
+		h.updateReference()
+		h.updateDisplay()
+		h.updateStatus()
+		if len(h.reference.diffs) > 0 {
+			h.goToOffset(h.reference.diffs[0].start)
+		}
+	}, h.window)
+	form.Resize(fyne.NewSize(420, 280))
+	form.Show()
+	h.window.Canvas().Focus(patternEntry)
+}
+
+// clearPatternComparison stops highlighting the bytes that differ from the pattern
+func (h *HexDumpApp) clearPatternComparison() {
+	h.reference = nil
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// updateReference compares the data with the pattern again after it changed
+func (h *HexDumpApp) updateReference() {
+	if h.reference != nil {
+		h.reference.diffs = referenceDiffRanges(h.fileData, h.reference)
+	}
+}
+
+// referenceSpans returns highlight spans for the bytes differing from the pattern
+// intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) referenceSpans(lineStart, lineEnd int) []highlightSpan {
+	if h.reference == nil {
+		return nil
+	}
+	diffs := h.reference.diffs
+	var spans []highlightSpan
+	first := sort.Search(len(diffs), func(i int) bool { return diffs[i].end > lineStart })
+	for _, diff := range diffs[first:] {
+		if diff.start >= lineEnd {
+			break
+		}
+		spans = append(spans, highlightSpan{start: max(diff.start, lineStart), end: min(diff.end, lineEnd),
+			color: theme.Color(colorNameReferenceDiff)})
+	}
+	return spans
+}
+
+// referenceStatus describes the bytes differing from the pattern for the status bar
+func (h *HexDumpApp) referenceStatus() string {
+	if h.reference == nil {
+		return ""
+	}
+	differing := 0
+	for _, diff := range h.reference.diffs {
+		differing += diff.end - diff.start
+	}
+	return fmt.Sprintf("Differs from %s: %d bytes in %d ranges", strings.ToUpper(strings.Join(
+		strings.Fields(h.reference.text), " ")), differing, len(h.reference.diffs))
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxPointerHits is the largest number of pointers the pointer scan reports
+const maxPointerHits = 100000
+
+// pointerHit is a value at offset that points at target, a file offset
+type pointerHit struct {
+	offset int
+	width  int
+	value  uint64
+	target int
+}
+
+// findPointers returns the width-byte values in data, at multiples of align, that
+// toOffset translates into offsets within data
+func findPointers(data []byte, width, align int, order binary.ByteOrder, toOffset func(uint64) (int, bool)) []pointerHit {
+	var hits []pointerHit
+	for offset := 0; offset+width <= len(data) && len(hits) < maxPointerHits; offset += align {
+		var value uint64
+		if width == 8 {
+			value = order.Uint64(data[offset:])
+		} else {
+			value = uint64(order.Uint32(data[offset:]))
+		}
+		if target, ok := toOffset(value); ok && target >= 0 && target < len(data) {
+			hits = append(hits, pointerHit{offset: offset, width: width, value: value, target: target})
+		}
+	}
+	return hits
+}
+
+// showPointerScan asks for the pointer size and the address at which the dump starts,
+// and finds the values that point into the dump. With an address map, pointers can be
+// translated through it instead.
+func (h *HexDumpApp) showPointerScan() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Pointer Scan"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	widthSelect := widget.NewSelect([]string{"32-bit", "64-bit"}, nil)
+	widthSelect.SetSelected("32-bit")
+	baseEntry := widget.NewEntry()
+	baseEntry.SetPlaceHolder(lang.L("e.g. 0x08000000"))
+	mapCheck := widget.NewCheck(lang.L("Translate through the address map instead"), func(checked bool) {
+		if checked {
+			baseEntry.Disable()
+		} else {
+			baseEntry.Enable()
+		}
+	})
+	if len(h.segments) == 0 {
+		mapCheck.Disable()
+	}
+	alignCheck := widget.NewCheck(lang.L("Only aligned values"), nil)
+	alignCheck.SetChecked(true)
+
+	baseItem := widget.NewFormItem(lang.L("Base address"), baseEntry)
+	baseItem.HintText = "The address of the first byte of the dump"
+	dialog.ShowForm(lang.L("Pointer Scan"), lang.L("Scan"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Pointer size"), widthSelect),
+		baseItem,
+		widget.NewFormItem("", mapCheck),
+		widget.NewFormItem("", alignCheck),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		width := 4
+		if widthSelect.Selected == "64-bit" {
+			width = 8
+		}
+		align := 1
+		if alignCheck.Checked {
+			align = width
+		}
+
+		toOffset := h.fromVirtual
+		if !mapCheck.Checked {
+			base, err := parseOffset(baseEntry.Text)
+			if err != nil || base < 0 {
+				dialog.ShowError(fmt.Errorf("the base address must be a non-negative number"), h.window)
+				return
+			}
+			toOffset = func(value uint64) (int, bool) {
+				if value < uint64(base) || value-uint64(base) >= uint64(len(h.fileData)) {
+					return 0, false
+				}
+				return int(value - uint64(base)), true
+			}
+		}
+
+		h.pointers = findPointers(h.fileData, width, align, h.byteOrder(), toOffset)
+		h.updateDisplay()
+		h.updateStatus()
+		h.showPointerList()
+	}, h.window)
+}
+
+// clearPointers removes the pointers found by the pointer scan
+func (h *HexDumpApp) clearPointers() {
+	h.pointers = nil
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// pointerAt returns the pointer found by the pointer scan that covers offset, or nil
+func (h *HexDumpApp) pointerAt(offset int) *pointerHit {
+	index := sort.Search(len(h.pointers), func(i int) bool { return h.pointers[i].offset+h.pointers[i].width > offset })
+	for ; index < len(h.pointers) && h.pointers[index].offset <= offset; index++ {
+		if offset < h.pointers[index].offset+h.pointers[index].width {
+			return &h.pointers[index]
+		}
+	}
+	return nil
+}
+
+// followPointer selects the target of the pointer covering offset, reporting whether
+// there was one
+func (h *HexDumpApp) followPointer(offset int) bool {
+	pointer := h.pointerAt(offset)
+	if pointer == nil {
+		return false
+	}
+	h.selAnchor, h.caret = pointer.target, pointer.target
+	h.setSelection(pointer.target, pointer.target+1)
+	h.goToOffset(pointer.target)
+	return true
+}
+
+// pointerSpans returns highlight spans for the pointers intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) pointerSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	first := sort.Search(len(h.pointers), func(i int) bool { return h.pointers[i].offset+h.pointers[i].width > lineStart })
+	for _, pointer := range h.pointers[first:] {
+		if pointer.offset >= lineEnd {
+			break
+		}
+		spans = append(spans, highlightSpan{start: max(pointer.offset, lineStart),
+			end: min(pointer.offset+pointer.width, lineEnd), color: theme.Color(colorNamePointer)})
+	}
+	return spans
+}
+
+// pointerStatus describes the pointer at the caret for the status bar
+func (h *HexDumpApp) pointerStatus() string {
+	if pointer := h.pointerAt(h.caret); pointer != nil {
+		return "Pointer to " + formatHex(uint64(pointer.target), 8)
+	}
+	return ""
+}
+
+// showPointerList lists the pointers found by the pointer scan. Selecting one selects
+// the pointer, and Follow jumps to its target.
+func (h *HexDumpApp) showPointerList() {
+	pointers := h.pointers
+	if len(pointers) == 0 {
+		dialog.ShowInformation(lang.L("Pointer Scan"), lang.L("No values point into the dump."), h.window)
+		return
+	}
+
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(pointers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			pointer := pointers[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %0*X  ->  %08X", pointer.offset, pointer.width*2,
+				pointer.value, pointer.target))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		h.setSelection(pointers[id].offset, pointers[id].offset+pointers[id].width)
+		h.goToOffset(pointers[id].offset)
+	}
+	followBtn := widget.NewButton(lang.L("Follow"), func() {
+		if selected >= 0 {
+			target := pointers[selected].target
+			h.selAnchor, h.caret = target, target
+			h.setSelection(target, target+1)
+			h.goToOffset(target)
+		}
+	})
+
+	summary := fmt.Sprintf("%d pointers into the dump", len(pointers))
+	if len(pointers) == maxPointerHits {
+		summary = fmt.Sprintf("The first %d pointers into the dump", maxPointerHits)
+	}
+	window := h.app.NewWindow(fmt.Sprintf("Pointers - %s", h.fileName))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(widget.NewLabel(summary), widget.NewLabel("Offset    Value         Target")),
+		container.NewHBox(followBtn, widget.NewLabel(lang.L("Ctrl+click a highlighted pointer to follow it"))),
+		nil, nil,
+		list,
+	))
+	window.Resize(fyne.NewSize(450, 450))
+	window.Show()
+}
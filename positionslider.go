@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// positionSliderSteps is the resolution of the position slider
+const positionSliderSteps = 10000
+
+// createPositionSlider creates the slider representing the whole file, which scrolls the
+// data list proportionally while it is dragged
+func (h *HexDumpApp) createPositionSlider() fyne.CanvasObject {
+	h.positionSlider = widget.NewSlider(0, positionSliderSteps)
+	h.positionSlider.OnChanged = func(value float64) {
+		if len(h.fileData) == 0 {
+			return
+		}
+		fraction := value / positionSliderSteps
+		h.scrollToFraction(fraction)
+
+		// Show the target offset below the slider's thumb
+		offset := min(int(fraction*float64(len(h.fileData))), len(h.fileData)-1)
+		position := fyne.CurrentApp().Driver().AbsolutePositionForObject(h.positionSlider)
+		size := h.positionSlider.Size()
+		h.showTooltip(fmt.Sprintf("%08X (%.1f%%)", offset, fraction*100),
+			position.Add(fyne.NewPos(float32(fraction)*size.Width-12, size.Height-18)))
+	}
+	h.positionSlider.OnChangeEnded = func(float64) { h.hideTooltip() }
+	return h.positionSlider
+}
+
+// scrollToFraction scrolls the data list to a fraction of the way through the file
+func (h *HexDumpApp) scrollToFraction(fraction float64) {
+	h.stopScrollAnimation()
+	pitch := h.rowPitch()
+	contentHeight := float32(h.totalLines)*pitch - theme.Padding()
+	scrollable := max(0, contentHeight-h.dataList.Size().Height)
+	h.dataList.ScrollToOffset(float32(fraction) * scrollable)
+}
+
+// syncPositionSlider moves the position slider to offset without scrolling the list
+func (h *HexDumpApp) syncPositionSlider(offset int) {
+	if h.positionSlider == nil || len(h.fileData) == 0 {
+		return
+	}
+	h.positionSlider.Value = float64(offset) / float64(len(h.fileData)) * positionSliderSteps
+	h.positionSlider.Refresh()
+}
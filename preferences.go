@@ -0,0 +1,187 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// separatorNames maps the group separators to their names in the Preferences dialog
+var separatorNames = map[string]string{
+	separatorSpace: "Space",
+	separatorDash:  "Dash",
+}
+
+// showPreferences opens the Preferences dialog. Changes apply when OK is pressed.
+func (h *HexDumpApp) showPreferences() {
+	separatorSelect := widget.NewSelect([]string{separatorNames[separatorSpace], separatorNames[separatorDash]}, nil)
+	separatorSelect.SetSelected(separatorNames[appSettings.GroupSeparator])
+	if separatorSelect.Selected == "" {
+		separatorSelect.SetSelected(separatorNames[separatorSpace])
+	}
+	midLineGapCheck := widget.NewCheck(lang.L("Extra gap after 8 bytes"), nil)
+	midLineGapCheck.SetChecked(appSettings.MidLineGap)
+	lowercaseCheck := widget.NewCheck(lang.L("Lowercase hex digits"), nil)
+	lowercaseCheck.SetChecked(appSettings.LowercaseHex)
+	addressOptions := []string{lang.L("Fit the file")}
+	for _, digits := range addressDigitChoices {
+		addressOptions = append(addressOptions, strconv.Itoa(digits))
+	}
+	addressSelect := widget.NewSelect(addressOptions, nil)
+	addressSelect.SetSelectedIndex(slices.Index(addressDigitChoices, appSettings.AddressDigits) + 1)
+	checksumSelect := widget.NewSelect([]string{lineChecksumNames[lineChecksumNone], lineChecksumNames[lineChecksumSum],
+		lineChecksumNames[lineChecksumXOR], lineChecksumNames[lineChecksumCRC8]}, nil)
+	checksumSelect.SetSelected(lineChecksumNames[appSettings.LineChecksum])
+	if checksumSelect.Selected == "" {
+		checksumSelect.SetSelected(lineChecksumNames[lineChecksumNone])
+	}
+	paletteSelect := widget.NewSelect([]string{paletteNames[paletteStandard], paletteNames[paletteColorBlind],
+		paletteNames[paletteHighContrast]}, nil)
+	paletteSelect.SetSelected(paletteNames[appSettings.Palette])
+	if paletteSelect.Selected == "" {
+		paletteSelect.SetSelected(paletteNames[paletteStandard])
+	}
+	densitySelect := widget.NewSelect([]string{densityNames[densityCompact], densityNames[densityNormal],
+		densityNames[densityComfortable]}, nil)
+	densitySelect.SetSelected(densityNames[appSettings.Density])
+	if densitySelect.Selected == "" {
+		densitySelect.SetSelected(densityNames[densityNormal])
+	}
+	var fontSizeNames []string
+	for _, size := range dataFontSizes {
+		fontSizeNames = append(fontSizeNames, strconv.FormatFloat(float64(size), 'f', -1, 32))
+	}
+	fontSizeSelect := widget.NewSelect(fontSizeNames, nil)
+	fontSizeSelect.SetSelectedIndex(slices.Index(dataFontSizes, rowTextSize()))
+
+	fallbackOptions := make([]string, len(fallbackKinds))
+	for index, kind := range fallbackKinds {
+		fallbackOptions[index] = fallbackNames[kind]
+	}
+	fallbackSelect := widget.NewSelect(fallbackOptions, nil)
+	fallbackSelect.SetSelected(fallbackNames[appSettings.Fallback])
+	if fallbackSelect.Selected == "" {
+		fallbackSelect.SetSelected(fallbackNames[fallbackDot])
+	}
+	// Each encoding can have its own fallback character, or use the one for all of them
+	encodingFallbackOptions := append([]string{lang.L("Same for all")}, fallbackOptions...)
+	encodingFallbackGrid := container.NewGridWithColumns(2)
+	encodingFallbackSelects := make(map[string]*widget.Select)
+	for _, encoding := range encodingNames {
+		encodingSelect := widget.NewSelect(encodingFallbackOptions, nil)
+		encodingSelect.SetSelectedIndex(0)
+		if kind, ok := appSettings.EncodingFallbacks[encoding]; ok {
+			encodingSelect.SetSelected(fallbackNames[kind])
+		}
+		encodingFallbackSelects[encoding] = encodingSelect
+		encodingFallbackGrid.Add(widget.NewLabel(encoding))
+		encodingFallbackGrid.Add(encodingSelect)
+	}
+
+	addressItem := widget.NewFormItem(lang.L("Address digits"), addressSelect)
+	addressItem.HintText = lang.L("Widened when the file or its virtual addresses need more")
+	checksumItem := widget.NewFormItem(lang.L("Line checksum"), checksumSelect)
+	checksumItem.HintText = lang.L("Shown after each line, for checking against listings")
+	updateCheck := widget.NewCheck(lang.L("Check for updates at startup"), nil)
+	updateCheck.SetChecked(appSettings.CheckForUpdates)
+	updateItem := widget.NewFormItem("", updateCheck)
+	updateItem.HintText = lang.L("Asks GitHub for the latest release once a day")
+	paletteItem := widget.NewFormItem(lang.L("Colors"), paletteSelect)
+	paletteItem.HintText = lang.L("Colors of highlights, selection, and changes")
+	smoothCheck := widget.NewCheck(lang.L("Animate short jumps"), nil)
+	smoothCheck.SetChecked(appSettings.SmoothScrolling)
+	densityItem := widget.NewFormItem(lang.L("Line spacing"), densitySelect)
+	densityItem.HintText = lang.L("Line height follows the font size, so no text is clipped")
+	fallbackItem := widget.NewFormItem(lang.L("Non-printable"), fallbackSelect)
+	fallbackItem.HintText = lang.L("Shown for control characters and undecodable bytes, also in text exports")
+	shortcutsItem := widget.NewFormItem(lang.L("Keyboard shortcuts"),
+		widget.NewButton(lang.L("Edit..."), h.showShortcutEditor))
+	form := dialog.NewForm(lang.L("Preferences"), lang.L("OK"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Group separator"), separatorSelect),
+		widget.NewFormItem("", midLineGapCheck),
+		widget.NewFormItem("", lowercaseCheck),
+		addressItem,
+		checksumItem,
+		paletteItem,
+		widget.NewFormItem(lang.L("Font size"), fontSizeSelect),
+		densityItem,
+		widget.NewFormItem("", smoothCheck),
+		fallbackItem,
+		widget.NewFormItem(lang.L("Per encoding"), encodingFallbackGrid),
+		shortcutsItem,
+		updateItem,
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		for separator, name := range separatorNames {
+			if name == separatorSelect.Selected {
+				appSettings.GroupSeparator = separator
+			}
+		}
+		for kind, name := range lineChecksumNames {
+			if name == checksumSelect.Selected {
+				appSettings.LineChecksum = kind
+			}
+		}
+		palette := appSettings.Palette
+		for name, title := range paletteNames {
+			if title == paletteSelect.Selected {
+				appSettings.Palette = name
+			}
+		}
+		for kind, name := range fallbackNames {
+			if name == fallbackSelect.Selected {
+				appSettings.Fallback = kind
+			}
+		}
+		appSettings.EncodingFallbacks = make(map[string]string)
+		for encoding, encodingSelect := range encodingFallbackSelects {
+			for kind, name := range fallbackNames {
+				if name == encodingSelect.Selected {
+					appSettings.EncodingFallbacks[encoding] = kind
+				}
+			}
+		}
+		fontSize, density := rowTextSize(), appSettings.Density
+		if index := fontSizeSelect.SelectedIndex(); index >= 0 {
+			appSettings.DataFontSize = dataFontSizes[index]
+		}
+		for name, title := range densityNames {
+			if title == densitySelect.Selected {
+				appSettings.Density = name
+			}
+		}
+		appSettings.MidLineGap = midLineGapCheck.Checked
+		appSettings.LowercaseHex = lowercaseCheck.Checked
+		appSettings.AddressDigits = 0
+		if index := addressSelect.SelectedIndex(); index > 0 {
+			appSettings.AddressDigits = addressDigitChoices[index-1]
+		}
+		appSettings.CheckForUpdates = updateCheck.Checked
+		appSettings.SmoothScrolling = smoothCheck.Checked
+		saveSettings()
+		if appSettings.Palette != palette {
+			// Setting the theme again redraws every window in the new colors
+			h.app.Settings().SetTheme(NewCustomTheme())
+		}
+		if rowTextSize() != fontSize || appSettings.Density != density {
+			// Rows are sized when they are first shown, so every window needs a new list
+			for _, app := range openApps {
+				app.rebuildDataList()
+			}
+		}
+		// Fallback characters may have changed in every window
+		for _, app := range openApps {
+			app.updateDisplay()
+		}
+		h.updateStatus()
+	}, h.window)
+	form.Resize(fyne.NewSize(420, 910))
+	form.Show()
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// compareRowBytes is the number of bytes of each range shown per row of the byte view
+const compareRowBytes = 16
+
+// Views of Compare Selection with Range A
+const (
+	compareViewBytes  = "Bytes"
+	compareViewGroups = "Groups"
+)
+
+// compareRow is a row of a range comparison: the bytes of each range at offset from the
+// start of the ranges, and whether they differ
+type compareRow struct {
+	offset int
+	a, b   []byte
+	differ bool
+}
+
+// compareRows splits ranges a and b into rows of size bytes, in order of offset. Bytes
+// that only one range has count as differing.
+func compareRows(a, b []byte, size int) []compareRow {
+	var rows []compareRow
+	for offset := 0; offset < max(len(a), len(b)); offset += size {
+		rowA := a[min(offset, len(a)):min(offset+size, len(a))]
+		rowB := b[min(offset, len(b)):min(offset+size, len(b))]
+		rows = append(rows, compareRow{offset: offset, a: rowA, b: rowB, differ: string(rowA) != string(rowB)})
+	}
+	return rows
+}
+
+// markRangeA remembers the selection as range A for Compare Selection with Range A
+func (h *HexDumpApp) markRangeA() {
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("Mark Range A"), lang.L("Select the bytes to compare first."), h.window)
+		return
+	}
+	h.rangeA = &byteRange{h.selStart, h.selEnd}
+	h.updateStatus()
+}
+
+// rangeAStatus describes range A for the status bar
+func (h *HexDumpApp) rangeAStatus() string {
+	if h.rangeA == nil {
+		return ""
+	}
+	return fmt.Sprintf("Range A: %s-%s", formatHex(uint64(h.rangeA.start), 8), formatHex(uint64(h.rangeA.end-1), 8))
+}
+
+// showCompareRanges compares the selection, range B, with range A in a window with a
+// byte view, which marks the differing bytes of each row, and a group view, which lists
+// the groups of the current grouping with their values in the chosen byte order
+func (h *HexDumpApp) showCompareRanges() {
+	if h.rangeA == nil {
+		dialog.ShowInformation(lang.L("Compare Ranges"), lang.L("Select a range and use Tools → Mark Selection as Range A first."),
+			h.window)
+		return
+	}
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("Compare Ranges"), lang.L("Select range B to compare with range A."), h.window)
+		return
+	}
+	rangeA, rangeB := *h.rangeA, byteRange{h.selStart, h.selEnd}
+	a := append([]byte(nil), h.fileData[rangeA.start:rangeA.end]...)
+	b := append([]byte(nil), h.fileData[rangeB.start:rangeB.end]...)
+	groupSize, order := h.bytesPerGroup, h.byteOrder()
+
+	differing, runs, inRun := 0, 0, false
+	for index := 0; index < max(len(a), len(b)); index++ {
+		differs := index >= len(a) || index >= len(b) || a[index] != b[index]
+		if differs {
+			differing++
+			if !inRun {
+				runs++
+			}
+		}
+		inRun = differs
+	}
+	summary := fmt.Sprintf("A: %s-%s (%d bytes)   B: %s-%s (%d bytes)\n%d bytes differ in %d runs",
+		formatHex(uint64(rangeA.start), 8), formatHex(uint64(rangeA.end-1), 8), len(a),
+		formatHex(uint64(rangeB.start), 8), formatHex(uint64(rangeB.end-1), 8), len(b), differing, runs)
+
+	// The rows shown in the current view
+	var rows []compareRow
+	view, onlyDiffering := compareViewBytes, false
+	filter := func() {
+		size := compareRowBytes
+		if view == compareViewGroups {
+			size = groupSize
+		}
+		rows = nil
+		for _, row := range compareRows(a, b, size) {
+			if row.differ || !onlyDiffering {
+				rows = append(rows, row)
+			}
+		}
+	}
+	filter()
+
+	// Byte view: each row shows the bytes of A and B, with the differing ones in color
+	byteSegments := func(row compareRow, name string, data, other []byte) []widget.RichTextSegment {
+		style := widget.RichTextStyle{Inline: true, TextStyle: fyne.TextStyle{Monospace: true}}
+		segments := []widget.RichTextSegment{&widget.TextSegment{Text: fmt.Sprintf("%s +%s ", name,
+			formatHex(uint64(row.offset), 6)), Style: style}}
+		for index := 0; index < compareRowBytes; index++ {
+			text, byteStyle := "   ", style
+			if index < len(data) {
+				text = " " + formatHex(uint64(data[index]), 2)
+				if index >= len(other) || data[index] != other[index] {
+					byteStyle.ColorName = theme.ColorNameError
+					byteStyle.TextStyle.Bold = true
+				}
+			}
+			segments = append(segments, &widget.TextSegment{Text: text, Style: byteStyle})
+		}
+		return segments
+	}
+	byteList := widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject {
+			return container.NewVBox(widget.NewRichText(), widget.NewRichText())
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := rows[id]
+			lines := item.(*fyne.Container).Objects
+			lines[0].(*widget.RichText).Segments = byteSegments(row, "A", row.a, row.b)
+			lines[0].Refresh()
+			lines[1].(*widget.RichText).Segments = byteSegments(row, "B", row.b, row.a)
+			lines[1].Refresh()
+		},
+	)
+
+	// Group view: each row shows a group of A and B decoded in the chosen byte order
+	groupValue := func(data []byte) string {
+		if len(data) == 0 {
+			return "-"
+		}
+		if len(data) != groupSize || len(data) > 8 {
+			return strings.ToUpper(hex.EncodeToString(data))
+		}
+		var value uint64
+		switch len(data) {
+		case 1:
+			value = uint64(data[0])
+		case 2:
+			value = uint64(order.Uint16(data))
+		case 4:
+			value = uint64(order.Uint32(data))
+		case 8:
+			value = order.Uint64(data)
+		}
+		return fmt.Sprintf("%s (%d)", formatHex(value, 2*len(data)), value)
+	}
+	groupList := widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := rows[id]
+			mark := " "
+			if row.differ {
+				mark = "≠"
+			}
+			item.(*widget.Label).SetText(fmt.Sprintf("%s +%s   A %s   B %s", mark, formatHex(uint64(row.offset), 6),
+				groupValue(row.a), groupValue(row.b)))
+		},
+	)
+
+	// Selecting a row selects its bytes in range B
+	onSelected := func(id widget.ListItemID) {
+		row := rows[id]
+		start := min(rangeB.start+row.offset, rangeB.end-1)
+		h.setSelection(start, min(start+max(len(row.b), 1), rangeB.end))
+		h.goToOffset(start)
+	}
+	byteList.OnSelected = onSelected
+	groupList.OnSelected = onSelected
+
+	lists := container.NewStack(byteList, groupList)
+	groupList.Hide()
+	refresh := func() {
+		filter()
+		byteList.UnselectAll()
+		groupList.UnselectAll()
+		if view == compareViewGroups {
+			byteList.Hide()
+			groupList.Show()
+			groupList.Refresh()
+		} else {
+			groupList.Hide()
+			byteList.Show()
+			byteList.Refresh()
+		}
+	}
+	viewSelect := widget.NewRadioGroup([]string{compareViewBytes, compareViewGroups}, func(value string) {
+		view = value
+		refresh()
+	})
+	viewSelect.Horizontal = true
+	viewSelect.Required = true
+	viewSelect.SetSelected(compareViewBytes)
+	onlyCheck := widget.NewCheck(lang.L("Only differences"), func(checked bool) {
+		onlyDiffering = checked
+		refresh()
+	})
+
+	window := h.app.NewWindow(fmt.Sprintf("Compare Ranges - %s", h.fileName))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(widget.NewLabel(summary), container.NewHBox(viewSelect, onlyCheck)),
+		widget.NewLabel(lang.L("Click a row to select its bytes in range B")),
+		nil, nil,
+		lists,
+	))
+	window.Resize(fyne.NewSize(700, 500))
+	window.Show()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Raw bitmap pixel formats, as shown in the GUI
+const (
+	pixelGray8    = "Gray8"
+	pixelRGB565   = "RGB565"
+	pixelRGBA8888 = "RGBA8888"
+)
+
+// pixelFormats lists the raw bitmap pixel formats in display order
+var pixelFormats = []string{pixelGray8, pixelRGB565, pixelRGBA8888}
+
+// Raw image dimensions
+const (
+	maxRawImageHeight = 4096 // Rows beyond this are not drawn
+	rawImageViewSize  = 512  // Width at which narrow images are displayed
+)
+
+// pixelSize returns the number of bytes in one pixel of the given format
+func pixelSize(format string) int {
+	switch format {
+	case pixelRGB565:
+		return 2
+	case pixelRGBA8888:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// renderRawImage draws data as a bitmap of the given width in pixels, pixel format,
+// and stride in bytes between the starts of rows. Multi-byte RGB565 pixels use the
+// given byte order; RGBA8888 pixels are stored in R, G, B, A byte order.
+func renderRawImage(data []byte, width int, format string, stride int, order binary.ByteOrder) *image.RGBA {
+	size := pixelSize(format)
+	height := 0
+	if len(data) >= width*size {
+		height = min((len(data)-width*size)/stride+1, maxRawImageHeight)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, max(height, 1)))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixel := data[y*stride+x*size:]
+			var c color.RGBA
+			switch format {
+			case pixelRGB565:
+				value := order.Uint16(pixel)
+				r, g, b := value>>11, value>>5&0x3F, value&0x1F
+				c = color.RGBA{R: uint8(r<<3 | r>>2), G: uint8(g<<2 | g>>4), B: uint8(b<<3 | b>>2), A: 255}
+			case pixelRGBA8888:
+				c = color.RGBA{R: pixel[0], G: pixel[1], B: pixel[2], A: pixel[3]}
+			default:
+				c = color.RGBA{R: pixel[0], G: pixel[0], B: pixel[0], A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// showRawImage renders the selection as a raw bitmap of adjustable width, pixel format,
+// and stride, for finding framebuffers and sprites. Clicking a pixel selects its bytes.
+func (h *HexDumpApp) showRawImage() {
+	if !h.hasSelection() {
+		dialog.ShowInformation(lang.L("View as Image"), lang.L("Select the bytes to view first."), h.window)
+		return
+	}
+
+	start := h.selStart
+	data := append([]byte(nil), h.selectedBytes()...)
+	infoLabel := widget.NewLabel("")
+	hoverLabel := widget.NewLabel(lang.L("Click a pixel to select its bytes"))
+	view := newPixelView(image.NewRGBA(image.Rect(0, 0, 1, 1)), fyne.NewSize(rawImageViewSize, rawImageViewSize))
+
+	widthEntry := widget.NewEntry()
+	widthEntry.SetText("64")
+	strideEntry := widget.NewEntry()
+	strideEntry.SetPlaceHolder(lang.L("auto"))
+	formatSelect := widget.NewSelect(pixelFormats, nil)
+
+	width, stride := 0, 0
+	redraw := func(string) {
+		if formatSelect.Selected == "" {
+			return
+		}
+		size := pixelSize(formatSelect.Selected)
+		var err error
+		width, err = strconv.Atoi(strings.TrimSpace(widthEntry.Text))
+		if err != nil || width <= 0 {
+			infoLabel.SetText(lang.L("Width must be a positive number of pixels"))
+			return
+		}
+		stride = width * size
+		if text := strings.TrimSpace(strideEntry.Text); text != "" {
+			if stride, err = parseOffset(text); err != nil || stride < width*size {
+				infoLabel.SetText(fmt.Sprintf("Stride must be at least %d bytes", width*size))
+				return
+			}
+		}
+
+		img := renderRawImage(data, width, formatSelect.Selected, stride, h.byteOrder())
+		view.setImage(img)
+		scale := max(1, rawImageViewSize/float32(width))
+		view.minSize = fyne.NewSize(float32(width)*scale, float32(img.Bounds().Dy())*scale)
+		view.Refresh()
+		infoLabel.SetText(fmt.Sprintf("%dx%d pixels, %d bytes per row", width, img.Bounds().Dy(), stride))
+	}
+	widthEntry.OnChanged = redraw
+	strideEntry.OnChanged = redraw
+	formatSelect.OnChanged = redraw
+	formatSelect.SetSelected(pixelGray8)
+
+	// Step the width, which is the quickest way to find the width of a framebuffer
+	stepWidth := func(delta int) {
+		if width+delta > 0 {
+			widthEntry.SetText(strconv.Itoa(width + delta))
+		}
+	}
+
+	view.onHover = func(x, y int) {
+		hoverLabel.SetText(fmt.Sprintf("Pixel (%d, %d) at offset %08X", x, y, start+y*stride+x*pixelSize(formatSelect.Selected)))
+	}
+	view.onTapped = func(x, y int) {
+		offset := start + y*stride + x*pixelSize(formatSelect.Selected)
+		h.selAnchor = offset
+		h.caret = offset
+		h.setSelection(offset, offset+pixelSize(formatSelect.Selected))
+		h.goToOffset(offset)
+	}
+
+	controls := container.NewHBox(
+		widget.NewLabel(lang.L("Width:")),
+		widget.NewButton("-", func() { stepWidth(-1) }),
+		container.NewGridWrap(fyne.NewSize(70, widthEntry.MinSize().Height), widthEntry),
+		widget.NewButton("+", func() { stepWidth(1) }),
+		widget.NewLabel(lang.L("Format:")), formatSelect,
+		widget.NewLabel(lang.L("Stride:")),
+		container.NewGridWrap(fyne.NewSize(80, strideEntry.MinSize().Height), strideEntry),
+	)
+
+	window := h.app.NewWindow(fmt.Sprintf("View as Image - %08X-%08X", start, start+len(data)-1))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(controls, infoLabel),
+		container.NewVBox(widget.NewLabel(lang.L("RGB565 pixels use the chosen byte order")), hoverLabel),
+		nil, nil,
+		container.NewScroll(view),
+	))
+	window.Resize(fyne.NewSize(600, 700))
+	window.Show()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// linesPerRecord returns the number of lines each record occupies in record mode
+func (h *HexDumpApp) linesPerRecord() int {
+	return (h.recordSize + h.bytesPerLine - 1) / h.bytesPerLine
+}
+
+// lineCount returns the number of lines needed to show the file. In record mode each
+// record starts a new line, so the last line of a record may be short.
+func (h *HexDumpApp) lineCount() int {
+	if len(h.fileData) == 0 || h.bytesPerLine == 0 {
+		return 0
+	}
+	return h.lineOf(len(h.fileData)-1) + 1
+}
+
+// lineStart returns the offset of the first byte shown on line
+func (h *HexDumpApp) lineStart(line int) int {
+	if h.recordSize == 0 {
+		return line * h.bytesPerLine
+	}
+	perRecord := h.linesPerRecord()
+	return line/perRecord*h.recordSize + line%perRecord*h.bytesPerLine
+}
+
+// lineOf returns the line showing the byte at offset
+func (h *HexDumpApp) lineOf(offset int) int {
+	if h.recordSize == 0 {
+		return offset / h.bytesPerLine
+	}
+	return offset/h.recordSize*h.linesPerRecord() + offset%h.recordSize/h.bytesPerLine
+}
+
+// lineEnd returns the offset after the last byte of the line starting at offset, which
+// is also the start of the next line
+func (h *HexDumpApp) lineEnd(offset int) int {
+	end := offset + h.bytesPerLine
+	if h.recordSize > 0 {
+		end = min(end, (offset/h.recordSize+1)*h.recordSize)
+	}
+	return min(end, len(h.fileData))
+}
+
+// startsRecord reports whether the line starting at offset is the first line of a
+// record whose boundary is marked
+func (h *HexDumpApp) startsRecord(offset int) bool {
+	return h.recordSize > 0 && h.recordRuler && offset > 0 && offset%h.recordSize == 0
+}
+
+// recordStatus describes the record containing the caret for the status bar
+func (h *HexDumpApp) recordStatus() string {
+	if h.recordSize == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Record: %d +0x%s", h.caret/h.recordSize, formatHex(uint64(h.caret%h.recordSize), 1))
+}
+
+// moveByRecords moves the caret by the given number of records, keeping its offset
+// within the record, and scrolls to it
+func (h *HexDumpApp) moveByRecords(count int) {
+	if h.recordSize == 0 || len(h.fileData) == 0 {
+		return
+	}
+	offset := h.caret + count*h.recordSize
+	if offset < 0 || offset >= len(h.fileData) {
+		return
+	}
+	h.selAnchor = offset
+	h.caret = offset
+	h.setSelection(offset, offset+1)
+	h.goToOffset(offset)
+}
+
+// showRecordMode asks for the record size of record mode, in which lines are aligned to
+// record boundaries and Page Up and Page Down move by whole records
+func (h *HexDumpApp) showRecordMode() {
+	sizeEntry := widget.NewEntry()
+	sizeEntry.SetPlaceHolder(lang.L("128, 0x80, or 80h"))
+	if h.recordSize > 0 {
+		sizeEntry.SetText(fmt.Sprint(h.recordSize))
+	}
+	rulerCheck := widget.NewCheck(lang.L("Mark record boundaries"), nil)
+	rulerCheck.SetChecked(h.recordRuler)
+
+	sizeItem := widget.NewFormItem(lang.L("Record size"), sizeEntry)
+	sizeItem.HintText = "Bytes; empty or 0 turns record mode off"
+	form := dialog.NewForm(lang.L("Record Mode"), lang.L("OK"), lang.L("Cancel"), []*widget.FormItem{
+		sizeItem,
+		widget.NewFormItem("", rulerCheck),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		size := 0
+		if sizeEntry.Text != "" {
+			var err error
+			if size, err = parseOffset(sizeEntry.Text); err != nil || size < 0 {
+				dialog.ShowError(fmt.Errorf("invalid record size %q", sizeEntry.Text), h.window)
+				return
+			}
+		}
+		h.recordSize = size
+		h.recordRuler = rulerCheck.Checked
+		h.updateDisplay()
+		h.updateStatus()
+		h.goToOffset(h.caret)
+	}, h.window)
+	form.Resize(fyne.NewSize(380, 240))
+	form.Show()
+	h.window.Canvas().Focus(sizeEntry)
+}
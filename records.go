@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fixedSize returns the size of fields if it doesn't depend on the data: they have no
+// arrays whose count is an expression
+func fixedSize(fields []templateField) (int, bool) {
+	total := 0
+	for _, field := range fields {
+		size := 0
+		switch {
+		case field.Type == "computed":
+		case field.Count != "":
+			count, err := strconv.ParseUint(strings.TrimSpace(string(field.Count)), 0, 31)
+			recordSize, fixed := fixedSize(field.Fields)
+			if err != nil || !fixed {
+				return 0, false
+			}
+			size = int(count) * recordSize
+		case field.Type == "struct":
+			structSize, fixed := fixedSize(field.Fields)
+			if !fixed {
+				return 0, false
+			}
+			size = structSize
+		default:
+			size, _ = fieldSize(field.Type)
+		}
+		total += size
+	}
+	return total, true
+}
+
+// decodeArray decodes the array of records field at offset into parsed, whose children
+// are the fields of the record shown, and returns the offset after the last record.
+// Records are found by their fixed size or, if it varies, by decoding those before.
+func (d *templateDecoder) decodeArray(offset int, field templateField, parsed *parsedField,
+	uid widget.TreeNodeID, scopes []*parsedField) (int, error) {
+
+	node, err := parseExpression(string(field.Count))
+	var count int64
+	if err == nil {
+		count, err = (&exprContext{data: d.data, order: d.order, scopes: scopes}).evaluate(node)
+	}
+	if err != nil {
+		return offset, fmt.Errorf("count of %q: %v", field.Name, err)
+	}
+	if count < 0 || count > int64(len(d.data)) {
+		return offset, fmt.Errorf("array %q at %08X can't have %d records", field.Name, offset, count)
+	}
+	parsed.array, parsed.count = true, int(count)
+	parsed.index = max(0, min(d.records[uid], parsed.count-1))
+
+	recordOffset, end := offset, offset
+	if size, fixed := fixedSize(field.Fields); fixed {
+		recordOffset, end = offset+parsed.index*size, offset+parsed.count*size
+	} else {
+		for index := range parsed.count {
+			if index == parsed.index {
+				recordOffset = end
+			}
+			if end, err = d.decodeFields(end, field.Fields, &parsedField{}, uid, scopes); err != nil {
+				return end, fmt.Errorf("record %d of %q: %v", index+1, field.Name, err)
+			}
+		}
+	}
+
+	parsed.offset = recordOffset
+	if parsed.count > 0 {
+		recordEnd, err := d.decodeFields(recordOffset, field.Fields, parsed, uid, scopes)
+		parsed.size = recordEnd - recordOffset
+		if err != nil {
+			return recordEnd, err
+		}
+	}
+	if end > len(d.data) {
+		return len(d.data), fmt.Errorf("array %q at %08X runs past the end of the file", field.Name, offset)
+	}
+	return end, nil
+}
+
+// firstArray returns the tree node ID of the first array of records below the field
+// with the given ID, or ""
+func (root *parsedField) firstArray(uid widget.TreeNodeID) widget.TreeNodeID {
+	field := root.fieldAt(uid)
+	if field == nil {
+		return ""
+	}
+	for index, child := range field.children {
+		childID := strconv.Itoa(index)
+		if uid != "" {
+			childID = uid + "." + childID
+		}
+		if child.array {
+			return childID
+		}
+		if found := root.firstArray(childID); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// arrayContaining returns the tree node ID of the innermost array of records that is
+// or contains the field with the given ID, or ""
+func (root *parsedField) arrayContaining(uid widget.TreeNodeID) widget.TreeNodeID {
+	for uid != "" {
+		if field := root.fieldAt(uid); field != nil && field.array {
+			return uid
+		}
+		dot := strings.LastIndexByte(uid, '.')
+		if dot < 0 {
+			break
+		}
+		uid = uid[:dot]
+	}
+	return ""
+}
+
+// currentArray returns the array of records the record navigator steps through: the
+// last one a field was selected in, or the first one, with its tree node ID
+func (h *HexDumpApp) currentArray() (widget.TreeNodeID, *parsedField) {
+	if h.templateFields == nil {
+		return "", nil
+	}
+	uid := h.recordArray
+	if field := h.templateFields.fieldAt(uid); uid == "" || field == nil || !field.array {
+		uid = h.templateFields.firstArray("")
+	}
+	if uid == "" {
+		return "", nil
+	}
+	return uid, h.templateFields.fieldAt(uid)
+}
+
+// showRecord decodes the record at index of the current array of records, shows its
+// fields in the Structure panel, and selects its bytes
+func (h *HexDumpApp) showRecord(index int) {
+	uid, array := h.currentArray()
+	if array == nil || array.count == 0 {
+		return
+	}
+	if h.recordIndexes == nil {
+		h.recordIndexes = make(map[widget.TreeNodeID]int)
+	}
+	h.recordIndexes[uid] = max(0, min(index, array.count-1))
+	h.recordArray = uid
+	h.decodeTemplate(h.templateFields.offset)
+	if h.structureTree != nil {
+		h.structureTree.Refresh()
+	}
+	h.updateFieldHighlights()
+
+	if array = h.templateFields.fieldAt(uid); array != nil && array.size > 0 {
+		h.setSelection(array.offset, array.offset+array.size)
+		h.goToOffset(array.offset)
+	} else {
+		h.updateDisplay()
+		h.updateStatus()
+	}
+}
+
+// nextRecord shows the record after the one shown of the current array of records
+func (h *HexDumpApp) nextRecord() {
+	if _, array := h.currentArray(); array != nil {
+		h.showRecord(array.index + 1)
+	}
+}
+
+// previousRecord shows the record before the one shown of the current array of records
+func (h *HexDumpApp) previousRecord() {
+	if _, array := h.currentArray(); array != nil {
+		h.showRecord(array.index - 1)
+	}
+}
+
+// showGoToRecord asks for the number of the record of the current array of records to show
+func (h *HexDumpApp) showGoToRecord() {
+	_, array := h.currentArray()
+	if array == nil {
+		dialog.ShowInformation(lang.L("Go to Record"), lang.L("The applied template has no array of records."),
+			h.window)
+		return
+	}
+	recordEntry := widget.NewEntry()
+	recordEntry.SetText(strconv.Itoa(array.index + 1))
+	recordEntry.Validator = func(text string) error {
+		number, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || number < 1 || number > array.count {
+			return fmt.Errorf("enter a record number from 1 to %d", array.count)
+		}
+		return nil
+	}
+	recordItem := widget.NewFormItem(lang.L("Record"), recordEntry)
+	recordItem.HintText = fmt.Sprintf("1 to %d", array.count)
+	dialog.ShowForm(fmt.Sprintf("Go to Record of %s", array.name), lang.L("Go"), lang.L("Cancel"),
+		[]*widget.FormItem{recordItem}, func(ok bool) {
+			if number, err := strconv.Atoi(strings.TrimSpace(recordEntry.Text)); ok && err == nil {
+				h.showRecord(number - 1)
+			}
+		}, h.window)
+	h.window.Canvas().Focus(recordEntry)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// remoteChunkSize is how many bytes File → Open Remote asks for at a time
+const remoteChunkSize = 4 << 20
+
+// maxRemoteResponse is the largest response accepted from a server, so that a broken
+// or hostile one can't exhaust memory with a single answer
+const maxRemoteResponse = 64 << 20
+
+// remoteScheme starts the names of files opened with File → Open Remote. They name no
+// local file, so they can't be saved, reloaded or monitored in place.
+const remoteScheme = "remote://"
+
+// remoteClient talks to a "hexdump serve" server
+type remoteClient struct {
+	base   string // URL of the server, such as http://host:8765
+	token  string
+	client *http.Client
+}
+
+// newRemoteClient returns a client of the server at address, which defaults to http
+func newRemoteClient(address, token string) (*remoteClient, error) {
+	address = strings.TrimRight(strings.TrimSpace(address), "/")
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	if _, err := url.Parse(address); err != nil {
+		return nil, err
+	}
+	return &remoteClient{base: address, token: token, client: &http.Client{Timeout: time.Minute}}, nil
+}
+
+// get requests path from the server with the given query, returning the body of a
+// successful response
+func (c *remoteClient) get(path string, query url.Values) ([]byte, error) {
+	address := c.base + path
+	if len(query) > 0 {
+		address += "?" + query.Encode()
+	}
+	request, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxRemoteResponse+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxRemoteResponse {
+		return nil, fmt.Errorf("the response to %s is larger than %d bytes", path, maxRemoteResponse)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// files lists the files the server serves
+func (c *remoteClient) files() ([]remoteFile, error) {
+	body, err := c.get("/files", nil)
+	if err != nil {
+		return nil, err
+	}
+	var files []remoteFile
+	if err := json.Unmarshal(body, &files); err != nil {
+		return nil, fmt.Errorf("reading the file list: %w", err)
+	}
+	return files, nil
+}
+
+// download reads a served file in chunks, reporting progress as a background task
+// does. It stops with an error if progress returns false. The buffer grows with the
+// chunks received rather than by the size the server lists.
+func (c *remoteClient) download(file remoteFile, progress func(done, total int) bool) ([]byte, error) {
+	var data bytes.Buffer
+	for int64(data.Len()) < file.Size {
+		chunk, err := c.get("/files/"+url.PathEscape(file.Name), url.Values{
+			"offset": {fmt.Sprint(data.Len())},
+			"length": {fmt.Sprint(remoteChunkSize)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(chunk) == 0 {
+			break // The file shrank since it was listed
+		}
+		if len(chunk) > remoteChunkSize {
+			return nil, fmt.Errorf("the server sent %d bytes of %s instead of at most %d", len(chunk), file.Name,
+				remoteChunkSize)
+		}
+		data.Write(chunk)
+		if !progress(data.Len(), int(file.Size)) {
+			return nil, fmt.Errorf("download cancelled")
+		}
+	}
+	return data.Bytes(), nil
+}
+
+// remoteName returns the name under which a file downloaded from a server is shown,
+// such as remote://host:8765/data.bin
+func (c *remoteClient) remoteName(file remoteFile) string {
+	host := c.base
+	if address, err := url.Parse(c.base); err == nil && address.Host != "" {
+		host = address.Host
+	}
+	return remoteScheme + host + "/" + file.Name
+}
+
+// isRemoteName reports whether name is that of a file downloaded from a server rather
+// than a local path
+func isRemoteName(name string) bool {
+	return strings.HasPrefix(name, remoteScheme)
+}
+
+// openRemote asks for the address of a "hexdump serve" server, and lists its files to
+// open one
+func (h *HexDumpApp) openRemote() {
+	addressEntry := widget.NewEntry()
+	addressEntry.SetPlaceHolder(defaultServeAddress)
+	addressEntry.SetText(appSettings.RemoteAddress)
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetPlaceHolder(lang.L("Only if the server requires one"))
+
+	form := dialog.NewForm(lang.L("Open Remote"), lang.L("Connect"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Server"), addressEntry),
+		widget.NewFormItem(lang.L("Token"), tokenEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		address := addressEntry.Text
+		if strings.TrimSpace(address) == "" {
+			address = defaultServeAddress
+		}
+		client, err := newRemoteClient(address, tokenEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		appSettings.RemoteAddress = address
+		saveSettings()
+		h.listRemoteFiles(client)
+	}, h.window)
+	form.Resize(fyne.NewSize(420, 200))
+	form.Show()
+}
+
+// listRemoteFiles asks a server for its files as a background task, and shows them to
+// pick one to open
+func (h *HexDumpApp) listRemoteFiles(client *remoteClient) {
+	task := h.startTask(lang.L("Open Remote"))
+	go func() {
+		defer h.recoverPanic()
+		files, err := client.files()
+		fyne.Do(func() {
+			result := lang.L("{{.Count}} file(s)", map[string]any{"Count": len(files)})
+			if err != nil {
+				result = err.Error()
+			}
+			if !h.finishTask(task, result, nil) {
+				return
+			}
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			h.showRemoteFiles(client, files)
+		})
+	}()
+}
+
+// showRemoteFiles shows the files of a server; choosing one downloads and opens it
+func (h *HexDumpApp) showRemoteFiles(client *remoteClient, files []remoteFile) {
+	var popup dialog.Dialog
+	list := widget.NewList(
+		func() int { return len(files) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, object fyne.CanvasObject) {
+			object.(*widget.Label).SetText(fmt.Sprintf("%s (%d bytes)", files[id].Name, files[id].Size))
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		popup.Hide()
+		file := files[id]
+		h.confirmDiscardEdits(func() { h.downloadRemoteFile(client, file) })
+	}
+	popup = dialog.NewCustom(lang.L("Open Remote")+" — "+client.base, lang.L("Cancel"), list, h.window)
+	popup.Resize(fyne.NewSize(460, 360))
+	popup.Show()
+}
+
+// downloadRemoteFile downloads a served file as a background task and opens it
+func (h *HexDumpApp) downloadRemoteFile(client *remoteClient, file remoteFile) {
+	task := h.startTask(lang.L("Download {{.Name}}", map[string]any{"Name": file.Name}))
+	go func() {
+		defer h.recoverPanic()
+		data, err := client.download(file, task.report)
+		fyne.Do(func() {
+			result := fmt.Sprintf("%d bytes", len(data))
+			if err != nil {
+				result = err.Error()
+			}
+			if !h.finishTask(task, result, nil) {
+				return
+			}
+			if err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+			h.loadData(client.remoteName(file), data)
+		})
+	}()
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+)
+
+// clipboardFlavor is a kind of clipboard content other than plain text, which the
+// clipboard of the GUI toolkit can't hold
+type clipboardFlavor struct {
+	mimeType    string // Type given to wl-copy and xclip
+	appleClass  string // AppleScript class of the content
+	powerShell  string // PowerShell command placing the content of the file $path
+	description string // For errors
+}
+
+var (
+	flavorPNG = clipboardFlavor{"image/png", "PNGf",
+		"Add-Type -AssemblyName System.Windows.Forms; [Windows.Forms.Clipboard]::SetImage([Drawing.Image]::FromFile($path))",
+		"images"}
+	flavorHTML = clipboardFlavor{"text/html", "HTML",
+		"Set-Clipboard -AsHtml -Value (Get-Content -Raw -Encoding UTF8 -LiteralPath $path)", "colored text"}
+)
+
+// copyToSystemClipboard puts data of the given flavor on the system clipboard. This is
+// left to the tools of the platform: PowerShell on Windows, AppleScript on macOS, and
+// wl-copy or xclip on Linux.
+func copyToSystemClipboard(flavor clipboardFlavor, data []byte) error {
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		file, err := os.CreateTemp("", "hexdump-clipboard-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		var command *exec.Cmd
+		if runtime.GOOS == "windows" {
+			command = exec.Command("powershell", "-NoProfile", "-STA", "-Command",
+				"$path = '"+strings.ReplaceAll(file.Name(), "'", "''")+"'; "+flavor.powerShell)
+		} else {
+			command = exec.Command("osascript", "-e",
+				fmt.Sprintf("set the clipboard to (read (POSIX file %q) as «class %s»)", file.Name(), flavor.appleClass))
+		}
+		if output, err := command.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	default:
+		command := exec.Command("xclip", "-selection", "clipboard", "-t", flavor.mimeType)
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			command = exec.Command("wl-copy", "--type", flavor.mimeType)
+		}
+		command.Stdin = bytes.NewReader(data)
+		if err := command.Start(); err != nil {
+			return fmt.Errorf("%s is needed to copy %s: %w", command.Args[0], flavor.description, err)
+		}
+		// Both tools keep running to serve the clipboard, so don't wait for them
+		go command.Wait()
+		return nil
+	}
+}
+
+// htmlColor formats a color for CSS
+func htmlColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// selectionHTML formats the selection as an HTML fragment laid out like the display: a
+// line of address, hex and characters for each line of each selected range, with the
+// bytes on the background colors of their bookmarks, template fields and other
+// highlights
+func (h *HexDumpApp) selectionHTML() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<pre style="font-family: monospace; background: %s; color: %s; padding: 4px">`,
+		htmlColor(theme.Color(theme.ColorNameBackground)), htmlColor(theme.Color(theme.ColorNameForeground)))
+	for _, r := range h.selectedRanges() {
+		for lineStart := h.lineStart(h.lineOf(r.start)); lineStart < r.end; lineStart = h.lineEnd(lineStart) {
+			start, end := max(lineStart, r.start), min(h.lineEnd(lineStart), r.end)
+			colors := make([]color.Color, end-start)
+			for _, span := range h.annotationSpans(start, end) {
+				for offset := span.start; offset < span.end; offset++ {
+					colors[offset-start] = span.color
+				}
+			}
+
+			// Bytes of the line outside the range are left blank, so that columns line up
+			lead, trail := start-lineStart, h.lineEnd(lineStart)-end
+			fmt.Fprintf(&builder, "%s: %*s", html.EscapeString(h.formatAddress(lineStart)), 3*lead, "")
+			for index := 0; index < len(colors); {
+				run := index + 1
+				for run < len(colors) && colors[run] == colors[index] {
+					run++
+				}
+				text := html.EscapeString(h.formatCopy(copyHex, h.fileData[start+index:start+run]))
+				if colors[index] != nil {
+					fmt.Fprintf(&builder, `<span style="background: %s">%s</span>`, htmlColor(colors[index]), text)
+				} else {
+					builder.WriteString(text)
+				}
+				if run < len(colors) {
+					builder.WriteString(" ")
+				}
+				index = run
+			}
+			fmt.Fprintf(&builder, "%*s  %*s%s\n", 3*trail, "", lead, "",
+				html.EscapeString(h.bytesToChars(h.fileData[start:end])))
+		}
+	}
+	builder.WriteString("</pre>")
+	return builder.String()
+}
+
+// copySelectionHTML copies the selection to the clipboard as colored HTML, which keeps
+// the colors of its highlights when pasted into word processors and wikis
+func (h *HexDumpApp) copySelectionHTML() {
+	if !h.hasAnySelection() {
+		dialog.ShowInformation(lang.L("Copy"), lang.L("Select the bytes to copy first."), h.window)
+		return
+	}
+	if err := copyToSystemClipboard(flavorHTML, []byte(h.selectionHTML())); err != nil {
+		dialog.ShowError(fmt.Errorf("copying the selection with colors: %w", err), h.window)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// savePreviewBytes is the number of old and new bytes the save preview shows per range
+const savePreviewBytes = 16
+
+// formatPreviewBytes formats the bytes of a changed range for the save preview, shortened
+// to savePreviewBytes
+func formatPreviewBytes(data []byte) string {
+	if len(data) == 0 {
+		return "(none)"
+	}
+	var digits []string
+	for _, b := range data[:min(len(data), savePreviewBytes)] {
+		digits = append(digits, formatHex(uint64(b), 2))
+	}
+	text := strings.Join(digits, " ")
+	if len(data) > savePreviewBytes {
+		text += " …"
+	}
+	return text
+}
+
+// confirmSave compares the data with the file at path and, if saving would change it,
+// lists the changed ranges with their old and new bytes and calls proceed only if the
+// user confirms. A file that doesn't exist yet is saved without asking.
+func (h *HexDumpApp) confirmSave(path string, proceed func()) {
+	old, mapped, err := readFileData(path)
+	if err != nil {
+		proceed()
+		return
+	}
+	ranges := diffRanges(old, h.fileData)
+	if len(ranges) == 0 && len(old) == len(h.fileData) {
+		mapped.release()
+		proceed()
+		return
+	}
+
+	changed := 0
+	for _, r := range ranges {
+		changed += r.end - r.start
+	}
+	summary := fmt.Sprintf("Saving changes %d bytes in %d ranges of %s.", changed, len(ranges), path)
+	if len(old) > len(h.fileData) {
+		summary += fmt.Sprintf("\nThe file on disk is %d bytes longer; they will be removed.", len(old)-len(h.fileData))
+	}
+
+	list := widget.NewList(
+		func() int { return len(ranges) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("\n\n") // Three lines, for the list's row height
+			label.TextStyle.Monospace = true
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			r := ranges[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%s-%s (%d bytes)\n  old: %s\n  new: %s",
+				formatHex(uint64(r.start), 8), formatHex(uint64(r.end-1), 8), r.end-r.start,
+				formatPreviewBytes(old[min(r.start, len(old)):min(r.end, len(old))]),
+				formatPreviewBytes(h.fileData[r.start:r.end])))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		h.setSelection(ranges[id].start, ranges[id].end)
+		h.goToOffset(ranges[id].start)
+	}
+
+	content := container.NewBorder(widget.NewLabel(summary), widget.NewLabel(lang.L("Click a range to go to it")),
+		nil, nil, list)
+	confirm := dialog.NewCustomConfirm(lang.L("Save Changes"), lang.L("Save"), lang.L("Cancel"), content, func(ok bool) {
+		mapped.release() // Before saving, which may replace the mapped file
+		if ok {
+			proceed()
+		}
+	}, h.window)
+	confirm.Resize(fyne.NewSize(560, 420))
+	confirm.Show()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// scrollAnimationTime is how long an animated jump of the data list takes
+const scrollAnimationTime = 150 * time.Millisecond
+
+// maxAnimatedScreens limits animated jumps to a few screens, so that long jumps, which
+// would only blur past, happen at once
+const maxAnimatedScreens = 3
+
+// scrollAnchor is a point of the data list that is kept in place when its rows change:
+// the first byte of the top visible row, and how far the list is scrolled past the top
+// of that row
+type scrollAnchor struct {
+	offset int
+	shift  float32
+}
+
+// rowPitch returns the distance between the tops of consecutive rows of the data list.
+// Every row has the same height, so the position of any row can be computed.
+func (h *HexDumpApp) rowPitch() float32 {
+	if appSettings.AccessibleMode {
+		return newAccessibleRow(h).MinSize().Height + theme.Padding()
+	}
+	return rowHeight() + theme.Padding()
+}
+
+// scrollAnchor returns the anchor of the data list as it is scrolled now
+func (h *HexDumpApp) scrollAnchor() scrollAnchor {
+	if h.dataList == nil || len(h.fileData) == 0 || h.bytesPerLine == 0 {
+		return scrollAnchor{}
+	}
+	pitch := h.rowPitch()
+	scrolled := h.dataList.GetScrollOffset()
+	row := min(int(scrolled/pitch), max(0, h.rowCount()-1))
+	return scrollAnchor{offset: min(h.rowStart(row), len(h.fileData)-1), shift: scrolled - float32(row)*pitch}
+}
+
+// restoreScrollAnchor scrolls the data list so that the anchor is where it was, after
+// the rows have changed
+func (h *HexDumpApp) restoreScrollAnchor(anchor scrollAnchor) {
+	if h.dataList == nil || anchor.offset >= len(h.fileData) {
+		return
+	}
+	h.stopScrollAnimation()
+	h.dataList.ScrollToOffset(float32(h.rowOf(anchor.offset))*h.rowPitch() + anchor.shift)
+}
+
+// scrollToByte scrolls the data list so that the row holding offset is in the middle
+// of it. Short jumps are animated if the preferences ask for it.
+func (h *HexDumpApp) scrollToByte(offset int) {
+	if h.dataList == nil || offset < 0 || offset >= len(h.fileData) {
+		return
+	}
+	pitch := h.rowPitch()
+	visible := h.dataList.Size().Height
+	target := max(0, float32(h.rowOf(offset))*pitch+(pitch-theme.Padding())/2-visible/2)
+	target = min(target, max(0, float32(h.rowCount())*pitch-theme.Padding()-visible))
+	h.scrollToPosition(target)
+	h.syncPositionSlider(offset)
+}
+
+// centerCaret scrolls the data list so that the caret is in the middle of it
+func (h *HexDumpApp) centerCaret() {
+	h.scrollToByte(h.caret)
+}
+
+// scrollToPosition scrolls the data list to a scroll offset, animating the movement if
+// smooth scrolling is on and the jump is short
+func (h *HexDumpApp) scrollToPosition(target float32) {
+	h.stopScrollAnimation()
+	from := h.dataList.GetScrollOffset()
+	distance := target - from
+	if !appSettings.SmoothScrolling || distance == 0 ||
+		max(distance, -distance) > maxAnimatedScreens*h.dataList.Size().Height {
+		h.dataList.ScrollToOffset(target)
+		return
+	}
+	list := h.dataList
+	h.scrollAnimation = fyne.NewAnimation(scrollAnimationTime, func(progress float32) {
+		list.ScrollToOffset(from + distance*progress)
+	})
+	h.scrollAnimation.Curve = fyne.AnimationEaseOut
+	h.scrollAnimation.Start()
+}
+
+// stopScrollAnimation stops an animated jump of the data list, so that it doesn't fight
+// with a newer scroll
+func (h *HexDumpApp) stopScrollAnimation() {
+	if h.scrollAnimation != nil {
+		h.scrollAnimation.Stop()
+		h.scrollAnimation = nil
+	}
+}
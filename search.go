@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// Search kinds, as shown in the GUI
+const (
+	searchKindHex  = "Hex bytes"
+	searchKindText = "Text"
+)
+
+// searchKinds lists the supported search kinds in display order
+var searchKinds = []string{searchKindHex, searchKindText}
+
+// searchPattern is a byte sequence to search for. Each byte of mask selects which
+// bits of the corresponding byte of data must match, so 0x00 is a full wildcard.
+type searchPattern struct {
+	data []byte
+	mask []byte
+}
+
+// parseSearchPattern parses text as a pattern of the given kind. Text patterns are
+// encoded with the given character encoding.
+func parseSearchPattern(kind string, text string, encoding string) (searchPattern, error) {
+	if kind == searchKindText {
+		return textPattern(text, encoding)
+	}
+	return parseHexPattern(text)
+}
+
+// parseHexPattern parses a hex pattern such as "4D 5A ?? 00" or "1F8B08". A "?" in
+// place of a hex digit matches any value of that nibble.
+func parseHexPattern(text string) (searchPattern, error) {
+	digits := strings.Join(strings.Fields(text), "")
+	digits = strings.TrimPrefix(strings.TrimPrefix(digits, "0x"), "0X")
+	if digits == "" {
+		return searchPattern{}, fmt.Errorf("empty hex pattern")
+	}
+	if len(digits)%2 != 0 {
+		return searchPattern{}, fmt.Errorf("hex pattern %q has an odd number of digits", text)
+	}
+
+	pattern := searchPattern{
+		data: make([]byte, len(digits)/2),
+		mask: make([]byte, len(digits)/2),
+	}
+	for index := 0; index < len(digits); index++ {
+		var value, mask byte
+		switch digit := digits[index]; {
+		case digit == '?':
+			value, mask = 0, 0
+		case digit >= '0' && digit <= '9':
+			value, mask = digit-'0', 0xF
+		case digit >= 'a' && digit <= 'f':
+			value, mask = digit-'a'+10, 0xF
+		case digit >= 'A' && digit <= 'F':
+			value, mask = digit-'A'+10, 0xF
+		default:
+			return searchPattern{}, fmt.Errorf("invalid character %q in hex pattern", digit)
+		}
+
+		shift := 4 * (1 - index%2)
+		pattern.data[index/2] |= value << shift
+		pattern.mask[index/2] |= mask << shift
+	}
+	return pattern, nil
+}
+
+// textPattern returns a pattern matching text encoded with the given encoding
+func textPattern(text string, encoding string) (searchPattern, error) {
+	data, err := encodeText(text, encoding)
+	if err != nil {
+		return searchPattern{}, err
+	}
+	if len(data) == 0 {
+		return searchPattern{}, fmt.Errorf("empty search text")
+	}
+	return searchPattern{data: data, mask: bytes.Repeat([]byte{0xFF}, len(data))}, nil
+}
+
+// encodeText converts text to bytes in the given character encoding. Characters the
+// encoding can't represent are an error, rather than being silently turned into other
+// bytes.
+func encodeText(text string, encoding string) ([]byte, error) {
+	if r, found := unrepresentable(text, encoding); found {
+		return nil, fmt.Errorf("%q (U+%04X) cannot be represented in %s", string(r), r, encoding)
+	}
+	return encodeChars(text, encoding)
+}
+
+// encodeChars converts text to bytes in the given character encoding without checking
+// that it can be represented: characters beyond ISO Latin-1 lose their high bits
+func encodeChars(text string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "UTF-8":
+		return []byte(text), nil
+	case "UTF-16LE":
+		units := utf16.Encode([]rune(text))
+		data := make([]byte, 0, len(units)*2)
+		for _, unit := range units {
+			data = append(data, byte(unit), byte(unit>>8))
+		}
+		return data, nil
+	case "GB 18030":
+		return simplifiedchinese.GB18030.NewEncoder().Bytes([]byte(text))
+	default:
+		data := make([]byte, 0, len(text))
+		for _, r := range text {
+			data = append(data, byte(r))
+		}
+		return data, nil
+	}
+}
+
+// unrepresentable returns the first character of text that the encoding can't
+// represent: one that doesn't decode to itself once encoded
+func unrepresentable(text string, encoding string) (rune, bool) {
+	for _, r := range text {
+		data, err := encodeChars(string(r), encoding)
+		if err != nil || len(data) == 0 {
+			return r, true
+		}
+		if decoded, size := decodeRune(data, encoding); decoded != r || size != len(data) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// textPatternWarning describes why text typed as a pattern of the given kind can't be
+// searched for in the encoding, or returns "" if it can, so that dialogs can warn while
+// it is typed
+func textPatternWarning(kind string, text string, encoding string) string {
+	if kind != searchKindText {
+		return ""
+	}
+	if r, found := unrepresentable(text, encoding); found {
+		return fmt.Sprintf("%q cannot be represented in %s, so the text can't be searched for", string(r), encoding)
+	}
+	return ""
+}
+
+// isExact reports whether the pattern has no wildcard bits
+func (p searchPattern) isExact() bool {
+	for _, m := range p.mask {
+		if m != 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAt reports whether the pattern matches data at offset
+func (p searchPattern) matchAt(data []byte, offset int) bool {
+	if offset < 0 || offset+len(p.data) > len(data) {
+		return false
+	}
+	for index, b := range p.data {
+		if data[offset+index]&p.mask[index] != b {
+			return false
+		}
+	}
+	return true
+}
+
+// findNext returns the offset of the first match at or after start, or -1
+func (p searchPattern) findNext(data []byte, start int) int {
+	if len(p.data) == 0 || start < 0 {
+		return -1
+	}
+	if p.isExact() {
+		if start > len(data) {
+			return -1
+		}
+		if index := bytes.Index(data[start:], p.data); index >= 0 {
+			return start + index
+		}
+		return -1
+	}
+	for offset := start; offset+len(p.data) <= len(data); offset++ {
+		if p.matchAt(data, offset) {
+			return offset
+		}
+	}
+	return -1
+}
+
+// searchChunkSize is how much of a file findInReader reads at a time
+const searchChunkSize = 1 << 20
+
+// findInReader returns the offsets of non-overlapping matches in the data read from r,
+// as findAll does for data in memory, reading it a chunk at a time. Each chunk starts
+// with the last bytes of the one before, so that matches spanning two chunks are found.
+func (p searchPattern) findInReader(r io.Reader, limit int) ([]int, error) {
+	var offsets []int
+	buffer := make([]byte, 0, searchChunkSize+len(p.data))
+	base, next := 0, 0 // File offsets of buffer[0] and of the first byte a match may start at
+	for {
+		n, err := io.ReadFull(r, buffer[len(buffer):cap(buffer)])
+		buffer = buffer[:len(buffer)+n]
+		for offset := p.findNext(buffer, max(next-base, 0)); offset >= 0; offset = p.findNext(buffer, next-base) {
+			offsets = append(offsets, base+offset)
+			next = base + offset + len(p.data)
+			if limit > 0 && len(offsets) >= limit {
+				return offsets, nil
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return offsets, nil
+		}
+		if err != nil {
+			return offsets, err
+		}
+		// Keep the bytes a match may start in that runs into the next chunk
+		keep := min(len(buffer), max(len(p.data)-1, 0))
+		base += len(buffer) - keep
+		buffer = buffer[:copy(buffer, buffer[len(buffer)-keep:])]
+	}
+}
+
+// findAll returns the offsets of non-overlapping matches in data, stopping after
+// limit matches when limit is positive
+func (p searchPattern) findAll(data []byte, limit int) []int {
+	var offsets []int
+	for offset := p.findNext(data, 0); offset >= 0; offset = p.findNext(data, offset+len(p.data)) {
+		offsets = append(offsets, offset)
+		if limit > 0 && len(offsets) >= limit {
+			break
+		}
+	}
+	return offsets
+}
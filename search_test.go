@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestFindInReaderMatchesFindAll(t *testing.T) {
+	// Matches straddle the chunk boundaries, and some overlap one another
+	data := make([]byte, 3*searchChunkSize+100)
+	for _, offset := range []int{0, searchChunkSize - 2, searchChunkSize + 5, 2*searchChunkSize - 1, len(data) - 4} {
+		copy(data[offset:], "\x4D\x5A\x4D\x5A")
+	}
+	tests := []struct {
+		pattern string
+		limit   int
+	}{
+		{"4D 5A", 0},
+		{"4D 5A 4D", 0},
+		{"5A ?? 5A", 0},
+		{"4D 5A", 3},
+		{"4D 5A 4D 5A 4D 5A", 0},
+	}
+	for _, test := range tests {
+		pattern, err := parseHexPattern(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := pattern.findAll(data, test.limit)
+		got, err := pattern.findInReader(bytes.NewReader(data), test.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s, limit %d: got %v, want %v", test.pattern, test.limit, got, want)
+		}
+	}
+}
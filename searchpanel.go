@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxSearchResults limits the number of matches listed by the Search Results panel
+const maxSearchResults = 10000
+
+// createSearchPanel creates the Search Results side panel, which finds every match of a
+// hex or text pattern in the file. Tapping a match selects its bytes.
+func (h *HexDumpApp) createSearchPanel() panelContent {
+	var matches []int
+	var matchLength int
+
+	kindSelect := widget.NewSelect(searchKinds, nil)
+	kindSelect.SetSelected(searchKindHex)
+	encodingSelect := widget.NewSelect(encodingNames, nil)
+	encodingSelect.SetSelected(h.encoding)
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder(lang.L("e.g. 4D 5A ?? 00, or text"))
+	summaryLabel := widget.NewLabel("")
+	warningLabel := newEncodingWarning(kindSelect, encodingSelect, patternEntry)
+
+	list := widget.NewList(
+		func() int { return len(matches) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			offset := matches[id]
+			text := fmt.Sprintf("%08X", offset)
+			if symbol := h.symbolAt(offset); symbol != "" {
+				text += "  " + symbol
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		offset := matches[id]
+		h.setSelection(offset, offset+matchLength)
+		h.goToOffset(offset)
+	}
+
+	findAll := func() {
+		pattern, err := parseSearchPattern(kindSelect.Selected, patternEntry.Text, encodingSelect.Selected)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		matches = pattern.findAll(h.fileData, maxSearchResults)
+		matchLength = len(pattern.data)
+		list.UnselectAll()
+		list.Refresh()
+
+		summary := fmt.Sprintf("%d matches", len(matches))
+		if len(matches) == maxSearchResults {
+			summary = fmt.Sprintf("First %d matches", maxSearchResults)
+		}
+		summaryLabel.SetText(summary)
+	}
+	patternEntry.OnSubmitted = func(string) { findAll() }
+	findBtn := widget.NewButton(lang.L("Find All"), findAll)
+
+	reset := func() {
+		matches = nil
+		list.UnselectAll()
+		list.Refresh()
+		summaryLabel.SetText("")
+	}
+
+	controls := container.NewVBox(
+		container.NewGridWithColumns(2, kindSelect, encodingSelect),
+		container.NewBorder(nil, nil, nil, findBtn, patternEntry),
+		warningLabel,
+		summaryLabel,
+	)
+	return panelContent{
+		object: container.NewBorder(controls, nil, nil, nil, list),
+		reset:  reset,
+	}
+}
+
+// newEncodingWarning returns a label warning, while a text pattern is typed, of any
+// character the selected encoding can't represent, rather than letting the search turn
+// it into other bytes. The label is hidden while there is nothing to warn of.
+func newEncodingWarning(kindSelect, encodingSelect *widget.Select, patternEntry *widget.Entry) *widget.Label {
+	label := widget.NewLabel("")
+	label.Importance = widget.WarningImportance
+	label.Wrapping = fyne.TextWrapWord
+	update := func() {
+		warning := textPatternWarning(kindSelect.Selected, patternEntry.Text, encodingSelect.Selected)
+		label.SetText(warning)
+		if warning == "" {
+			label.Hide()
+		} else {
+			label.Show()
+		}
+	}
+	kindSelect.OnChanged = func(string) { update() }
+	encodingSelect.OnChanged = func(string) { update() }
+	patternEntry.OnChanged = func(string) { update() }
+	update()
+	return label
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// setSelection selects the byte range [start, end), clamped to the file data
+func (h *HexDumpApp) setSelection(start, end int) {
+	if start > end {
+		start, end = end, start
+	}
+	h.columnSelect = false
+	if !h.addingRange {
+		h.extraRanges = nil
+	}
+	h.selStart = max(0, min(start, len(h.fileData)))
+	h.selEnd = max(0, min(end, len(h.fileData)))
+	if h.selAnchor < h.selStart || h.selAnchor >= h.selEnd {
+		h.selAnchor = h.selStart
+	}
+	if h.caret < h.selStart || h.caret >= max(h.selEnd, h.selStart+1) {
+		h.caret = h.selStart
+	}
+
+	if h.dataList != nil {
+		h.dataList.Refresh()
+	}
+	h.updateStatus()
+}
+
+// extendSelection selects the bytes from the selection anchor through offset
+func (h *HexDumpApp) extendSelection(offset int) {
+	h.caret = offset
+	if offset >= h.selAnchor {
+		h.setSelection(h.selAnchor, offset+1)
+	} else {
+		h.setSelection(offset, h.selAnchor+1)
+	}
+}
+
+// clearSelection removes the selection
+func (h *HexDumpApp) clearSelection() {
+	h.setSelection(0, 0)
+}
+
+// hasSelection reports whether at least one byte is selected
+func (h *HexDumpApp) hasSelection() bool {
+	return h.selEnd > h.selStart
+}
+
+// selectedBytes returns the selected bytes, or nil if nothing is selected
+func (h *HexDumpApp) selectedBytes() []byte {
+	if !h.hasSelection() {
+		return nil
+	}
+	return h.fileData[h.selStart:h.selEnd]
+}
+
+// selectionStatus describes the selection for the status bar
+func (h *HexDumpApp) selectionStatus() string {
+	if h.columnSelect {
+		return h.columnSelectionStatus()
+	}
+	if len(h.extraRanges) > 0 {
+		return lang.L("Selection: {{.Count}} ranges ({{.Length}} bytes)", map[string]any{
+			"Count": len(h.selectedRanges()), "Length": h.selectionLength()})
+	}
+	if !h.hasSelection() {
+		return ""
+	}
+	return lang.L("Selection: {{.Start}}-{{.End}} ({{.Length}} bytes)", map[string]any{
+		"Start": formatHex(uint64(h.selStart), 8), "End": formatHex(uint64(h.selEnd-1), 8), "Length": h.selEnd - h.selStart})
+}
+
+// goToOffset scrolls the data list so that the line containing offset is visible
+func (h *HexDumpApp) goToOffset(offset int) {
+	if h.dataList == nil || offset < 0 || offset >= len(h.fileData) {
+		return
+	}
+	h.stopScrollAnimation()
+	h.dataList.ScrollTo(h.rowOf(offset))
+	h.syncPositionSlider(offset)
+}
+
+// parseOffset parses an offset or length typed by the user. Hex values are written
+// with a "0x" prefix or an "h" suffix, and anything else is decimal.
+func parseOffset(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	lower := strings.ToLower(text)
+
+	var value int64
+	var err error
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		value, err = strconv.ParseInt(lower[2:], 16, 64)
+	case strings.HasSuffix(lower, "h") && len(lower) > 1:
+		value, err = strconv.ParseInt(lower[:len(lower)-1], 16, 64)
+	default:
+		value, err = strconv.ParseInt(lower, 10, 64)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q", text)
+	}
+	return int(value), nil
+}
+
+// showSelectBlock asks for a start offset and either an end offset or a length, and
+// selects that block. The fields default to the current selection.
+func (h *HexDumpApp) showSelectBlock() {
+	if len(h.fileData) == 0 {
+		return
+	}
+
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder(lang.L("0x1F00, 7936, or 1F00h"))
+	valueEntry := widget.NewEntry()
+	if h.hasSelection() {
+		startEntry.SetText(fmt.Sprintf("0x%X", h.selStart))
+		valueEntry.SetText(fmt.Sprintf("0x%X", h.selEnd-1))
+	}
+	kindRadio := widget.NewRadioGroup([]string{"End offset", "Length"}, func(kind string) {
+		// Convert the value between an inclusive end offset and a length
+		start, err1 := parseOffset(startEntry.Text)
+		value, err2 := parseOffset(valueEntry.Text)
+		if err1 != nil || err2 != nil {
+			return
+		}
+		if kind == "Length" {
+			valueEntry.SetText(fmt.Sprintf("0x%X", value-start+1))
+		} else {
+			valueEntry.SetText(fmt.Sprintf("0x%X", start+value-1))
+		}
+	})
+	kindRadio.Horizontal = true
+	kindRadio.SetSelected("End offset")
+
+	form := dialog.NewForm(lang.L("Select Block"), lang.L("Select"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Start offset"), startEntry),
+		widget.NewFormItem("", kindRadio),
+		widget.NewFormItem(lang.L("Value"), valueEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		start, err := parseOffset(startEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		value, err := parseOffset(valueEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+
+		end := value + 1
+		if kindRadio.Selected == "Length" {
+			end = start + value
+		}
+		if start < 0 || start >= len(h.fileData) || end <= start || end > len(h.fileData) {
+			dialog.ShowError(fmt.Errorf("the block must lie within the file (offsets 0x0-0x%X)", len(h.fileData)-1), h.window)
+			return
+		}
+
+		h.selAnchor = start
+		h.caret = start
+		h.setSelection(start, end)
+		h.goToOffset(start)
+	}, h.window)
+	form.Resize(fyne.NewSize(380, 230))
+	form.Show()
+	h.window.Canvas().Focus(startEntry)
+}
+
+// selectAll selects the whole file
+func (h *HexDumpApp) selectAll() {
+	h.selAnchor = 0
+	h.caret = 0
+	h.setSelection(0, len(h.fileData))
+}
+
+// selectToLineEnd extends the selection from its anchor, or from the caret if nothing
+// is selected, to the end of the caret's line
+func (h *HexDumpApp) selectToLineEnd() {
+	if len(h.fileData) == 0 {
+		return
+	}
+	if !h.hasSelection() {
+		h.selAnchor = h.caret
+	}
+	lineEnd := h.lineEnd(h.lineStart(h.lineOf(h.caret)))
+	h.extendSelection(lineEnd - 1)
+}
+
+// selectToFileEnd extends the selection from its anchor, or from the caret if nothing
+// is selected, to the end of the file
+func (h *HexDumpApp) selectToFileEnd() {
+	if len(h.fileData) == 0 {
+		return
+	}
+	if !h.hasSelection() {
+		h.selAnchor = h.caret
+	}
+	h.extendSelection(len(h.fileData) - 1)
+	h.goToOffset(len(h.fileData) - 1)
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// defaultServeAddress is where "hexdump serve" listens unless told otherwise: only on
+// this machine, since the files are served to anyone who can connect
+const defaultServeAddress = "127.0.0.1:8765"
+
+// maxServeStringsSize is the most bytes of a file "hexdump serve" reads into memory to
+// extract strings from. Larger ranges must be asked for in pieces.
+const maxServeStringsSize = 64 << 20
+
+// maxServeSearchHits is the largest max query parameter of a search, which bounds the
+// offsets a search collects before answering
+const maxServeSearchHits = 100000
+
+// remoteFile describes a file served by "hexdump serve"
+type remoteFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// fileServer serves files read-only over HTTP: their list, ranges of their bytes, and
+// the results of searching them, hashing them, and extracting strings from them
+type fileServer struct {
+	paths map[string]string // Paths of the served files by name
+	names []string          // Names of the served files, in the order given
+	token string            // Bearer token required of requests, if not empty
+}
+
+// newFileServer returns a server for the files at paths, named by their base names
+func newFileServer(paths []string, token string) (*fileServer, error) {
+	server := &fileServer{paths: map[string]string{}, token: token}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s is not a regular file", path)
+		}
+		name := filepath.Base(path)
+		if _, ok := server.paths[name]; ok {
+			return nil, fmt.Errorf("two files are named %s", name)
+		}
+		server.paths[name] = path
+		server.names = append(server.names, name)
+	}
+	return server, nil
+}
+
+// handler returns the HTTP handler of the server's endpoints
+func (s *fileServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /files", s.serveList)
+	mux.HandleFunc("GET /files/{name}", s.serveRange)
+	mux.HandleFunc("GET /files/{name}/search", s.serveSearch)
+	mux.HandleFunc("GET /files/{name}/hashes", s.serveHashes)
+	mux.HandleFunc("GET /files/{name}/strings", s.serveStrings)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slog.Debug("serving", "remote", r.RemoteAddr, "url", r.URL.String())
+		if s.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")),
+			[]byte("Bearer "+s.token)) != 1 {
+			http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serveList answers with the names and sizes of the files
+func (s *fileServer) serveList(w http.ResponseWriter, r *http.Request) {
+	files := []remoteFile{}
+	for _, name := range s.names {
+		info, err := os.Stat(s.paths[name])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		files = append(files, remoteFile{Name: name, Size: info.Size()})
+	}
+	writeJSONResponse(w, files)
+}
+
+// open opens the file named in a request, answering with an error if it can't
+func (s *fileServer) open(w http.ResponseWriter, r *http.Request) (*os.File, bool) {
+	path, ok := s.paths[r.PathValue("name")]
+	if !ok {
+		http.Error(w, "no such file", http.StatusNotFound)
+		return nil, false
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return file, true
+}
+
+// queryRange returns the range of a file of the given size selected by the offset and
+// length query parameters of a request, the whole file if they are missing
+func queryRange(r *http.Request, size int64) (int64, int64, error) {
+	offset, length := int64(0), size
+	if text := r.URL.Query().Get("offset"); text != "" {
+		value, err := parseDumpOffset(text)
+		if err != nil {
+			return 0, 0, err
+		}
+		offset, length = min(value, size), size-min(value, size)
+	}
+	if text := r.URL.Query().Get("length"); text != "" {
+		value, err := parseDumpOffset(text)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid length %q", text)
+		}
+		length = min(value, length)
+	}
+	return offset, length, nil
+}
+
+// openQueryRange opens the file named in a request and returns a reader of the range
+// that queryRange selects, answering with an error if it can't. The caller closes the
+// file.
+func (s *fileServer) openQueryRange(w http.ResponseWriter, r *http.Request) (*os.File, *io.SectionReader, bool) {
+	file, ok := s.open(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	offset, length, err := queryRange(r, info.Size())
+	if err != nil {
+		file.Close()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return file, io.NewSectionReader(file, offset, length), true
+}
+
+// serveRange answers with the bytes of a file, or the range of them given by the offset
+// and length query parameters. Range headers are honored too.
+func (s *fileServer) serveRange(w http.ResponseWriter, r *http.Request) {
+	file, ok := s.open(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	offset, length, err := queryRange(r, info.Size())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, "", info.ModTime(), io.NewSectionReader(file, offset, length))
+}
+
+// serveSearch answers with the offsets at which the pattern query parameter occurs in a
+// file: hex bytes, or text if the text parameter is given, in the encoding parameter
+func (s *fileServer) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kind, encoding := searchKindHex, query.Get("encoding")
+	if query.Has("text") {
+		kind = searchKindText
+	}
+	if encoding == "" {
+		encoding = "ISO Latin-1"
+	} else if !slices.Contains(encodingNames, encoding) {
+		http.Error(w, "unknown encoding: "+encoding, http.StatusBadRequest)
+		return
+	}
+	pattern, err := parseSearchPattern(kind, query.Get("pattern"), encoding)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := maxHitsPerFile
+	if text := query.Get("max"); text != "" {
+		if limit, err = strconv.Atoi(text); err != nil || limit < 1 || limit > maxServeSearchHits {
+			http.Error(w, fmt.Sprintf("invalid max %q; it must be from 1 to %d", text, maxServeSearchHits),
+				http.StatusBadRequest)
+			return
+		}
+	}
+	file, section, ok := s.openQueryRange(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	offsets, err := pattern.findInReader(section, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, offset, _ := section.Outer()
+	records := []jsonRecord{}
+	for _, found := range offsets {
+		at := int(offset) + found
+		records = append(records, jsonRecord{Type: "match", File: r.PathValue("name"), Offset: &at,
+			Length: len(pattern.data)})
+	}
+	writeJSONResponse(w, records)
+}
+
+// serveHashes answers with the digests of a file, or a range of it, with each algorithm
+// of the Checksums panel
+func (s *fileServer) serveHashes(w http.ResponseWriter, r *http.Request) {
+	file, section, ok := s.openQueryRange(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	digests := make([]hash.Hash, len(checksumAlgorithms))
+	writers := make([]io.Writer, len(checksumAlgorithms))
+	for index, algorithm := range checksumAlgorithms {
+		digests[index] = algorithm.new()
+		writers[index] = digests[index]
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), section); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	records := []jsonRecord{}
+	for index, algorithm := range checksumAlgorithms {
+		records = append(records, jsonRecord{Type: "hash", File: r.PathValue("name"), Algorithm: algorithm.name,
+			Digest: hex.EncodeToString(digests[index].Sum(nil))})
+	}
+	writeJSONResponse(w, records)
+}
+
+// serveStrings answers with the strings in a file, or a range of it, of at least the
+// min query parameter characters. Ranges over maxServeStringsSize are refused.
+func (s *fileServer) serveStrings(w http.ResponseWriter, r *http.Request) {
+	minLength := 4
+	if text := r.URL.Query().Get("min"); text != "" {
+		var err error
+		if minLength, err = strconv.Atoi(text); err != nil || minLength < 1 {
+			http.Error(w, "invalid min "+strconv.Quote(text), http.StatusBadRequest)
+			return
+		}
+	}
+	file, section, ok := s.openQueryRange(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	if section.Size() > maxServeStringsSize {
+		http.Error(w, fmt.Sprintf("the range is larger than %d bytes; use offset and length", maxServeStringsSize),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	data := make([]byte, section.Size())
+	if _, err := io.ReadFull(section, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, offset, _ := section.Outer()
+	records := []jsonRecord{}
+	for _, found := range extractStrings(data, minLength, maxStringsFound) {
+		encoding := "ascii"
+		if found.wide {
+			encoding = "utf-16le"
+		}
+		at := int(offset) + found.offset
+		records = append(records, jsonRecord{Type: "string", File: r.PathValue("name"), Offset: &at,
+			Length: found.length, Encoding: encoding, Text: found.text})
+	}
+	writeJSONResponse(w, records)
+}
+
+// writeJSONResponse answers with value as JSON
+func writeJSONResponse(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		slog.Warn("writing response", "error", err)
+	}
+}
+
+// isLoopbackAddress reports whether a listen address such as "127.0.0.1:8765" or
+// "[::1]:8765" only accepts connections from this machine
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// runServeCommand implements "hexdump serve", which serves files read-only over HTTP
+// until it is stopped, for File → Open Remote and for scripts on other machines
+func runServeCommand(args []string) int {
+	flags := newFlagSet("serve", "[options] FILE...")
+	address := flags.String("addr", defaultServeAddress, "`address` to listen on; 0.0.0.0:8765 for other machines")
+	token := flags.String("token", os.Getenv("HEXDUMP_SERVE_TOKEN"),
+		"bearer `token` requests must carry; defaults to $HEXDUMP_SERVE_TOKEN")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	server, err := newFileServer(flags.Args(), *token)
+	if err != nil {
+		return cliError(os.Stderr, "serve", err)
+	}
+	if *token == "" && !isLoopbackAddress(*address) {
+		fmt.Fprintf(os.Stderr, "hexdump serve: warning: anyone who can reach %s can read the files; consider -token\n",
+			*address)
+	}
+	fmt.Fprintf(os.Stderr, "hexdump serve: serving %d file(s) on http://%s\n", len(server.names), *address)
+	httpServer := &http.Server{
+		Addr:              *address,
+		Handler:           server.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      10 * time.Minute,
+	}
+	if err := httpServer.ListenAndServe(); err != nil {
+		return cliError(os.Stderr, "serve", err)
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsLoopbackAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"127.0.0.1:8765", true},
+		{"127.1.2.3:80", true},
+		{"localhost:8765", true},
+		{"[::1]:8765", true},
+		{"0.0.0.0:8765", false},
+		{"[::]:8765", false},
+		{":8765", false},
+		{"[2001:db8::1]:8765", false},
+		{"192.168.1.10:8765", false},
+		{"127.0.0.1", false},
+	}
+	for _, test := range tests {
+		if got := isLoopbackAddress(test.address); got != test.want {
+			t.Errorf("isLoopbackAddress(%q) = %v, want %v", test.address, got, test.want)
+		}
+	}
+}
+
+func TestFileServerToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte("served data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := newFileServer([]string{path}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		authorization string
+		want          int
+	}{
+		{"Bearer secret", http.StatusOK},
+		{"Bearer secre", http.StatusUnauthorized},
+		{"Bearer secrets", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/files/data.bin/hashes?offset=2", nil)
+		if test.authorization != "" {
+			request.Header.Set("Authorization", test.authorization)
+		}
+		response := httptest.NewRecorder()
+		server.handler().ServeHTTP(response, request)
+		if response.Code != test.want {
+			t.Errorf("%q: got status %d, want %d", test.authorization, response.Code, test.want)
+		}
+	}
+}
+
+func TestServeSearchMax(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte("abcabcabc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := newFileServer([]string{path}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		max  string
+		want int
+	}{
+		{"", http.StatusOK},
+		{"1", http.StatusOK},
+		{"100000", http.StatusOK},
+		{"100001", http.StatusBadRequest},
+		{"0", http.StatusBadRequest},
+		{"many", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/files/data.bin/search?pattern=61&max="+test.max, nil)
+		response := httptest.NewRecorder()
+		server.handler().ServeHTTP(response, request)
+		if response.Code != test.want {
+			t.Errorf("max %q: got status %d, want %d", test.max, response.Code, test.want)
+		}
+	}
+}
+
+func TestRemoteDownload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte("served data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := newFileServer([]string{path}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server.handler())
+	defer httpServer.Close()
+	client, err := newRemoteClient(httpServer.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A listed size far larger than the file is not allocated up front
+	file := remoteFile{Name: "data.bin", Size: 1 << 60}
+	data, err := client.download(file, func(done, total int) bool { return true })
+	if err != nil || string(data) != "served data" {
+		t.Errorf("got %q, %v; want \"served data\"", data, err)
+	}
+	if name := client.remoteName(file); !isRemoteName(name) || name != "remote://"+httpServer.Listener.Addr().String()+"/data.bin" {
+		t.Errorf("remoteName = %q", name)
+	}
+}
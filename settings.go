@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Group separators of the hex pane
+const (
+	separatorSpace = "space"
+	separatorDash  = "dash"
+)
+
+// Kinds of checksum shown for each line
+const (
+	lineChecksumNone = ""
+	lineChecksumSum  = "sum"
+	lineChecksumXOR  = "xor"
+	lineChecksumCRC8 = "crc8"
+)
+
+// Palettes of highlight colors
+const (
+	paletteStandard     = ""
+	paletteColorBlind   = "colorBlind"
+	paletteHighContrast = "highContrast"
+)
+
+// settingsFileName is the name of the settings file in the settings directory
+const settingsFileName = "settings.json"
+
+// appSettings holds the user's preferences, shared by all windows. It is loaded once
+// at startup and saved whenever a preference changes.
+var appSettings = defaultSettings()
+
+// Settings holds the preferences persisted between sessions
+type Settings struct {
+	// Side panel layout
+	PanelVisible bool    `json:"panelVisible"`
+	PanelSplit   float64 `json:"panelSplit"`
+	PanelTab     string  `json:"panelTab"`
+
+	// Names of the side panels shown in their own windows
+	DetachedPanels []string `json:"detachedPanels"`
+
+	// Hex pane layout: the separator between groups of bytes (separatorSpace or
+	// separatorDash), and whether an extra space follows the first 8 bytes of a line
+	GroupSeparator string `json:"groupSeparator"`
+	MidLineGap     bool   `json:"midLineGap"`
+
+	// Whether hex digits are shown in lowercase, as by xxd
+	LowercaseHex bool `json:"lowercaseHex"`
+
+	// Hex digits of the address column, one of addressDigitChoices, or 0 to fit the file
+	AddressDigits int `json:"addressDigits"`
+
+	// Checksum shown after each line, one of the lineChecksum kinds
+	LineChecksum string `json:"lineChecksum"`
+
+	// Palette of highlight colors, one of the palette constants
+	Palette string `json:"palette"`
+
+	// Font size of the data list, or 0 for the default
+	DataFontSize float32 `json:"dataFontSize"`
+
+	// Spacing between the lines of the data list, one of the density constants
+	Density string `json:"density"`
+
+	// Character shown for non-printable characters, one of the fallback constants, and
+	// the ones of the encodings that have their own, by encoding name
+	Fallback          string            `json:"fallback"`
+	EncodingFallbacks map[string]string `json:"encodingFallbacks"`
+
+	// Whether short jumps of the data list are animated
+	SmoothScrolling bool `json:"smoothScrolling"`
+
+	// Whether hovering over a byte shows how it decodes in every encoding
+	EncodingTooltips bool `json:"encodingTooltips"`
+
+	// Whether the data list shows each line as a focusable label that spells it out,
+	// for screen readers and keyboard-only use
+	AccessibleMode bool `json:"accessibleMode"`
+
+	// Watch expressions shown in the Watches panel, such as "u32le@caret+8"
+	Watches []string `json:"watches"`
+
+	// Fields extracted by the Bit Stream panel, such as "sync:11 version:2 layer:2"
+	BitFields string `json:"bitFields"`
+
+	// Hash set file last used by Look Up Hash
+	HashSetPath string `json:"hashSetPath"`
+
+	// IDs of the toolbar items in display order, or nil for the default toolbar
+	Toolbar []string `json:"toolbar"`
+
+	// Keyboard shortcuts that differ from the standard ones, by command ID, such as
+	// "Ctrl+Shift+F"; an empty shortcut removes the standard one
+	Shortcuts map[string]string `json:"shortcuts"`
+
+	// Recently opened files, most recent first
+	RecentFiles []string `json:"recentFiles"`
+
+	// Whether the welcome screen shows while no file is open
+	WelcomeScreen bool `json:"welcomeScreen"`
+
+	// Whether GitHub is asked for a newer release at startup, at most once a day, and
+	// when it last was
+	CheckForUpdates bool      `json:"checkForUpdates"`
+	LastUpdateCheck time.Time `json:"lastUpdateCheck"`
+
+	// The last server File → Open Remote connected to
+	RemoteAddress string `json:"remoteAddress"`
+}
+
+// defaultSettings returns the preferences used when no settings file exists
+func defaultSettings() *Settings {
+	return &Settings{
+		PanelVisible: false,
+		PanelSplit:   0.68,
+		PanelTab:     "Inspector",
+
+		GroupSeparator:   separatorSpace,
+		EncodingTooltips: true,
+		WelcomeScreen:    true,
+	}
+}
+
+// portableDirName is the directory beside the executable that, if it exists, holds the
+// settings instead of the user's configuration directory
+const portableDirName = "hexdump-data"
+
+// portableDir returns the directory holding the settings in portable mode, or "" if the
+// executable cannot be located
+func portableDir() string {
+	executable, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(executable), portableDirName)
+}
+
+// makePortable creates the portable settings directory, so that the settings are kept
+// beside the executable from now on, starting from the current settings
+func makePortable() error {
+	dir := portableDir()
+	if dir == "" {
+		return fmt.Errorf("cannot locate the executable for portable mode")
+	}
+	current, err := settingsDir()
+	if err != nil || current == dir {
+		return os.MkdirAll(dir, 0755)
+	}
+	loadSettings()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return saveSettings()
+}
+
+// settingsDir returns the directory holding the settings file, logs, crash reports and
+// imported templates: hexdump-data beside the executable if it exists, which makes the
+// application portable, or else hexdump in the user's configuration directory
+func settingsDir() (string, error) {
+	if dir := portableDir(); dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "hexdump"), nil
+}
+
+// loadSettings reads the settings file into appSettings. A missing file leaves the
+// defaults in place; a damaged one is reported and ignored.
+func loadSettings() {
+	dir, err := settingsDir()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, settingsFileName))
+	if err != nil {
+		return
+	}
+
+	loaded := defaultSettings()
+	if err := json.Unmarshal(data, loaded); err != nil {
+		slog.Warn("ignoring damaged settings file", "error", err)
+		return
+	}
+	appSettings = loaded
+}
+
+// saveSettings writes appSettings to the settings file
+func saveSettings() error {
+	dir, err := settingsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(appSettings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, settingsFileName), data, 0644)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// keyCapture shows the shortcut of a command in the shortcut editor. Clicked, it takes
+// the next key combination pressed as the new shortcut; Backspace or Delete removes it.
+type keyCapture struct {
+	widget.BaseWidget
+	shortcut   string
+	modifier   fyne.KeyModifier
+	focused    bool
+	onCapture  func(shortcut string)
+	label      *widget.Label
+	background *canvas.Rectangle
+}
+
+// newKeyCapture creates a key capture widget
+func newKeyCapture() *keyCapture {
+	k := &keyCapture{label: widget.NewLabel(""), background: canvas.NewRectangle(color.Transparent)}
+	k.label.TextStyle.Monospace = true
+	k.ExtendBaseWidget(k)
+	return k
+}
+
+// setShortcut changes the shortcut shown
+func (k *keyCapture) setShortcut(shortcut string) {
+	k.shortcut = shortcut
+	k.update()
+}
+
+// update shows the shortcut, or a prompt while the widget waits for keys
+func (k *keyCapture) update() {
+	switch {
+	case k.focused:
+		k.label.SetText(lang.L("Press keys..."))
+		k.background.FillColor = theme.Color(theme.ColorNameFocus)
+	case k.shortcut == "":
+		k.label.SetText(lang.L("None"))
+		k.background.FillColor = theme.Color(theme.ColorNameInputBackground)
+	default:
+		k.label.SetText(k.shortcut)
+		k.background.FillColor = theme.Color(theme.ColorNameInputBackground)
+	}
+	k.background.Refresh()
+}
+
+// CreateRenderer implements fyne.Widget
+func (k *keyCapture) CreateRenderer() fyne.WidgetRenderer {
+	k.update()
+	return widget.NewSimpleRenderer(container.NewStack(k.background, k.label))
+}
+
+// MinSize implements fyne.Widget, leaving room for the longest shortcuts
+func (k *keyCapture) MinSize() fyne.Size {
+	return fyne.NewSize(180, k.label.MinSize().Height)
+}
+
+// Tapped focuses the widget to wait for keys
+func (k *keyCapture) Tapped(*fyne.PointEvent) {
+	if c := fyne.CurrentApp().Driver().CanvasForObject(k); c != nil {
+		c.Focus(k)
+	}
+}
+
+// FocusGained implements fyne.Focusable
+func (k *keyCapture) FocusGained() {
+	k.focused = true
+	k.modifier = 0
+	k.update()
+}
+
+// FocusLost implements fyne.Focusable
+func (k *keyCapture) FocusLost() {
+	k.focused = false
+	k.update()
+}
+
+// TypedRune implements fyne.Focusable
+func (k *keyCapture) TypedRune(rune) {}
+
+// TypedKey implements fyne.Focusable; keys are taken in KeyDown, which sees them first
+func (k *keyCapture) TypedKey(*fyne.KeyEvent) {}
+
+// captureModifiers maps the modifier keys to the modifiers they hold down
+var captureModifiers = map[fyne.KeyName]fyne.KeyModifier{
+	desktop.KeyControlLeft: fyne.KeyModifierControl, desktop.KeyControlRight: fyne.KeyModifierControl,
+	desktop.KeyAltLeft: fyne.KeyModifierAlt, desktop.KeyAltRight: fyne.KeyModifierAlt,
+	desktop.KeyShiftLeft: fyne.KeyModifierShift, desktop.KeyShiftRight: fyne.KeyModifierShift,
+	desktop.KeySuperLeft: fyne.KeyModifierSuper, desktop.KeySuperRight: fyne.KeyModifierSuper,
+}
+
+// KeyDown notes the modifiers held down and takes the first other key as the shortcut.
+// Tab is left to move the focus, and Escape leaves the shortcut as it is.
+func (k *keyCapture) KeyDown(event *fyne.KeyEvent) {
+	if modifier, ok := captureModifiers[event.Name]; ok {
+		k.modifier |= modifier
+		return
+	}
+	shortcut := formatShortcut(k.modifier, event.Name)
+	switch {
+	case event.Name == fyne.KeyTab:
+		return
+	case event.Name == fyne.KeyEscape:
+		shortcut = k.shortcut
+	case k.modifier == 0 && (event.Name == fyne.KeyBackspace || event.Name == fyne.KeyDelete):
+		shortcut = ""
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(k); c != nil {
+		c.Unfocus()
+	}
+	if shortcut != k.shortcut && k.onCapture != nil {
+		k.onCapture(shortcut)
+	}
+}
+
+// KeyUp notes the modifiers released
+func (k *keyCapture) KeyUp(event *fyne.KeyEvent) {
+	k.modifier &^= captureModifiers[event.Name]
+}
+
+// showShortcutEditor opens the dialog listing every command with its keyboard shortcut.
+// A key set can be chosen to start from, such as that of another hex editor. Giving a
+// command a shortcut another command has asks whether to move it. The window's shortcuts
+// are off while the dialog is open, so that they can be pressed to assign them.
+func (h *HexDumpApp) showShortcutEditor() {
+	shortcuts := currentShortcuts()
+	h.clearShortcuts()
+
+	visible := commands
+	var list *widget.List
+	assign := func(id, shortcut string) {
+		if shortcut != "" {
+			if _, err := parseShortcut(shortcut); err != nil {
+				dialog.ShowError(err, h.window)
+				return
+			}
+		}
+		for _, other := range commands {
+			if other.id == id || shortcut == "" || shortcuts[other.id] != shortcut {
+				continue
+			}
+			message := lang.L("{{.Shortcut}} is the shortcut of {{.Command}}. Move it to {{.NewCommand}}?",
+				map[string]any{"Shortcut": shortcut, "Command": other.name(), "NewCommand": commandByID(id).name()})
+			dialog.ShowConfirm(lang.L("Shortcut In Use"), message, func(ok bool) {
+				if ok {
+					shortcuts[other.id] = ""
+					shortcuts[id] = shortcut
+					list.Refresh()
+				}
+			}, h.window)
+			return
+		}
+		shortcuts[id] = shortcut
+		list.Refresh()
+	}
+
+	list = widget.NewList(
+		func() int { return len(visible) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, newKeyCapture(), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			c := visible[id]
+			row := item.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(lang.L(c.menu) + " → " + lang.L(c.label))
+			capture := row.Objects[1].(*keyCapture)
+			capture.setShortcut(shortcuts[c.id])
+			capture.onCapture = func(shortcut string) { assign(c.id, shortcut) }
+		},
+	)
+
+	filter := widget.NewEntry()
+	filter.SetPlaceHolder(lang.L("Filter by name"))
+	filter.OnChanged = func(text string) {
+		visible = nil
+		for _, c := range commands {
+			name := lang.L(c.menu) + " " + lang.L(c.label)
+			if strings.Contains(strings.ToLower(name), strings.ToLower(text)) {
+				visible = append(visible, c)
+			}
+		}
+		list.Refresh()
+	}
+	keySetSelect := widget.NewSelect(keySetNames, func(name string) {
+		shortcuts = keySetShortcuts(name)
+		list.Refresh()
+	})
+	keySetSelect.PlaceHolder = lang.L("Start from a key set")
+
+	top := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(lang.L("Key set")), nil, keySetSelect),
+		filter,
+	)
+	hint := widget.NewLabel(lang.L("Click a shortcut and press the new keys. Backspace removes it."))
+	content := container.NewBorder(top, hint, nil, nil, list)
+	editor := dialog.NewCustomConfirm(lang.L("Keyboard Shortcuts"), lang.L("Save"), lang.L("Cancel"), content,
+		func(ok bool) {
+			if ok {
+				appSettings.Shortcuts = shortcutChanges(shortcuts)
+				saveSettings()
+			}
+			h.createMenu()
+		}, h.window)
+	editor.Resize(fyne.NewSize(600, 520))
+	editor.Show()
+}
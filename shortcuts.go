@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+)
+
+// command is a menu command that can be given a keyboard shortcut
+type command struct {
+	id    string // Identifies the command in the settings
+	menu  string // Menu holding the command, shown in the shortcut editor
+	label string // Text of the menu item
+	run   func(h *HexDumpApp)
+}
+
+// name returns the translated label of the command without its trailing ellipsis
+func (c *command) name() string {
+	return strings.TrimSuffix(lang.L(c.label), "...")
+}
+
+// showPanelCommand returns a command of the View menu showing the named side panel
+func showPanelCommand(id, name string) command {
+	return command{id, "View", name, func(h *HexDumpApp) { h.showPanel(name) }}
+}
+
+// commands lists every menu command in menu order. It is filled in by init, since some
+// commands open windows whose menus are made from it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"open", "File", "Open file...", (*HexDumpApp).openFile},
+		{"openClipboard", "File", "Open Clipboard Data", (*HexDumpApp).openClipboardData},
+		{"openSample", "File", "Open Sample File", (*HexDumpApp).openSampleFile},
+		{"openRemote", "File", "Open Remote...", (*HexDumpApp).openRemote},
+		{"openEncrypted", "File", "Open Encrypted...", (*HexDumpApp).openEncryptedFile},
+		{"save", "File", "Save", (*HexDumpApp).saveFile},
+		{"saveAs", "File", "Save As...", (*HexDumpApp).saveFileAs},
+		{"reload", "File", "Reload", (*HexDumpApp).reloadFile},
+		{"saveSelection", "File", "Save Selection...", (*HexDumpApp).saveSelection},
+		{"exportCSV", "Export", "Selection as CSV...", (*HexDumpApp).exportSelectionCSV},
+		{"exportText", "Export", "Decoded Text...", (*HexDumpApp).exportDecodedText},
+		{"exportPatch", "Export", "Changes as Patch List...", (*HexDumpApp).exportPatch},
+		{"exportViewPNG", "Export", "View as PNG...", (*HexDumpApp).saveViewPNG},
+		{"extractOverlay", "Export", "Overlay...", (*HexDumpApp).extractOverlay},
+		{"quit", "File", "Quit", func(h *HexDumpApp) { h.confirmDiscardEdits(h.app.Quit) }},
+
+		{"undo", "Edit", "Undo", (*HexDumpApp).undo},
+		{"redo", "Edit", "Redo", (*HexDumpApp).redo},
+		{"addCheckpoint", "Edit", "Add Checkpoint...", (*HexDumpApp).showAddCheckpoint},
+		{"revertCheckpoint", "Edit", "Revert to Checkpoint...", (*HexDumpApp).showRevertToCheckpoint},
+		{"fill", "Edit", "Fill...", (*HexDumpApp).showFillSelection},
+		{"xor", "Edit", "XOR...", (*HexDumpApp).showXORSelection},
+		{"applyPatch", "Edit", "Apply Patch List...", (*HexDumpApp).applyPatch},
+		{"copyViewImage", "Edit", "Copy View as Image", (*HexDumpApp).copyViewImage},
+		{"selectAll", "Edit", "Select All", (*HexDumpApp).selectAll},
+		{"selectLineEnd", "Edit", "Select to End of Line", (*HexDumpApp).selectToLineEnd},
+		{"selectFileEnd", "Edit", "Select to End of File", (*HexDumpApp).selectToFileEnd},
+		{"selectBlock", "Edit", "Select Block...", (*HexDumpApp).showSelectBlock},
+		{"selectOverlay", "Edit", "Select Overlay", (*HexDumpApp).selectOverlay},
+		{"goTo", "Edit", "Go To...", (*HexDumpApp).showGoTo},
+
+		{"sidePanel", "View", "Side Panel", (*HexDumpApp).togglePanels},
+		{"detachPanel", "View", "Detach Current Panel", (*HexDumpApp).detachSelectedPanel},
+		{"dockPanels", "View", "Dock All Panels", (*HexDumpApp).dockAllPanels},
+		{"signedValues", "View", "Signed Values", (*HexDumpApp).toggleSignedValues},
+		{"recordMode", "View", "Record Mode...", (*HexDumpApp).showRecordMode},
+		{"signatures", "View", "Highlight Signatures", (*HexDumpApp).toggleSignatures},
+		{"collapsePadding", "View", "Collapse Padding", (*HexDumpApp).toggleCollapsePadding},
+		{"filterLines", "View", "Filter Lines...", (*HexDumpApp).showFilterLines},
+		{"showAllLines", "View", "Show All Lines", (*HexDumpApp).clearFilter},
+		{"sqliteOverlay", "View", "SQLite Page Overlay", (*HexDumpApp).toggleSQLiteOverlay},
+		{"encodingTooltips", "View", "Encoding Tooltips", func(h *HexDumpApp) {
+			appSettings.EncodingTooltips = !appSettings.EncodingTooltips
+			saveSettings()
+		}},
+		{"customizeToolbar", "View", "Customize Toolbar...", (*HexDumpApp).showCustomizeToolbar},
+		{"accessibleMode", "View", "Accessible Mode", (*HexDumpApp).toggleAccessibleMode},
+		{"nextPane", "View", "Next Pane", (*HexDumpApp).focusNextPane},
+		{"centerCaret", "View", "Center Caret", (*HexDumpApp).centerCaret},
+		showPanelCommand("inspectorPanel", panelInspector),
+		showPanelCommand("stringsPanel", panelStrings),
+		showPanelCommand("bookmarksPanel", panelBookmarks),
+		showPanelCommand("searchPanel", panelSearch),
+		showPanelCommand("structurePanel", panelStructure),
+		showPanelCommand("checksumsPanel", panelChecksums),
+		showPanelCommand("textPanel", panelText),
+		showPanelCommand("watchesPanel", panelWatches),
+		showPanelCommand("graphPanel", panelGraph),
+		showPanelCommand("fieldsPanel", panelFields),
+		showPanelCommand("diskPanel", panelDisk),
+		showPanelCommand("bitsPanel", panelBits),
+		showPanelCommand("historyPanel", panelHistory),
+
+		{"addBookmark", "Bookmarks", "Add Bookmark...", (*HexDumpApp).showAddBookmark},
+		{"showBookmarks", "Bookmarks", "Show Bookmarks", (*HexDumpApp).showBookmarks},
+		{"nextBookmark", "Bookmarks", "Next Bookmark", (*HexDumpApp).goToNextBookmark},
+		{"previousBookmark", "Bookmarks", "Previous Bookmark", (*HexDumpApp).goToPreviousBookmark},
+		{"importBookmarks", "Bookmarks", "Import Bookmarks...", (*HexDumpApp).showImportBookmarks},
+		{"importOffsets", "Bookmarks", "Import Offsets from Clipboard", (*HexDumpApp).importOffsetsFromClipboard},
+		{"clearMarkers", "Bookmarks", "Clear Clipboard Markers", (*HexDumpApp).clearTemporaryBookmarks},
+
+		{"findInFiles", "Tools", "Find in Files...", (*HexDumpApp).showFindInFiles},
+		{"findDuplicates", "Tools", "Find Duplicate Regions...", (*HexDumpApp).showDuplicatesDialog},
+		{"hashSearch", "Tools", "Find Block by Hash...", (*HexDumpApp).showHashSearch},
+		{"detectPadding", "Tools", "Detect Padding...", (*HexDumpApp).showDetectPadding},
+		{"sectorMap", "Tools", "Flash Sector Map...", (*HexDumpApp).showSectorMap},
+		{"sqlitePages", "Tools", "SQLite Pages...", (*HexDumpApp).showSQLitePageList},
+		{"metadata", "Tools", "Media Metadata...", (*HexDumpApp).showMetadata},
+		{"endianness", "Tools", "Guess Endianness...", (*HexDumpApp).showEndiannessGuess},
+		{"pointerScan", "Tools", "Pointer Scan...", (*HexDumpApp).showPointerScan},
+		{"clearPointers", "Tools", "Clear Pointers", (*HexDumpApp).clearPointers},
+		{"tlvWalker", "Tools", "TLV Walker...", (*HexDumpApp).showTLVWalker},
+		{"clearTLV", "Tools", "Clear TLV Entries", (*HexDumpApp).clearTLV},
+		{"findReferences", "Tools", "Find References...", (*HexDumpApp).showFindReferences},
+		{"hashLookup", "Tools", "Look Up Hash...", (*HexDumpApp).showHashLookup},
+		{"yaraScan", "Tools", "YARA Scan...", (*HexDumpApp).scanYARAFile},
+		{"clearYARA", "Tools", "Clear YARA Matches", (*HexDumpApp).clearYARAMatches},
+		{"bytePairs", "Tools", "Byte Pair Statistics...", (*HexDumpApp).showNgramView},
+		{"visualization", "Tools", "Binary Visualization...", (*HexDumpApp).showVisualization},
+		{"audioPreview", "Tools", "Audio Preview...", (*HexDumpApp).showAudioPreview},
+		{"rawImage", "Tools", "View as Image...", (*HexDumpApp).showRawImage},
+		{"loadSymbols", "Tools", "Load Symbols...", (*HexDumpApp).showLoadSymbols},
+		{"symbolList", "Tools", "Symbol List", (*HexDumpApp).showSymbolList},
+		{"addressMap", "Tools", "Address Map...", (*HexDumpApp).showAddressMap},
+		{"templateManager", "Tools", "Template Manager...", (*HexDumpApp).showTemplateManager},
+		{"nextRecord", "Tools", "Next Record", (*HexDumpApp).nextRecord},
+		{"previousRecord", "Tools", "Previous Record", (*HexDumpApp).previousRecord},
+		{"goToRecord", "Tools", "Go to Record...", (*HexDumpApp).showGoToRecord},
+		{"extractColumn", "Tools", "Extract Column...", (*HexDumpApp).showExtractColumn},
+		{"takeSnapshot", "Tools", "Take Snapshot", (*HexDumpApp).takeSnapshot},
+		{"snapshotChanges", "Tools", "Changes Since Snapshot...", (*HexDumpApp).showSnapshotChanges},
+		{"clearSnapshot", "Tools", "Clear Snapshot", (*HexDumpApp).clearSnapshot},
+		{"maskSelection", "Tools", "Ignore Selection in Comparisons...", (*HexDumpApp).showMaskSelection},
+		{"maskRegions", "Tools", "Mask Regions...", (*HexDumpApp).showMaskRegions},
+		{"markRangeA", "Tools", "Mark Selection as Range A", (*HexDumpApp).markRangeA},
+		{"compareRanges", "Tools", "Compare Selection with Range A...", (*HexDumpApp).showCompareRanges},
+		{"comparePattern", "Tools", "Compare with Pattern...", (*HexDumpApp).showCompareWithPattern},
+		{"clearPatternComparison", "Tools", "Clear Pattern Comparison", (*HexDumpApp).clearPatternComparison},
+		{"monitor", "Tools", "Monitor File...", (*HexDumpApp).startMonitoring},
+		{"stopMonitoring", "Tools", "Stop Monitoring", (*HexDumpApp).stopMonitoring},
+		{"batchConvert", "Tools", "Batch Convert...", (*HexDumpApp).showBatchDialog},
+
+		{"preferences", "Options", "Preferences...", (*HexDumpApp).showPreferences},
+		{"showLog", "Options", "Log Viewer", (*HexDumpApp).showLogViewer},
+		{"checkUpdates", "Options", "Check for Updates...", (*HexDumpApp).showUpdateCheck},
+		{"about", "Options", "About", (*HexDumpApp).showAbout},
+	}
+}
+
+// commandByID returns the command with the given ID, or nil if there is none
+func commandByID(id string) *command {
+	for index := range commands {
+		if commands[index].id == id {
+			return &commands[index]
+		}
+	}
+	return nil
+}
+
+// Key sets: the standard shortcuts, and those of other hex editors for their users
+const (
+	keySetStandard = "Standard"
+	keySetHxD      = "HxD"
+	keySet010      = "010 Editor"
+	keySetOkteta   = "Okteta"
+)
+
+// keySetNames lists the key sets in the order the shortcut editor offers them
+var keySetNames = []string{keySetStandard, keySetHxD, keySet010, keySetOkteta}
+
+// standardShortcuts maps command IDs to their standard shortcuts. "Ctrl" is Cmd on macOS.
+var standardShortcuts = map[string]string{
+	"save":             "Ctrl+S",
+	"undo":             "Ctrl+Z",
+	"redo":             "Ctrl+Y",
+	"selectAll":        "Ctrl+A",
+	"selectLineEnd":    "Shift+End",
+	"selectFileEnd":    "Ctrl+Shift+End",
+	"selectBlock":      "Ctrl+E",
+	"goTo":             "Ctrl+G",
+	"nextPane":         "Ctrl+F6",
+	"centerCaret":      "Ctrl+L",
+	"filterLines":      "Ctrl+Shift+L",
+	"nextBookmark":     "F2",
+	"previousBookmark": "Shift+F2",
+}
+
+// keySetChanges maps each key set other than the standard one to the shortcuts in which
+// it differs from it, where "" removes a shortcut
+var keySetChanges = map[string]map[string]string{
+	keySetHxD: {
+		"open":        "Ctrl+O",
+		"searchPanel": "Ctrl+F",
+	},
+	keySet010: {
+		"open":        "Ctrl+O",
+		"searchPanel": "Ctrl+F",
+		"findInFiles": "Ctrl+Shift+F",
+		"selectBlock": "Ctrl+Shift+A",
+		"addBookmark": "Ctrl+F2",
+	},
+	keySetOkteta: {
+		"open":        "Ctrl+O",
+		"quit":        "Ctrl+Q",
+		"redo":        "Ctrl+Shift+Z",
+		"searchPanel": "Ctrl+F",
+		"addBookmark": "Ctrl+B",
+		"nextPane":    "",
+	},
+}
+
+// keySetShortcuts returns the shortcuts of the named key set
+func keySetShortcuts(name string) map[string]string {
+	shortcuts := make(map[string]string)
+	for id, key := range standardShortcuts {
+		shortcuts[id] = key
+	}
+	for id, key := range keySetChanges[name] {
+		shortcuts[id] = key
+	}
+	return shortcuts
+}
+
+// currentShortcuts returns the shortcuts in effect: the standard ones with the changes
+// in the settings. Of several commands given the same key, only the first in menu order
+// keeps it.
+func currentShortcuts() map[string]string {
+	shortcuts := keySetShortcuts(keySetStandard)
+	for id, key := range appSettings.Shortcuts {
+		shortcuts[id] = key
+	}
+	owners := make(map[string]string)
+	for _, c := range commands {
+		key := shortcuts[c.id]
+		if key == "" {
+			continue
+		}
+		if _, err := parseShortcut(key); err != nil {
+			slog.Debug("ignoring shortcut", "command", c.id, "error", err)
+			delete(shortcuts, c.id)
+		} else if owner, taken := owners[key]; taken {
+			slog.Debug("ignoring shortcut another command already has", "shortcut", key, "command", c.id,
+				"owner", owner)
+			delete(shortcuts, c.id)
+		} else {
+			owners[key] = c.id
+		}
+	}
+	return shortcuts
+}
+
+// shortcutChanges returns how shortcuts differ from the standard ones, for the settings
+func shortcutChanges(shortcuts map[string]string) map[string]string {
+	changes := make(map[string]string)
+	for _, c := range commands {
+		if shortcuts[c.id] != standardShortcuts[c.id] {
+			changes[c.id] = shortcuts[c.id]
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// modifierName is the name of a modifier key in shortcuts
+type modifierName struct {
+	modifier fyne.KeyModifier
+	name     string
+}
+
+// modifierNames names the modifiers of a shortcut in the order they are written. Super is
+// the Windows key on Windows and Linux and Control on macOS, where Ctrl is Cmd.
+var modifierNames = []modifierName{
+	{fyne.KeyModifierShortcutDefault, "Ctrl"},
+	{fyne.KeyModifierAlt, "Alt"},
+	{fyne.KeyModifierShift, "Shift"},
+	{(fyne.KeyModifierControl | fyne.KeyModifierSuper) &^ fyne.KeyModifierShortcutDefault, "Super"},
+}
+
+// formatShortcut writes a key and its modifiers as text, such as "Ctrl+Shift+End"
+func formatShortcut(modifier fyne.KeyModifier, key fyne.KeyName) string {
+	var parts []string
+	for _, m := range modifierNames {
+		if modifier&m.modifier != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, string(key)), "+")
+}
+
+// parseShortcut parses a shortcut written by formatShortcut. The driver reports some key
+// combinations as its standard shortcuts, such as Ctrl+Z as undo, so these are returned
+// as the standard shortcuts to match. Combinations the driver reports neither as a
+// shortcut nor as a key, and those text fields use for the clipboard, are rejected.
+func parseShortcut(text string) (fyne.Shortcut, error) {
+	parts := strings.Split(text, "+")
+	key := fyne.KeyName(parts[len(parts)-1])
+	if key == "" && len(parts) > 1 {
+		key, parts = "+", parts[:len(parts)-1] // The plus key itself
+	}
+	var modifier fyne.KeyModifier
+	for _, part := range parts[:len(parts)-1] {
+		index := slices.IndexFunc(modifierNames, func(m modifierName) bool { return strings.EqualFold(m.name, part) })
+		if index < 0 {
+			return nil, fmt.Errorf("unknown modifier %q in %q", part, text)
+		}
+		modifier |= modifierNames[index].modifier
+	}
+	if key == "" {
+		return nil, fmt.Errorf("no key in %q", text)
+	}
+
+	functionKey := len(key) > 1 && key[0] == 'F' && key[1] >= '1' && key[1] <= '9'
+	switch {
+	case modifier == fyne.KeyModifierShortcutDefault && slices.Contains([]fyne.KeyName{fyne.KeyC, fyne.KeyV,
+		fyne.KeyX, fyne.KeyInsert}, key),
+		modifier == fyne.KeyModifierShift && (key == fyne.KeyInsert || key == fyne.KeyDelete):
+		return nil, fmt.Errorf("%s is kept for copying and pasting in text fields", text)
+	case modifier == fyne.KeyModifierShortcutDefault && key == fyne.KeyZ:
+		return &fyne.ShortcutUndo{}, nil
+	case modifier == fyne.KeyModifierShortcutDefault && key == fyne.KeyY:
+		return &fyne.ShortcutRedo{}, nil
+	case modifier == fyne.KeyModifierShortcutDefault && key == fyne.KeyA:
+		return &fyne.ShortcutSelectAll{}, nil
+	case modifier&^fyne.KeyModifierShift == 0 && !functionKey && (modifier == 0 || len(key) == 1):
+		// Plain keys and Shift with a character are typed, not shortcuts
+		return nil, fmt.Errorf("%s needs Ctrl, Alt, or Super, unless it is a function key", text)
+	}
+	return &desktop.CustomShortcut{KeyName: key, Modifier: modifier}, nil
+}
+
+// commandItem creates the menu item of the command with the given ID, with its shortcut
+func (h *HexDumpApp) commandItem(id string) *fyne.MenuItem {
+	c := commandByID(id)
+	item := fyne.NewMenuItem(lang.L(c.label), h.guard(func() { c.run(h) }))
+	if key := h.shortcuts[id]; key != "" {
+		item.Shortcut, _ = parseShortcut(key)
+	}
+	return item
+}
+
+// registerShortcuts makes the shortcuts of the menu items work in the window: on the
+// canvas for those the driver reports as shortcuts, and through onKeyDown for function
+// keys and Shift combinations, which it reports as keys
+func (h *HexDumpApp) registerShortcuts(menu *fyne.MainMenu) {
+	c := h.window.Canvas()
+	for _, shortcut := range h.registered {
+		c.RemoveShortcut(shortcut)
+	}
+	h.registered = nil
+	h.keyCommands = make(map[string]func())
+
+	var register func(items []*fyne.MenuItem)
+	register = func(items []*fyne.MenuItem) {
+		for _, item := range items {
+			if item.ChildMenu != nil {
+				register(item.ChildMenu.Items)
+			}
+			if item.Shortcut == nil {
+				continue
+			}
+			action := item.Action
+			c.AddShortcut(item.Shortcut, func(fyne.Shortcut) { action() })
+			h.registered = append(h.registered, item.Shortcut)
+			if custom, ok := item.Shortcut.(*desktop.CustomShortcut); ok && custom.Modifier&^fyne.KeyModifierShift == 0 {
+				h.keyCommands[formatShortcut(custom.Modifier, custom.KeyName)] = action
+			}
+		}
+	}
+	for _, menu := range menu.Items {
+		register(menu.Items)
+	}
+}
+
+// clearShortcuts turns off the shortcuts of the window, so that keys can be pressed to
+// assign them without running commands. createMenu turns them on again.
+func (h *HexDumpApp) clearShortcuts() {
+	var clear func(items []*fyne.MenuItem)
+	clear = func(items []*fyne.MenuItem) {
+		for _, item := range items {
+			item.Shortcut = nil
+			if item.ChildMenu != nil {
+				clear(item.ChildMenu.Items)
+			}
+		}
+	}
+	for _, menu := range h.window.MainMenu().Items {
+		clear(menu.Items)
+	}
+	h.registerShortcuts(h.window.MainMenu())
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fyne.io/fyne/v2/theme"
+	"sort"
+)
+
+// Limits of the signature matches
+const (
+	maxSignatureMatches = 10000 // Largest number of matches highlighted for each signature
+	maxSignatureLength  = 16    // Length of the longest signature in fileSignatures
+)
+
+// fileSignature is the magic bytes of a file format, as a hex pattern
+type fileSignature struct {
+	name    string
+	pattern string
+}
+
+// fileSignatures lists the magic bytes recognized by Highlight Signatures
+var fileSignatures = []fileSignature{
+	{"ZIP archive", "50 4B 03 04"},
+	{"ZIP central directory", "50 4B 01 02"},
+	{"ZIP end of central directory", "50 4B 05 06"},
+	{"DOS/Windows executable (MZ)", "4D 5A ?? 00"},
+	{"PE header", "50 45 00 00 4C 01"},
+	{"PE header (x64)", "50 45 00 00 64 86"},
+	{"ELF executable", "7F 45 4C 46"},
+	{"Mach-O executable", "CF FA ED FE"},
+	{"Mach-O executable (32-bit)", "CE FA ED FE"},
+	{"Java class file", "CA FE BA BE"},
+	{"PNG image", "89 50 4E 47 0D 0A 1A 0A"},
+	{"JPEG image", "FF D8 FF"},
+	{"GIF image", "47 49 46 38 ?? 61"},
+	{"BMP image", "42 4D ?? ?? ?? ?? 00 00 00 00"},
+	{"RIFF container (WAV, AVI, WebP)", "52 49 46 46"},
+	{"Ogg stream", "4F 67 67 53"},
+	{"FLAC audio", "66 4C 61 43"},
+	{"MP3 with ID3 tag", "49 44 33 0? 00"},
+	{"PDF document", "25 50 44 46 2D"},
+	{"gzip stream", "1F 8B 08"},
+	{"bzip2 stream", "42 5A 68 3? 31 41 59 26 53 59"},
+	{"xz stream", "FD 37 7A 58 5A 00"},
+	{"Zstandard frame", "28 B5 2F FD"},
+	{"7-Zip archive", "37 7A BC AF 27 1C"},
+	{"RAR archive", "52 61 72 21 1A 07"},
+	{"SQLite database", "53 51 4C 69 74 65 20 66 6F 72 6D 61 74 20 33 00"},
+	{"OLE compound document (Office)", "D0 CF 11 E0 A1 B1 1A E1"},
+	{"Windows icon", "00 00 01 00 0? 00"},
+}
+
+// signatureMatch is an occurrence of a file signature
+type signatureMatch struct {
+	offset int
+	length int
+	name   string
+}
+
+// findSignatures returns the occurrences of the known file signatures in data, in
+// order of offset
+func findSignatures(data []byte) []signatureMatch {
+	var matches []signatureMatch
+	for _, signature := range fileSignatures {
+		pattern, err := parseHexPattern(signature.pattern)
+		if err != nil {
+			continue
+		}
+		for _, offset := range pattern.findAll(data, maxSignatureMatches) {
+			matches = append(matches, signatureMatch{offset: offset, length: len(pattern.data), name: signature.name})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].offset < matches[j].offset })
+	return matches
+}
+
+// toggleSignatures turns the highlighting of file signatures on or off
+func (h *HexDumpApp) toggleSignatures() {
+	h.showSignatures = !h.showSignatures
+	h.updateSignatures()
+	h.updateDisplay()
+}
+
+// updateSignatures finds the file signatures again after the data changed
+func (h *HexDumpApp) updateSignatures() {
+	h.signatures = nil
+	if h.showSignatures {
+		h.signatures = findSignatures(h.fileData)
+	}
+}
+
+// firstSignatureNear returns the index of the first signature match that may cover
+// offset or a later byte
+func (h *HexDumpApp) firstSignatureNear(offset int) int {
+	return sort.Search(len(h.signatures), func(i int) bool {
+		return h.signatures[i].offset+maxSignatureLength > offset
+	})
+}
+
+// signatureSpans returns highlight spans for the signatures intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) signatureSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	for _, match := range h.signatures[h.firstSignatureNear(lineStart):] {
+		if match.offset >= lineEnd {
+			break
+		}
+		start, end := max(match.offset, lineStart), min(match.offset+match.length, lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(colorNameSignature)})
+		}
+	}
+	return spans
+}
+
+// signatureAt returns the name of the format whose signature covers offset, or ""
+func (h *HexDumpApp) signatureAt(offset int) string {
+	for _, match := range h.signatures[h.firstSignatureNear(offset):] {
+		if match.offset > offset {
+			break
+		}
+		if offset < match.offset+match.length {
+			return match.name
+		}
+	}
+	return ""
+}
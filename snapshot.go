@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// byteRange is a range of file offsets [start, end)
+type byteRange struct {
+	start, end int
+}
+
+// diffRanges returns the ranges of current that differ from old, in order. Bytes past
+// the end of old count as changed.
+func diffRanges(old, current []byte) []byteRange {
+	var ranges []byteRange
+	start := -1
+	for offset := 0; offset <= len(current); offset++ {
+		changed := offset < len(current) && (offset >= len(old) || old[offset] != current[offset])
+		if changed && start < 0 {
+			start = offset
+		} else if !changed && start >= 0 {
+			ranges = append(ranges, byteRange{start, offset})
+			start = -1
+		}
+	}
+	return ranges
+}
+
+// takeSnapshot remembers the current file data, so that later changes to it, by edits
+// or by another program when the file is reloaded, can be shown
+func (h *HexDumpApp) takeSnapshot() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Snapshot"), lang.L("No file is loaded."), h.window)
+		return
+	}
+	snapshot, mapped, err := copyData(h.fileData)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("taking a snapshot: %w", err), h.window)
+		return
+	}
+	h.setSnapshot(snapshot, mapped)
+	h.snapshotChanged()
+}
+
+// clearSnapshot forgets the snapshot and its highlighted changes
+func (h *HexDumpApp) clearSnapshot() {
+	h.setSnapshot(nil, nil)
+	h.snapshotChanged()
+}
+
+// setSnapshot replaces the snapshot. The mapping of the one before is unmapped once no
+// background scan reads it any more.
+func (h *HexDumpApp) setSnapshot(snapshot []byte, mapped *mappedData) {
+	if h.snapshotMapped != nil {
+		h.retired = append(h.retired, h.snapshotMapped)
+	}
+	h.snapshot, h.snapshotMapped = snapshot, mapped
+	h.unmapRetired()
+}
+
+// snapshotChanged recomputes the changes since the snapshot and redraws them
+func (h *HexDumpApp) snapshotChanged() {
+	h.snapshotDiffs = nil
+	if h.snapshot != nil {
+		h.snapshotDiffs = h.compareWithSnapshot(h.fileData)
+	}
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// snapshotSpans returns highlight spans for the bytes changed since the snapshot
+// intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) snapshotSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	first := sort.Search(len(h.snapshotDiffs), func(i int) bool { return h.snapshotDiffs[i].end > lineStart })
+	for _, diff := range h.snapshotDiffs[first:] {
+		if diff.start >= lineEnd {
+			break
+		}
+		spans = append(spans, highlightSpan{start: max(diff.start, lineStart), end: min(diff.end, lineEnd),
+			color: theme.Color(colorNameSnapshot)})
+	}
+	return spans
+}
+
+// snapshotStatus describes the changes since the snapshot for the status bar
+func (h *HexDumpApp) snapshotStatus() string {
+	if h.snapshot == nil {
+		return ""
+	}
+	changed := 0
+	for _, diff := range h.snapshotDiffs {
+		changed += diff.end - diff.start
+	}
+	status := fmt.Sprintf("Changed since snapshot: %d bytes", changed)
+	if len(h.diffMasks) > 0 {
+		status += fmt.Sprintf(" (%d masked regions ignored)", len(h.diffMasks))
+	}
+	if len(h.fileData) < len(h.snapshot) {
+		status += fmt.Sprintf(", %d bytes shorter", len(h.snapshot)-len(h.fileData))
+	}
+	return status
+}
+
+// reloadFile reads the file again from disk, keeping the snapshot and the caret, to
+// pick up changes made by another program
+func (h *HexDumpApp) reloadFile() {
+	if h.fileName == "" {
+		return
+	}
+	if isRemoteName(h.fileName) {
+		dialog.ShowInformation(lang.L("Reload"), lang.L("A remote file is reloaded by opening it again with File → Open Remote..."), h.window)
+		return
+	}
+	h.confirmDiscardEdits(func() {
+		caret := h.caret
+		h.loadFileFromPath(h.fileName)
+		if caret < len(h.fileData) {
+			h.caret, h.selAnchor = caret, caret
+			h.goToOffset(caret)
+		}
+		h.snapshotChanged()
+	})
+}
+
+// showSnapshotChanges lists the ranges changed since the snapshot, with their old and
+// new bytes. Clicking a range selects it.
+func (h *HexDumpApp) showSnapshotChanges() {
+	if h.snapshot == nil {
+		dialog.ShowInformation(lang.L("Snapshot"), lang.L("Take a snapshot first (Tools → Take Snapshot)."), h.window)
+		return
+	}
+
+	diffs := h.snapshotDiffs
+	preview := func(data []byte, start, end int) string {
+		end = min(end, len(data), start+8)
+		if start >= end {
+			return "-"
+		}
+		return strings.ToUpper(hex.EncodeToString(data[start:end]))
+	}
+	list := widget.NewList(
+		func() int { return len(diffs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			diff := diffs[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %d bytes  %s -> %s", diff.start, diff.end-diff.start,
+				preview(h.snapshot, diff.start, diff.end), preview(h.fileData, diff.start, diff.end)))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		diff := diffs[id]
+		h.setSelection(diff.start, diff.end)
+		h.goToOffset(diff.start)
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("Changes Since Snapshot - %s", h.fileName))
+	window.SetContent(container.NewBorder(widget.NewLabel(h.snapshotStatus()), nil, nil, nil, list))
+	window.Resize(fyne.NewSize(500, 400))
+	window.Show()
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// sqliteMagic starts every SQLite 3 database file
+const sqliteMagic = "SQLite format 3\x00"
+
+// SQLite page kinds
+const (
+	sqliteTableInterior = "table interior"
+	sqliteTableLeaf     = "table leaf"
+	sqliteIndexInterior = "index interior"
+	sqliteIndexLeaf     = "index leaf"
+	sqliteFreeTrunk     = "freelist trunk"
+	sqliteFreeLeaf      = "freelist leaf"
+	sqliteOverflow      = "overflow"
+	sqliteUnknown       = "unknown"
+)
+
+// sqlitePageColors are the backgrounds of the page headers of each kind of page
+var sqlitePageColors = map[string]fyne.ThemeColorName{
+	sqliteTableInterior: colorNameSQLiteTableInterior,
+	sqliteTableLeaf:     colorNameSQLiteTableLeaf,
+	sqliteIndexInterior: colorNameSQLiteIndexInterior,
+	sqliteIndexLeaf:     colorNameSQLiteIndexLeaf,
+	sqliteFreeTrunk:     colorNameSQLiteFreeTrunk,
+	sqliteFreeLeaf:      colorNameSQLiteFreeLeaf,
+	sqliteOverflow:      colorNameSQLiteOverflow,
+}
+
+// sqlitePage is one page of a SQLite database. Pages are numbered from 1, and the
+// header of page 1 follows the 100-byte database header.
+type sqlitePage struct {
+	number     int
+	offset     int
+	kind       string
+	cells      int // Cells of a b-tree page, or leaf pages listed by a freelist trunk
+	headerSize int
+}
+
+// sqliteDatabase is the page structure of a SQLite database
+type sqliteDatabase struct {
+	pageSize   int
+	usableSize int // The page size less the bytes reserved at the end of each page
+	freePages  int
+	pages      []sqlitePage
+}
+
+// parseSQLite works out the kind of each page of a SQLite database from its b-tree page
+// header, the freelist, and the overflow chains of the b-tree cells. It returns nil if
+// data is not a SQLite database.
+func parseSQLite(data []byte) *sqliteDatabase {
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil
+	}
+	db := &sqliteDatabase{pageSize: pageSize, usableSize: pageSize - int(data[20]),
+		freePages: int(binary.BigEndian.Uint32(data[36:]))}
+
+	count := (len(data) + pageSize - 1) / pageSize
+	for number := 1; number <= count; number++ {
+		page := sqlitePage{number: number, offset: (number - 1) * pageSize, kind: sqliteUnknown}
+		header := page.offset
+		if number == 1 {
+			header = 100
+		}
+		if header+8 <= len(data) {
+			interior := false
+			switch data[header] {
+			case 0x02:
+				page.kind, interior = sqliteIndexInterior, true
+			case 0x05:
+				page.kind, interior = sqliteTableInterior, true
+			case 0x0A:
+				page.kind = sqliteIndexLeaf
+			case 0x0D:
+				page.kind = sqliteTableLeaf
+			}
+			if page.kind != sqliteUnknown {
+				page.cells = int(binary.BigEndian.Uint16(data[header+3:]))
+				page.headerSize = 8
+				if interior {
+					page.headerSize = 12
+				}
+			}
+		}
+		db.pages = append(db.pages, page)
+	}
+
+	// Pages on the freelist, and the overflow pages of cells too large for their page
+	db.markFreelist(data, int(binary.BigEndian.Uint32(data[32:])))
+	for index := range db.pages {
+		if page := db.pages[index]; page.headerSize > 0 {
+			for _, overflow := range db.overflowStarts(data, page) {
+				db.markOverflow(data, overflow)
+			}
+		}
+	}
+	return db
+}
+
+// page returns the page with the given number, or nil if there is none
+func (db *sqliteDatabase) page(number int) *sqlitePage {
+	if number < 1 || number > len(db.pages) {
+		return nil
+	}
+	return &db.pages[number-1]
+}
+
+// markFreelist follows the chain of freelist trunk pages from trunk, marking the trunk
+// pages and the leaf pages they list
+func (db *sqliteDatabase) markFreelist(data []byte, trunk int) {
+	for seen := 0; trunk != 0 && seen < len(db.pages); seen++ {
+		page := db.page(trunk)
+		if page == nil || page.offset+8 > len(data) {
+			return
+		}
+		leaves := int(binary.BigEndian.Uint32(data[page.offset+4:]))
+		page.kind, page.cells, page.headerSize = sqliteFreeTrunk, leaves, 8
+		for index := 0; index < leaves && page.offset+8+4*index+4 <= len(data); index++ {
+			if leaf := db.page(int(binary.BigEndian.Uint32(data[page.offset+8+4*index:]))); leaf != nil {
+				leaf.kind, leaf.headerSize = sqliteFreeLeaf, 0
+			}
+		}
+		trunk = int(binary.BigEndian.Uint32(data[page.offset:]))
+	}
+}
+
+// markOverflow follows a chain of overflow pages, each starting with the number of the next
+func (db *sqliteDatabase) markOverflow(data []byte, number int) {
+	for seen := 0; number != 0 && seen < len(db.pages); seen++ {
+		page := db.page(number)
+		if page == nil || page.kind != sqliteUnknown || page.offset+4 > len(data) {
+			return
+		}
+		page.kind, page.headerSize = sqliteOverflow, 4
+		number = int(binary.BigEndian.Uint32(data[page.offset:]))
+	}
+}
+
+// overflowStarts returns the first overflow page of each cell of a b-tree page whose
+// payload does not fit in the page, following the rules of the SQLite file format
+func (db *sqliteDatabase) overflowStarts(data []byte, page sqlitePage) []int {
+	header := page.offset
+	if page.number == 1 {
+		header = 100
+	}
+	if page.kind == sqliteTableInterior {
+		return nil // Its cells hold no payload
+	}
+	usable := db.usableSize
+	maxLocal := usable - 35
+	if page.kind != sqliteTableLeaf {
+		maxLocal = (usable-12)*64/255 - 23
+	}
+	minLocal := (usable-12)*32/255 - 23
+
+	var starts []int
+	for cell := 0; cell < page.cells; cell++ {
+		pointer := header + page.headerSize + 2*cell
+		if pointer+2 > len(data) {
+			break
+		}
+		offset := page.offset + int(binary.BigEndian.Uint16(data[pointer:]))
+		if page.kind == sqliteIndexInterior {
+			offset += 4 // Left child page number
+		}
+		if offset >= len(data) {
+			continue
+		}
+		payload, size := sqliteVarint(data[offset:])
+		offset += size
+		if page.kind == sqliteTableLeaf {
+			_, size = sqliteVarint(data[offset:])
+			offset += size
+		}
+		if payload <= uint64(maxLocal) {
+			continue
+		}
+		local := minLocal + int((payload-uint64(minLocal))%uint64(usable-4))
+		if local > maxLocal {
+			local = minLocal
+		}
+		if offset+local+4 <= len(data) {
+			starts = append(starts, int(binary.BigEndian.Uint32(data[offset+local:])))
+		}
+	}
+	return starts
+}
+
+// sqliteVarint decodes the big-endian variable-length integer of 1 to 9 bytes at the
+// start of data, returning it and its length
+func sqliteVarint(data []byte) (uint64, int) {
+	var value uint64
+	for index := 0; index < len(data) && index < 9; index++ {
+		if index == 8 {
+			return value<<8 | uint64(data[index]), 9
+		}
+		value = value<<7 | uint64(data[index]&0x7F)
+		if data[index] < 0x80 {
+			return value, index + 1
+		}
+	}
+	return value, max(len(data), 1)
+}
+
+// updateSQLite parses the file again if it is a SQLite database
+func (h *HexDumpApp) updateSQLite() {
+	h.sqlite = parseSQLite(h.fileData)
+}
+
+// toggleSQLiteOverlay shows or hides the SQLite page overlay
+func (h *HexDumpApp) toggleSQLiteOverlay() {
+	h.showSQLitePages = !h.showSQLitePages
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// sqlitePageAt returns the page containing offset when the SQLite overlay is shown, or nil
+func (h *HexDumpApp) sqlitePageAt(offset int) *sqlitePage {
+	if h.sqlite == nil || !h.showSQLitePages {
+		return nil
+	}
+	return h.sqlite.page(offset/h.sqlite.pageSize + 1)
+}
+
+// startsSQLitePage reports whether the line starting at offset begins a SQLite page
+// whose boundary is marked
+func (h *HexDumpApp) startsSQLitePage(offset int) bool {
+	return h.sqlite != nil && h.showSQLitePages && offset > 0 && offset%h.sqlite.pageSize == 0
+}
+
+// sqliteSpans returns highlight spans for the SQLite page headers intersecting
+// [lineStart, lineEnd), colored by the kind of page
+func (h *HexDumpApp) sqliteSpans(lineStart, lineEnd int) []highlightSpan {
+	if h.sqlite == nil || !h.showSQLitePages {
+		return nil
+	}
+	var spans []highlightSpan
+	for offset := lineStart - lineStart%h.sqlite.pageSize; offset < lineEnd; offset += h.sqlite.pageSize {
+		page := h.sqlitePageAt(offset)
+		if page == nil || page.headerSize == 0 {
+			continue
+		}
+		header := page.offset
+		if page.number == 1 {
+			header = 100
+		}
+		start, end := max(header, lineStart), min(header+page.headerSize, lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(sqlitePageColors[page.kind])})
+		}
+	}
+	return spans
+}
+
+// sqliteStatus describes the SQLite page containing the caret for the status bar
+func (h *HexDumpApp) sqliteStatus() string {
+	page := h.sqlitePageAt(h.caret)
+	if page == nil {
+		return ""
+	}
+	status := fmt.Sprintf("SQLite page %d: %s", page.number, page.kind)
+	switch page.kind {
+	case sqliteTableInterior, sqliteTableLeaf, sqliteIndexInterior, sqliteIndexLeaf:
+		status += fmt.Sprintf(", %d cells", page.cells)
+	case sqliteFreeTrunk:
+		status += fmt.Sprintf(", %d leaves", page.cells)
+	}
+	return status
+}
+
+// showSQLitePageList lists the pages of a SQLite database with their kinds. Selecting a
+// page selects it.
+func (h *HexDumpApp) showSQLitePageList() {
+	db := h.sqlite
+	if db == nil {
+		dialog.ShowInformation(lang.L("SQLite Pages"), lang.L("The file is not a SQLite database."), h.window)
+		return
+	}
+
+	counts := map[string]int{}
+	for _, page := range db.pages {
+		counts[page.kind]++
+	}
+	summary := fmt.Sprintf("%d pages of %d bytes: %d b-tree, %d on the freelist (header: %d), %d overflow, %d unknown",
+		len(db.pages), db.pageSize,
+		counts[sqliteTableInterior]+counts[sqliteTableLeaf]+counts[sqliteIndexInterior]+counts[sqliteIndexLeaf],
+		counts[sqliteFreeTrunk]+counts[sqliteFreeLeaf], db.freePages, counts[sqliteOverflow], counts[sqliteUnknown])
+
+	list := widget.NewList(
+		func() int { return len(db.pages) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			page := db.pages[id]
+			text := fmt.Sprintf("#%-6d %08X  %s", page.number, page.offset, page.kind)
+			switch page.kind {
+			case sqliteTableInterior, sqliteTableLeaf, sqliteIndexInterior, sqliteIndexLeaf:
+				text += fmt.Sprintf(" (%d cells)", page.cells)
+			case sqliteFreeTrunk:
+				text += fmt.Sprintf(" (%d leaves)", page.cells)
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		page := db.pages[id]
+		h.setSelection(page.offset, min(page.offset+db.pageSize, len(h.fileData)))
+		h.goToOffset(page.offset)
+	}
+
+	summaryLabel := widget.NewLabel(summary)
+	summaryLabel.Wrapping = fyne.TextWrapWord
+	window := h.app.NewWindow(fmt.Sprintf("SQLite Pages - %s", h.fileName))
+	window.SetContent(container.NewBorder(summaryLabel, nil, nil, nil, list))
+	window.Resize(fyne.NewSize(450, 500))
+	window.Show()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxStringsFound limits the number of strings listed by the Strings panel
+const maxStringsFound = 100000
+
+// foundString is a run of printable characters found in the file
+type foundString struct {
+	offset int
+	length int    // Length in bytes
+	wide   bool   // True for UTF-16LE strings
+	text   string // The decoded characters
+}
+
+// isPrintableASCII reports whether b is a printable ASCII character or a tab
+func isPrintableASCII(b byte) bool {
+	return (b >= 0x20 && b <= 0x7E) || b == '\t'
+}
+
+// extractStrings finds runs of at least minLength printable ASCII characters in data,
+// both as single bytes and as UTF-16LE code units, in order of offset
+func extractStrings(data []byte, minLength int, limit int) []foundString {
+	var found []foundString
+
+	// Single-byte strings
+	start := -1
+	for index := 0; index <= len(data); index++ {
+		if index < len(data) && isPrintableASCII(data[index]) {
+			if start < 0 {
+				start = index
+			}
+			continue
+		}
+		if start >= 0 && index-start >= minLength {
+			found = append(found, foundString{offset: start, length: index - start, text: string(data[start:index])})
+		}
+		start = -1
+	}
+
+	// UTF-16LE strings, at both even and odd alignments
+	for alignment := 0; alignment < 2; alignment++ {
+		var builder strings.Builder
+		start = -1
+		for index := alignment; index <= len(data); index += 2 {
+			if index+1 < len(data) && isPrintableASCII(data[index]) && data[index+1] == 0 {
+				if start < 0 {
+					start = index
+					builder.Reset()
+				}
+				builder.WriteByte(data[index])
+				continue
+			}
+			if start >= 0 && (index-start)/2 >= minLength {
+				found = append(found, foundString{offset: start, length: index - start, wide: true, text: builder.String()})
+			}
+			start = -1
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].offset < found[j].offset })
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// createStringsPanel creates the Strings side panel, which lists the printable strings
+// in the file. Tapping a string selects its bytes.
+func (h *HexDumpApp) createStringsPanel() panelContent {
+	var found []foundString
+	minLengthEntry := widget.NewEntry()
+	minLengthEntry.SetText("4")
+	summaryLabel := widget.NewLabel("")
+
+	list := widget.NewList(
+		func() int { return len(found) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			s := found[id]
+			kind := "A"
+			if s.wide {
+				kind = "U"
+			}
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X %s %s", s.offset, kind, s.text))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		s := found[id]
+		h.setSelection(s.offset, s.offset+s.length)
+		h.goToOffset(s.offset)
+	}
+
+	scanBtn := widget.NewButton(lang.L("Scan"), func() {
+		minLength, err := strconv.Atoi(strings.TrimSpace(minLengthEntry.Text))
+		if err != nil || minLength < 2 {
+			dialog.ShowError(fmt.Errorf("minimum length must be a number of at least 2"), h.window)
+			return
+		}
+		found = extractStrings(h.fileData, minLength, maxStringsFound)
+		list.UnselectAll()
+		list.Refresh()
+		summaryLabel.SetText(fmt.Sprintf("%d strings (A = ASCII, U = UTF-16LE)", len(found)))
+	})
+
+	reset := func() {
+		found = nil
+		list.UnselectAll()
+		list.Refresh()
+		summaryLabel.SetText(lang.L("Press Scan to list strings"))
+	}
+	reset()
+
+	controls := container.NewBorder(nil, nil, widget.NewLabel(lang.L("Min length:")), scanBtn, minLengthEntry)
+	return panelContent{
+		object: container.NewBorder(container.NewVBox(controls, summaryLabel), nil, nil, nil, list),
+		reset:  reset,
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// symbol is a named function or data object located at a file offset
+type symbol struct {
+	name    string
+	address uint64 // Address in the symbol source, usually a virtual address
+	offset  int    // Offset in the loaded file
+	size    int    // Size in bytes, or 0 if unknown
+	kind    string // "func", "data", or "" if unknown
+}
+
+// gnuMapSymbolLine matches a symbol definition line of a GNU ld map file, in which an
+// address is followed only by a symbol name
+var gnuMapSymbolLine = regexp.MustCompile(`^\s+0x([0-9A-Fa-f]+)\s+([A-Za-z_.$][\w.$@]*)\s*$`)
+
+// loadELFSymbols reads the symbol table of an ELF file. If sameFile is true, the
+// symbols describe the loaded file itself and are mapped onto file offsets through
+// the ELF sections. Otherwise the loaded file is taken to be a raw image of memory
+// starting at base, as for firmware built from the ELF file.
+func loadELFSymbols(path string, sameFile bool, base uint64) ([]symbol, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	elfSymbols, err := file.Symbols()
+	if err != nil || len(elfSymbols) == 0 {
+		// Stripped binaries may still have dynamic symbols
+		if elfSymbols, err = file.DynamicSymbols(); err != nil {
+			return nil, fmt.Errorf("%s has no symbol table: %w", path, err)
+		}
+	}
+
+	var symbols []symbol
+	for _, elfSymbol := range elfSymbols {
+		symbolType := elf.ST_TYPE(elfSymbol.Info)
+		if elfSymbol.Name == "" || elfSymbol.Section == elf.SHN_UNDEF || elfSymbol.Section >= elf.SHN_LORESERVE {
+			continue
+		}
+		if symbolType != elf.STT_FUNC && symbolType != elf.STT_OBJECT && symbolType != elf.STT_NOTYPE {
+			continue
+		}
+
+		s := symbol{name: elfSymbol.Name, address: elfSymbol.Value, size: int(elfSymbol.Size)}
+		switch symbolType {
+		case elf.STT_FUNC:
+			s.kind = "func"
+		case elf.STT_OBJECT:
+			s.kind = "data"
+		}
+
+		if sameFile {
+			section := file.Sections[elfSymbol.Section]
+			if section.Type == elf.SHT_NOBITS || elfSymbol.Value < section.Addr {
+				continue // No bytes in the file, e.g. .bss
+			}
+			s.offset = int(section.Offset + elfSymbol.Value - section.Addr)
+		} else {
+			if elfSymbol.Value < base {
+				continue
+			}
+			s.offset = int(elfSymbol.Value - base)
+		}
+		symbols = append(symbols, s)
+	}
+	return symbols, nil
+}
+
+// parseLinkerMap reads the symbol definitions of a GNU ld map file. Symbols are given
+// file offsets relative to base, and their sizes are taken to extend to the next symbol.
+func parseLinkerMap(text string, base uint64) []symbol {
+	var symbols []symbol
+	for _, line := range strings.Split(text, "\n") {
+		match := gnuMapSymbolLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		address, err := strconv.ParseUint(match[1], 16, 64)
+		if err != nil || address < base {
+			continue
+		}
+		symbols = append(symbols, symbol{name: match[2], address: address, offset: int(address - base)})
+	}
+
+	sortSymbols(symbols)
+	for index := 0; index+1 < len(symbols); index++ {
+		symbols[index].size = symbols[index+1].offset - symbols[index].offset
+	}
+	return symbols
+}
+
+// sortSymbols sorts symbols by offset and then by name
+func sortSymbols(symbols []symbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].offset != symbols[j].offset {
+			return symbols[i].offset < symbols[j].offset
+		}
+		return symbols[i].name < symbols[j].name
+	})
+}
+
+// symbolAt returns the symbol containing offset, described as name+displacement, or ""
+func (h *HexDumpApp) symbolAt(offset int) string {
+	index := sort.Search(len(h.symbols), func(i int) bool {
+		return h.symbols[i].offset > offset
+	}) - 1
+
+	describe := func(s *symbol) string {
+		if offset == s.offset {
+			return s.name
+		}
+		return fmt.Sprintf("%s+0x%s", s.name, formatHex(uint64(offset-s.offset), 1))
+	}
+
+	// A sized symbol shortly before the nearest one may still enclose the offset
+	for candidate := index; candidate >= 0 && candidate > index-16; candidate-- {
+		if s := &h.symbols[candidate]; s.size > 0 && offset < s.offset+s.size {
+			return describe(s)
+		}
+	}
+	if index >= 0 && h.symbols[index].size == 0 {
+		return describe(&h.symbols[index])
+	}
+	return ""
+}
+
+// setSymbols replaces the loaded symbols, dropping any outside the file
+func (h *HexDumpApp) setSymbols(symbols []symbol) {
+	var kept []symbol
+	for _, s := range symbols {
+		if s.offset >= 0 && s.offset < len(h.fileData) {
+			kept = append(kept, s)
+		}
+	}
+	sortSymbols(kept)
+	h.symbols = kept
+	h.updateStatus()
+}
+
+// showLoadSymbols loads symbols from a linker map or ELF file chosen by the user
+func (h *HexDumpApp) showLoadSymbols() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Load Symbols"), lang.L("Open a file before loading symbols."), h.window)
+		return
+	}
+
+	baseEntry := widget.NewEntry()
+	baseEntry.SetText("0")
+
+	form := dialog.NewForm(lang.L("Load Symbols"), lang.L("Choose File..."), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Image base address"), baseEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		base, err := parseOffset(baseEntry.Text)
+		if err != nil || base < 0 {
+			dialog.ShowError(fmt.Errorf("invalid base address %q", baseEntry.Text), h.window)
+			return
+		}
+
+		filename, err := nativedialog.File().Filter("Symbol files", "map", "elf", "axf", "out", "o", "so").
+			Filter("All Files", "*").Load()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, h.window)
+			}
+			return
+		}
+		h.loadSymbolsFromPath(filename, uint64(base))
+	}, h.window)
+	form.Resize(fyne.NewSize(400, 150))
+	form.Show()
+}
+
+// loadSymbolsFromPath loads symbols from an ELF file or a linker map file. ELF symbols
+// are mapped through the ELF sections when the ELF file is the loaded file itself.
+func (h *HexDumpApp) loadSymbolsFromPath(path string, base uint64) {
+	var symbols []symbol
+	if elfFile, err := elf.Open(path); err == nil {
+		elfFile.Close()
+		symbols, err = loadELFSymbols(path, path == h.fileName, base)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+	} else {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		symbols = parseLinkerMap(string(text), base)
+	}
+
+	h.setSymbols(symbols)
+	if len(h.symbols) == 0 {
+		dialog.ShowInformation(lang.L("Load Symbols"), lang.L("No symbols within the file were found."), h.window)
+		return
+	}
+	h.showSymbolList()
+}
+
+// showSymbolList opens a filterable list of the loaded symbols. Tapping a symbol
+// selects its bytes in the data view.
+func (h *HexDumpApp) showSymbolList() {
+	if len(h.symbols) == 0 {
+		dialog.ShowInformation(lang.L("Symbols"), lang.L("No symbols are loaded. Use Tools → Load Symbols... first."), h.window)
+		return
+	}
+
+	shown := h.symbols
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			s := shown[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %-4s  %s", s.offset, s.kind, s.name))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		s := shown[id]
+		h.setSelection(s.offset, s.offset+max(s.size, 1))
+		h.goToOffset(s.offset)
+	}
+
+	filterEntry := widget.NewEntry()
+	filterEntry.SetPlaceHolder(lang.L("Filter by name"))
+	filterEntry.OnChanged = func(text string) {
+		text = strings.ToLower(text)
+		shown = nil
+		for _, s := range h.symbols {
+			if strings.Contains(strings.ToLower(s.name), text) {
+				shown = append(shown, s)
+			}
+		}
+		list.UnselectAll()
+		list.Refresh()
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("Symbols - %s", h.fileName))
+	window.SetContent(container.NewBorder(filterEntry, nil, nil, nil, list))
+	window.Resize(fyne.NewSize(450, 500))
+	window.Show()
+}
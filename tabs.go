@@ -0,0 +1,127 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+
+	"fyne.io/fyne/v2/container"
+)
+
+// fileTabs are the files open in one window, each shown by its own HexDumpApp on a tab
+// of its own. The file of the selected tab has the window's menu, shortcuts and keys.
+type fileTabs struct {
+	tabs   *container.DocTabs
+	apps   []*HexDumpApp
+	items  []*container.TabItem
+	active *HexDumpApp
+}
+
+// windowApps returns the application instances of the files open in the window
+func (h *HexDumpApp) windowApps() []*HexDumpApp {
+	if h.tabs == nil {
+		return []*HexDumpApp{h}
+	}
+	return h.tabs.apps
+}
+
+// tabTitle returns the title of the tab showing the file
+func (h *HexDumpApp) tabTitle() string {
+	if h.fileName == "" {
+		return "Untitled"
+	}
+	return filepath.Base(h.fileName)
+}
+
+// openInNewTab opens a file in a new tab of the window, selecting length bytes at
+// offset. The file the window showed until then becomes its first tab.
+func (h *HexDumpApp) openInNewTab(filePath string, offset, length int) {
+	if h.tabs == nil {
+		t := &fileTabs{apps: []*HexDumpApp{h}, items: []*container.TabItem{container.NewTabItem(h.tabTitle(), h.content)},
+			active: h}
+		t.tabs = container.NewDocTabs(t.items[0])
+		t.tabs.OnSelected = func(item *container.TabItem) {
+			if index := slices.Index(t.items, item); index >= 0 {
+				t.activate(t.apps[index])
+			}
+		}
+		t.tabs.CloseIntercept = func(item *container.TabItem) {
+			if index := slices.Index(t.items, item); index >= 0 {
+				t.close(t.apps[index])
+			}
+		}
+		h.tabs = t
+		h.window.SetContent(t.tabs)
+	}
+
+	other := NewHexDumpApp(h.app, h.window)
+	other.tabs = h.tabs
+	other.setupGUI()
+	other.loadFileFromPath(filePath)
+	other.setSelection(offset, offset+length)
+	other.goToOffset(offset)
+
+	item := container.NewTabItem(other.tabTitle(), other.content)
+	h.tabs.apps = append(h.tabs.apps, other)
+	h.tabs.items = append(h.tabs.items, item)
+	h.tabs.tabs.Append(item)
+	h.tabs.tabs.Select(item)
+	h.tabs.activate(other)
+}
+
+// activate gives the window's menu, shortcuts and keys to the file of the selected tab
+func (t *fileTabs) activate(app *HexDumpApp) {
+	if previous := t.active; previous != nil && previous != app {
+		for _, shortcut := range previous.registered {
+			previous.window.Canvas().RemoveShortcut(shortcut)
+		}
+		previous.registered = nil
+	}
+	t.active = app
+	app.createMenu()
+	app.bindKeyboard()
+}
+
+// retitle shows the name of the file the tab of app shows now
+func (t *fileTabs) retitle(app *HexDumpApp) {
+	if index := slices.Index(t.apps, app); index >= 0 {
+		t.items[index].Text = app.tabTitle()
+		t.tabs.Refresh()
+	}
+}
+
+// close closes the tab of app, asking first if that discards edits. Closing the last
+// tab closes the window.
+func (t *fileTabs) close(app *HexDumpApp) {
+	if len(t.apps) == 1 {
+		app.confirmDiscardEdits(app.window.Close)
+		return
+	}
+	t.tabs.Select(t.items[slices.Index(t.apps, app)])
+	app.confirmDiscardEdits(func() {
+		index := slices.Index(t.apps, app)
+		if index < 0 {
+			return
+		}
+		item := t.items[index]
+		t.apps = slices.Delete(t.apps, index, index+1)
+		t.items = slices.Delete(t.items, index, index+1)
+		t.tabs.Remove(item)
+		app.closed()
+		t.tabs.SelectIndex(min(index, len(t.items)-1))
+		t.activate(t.apps[min(index, len(t.apps)-1)])
+	})
+}
+
+// confirmDiscardAll asks about the unsaved edits of each of apps in turn, showing the
+// tab of each, and calls proceed if none of them is cancelled
+func confirmDiscardAll(apps []*HexDumpApp, proceed func()) {
+	if len(apps) == 0 {
+		proceed()
+		return
+	}
+	app := apps[0]
+	if app.tabs != nil && app.isModified() {
+		app.tabs.tabs.Select(app.tabs.items[slices.Index(app.tabs.apps, app)])
+	}
+	app.confirmDiscardEdits(func() { confirmDiscardAll(apps[1:], proceed) })
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tagCount is a tag and the number of bookmarks that have it
+type tagCount struct {
+	tag   string
+	count int
+}
+
+// parseTags parses tags separated by spaces or commas, such as "#header #todo". The #
+// is optional, and tags are lowercased, sorted and deduplicated.
+func parseTags(text string) []string {
+	var tags []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		if tag := strings.ToLower(strings.TrimLeft(field, "#")); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// formatTags formats tags as they are entered, each with a leading #
+func formatTags(tags []string) string {
+	var builder strings.Builder
+	for index, tag := range tags {
+		if index > 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString("#" + tag)
+	}
+	return builder.String()
+}
+
+// hasTag reports whether the bookmark has the tag; every bookmark has the empty tag
+func (b *bookmark) hasTag(tag string) bool {
+	return tag == "" || slices.Contains(b.tags, tag)
+}
+
+// bookmarkTags returns the tags of the bookmarks with the number of bookmarks having
+// each, sorted by tag
+func (h *HexDumpApp) bookmarkTags() []tagCount {
+	counts := make(map[string]int)
+	for _, b := range h.bookmarks {
+		for _, tag := range b.tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]tagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, tagCount{tag, count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].tag < tags[j].tag })
+	return tags
+}
+
+// shownBookmarks returns the indices of the bookmarks having the tag the bookmarks are
+// filtered by, or of all bookmarks if they aren't filtered
+func (h *HexDumpApp) shownBookmarks() []int {
+	var shown []int
+	for index := range h.bookmarks {
+		if h.bookmarks[index].hasTag(h.tagFilter) {
+			shown = append(shown, index)
+		}
+	}
+	return shown
+}
+
+// setTagFilter shows only the bookmarks having tag, in the Bookmarks panel and the data
+// view; the empty tag shows all of them
+func (h *HexDumpApp) setTagFilter(tag string) {
+	h.tagFilter = tag
+	h.bookmarksChanged()
+}
+
+// dropStaleTagFilter shows all bookmarks again once none has the tag they are filtered by
+func (h *HexDumpApp) dropStaleTagFilter() {
+	if !slices.ContainsFunc(h.bookmarks, func(b bookmark) bool { return b.hasTag(h.tagFilter) }) {
+		h.tagFilter = ""
+	}
+}
+
+// goToNextBookmark selects the first shown bookmark after the caret, wrapping around to
+// the first one
+func (h *HexDumpApp) goToNextBookmark() {
+	shown := h.shownBookmarks()
+	if len(shown) == 0 {
+		return
+	}
+	next := shown[0]
+	for _, index := range shown {
+		if h.bookmarks[index].offset > h.caret {
+			next = index
+			break
+		}
+	}
+	h.selectBookmark(next)
+}
+
+// goToPreviousBookmark selects the last shown bookmark before the caret, wrapping around
+// to the last one
+func (h *HexDumpApp) goToPreviousBookmark() {
+	shown := h.shownBookmarks()
+	if len(shown) == 0 {
+		return
+	}
+	previous := shown[len(shown)-1]
+	for _, index := range slices.Backward(shown) {
+		if h.bookmarks[index].offset < h.caret {
+			previous = index
+			break
+		}
+	}
+	h.selectBookmark(previous)
+}
+
+// selectBookmark selects the bytes of the bookmark at the given index and scrolls to them
+func (h *HexDumpApp) selectBookmark(index int) {
+	b := h.bookmarks[index]
+	h.setSelection(b.offset, b.end())
+	h.caret = b.offset
+	h.goToOffset(b.offset)
+}
+
+// showEditTags edits the tags of the bookmark at the given index
+func (h *HexDumpApp) showEditTags(index int) {
+	if index < 0 || index >= len(h.bookmarks) {
+		return
+	}
+	b := &h.bookmarks[index]
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetText(formatTags(b.tags))
+	tagsEntry.SetPlaceHolder(lang.L("e.g. #header #todo"))
+
+	dialog.ShowForm(fmt.Sprintf("Tags of %s", b.label), lang.L("Save"), lang.L("Cancel"),
+		[]*widget.FormItem{widget.NewFormItem(lang.L("Tags"), tagsEntry)},
+		func(ok bool) {
+			if ok && index < len(h.bookmarks) {
+				h.bookmarks[index].tags = parseTags(tagsEntry.Text)
+				h.dropStaleTagFilter()
+				h.bookmarksChanged()
+			}
+		}, h.window)
+	h.window.Canvas().Focus(tagsEntry)
+}
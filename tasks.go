@@ -0,0 +1,147 @@
+package main
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxFinishedTasks is the number of finished tasks the task tray lists
+const maxFinishedTasks = 20
+
+// progressInterval is how many steps scans take between reports of their progress
+const progressInterval = 1 << 16
+
+// backgroundTask is a job running in the background, such as a scan of the file. While
+// it runs it shows as a chip in the status bar with its progress and a cancel button;
+// when it finishes it moves to the task tray with its result.
+type backgroundTask struct {
+	name     string
+	stopped  atomic.Bool
+	permille atomic.Int32 // Progress reported last, or -1 before any
+	finished time.Time
+	result   string
+	open     func() // Shows the results again, or nil
+
+	chip   *fyne.Container
+	bar    *widget.ProgressBar
+	cancel *widget.Button
+}
+
+// cancelled reports whether the user cancelled the task. It may be called from any
+// goroutine, and on a nil task, which is never cancelled.
+func (t *backgroundTask) cancelled() bool {
+	return t != nil && t.stopped.Load()
+}
+
+// report shows that done of total units of work are done, and returns false if the task
+// was cancelled so that the work should stop. It may be called from any goroutine, and
+// on a nil task.
+func (t *backgroundTask) report(done, total int) bool {
+	if t == nil {
+		return true
+	}
+	if total > 0 {
+		permille := int32(int64(done) * 1000 / int64(total))
+		if t.permille.Swap(permille) != permille {
+			fyne.Do(func() {
+				t.bar.Show()
+				t.bar.SetValue(float64(permille) / 1000)
+			})
+		}
+	}
+	return !t.cancelled()
+}
+
+// startTask adds a chip for a task with the given name to the status bar. Its progress
+// shows once the task reports it.
+func (h *HexDumpApp) startTask(name string) *backgroundTask {
+	t := &backgroundTask{name: name, bar: widget.NewProgressBar()}
+	t.permille.Store(-1)
+	t.bar.TextFormatter = func() string { return "" }
+	t.bar.Hide()
+	t.cancel = widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+		t.stopped.Store(true)
+		t.cancel.Disable()
+	})
+	t.cancel.Importance = widget.LowImportance
+	t.chip = container.NewHBox(widget.NewSeparator(), widget.NewLabel(name),
+		container.NewCenter(container.NewGridWrap(fyne.NewSize(80, 8), t.bar)), t.cancel)
+	slog.Debug("task started", "task", name)
+	h.taskChips.Add(t.chip)
+	h.statusContent.Refresh()
+	return t
+}
+
+// finishTask removes the chip of a task and lists it in the task tray with its result,
+// where open, if not nil, shows the results again. It returns false if the task was
+// cancelled, in which case its results should be dropped.
+func (h *HexDumpApp) finishTask(t *backgroundTask, result string, open func()) bool {
+	h.taskChips.Remove(t.chip)
+	h.unmapRetired() // The task no longer reads the data of a file loaded before
+	t.finished = time.Now()
+	slog.Debug("task finished", "task", t.name, "cancelled", t.cancelled(), "result", result)
+	if t.cancelled() {
+		t.result, t.open = lang.L("Cancelled"), nil
+	} else {
+		t.result, t.open = result, open
+	}
+	h.finishedTasks = append([]*backgroundTask{t}, h.finishedTasks[:min(len(h.finishedTasks), maxFinishedTasks-1)]...)
+	h.taskTrayButton.Show()
+	h.statusContent.Refresh()
+	return !t.cancelled()
+}
+
+// createTaskTray creates the status bar area holding the chips of running tasks and the
+// button of the task tray
+func (h *HexDumpApp) createTaskTray() fyne.CanvasObject {
+	h.taskChips = container.NewHBox()
+	h.taskTrayButton = widget.NewButtonWithIcon("", theme.HistoryIcon(), h.showTaskTray)
+	h.taskTrayButton.Importance = widget.LowImportance
+	h.taskTrayButton.Hide()
+	return container.NewHBox(h.taskChips, h.taskTrayButton)
+}
+
+// showTaskTray shows a popover above the task tray button listing the finished tasks,
+// newest first, with their results. Show opens the results of a task again.
+func (h *HexDumpApp) showTaskTray() {
+	rows := container.NewVBox()
+	var popUp *widget.PopUp
+	for _, t := range h.finishedTasks {
+		title := widget.NewLabel(t.finished.Format("15:04:05") + "  " + t.name)
+		title.TextStyle.Bold = true
+		result := widget.NewLabel(t.result)
+		result.Wrapping = fyne.TextWrapWord
+		var show fyne.CanvasObject
+		if t.open != nil {
+			open := t.open
+			show = container.NewCenter(widget.NewButton(lang.L("Show"), func() {
+				popUp.Hide()
+				open()
+			}))
+		}
+		rows.Add(container.NewBorder(title, nil, nil, show, result))
+		rows.Add(widget.NewSeparator())
+	}
+	clearButton := widget.NewButton(lang.L("Clear"), func() {
+		h.finishedTasks = nil
+		h.taskTrayButton.Hide()
+		h.statusContent.Refresh()
+		popUp.Hide()
+	})
+	content := container.NewBorder(widget.NewLabel(lang.L("Finished Tasks")), container.NewHBox(clearButton), nil, nil,
+		container.NewVScroll(rows))
+	popUp = widget.NewPopUp(content, h.window.Canvas())
+
+	size := fyne.NewSize(380, 320)
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(h.taskTrayButton)
+	position = position.Add(fyne.NewPos(h.taskTrayButton.Size().Width-size.Width, -size.Height))
+	popUp.ShowAtPosition(fyne.NewPos(max(0, position.X), max(0, position.Y)))
+	popUp.Resize(size)
+}
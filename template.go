@@ -0,0 +1,580 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// structTemplate describes the layout of a binary structure. Templates are JSON files:
+//
+//	{"name": "BMP header", "endian": "little", "fields": [
+//	    {"name": "magic", "type": "char[2]"},
+//	    {"name": "fileSize", "type": "u32"},
+//	    {"name": "reserved", "type": "bytes[4]"},
+//	    {"name": "info", "type": "struct", "fields": [...]}]}
+type structTemplate struct {
+	Name   string          `json:"name"`
+	Endian string          `json:"endian"` // "little" (the default) or "big"
+	Fields []templateField `json:"fields"`
+}
+
+// templateField is one field of a structure template. Fields of type "struct" contain
+// further fields. An integer field can hold the length of another field of the same
+// struct, named by LengthOf, which editing that field can update. Fields of type
+// "computed" take no bytes and show the value of the expression Value, and any field
+// can have a Check expression, which must be true for the data to be valid. A struct
+// with a Count, a number or an expression, is an array of that many records.
+type templateField struct {
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+	Fields   []templateField `json:"fields,omitempty"`
+	LengthOf string          `json:"lengthOf,omitempty"`
+	Value    string          `json:"value,omitempty"`
+	Check    string          `json:"check,omitempty"`
+	Count    exprText        `json:"count,omitempty"`
+}
+
+// parsedField is a template field decoded from the file
+type parsedField struct {
+	name     string
+	typeName string
+	offset   int
+	size     int
+	value    string
+	lengthOf string // Name of the sibling field whose length this field holds
+	children []*parsedField
+
+	expression    string // Expression of a computed field
+	computed      bool   // Whether computedValue holds the value of the expression
+	computedValue int64
+	check         string // Expression that must be true, or ""
+	checkResult   checkResult
+	checkMessage  string // Why the check failed
+
+	array bool // Whether the field is an array of records, showing the one at index
+	index int
+	count int
+}
+
+// fieldSizes gives the size of each fixed-size field type
+var fieldSizes = map[string]int{
+	"u8": 1, "u16": 2, "u32": 4, "u64": 8,
+	"i8": 1, "i16": 2, "i32": 4, "i64": 8,
+	"f32": 4, "f64": 8,
+}
+
+// arrayType matches the char[N] and bytes[N] field types
+var arrayType = regexp.MustCompile(`^(char|bytes)\[(\d+)\]$`)
+
+// loadTemplate reads and checks a structure template file
+func loadTemplate(path string) (*structTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTemplate(data)
+}
+
+// parseTemplate decodes and checks a structure template
+func parseTemplate(data []byte) (*structTemplate, error) {
+	var template structTemplate
+	if err := json.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	switch strings.ToLower(template.Endian) {
+	case "", "little", "big":
+	default:
+		return nil, fmt.Errorf("invalid template: endian must be \"little\" or \"big\", not %q", template.Endian)
+	}
+	if len(template.Fields) == 0 {
+		return nil, fmt.Errorf("invalid template: it has no fields")
+	}
+	if err := checkTemplateFields(template.Fields); err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return &template, nil
+}
+
+// checkTemplateFields reports the first field with an unknown type, or holding the
+// length of a field it has no sibling of that name for
+func checkTemplateFields(fields []templateField) error {
+	for _, field := range fields {
+		if field.LengthOf != "" {
+			if !isIntegerType(field.Type) {
+				return fmt.Errorf("field %q holds a length, so it must be an integer", field.Name)
+			}
+			if !slices.ContainsFunc(fields, func(f templateField) bool { return f.Name == field.LengthOf }) {
+				return fmt.Errorf("field %q holds the length of %q, which isn't in the same struct", field.Name,
+					field.LengthOf)
+			}
+		}
+		if field.Check != "" {
+			if _, err := parseExpression(field.Check); err != nil {
+				return fmt.Errorf("check of field %q: %v", field.Name, err)
+			}
+		}
+		if field.Count != "" {
+			if field.Type != "struct" {
+				return fmt.Errorf("field %q has a count, so it must be a struct", field.Name)
+			}
+			if _, err := parseExpression(string(field.Count)); err != nil {
+				return fmt.Errorf("count of field %q: %v", field.Name, err)
+			}
+		}
+		switch field.Type {
+		case "struct":
+			if err := checkTemplateFields(field.Fields); err != nil {
+				return err
+			}
+			continue
+		case "computed":
+			if _, err := parseExpression(field.Value); err != nil {
+				return fmt.Errorf("field %q: %v", field.Name, err)
+			}
+			continue
+		}
+		if _, err := fieldSize(field.Type); err != nil {
+			return fmt.Errorf("field %q: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// fieldSize returns the size in bytes of a field of a non-struct type
+func fieldSize(typeName string) (int, error) {
+	if size, ok := fieldSizes[typeName]; ok {
+		return size, nil
+	}
+	if match := arrayType.FindStringSubmatch(typeName); match != nil {
+		count, err := strconv.Atoi(match[2])
+		if err == nil && count > 0 {
+			return count, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown type %q", typeName)
+}
+
+// byteOrder returns the byte order of the template's fields
+func (t *structTemplate) byteOrder() binary.ByteOrder {
+	if strings.ToLower(t.Endian) == "big" {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// apply decodes the template from data at offset. Fields that run past the end of the
+// data are left out and reported as an error alongside the fields that were decoded.
+// Arrays of records show the record records gives for their tree node ID, or the first.
+func (t *structTemplate) apply(data []byte, offset int, records map[widget.TreeNodeID]int) (*parsedField, error) {
+	root := &parsedField{name: t.Name, typeName: "struct", offset: offset}
+	decoder := &templateDecoder{data: data, order: t.byteOrder(), records: records}
+	end, err := decoder.decodeFields(offset, t.Fields, root, "", nil)
+	root.size = end - offset
+	evaluateFields(root, nil, data, t.byteOrder())
+	return root, err
+}
+
+// templateDecoder decodes the fields of a template from data
+type templateDecoder struct {
+	data    []byte
+	order   binary.ByteOrder
+	records map[widget.TreeNodeID]int // Record shown of each array, by tree node ID
+}
+
+// decodeFields decodes fields starting at offset into parent's children, returning the
+// offset after the last field decoded. uid is parent's tree node ID, and scopes are the
+// structs enclosing parent, whose fields the counts of arrays can name.
+func (d *templateDecoder) decodeFields(offset int, fields []templateField, parent *parsedField,
+	uid widget.TreeNodeID, scopes []*parsedField) (int, error) {
+
+	for _, field := range fields {
+		parsed := &parsedField{name: field.Name, typeName: field.Type, offset: offset, lengthOf: field.LengthOf,
+			expression: field.Value, check: field.Check}
+		parent.children = append(parent.children, parsed)
+		if field.Type == "computed" {
+			continue
+		}
+
+		childID := strconv.Itoa(len(parent.children) - 1)
+		if uid != "" {
+			childID = uid + "." + childID
+		}
+		if field.Count != "" {
+			end, err := d.decodeArray(offset, field, parsed, childID, append(scopes, parent))
+			offset = end
+			if err != nil {
+				return offset, err
+			}
+			continue
+		}
+		if field.Type == "struct" {
+			end, err := d.decodeFields(offset, field.Fields, parsed, childID, append(scopes, parent))
+			parsed.size = end - offset
+			offset = end
+			if err != nil {
+				return offset, err
+			}
+			continue
+		}
+
+		size, err := fieldSize(field.Type)
+		if err != nil {
+			return offset, err
+		}
+		if offset+size > len(d.data) {
+			parent.children = parent.children[:len(parent.children)-1]
+			return offset, fmt.Errorf("field %q at %08X runs past the end of the file", field.Name, offset)
+		}
+		parsed.size = size
+		parsed.value = formatFieldValue(field.Type, d.data[offset:offset+size], d.order)
+		offset += size
+	}
+	return offset, nil
+}
+
+// formatFieldValue formats the bytes of a field of a non-struct type for display
+func formatFieldValue(typeName string, data []byte, order binary.ByteOrder) string {
+	switch typeName {
+	case "u8":
+		return fmt.Sprint(data[0])
+	case "u16":
+		return fmt.Sprint(order.Uint16(data))
+	case "u32":
+		return fmt.Sprint(order.Uint32(data))
+	case "u64":
+		return fmt.Sprint(order.Uint64(data))
+	case "i8":
+		return fmt.Sprint(int8(data[0]))
+	case "i16":
+		return fmt.Sprint(int16(order.Uint16(data)))
+	case "i32":
+		return fmt.Sprint(int32(order.Uint32(data)))
+	case "i64":
+		return fmt.Sprint(int64(order.Uint64(data)))
+	case "f32":
+		return fmt.Sprint(math.Float32frombits(order.Uint32(data)))
+	case "f64":
+		return fmt.Sprint(math.Float64frombits(order.Uint64(data)))
+	}
+
+	if strings.HasPrefix(typeName, "char[") {
+		text := make([]byte, 0, len(data))
+		for _, b := range data {
+			if b == 0 {
+				break
+			}
+			if !isPrintableASCII(b) {
+				b = '.'
+			}
+			text = append(text, b)
+		}
+		return strconv.Quote(string(text))
+	}
+	return strings.ToUpper(hex.EncodeToString(data))
+}
+
+// fieldAt returns the parsed field with the given tree node ID, a dot-separated path of
+// child indexes below root, or nil
+func (root *parsedField) fieldAt(uid widget.TreeNodeID) *parsedField {
+	field := root
+	if field == nil || uid == "" {
+		return field
+	}
+	for _, part := range strings.Split(uid, ".") {
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 || index >= len(field.children) {
+			return nil
+		}
+		field = field.children[index]
+	}
+	return field
+}
+
+// exportedField is a parsed field as copied to the clipboard
+type exportedField struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Offset int             `json:"offset"`
+	Size   int             `json:"size"`
+	Value  string          `json:"value,omitempty"`
+	Fields []exportedField `json:"fields,omitempty"`
+}
+
+// export converts the field and its children for copying. Text values are unquoted.
+func (field *parsedField) export() exportedField {
+	exported := exportedField{Name: field.name, Type: field.typeName, Offset: field.offset, Size: field.size,
+		Value: field.value}
+	if unquoted, err := strconv.Unquote(field.value); err == nil {
+		exported.Value = unquoted
+	}
+	for _, child := range field.children {
+		exported.Fields = append(exported.Fields, child.export())
+	}
+	return exported
+}
+
+// writeYAML writes the field to builder as a YAML mapping, indented by indent levels
+func (field exportedField) writeYAML(builder *strings.Builder, indent int, listItem bool) {
+	prefix := strings.Repeat("  ", indent)
+	first := prefix
+	if listItem {
+		first = prefix[:len(prefix)-2] + "- "
+	}
+	fmt.Fprintf(builder, "%sname: %s\n", first, strconv.Quote(field.Name))
+	fmt.Fprintf(builder, "%stype: %s\n", prefix, field.Type)
+	fmt.Fprintf(builder, "%soffset: 0x%X\n", prefix, field.Offset)
+	fmt.Fprintf(builder, "%ssize: %d\n", prefix, field.Size)
+	if field.Value != "" {
+		fmt.Fprintf(builder, "%svalue: %s\n", prefix, strconv.Quote(field.Value))
+	}
+	if len(field.Fields) > 0 {
+		fmt.Fprintf(builder, "%sfields:\n", prefix)
+		for _, child := range field.Fields {
+			child.writeYAML(builder, indent+2, true)
+		}
+	}
+}
+
+// copyStructure copies the decoded structure fields to the clipboard as JSON or YAML,
+// with their names, types, offsets, sizes, and values
+func (h *HexDumpApp) copyStructure(format string) {
+	if h.templateFields == nil {
+		dialog.ShowInformation(lang.L("Structure"), lang.L("Apply a structure template first."), h.window)
+		return
+	}
+
+	exported := h.templateFields.export()
+	var text string
+	if format == "YAML" {
+		var builder strings.Builder
+		exported.writeYAML(&builder, 0, false)
+		text = builder.String()
+	} else {
+		data, err := json.MarshalIndent(exported, "", "  ")
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		text = string(data)
+	}
+	h.window.Clipboard().SetContent(text)
+}
+
+// createStructurePanel creates the Structure side panel, which decodes a structure
+// template at the caret and shows its fields as a tree. Tapping a field selects its bytes.
+func (h *HexDumpApp) createStructurePanel() panelContent {
+	templateLabel := widget.NewLabel("")
+	messageLabel := widget.NewLabel("")
+	messageLabel.Wrapping = fyne.TextWrapWord
+
+	h.structureTree = widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			field := h.templateFields.fieldAt(uid)
+			if field == nil {
+				return nil
+			}
+			ids := make([]widget.TreeNodeID, len(field.children))
+			for index := range field.children {
+				if uid == "" {
+					ids[index] = strconv.Itoa(index)
+				} else {
+					ids[index] = uid + "." + strconv.Itoa(index)
+				}
+			}
+			return ids
+		},
+		func(uid widget.TreeNodeID) bool {
+			if h.templateFields == nil {
+				return uid == ""
+			}
+			field := h.templateFields.fieldAt(uid)
+			return field != nil && field.typeName == "struct"
+		},
+		func(bool) fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, widget.NewLabel(""))
+		},
+		func(uid widget.TreeNodeID, _ bool, item fyne.CanvasObject) {
+			field := h.templateFields.fieldAt(uid)
+			if field == nil {
+				return
+			}
+			text := fmt.Sprintf("%08X  %s (%s)", field.offset, field.name, field.typeName)
+			if field.array {
+				text = fmt.Sprintf("%08X  %s[%d] (record %d of %d)", field.offset, field.name, field.index,
+					field.index+1, field.count)
+			} else if field.typeName != "struct" {
+				text += " = " + field.value
+			}
+			objects := item.(*fyne.Container).Objects
+			badge := objects[1].(*widget.Icon)
+			switch field.checkResult {
+			case checkPassed:
+				badge.SetResource(theme.NewSuccessThemedResource(theme.ConfirmIcon()))
+			case checkFailed:
+				badge.SetResource(theme.NewErrorThemedResource(theme.CancelIcon()))
+				text += "  (" + field.checkMessage + ")"
+			default:
+				badge.SetResource(nil)
+			}
+			objects[0].(*widget.Label).SetText(text)
+		},
+	)
+	var selectedField widget.TreeNodeID
+	editBtn := widget.NewButton(lang.L("Edit Value..."), func() { h.showEditField(selectedField) })
+	editBtn.Disable()
+	h.structureTree.OnUnselected = func(widget.TreeNodeID) {
+		selectedField = ""
+		editBtn.Disable()
+	}
+	h.structureTree.OnSelected = func(uid widget.TreeNodeID) {
+		selectedField = uid
+		if array := h.templateFields.arrayContaining(uid); array != "" && array != h.recordArray {
+			h.recordArray = array
+			h.refreshPanels()
+		}
+		if field := h.templateFields.fieldAt(uid); field != nil && field.typeName != "struct" && field.size > 0 {
+			editBtn.Enable()
+		} else {
+			editBtn.Disable()
+		}
+		if field := h.templateFields.fieldAt(uid); field != nil && field.size > 0 {
+			h.setSelection(field.offset, field.offset+field.size)
+			h.goToOffset(field.offset)
+		}
+	}
+
+	loadBtn := widget.NewButton(lang.L("Load Template..."), h.showLoadTemplate)
+	libraryBtn := widget.NewButton(lang.L("Templates..."), h.showTemplateManager)
+	applyBtn := widget.NewButton(lang.L("Apply at Caret"), h.applyTemplateAtCaret)
+	copyJSONBtn := widget.NewButton(lang.L("Copy JSON"), func() { h.copyStructure("JSON") })
+	copyYAMLBtn := widget.NewButton(lang.L("Copy YAML"), func() { h.copyStructure("YAML") })
+	recordLabel := widget.NewLabel("")
+	previousBtn := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), h.previousRecord)
+	nextBtn := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), h.nextRecord)
+	goToRecordBtn := widget.NewButton(lang.L("Go to..."), h.showGoToRecord)
+	recordBar := container.NewHBox(previousBtn, nextBtn, goToRecordBtn, recordLabel)
+
+	refresh := func() {
+		if h.template == nil {
+			templateLabel.SetText(lang.L("No template loaded"))
+		} else {
+			templateLabel.SetText(lang.L("Template:") + " " + h.template.Name)
+		}
+		message := h.templateError
+		if passed, failed := h.templateFields.checkCounts(); passed+failed > 0 {
+			message = strings.TrimSpace(fmt.Sprintf("Checks: %d passed, %d failed\n%s", passed, failed, message))
+		}
+		messageLabel.SetText(message)
+		if _, array := h.currentArray(); array != nil {
+			recordLabel.SetText(fmt.Sprintf("%s: record %d of %d", array.name, min(array.index+1, array.count),
+				array.count))
+			recordBar.Show()
+		} else {
+			recordBar.Hide()
+		}
+	}
+
+	return panelContent{
+		object: container.NewBorder(
+			container.NewVBox(templateLabel, container.NewHBox(loadBtn, libraryBtn, applyBtn),
+				container.NewHBox(editBtn, copyJSONBtn, copyYAMLBtn), recordBar, messageLabel),
+			nil, nil, nil,
+			h.structureTree,
+		),
+		refresh: refresh,
+		reset: func() {
+			h.templateFields = nil
+			h.templateError = ""
+			h.recordIndexes, h.recordArray = nil, ""
+			h.structureChanged()
+		},
+	}
+}
+
+// showLoadTemplate asks for a structure template file and loads it
+func (h *HexDumpApp) showLoadTemplate() {
+	filename, err := nativedialog.File().Filter("Structure templates", "json").Load()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	template, err := loadTemplate(filename)
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	h.template = template
+	h.structureChanged()
+}
+
+// applyTemplateAtCaret decodes the loaded structure template at the caret and shows
+// the fields in the Structure panel
+func (h *HexDumpApp) applyTemplateAtCaret() {
+	if h.template == nil {
+		dialog.ShowInformation(lang.L("Structure"), lang.L("Load a structure template first."), h.window)
+		h.showPanel(panelStructure)
+		return
+	}
+
+	h.recordIndexes, h.recordArray = nil, ""
+	h.decodeTemplate(h.caret)
+	h.showPanel(panelStructure)
+	h.structureChanged()
+	h.structureTree.OpenAllBranches()
+}
+
+// decodeTemplate decodes the loaded structure template at offset into the fields the
+// Structure panel shows
+func (h *HexDumpApp) decodeTemplate(offset int) {
+	fields, err := h.template.apply(h.fileData, offset, h.recordIndexes)
+	h.templateFields = fields
+	h.templateError = ""
+	if err != nil {
+		h.templateError = err.Error()
+	}
+}
+
+// updateStructure decodes the applied structure template again after the data changed,
+// keeping the field selected in the Structure panel
+func (h *HexDumpApp) updateStructure() {
+	if h.template == nil || h.templateFields == nil {
+		return
+	}
+	h.decodeTemplate(h.templateFields.offset)
+	if h.structureTree != nil {
+		h.structureTree.Refresh()
+	}
+	h.updateFieldHighlights()
+}
+
+// structureChanged redraws the Structure panel and the field colors after the template
+// or its fields change
+func (h *HexDumpApp) structureChanged() {
+	if h.structureTree != nil {
+		h.structureTree.UnselectAll()
+		h.structureTree.Refresh()
+	}
+	h.updateFieldHighlights()
+	if h.dataList != nil {
+		h.dataList.Refresh()
+	}
+	h.refreshPanels()
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// checkResult is the outcome of the check expression of a template field
+type checkResult int
+
+const (
+	checkNone checkResult = iota // The field has no check
+	checkPassed
+	checkFailed
+)
+
+// exprNode is a node of a parsed template expression: a number, a field name, a function
+// call, or a unary or binary operator applied to its operands
+type exprNode struct {
+	kind     byte // 'n' number, 'f' field name, 'c' call, 'u' unary, 'b' binary
+	text     string
+	value    int64
+	operands []*exprNode
+	source   string // The text the node was parsed from, to explain failed checks
+}
+
+// exprFunctions are the functions usable in template expressions, with the number of
+// fields they take. Functions of bytes cover a field, or the range from the start of the
+// first field to the end of the second.
+var exprFunctions = map[string]struct{ min, max int }{
+	"crc32":   {1, 2},
+	"adler32": {1, 2},
+	"sum8":    {1, 2},
+	"size":    {1, 1},
+	"offset":  {1, 1},
+}
+
+// exprPrecedence gives the precedence of the binary operators, as in C
+var exprPrecedence = map[string]int{
+	"||": 1, "&&": 2,
+	"==": 3, "!=": 3, "<": 4, "<=": 4, ">": 4, ">=": 4,
+	"|": 5, "^": 6, "&": 7, "<<": 8, ">>": 8,
+	"+": 9, "-": 9, "*": 10, "/": 10, "%": 10,
+}
+
+// parseExpression parses a template expression such as "crc == crc32(body)". Expressions
+// use integers, field names, which may be dotted paths into structs, the functions of
+// exprFunctions, and the operators of C.
+func parseExpression(text string) (*exprNode, error) {
+	parser := &exprParser{text: text}
+	node, err := parser.binary(1)
+	if err == nil && parser.skipSpaces() < len(text) {
+		err = fmt.Errorf("unexpected %q", text[parser.pos:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", strings.TrimSpace(text), err)
+	}
+	return node, nil
+}
+
+// exprParser is a precedence-climbing parser for template expressions
+type exprParser struct {
+	text string
+	pos  int
+}
+
+// skipSpaces moves past white space and returns the new position
+func (p *exprParser) skipSpaces() int {
+	for p.pos < len(p.text) && (p.text[p.pos] == ' ' || p.text[p.pos] == '\t') {
+		p.pos++
+	}
+	return p.pos
+}
+
+// operator returns the binary operator at the current position, or ""
+func (p *exprParser) operator() string {
+	rest := p.text[p.skipSpaces():]
+	for _, length := range []int{2, 1} {
+		if len(rest) >= length {
+			if _, ok := exprPrecedence[rest[:length]]; ok {
+				return rest[:length]
+			}
+		}
+	}
+	return ""
+}
+
+// binary parses operands joined by binary operators of at least the given precedence
+func (p *exprParser) binary(precedence int) (*exprNode, error) {
+	start := p.skipSpaces()
+	left, err := p.unary()
+	for err == nil {
+		operator := p.operator()
+		if operator == "" || exprPrecedence[operator] < precedence {
+			break
+		}
+		p.pos += len(operator)
+		var right *exprNode
+		if right, err = p.binary(exprPrecedence[operator] + 1); err == nil {
+			left = &exprNode{kind: 'b', text: operator, operands: []*exprNode{left, right},
+				source: strings.TrimSpace(p.text[start:p.pos])}
+		}
+	}
+	return left, err
+}
+
+// unary parses an operand, possibly negated or complemented
+func (p *exprParser) unary() (*exprNode, error) {
+	start := p.skipSpaces()
+	if p.pos < len(p.text) && strings.IndexByte("-!~", p.text[p.pos]) >= 0 {
+		operator := p.text[p.pos : p.pos+1]
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{kind: 'u', text: operator, operands: []*exprNode{operand}, source: p.text[start:p.pos]}, nil
+	}
+	return p.operand()
+}
+
+// operand parses a number, a field name, a function call, or a parenthesized expression
+func (p *exprParser) operand() (*exprNode, error) {
+	start := p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return nil, fmt.Errorf("missing value")
+	}
+	if p.text[p.pos] == '(' {
+		p.pos++
+		node, err := p.binary(1)
+		if err == nil && (p.skipSpaces() >= len(p.text) || p.text[p.pos] != ')') {
+			err = fmt.Errorf("missing )")
+		}
+		p.pos++
+		return node, err
+	}
+
+	for p.pos < len(p.text) && isExprNameByte(p.text[p.pos]) {
+		p.pos++
+	}
+	token := p.text[start:p.pos]
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected %q", p.text[start:])
+	case token[0] >= '0' && token[0] <= '9':
+		value, err := strconv.ParseUint(token, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token)
+		}
+		return &exprNode{kind: 'n', value: int64(value), source: token}, nil
+	case p.skipSpaces() < len(p.text) && p.text[p.pos] == '(':
+		return p.call(token, start)
+	}
+	return &exprNode{kind: 'f', text: token, source: token}, nil
+}
+
+// call parses the field arguments of a call to the named function
+func (p *exprParser) call(name string, start int) (*exprNode, error) {
+	arity, ok := exprFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+	p.pos++
+	node := &exprNode{kind: 'c', text: name}
+	for {
+		argStart := p.skipSpaces()
+		for p.pos < len(p.text) && isExprNameByte(p.text[p.pos]) {
+			p.pos++
+		}
+		field := p.text[argStart:p.pos]
+		if field == "" || field[0] >= '0' && field[0] <= '9' {
+			return nil, fmt.Errorf("%s takes field names", name)
+		}
+		node.operands = append(node.operands, &exprNode{kind: 'f', text: field, source: field})
+		if p.skipSpaces() < len(p.text) && p.text[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos >= len(p.text) || p.text[p.pos] != ')' {
+			return nil, fmt.Errorf("missing ) after the arguments of %s", name)
+		}
+		p.pos++
+		break
+	}
+	if len(node.operands) < arity.min || len(node.operands) > arity.max {
+		return nil, fmt.Errorf("%s takes %d or %d fields", name, arity.min, arity.max)
+	}
+	node.source = p.text[start:p.pos]
+	return node, nil
+}
+
+// isExprNameByte reports whether b can be part of a number or a dotted field name
+func isExprNameByte(b byte) bool {
+	return b == '_' || b == '.' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// exprContext is what a template expression is evaluated against: the data, and the
+// structs enclosing the field it belongs to, innermost last, whose fields it can name
+type exprContext struct {
+	data   []byte
+	order  binary.ByteOrder
+	scopes []*parsedField
+}
+
+// resolve finds the field with a dotted name, looking it up in the innermost enclosing
+// struct that has it
+func (c *exprContext) resolve(name string) (*parsedField, error) {
+	parts := strings.Split(name, ".")
+	for _, scope := range slices.Backward(c.scopes) {
+		field := scope
+		for _, part := range parts {
+			index := slices.IndexFunc(field.children, func(child *parsedField) bool { return child.name == part })
+			if index < 0 {
+				field = nil
+				break
+			}
+			field = field.children[index]
+		}
+		if field != nil {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("no field %s", name)
+}
+
+// integer returns the value of a field as an integer
+func (c *exprContext) integer(field *parsedField) (int64, error) {
+	if field.typeName == "computed" {
+		if !field.computed {
+			return 0, fmt.Errorf("%s is not computed yet", field.name)
+		}
+		return field.computedValue, nil
+	}
+	data := c.data[field.offset : field.offset+field.size]
+	switch field.typeName {
+	case "u8":
+		return int64(data[0]), nil
+	case "u16":
+		return int64(c.order.Uint16(data)), nil
+	case "u32":
+		return int64(c.order.Uint32(data)), nil
+	case "u64":
+		return int64(c.order.Uint64(data)), nil
+	case "i8":
+		return int64(int8(data[0])), nil
+	case "i16":
+		return int64(int16(c.order.Uint16(data))), nil
+	case "i32":
+		return int64(int32(c.order.Uint32(data))), nil
+	case "i64":
+		return int64(c.order.Uint64(data)), nil
+	case "f32":
+		return int64(math.Float32frombits(c.order.Uint32(data))), nil
+	case "f64":
+		return int64(math.Float64frombits(c.order.Uint64(data))), nil
+	}
+	return 0, fmt.Errorf("%s is a %s, not a number", field.name, field.typeName)
+}
+
+// evaluate computes the value of an expression; comparisons and logical operators give
+// 1 for true and 0 for false
+func (c *exprContext) evaluate(node *exprNode) (int64, error) {
+	switch node.kind {
+	case 'n':
+		return node.value, nil
+	case 'f':
+		field, err := c.resolve(node.text)
+		if err != nil {
+			return 0, err
+		}
+		return c.integer(field)
+	case 'c':
+		return c.call(node)
+	case 'u':
+		value, err := c.evaluate(node.operands[0])
+		switch node.text {
+		case "-":
+			value = -value
+		case "~":
+			value = ^value
+		default:
+			value = boolValue(value == 0)
+		}
+		return value, err
+	}
+
+	left, err := c.evaluate(node.operands[0])
+	if err != nil {
+		return 0, err
+	}
+	// && and || don't evaluate their right operand when the left one decides
+	if node.text == "&&" && left == 0 || node.text == "||" && left != 0 {
+		return boolValue(left != 0), nil
+	}
+	right, err := c.evaluate(node.operands[1])
+	if err != nil {
+		return 0, err
+	}
+	switch node.text {
+	case "&&", "||":
+		return boolValue(right != 0), nil
+	case "==":
+		return boolValue(left == right), nil
+	case "!=":
+		return boolValue(left != right), nil
+	case "<":
+		return boolValue(left < right), nil
+	case "<=":
+		return boolValue(left <= right), nil
+	case ">":
+		return boolValue(left > right), nil
+	case ">=":
+		return boolValue(left >= right), nil
+	case "|":
+		return left | right, nil
+	case "^":
+		return left ^ right, nil
+	case "&":
+		return left & right, nil
+	case "<<":
+		return left << (right & 63), nil
+	case ">>":
+		return left >> (right & 63), nil
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	}
+	if right == 0 {
+		return 0, fmt.Errorf("division by zero in %s", node.source)
+	}
+	if node.text == "/" {
+		return left / right, nil
+	}
+	return left % right, nil
+}
+
+// call computes a function of the fields named by its arguments
+func (c *exprContext) call(node *exprNode) (int64, error) {
+	var fields []*parsedField
+	for _, operand := range node.operands {
+		field, err := c.resolve(operand.text)
+		if err != nil {
+			return 0, err
+		}
+		fields = append(fields, field)
+	}
+	start, end := fields[0].offset, fields[len(fields)-1].offset+fields[len(fields)-1].size
+	if end < start {
+		return 0, fmt.Errorf("%s ends before %s starts", node.operands[1].text, node.operands[0].text)
+	}
+
+	switch node.text {
+	case "size":
+		return int64(fields[0].size), nil
+	case "offset":
+		return int64(fields[0].offset), nil
+	case "crc32":
+		return int64(crc32.ChecksumIEEE(c.data[start:end])), nil
+	case "adler32":
+		return int64(adler32.Checksum(c.data[start:end])), nil
+	}
+	var sum byte
+	for _, b := range c.data[start:end] {
+		sum += b
+	}
+	return int64(sum), nil
+}
+
+// boolValue converts a truth value to 1 or 0
+func boolValue(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// formatExprValue formats the value of an expression, in hex unless it is small or negative
+func formatExprValue(value int64) string {
+	if value < 10 {
+		return strconv.FormatInt(value, 10)
+	}
+	return fmt.Sprintf("0x%X", value)
+}
+
+// checkFailure explains why a check failed: for a comparison, the values it compared
+func (c *exprContext) checkFailure(node *exprNode) string {
+	if node.kind == 'b' && exprPrecedence[node.text] >= 3 && exprPrecedence[node.text] <= 4 {
+		left, leftErr := c.evaluate(node.operands[0])
+		right, rightErr := c.evaluate(node.operands[1])
+		if leftErr == nil && rightErr == nil {
+			return fmt.Sprintf("%s is %s, %s is %s", node.operands[0].source, formatExprValue(left),
+				node.operands[1].source, formatExprValue(right))
+		}
+	}
+	return node.source + " is false"
+}
+
+// evaluateFields computes the computed fields below parent and runs the checks of its
+// fields, in template order, so computed fields can use the computed fields before them
+func evaluateFields(parent *parsedField, scopes []*parsedField, data []byte, order binary.ByteOrder) {
+	scopes = append(scopes, parent)
+	context := &exprContext{data: data, order: order, scopes: scopes}
+	for _, field := range parent.children {
+		if field.typeName == "computed" {
+			field.computed = false
+			node, err := parseExpression(field.expression)
+			var value int64
+			if err == nil {
+				value, err = context.evaluate(node)
+			}
+			if err != nil {
+				field.value = "error: " + err.Error()
+			} else {
+				field.computed, field.computedValue = true, value
+				field.value = formatExprValue(value)
+			}
+		}
+		if field.typeName == "struct" {
+			evaluateFields(field, scopes, data, order)
+		}
+		if field.check == "" {
+			continue
+		}
+		node, err := parseExpression(field.check)
+		var value int64
+		if err == nil {
+			value, err = context.evaluate(node)
+		}
+		switch {
+		case err != nil:
+			field.checkResult, field.checkMessage = checkFailed, err.Error()
+		case value == 0:
+			field.checkResult, field.checkMessage = checkFailed, context.checkFailure(node)
+		default:
+			field.checkResult, field.checkMessage = checkPassed, ""
+		}
+	}
+}
+
+// checkCounts counts the checks below field that passed and failed
+func (field *parsedField) checkCounts() (passed, failed int) {
+	if field == nil {
+		return 0, 0
+	}
+	for _, child := range field.children {
+		switch child.checkResult {
+		case checkPassed:
+			passed++
+		case checkFailed:
+			failed++
+		}
+		childPassed, childFailed := child.checkCounts()
+		passed, failed = passed+childPassed, failed+childFailed
+	}
+	return passed, failed
+}
+
+// exprText is an expression in a template, which can also be written as a JSON number
+type exprText string
+
+// UnmarshalJSON accepts a string or a number
+func (e *exprText) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err == nil {
+		*e = exprText(number)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("an expression must be a string or a number")
+	}
+	*e = exprText(text)
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// templatesDirName is the name of the template library directories, both under the
+// settings directory and beside the executable for the bundled standard templates
+const templatesDirName = "templates"
+
+// libraryTemplate is a structure template file in the template library
+type libraryTemplate struct {
+	path     string
+	bundled  bool // True for the standard templates shipped beside the executable
+	template *structTemplate
+}
+
+// userTemplatesDir returns the directory holding the user's imported templates
+func userTemplatesDir() (string, error) {
+	dir, err := settingsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, templatesDirName), nil
+}
+
+// bundledTemplatesDir returns the directory of standard templates beside the executable,
+// or "" if the executable cannot be located
+func bundledTemplatesDir() string {
+	executable, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(executable), templatesDirName)
+}
+
+// scanTemplates loads the templates in dir, returning them and the problems found with
+// files that are not valid templates. A missing directory holds no templates.
+func scanTemplates(dir string, bundled bool) ([]libraryTemplate, []string) {
+	if dir == "" {
+		return nil, nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+
+	var templates []libraryTemplate
+	var problems []string
+	for _, path := range paths {
+		template, err := loadTemplate(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+			continue
+		}
+		templates = append(templates, libraryTemplate{path: path, bundled: bundled, template: template})
+	}
+	return templates, problems
+}
+
+// scanTemplateLibrary reloads the template library from the bundled and user template
+// directories, sorted by name
+func (h *HexDumpApp) scanTemplateLibrary() {
+	h.templateLibrary, h.templateProblems = scanTemplates(bundledTemplatesDir(), true)
+	if dir, err := userTemplatesDir(); err == nil {
+		templates, problems := scanTemplates(dir, false)
+		h.templateLibrary = append(h.templateLibrary, templates...)
+		h.templateProblems = append(h.templateProblems, problems...)
+	}
+	sort.SliceStable(h.templateLibrary, func(i, j int) bool {
+		return strings.ToLower(h.templateLibrary[i].template.Name) < strings.ToLower(h.templateLibrary[j].template.Name)
+	})
+}
+
+// importTemplate checks the template file at path and copies it into the user's
+// template directory
+func importTemplate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := parseTemplate(data); err != nil {
+		return err
+	}
+
+	dir, err := userTemplatesDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	target := filepath.Join(dir, filepath.Base(path))
+	if !strings.EqualFold(filepath.Ext(target), ".json") {
+		target += ".json"
+	}
+	return os.WriteFile(target, data, 0644)
+}
+
+// showTemplateManager lists the templates in the library, for using them in the
+// Structure panel, importing and exporting template files, and deleting imported ones
+func (h *HexDumpApp) showTemplateManager() {
+	window := h.app.NewWindow(lang.L("Template Manager"))
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(h.templateLibrary) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := h.templateLibrary[id]
+			text := fmt.Sprintf("%s (%s)", entry.template.Name, filepath.Base(entry.path))
+			if entry.bundled {
+				text += " - standard"
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	problemsLabel := widget.NewLabel("")
+	problemsLabel.Wrapping = fyne.TextWrapWord
+	rescan := func() {
+		h.scanTemplateLibrary()
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+		problemsLabel.SetText(strings.Join(h.templateProblems, "\n"))
+	}
+	rescan()
+
+	useBtn := widget.NewButton(lang.L("Use"), func() {
+		if selected < 0 {
+			return
+		}
+		h.template = h.templateLibrary[selected].template
+		h.templateFields = nil
+		h.templateError = ""
+		h.showPanel(panelStructure)
+		h.structureChanged()
+	})
+	importBtn := widget.NewButton(lang.L("Import..."), func() {
+		filename, err := nativedialog.File().Filter("Structure templates", "json").Title("Import Template").Load()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, window)
+			}
+			return
+		}
+		if err := importTemplate(filename); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		rescan()
+	})
+	exportBtn := widget.NewButton(lang.L("Export..."), func() {
+		if selected < 0 {
+			return
+		}
+		entry := h.templateLibrary[selected]
+		filename, err := nativedialog.File().Filter("Structure templates", "json").Title("Export Template").
+			SetStartFile(filepath.Base(entry.path)).Save()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, window)
+			}
+			return
+		}
+		data, err := os.ReadFile(entry.path)
+		if err == nil {
+			err = os.WriteFile(filename, data, 0644)
+		}
+		if err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+	deleteBtn := widget.NewButton(lang.L("Delete"), func() {
+		if selected < 0 {
+			return
+		}
+		entry := h.templateLibrary[selected]
+		if entry.bundled {
+			dialog.ShowInformation(lang.L("Template Manager"), lang.L("Standard templates cannot be deleted."), window)
+			return
+		}
+		dialog.ShowConfirm(lang.L("Delete Template"), fmt.Sprintf("Delete the template %q?", entry.template.Name),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := os.Remove(entry.path); err != nil {
+					dialog.ShowError(err, window)
+				}
+				rescan()
+			}, window)
+	})
+
+	window.SetContent(container.NewBorder(
+		nil,
+		container.NewVBox(problemsLabel, container.NewHBox(useBtn, importBtn, exportBtn, deleteBtn)),
+		nil, nil,
+		list,
+	))
+	window.Resize(fyne.NewSize(450, 400))
+	window.Show()
+}
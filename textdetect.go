@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// textSampleSize is how much of a file textSummary examines, so that a file of many
+// gigabytes isn't read whole when it is opened and after every edit
+const textSampleSize = 64 << 20
+
+// textSummary describes whether data looks like text and how its lines end, such as
+// "Text (UTF-8, CRLF)" or "Binary (1234 null bytes)". Many files reported as binary
+// are text in an unexpected encoding or with unexpected line endings. Only the first
+// textSampleSize bytes are examined, cut at the start of a UTF-8 character.
+func textSummary(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if len(data) > textSampleSize {
+		end := textSampleSize
+		for end > textSampleSize-utf8.UTFMax && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		data = data[:end]
+	}
+
+	// UTF-16 text is mostly null bytes, so its code units are examined instead
+	encoding, unit, bigEndian := "", 1, false
+	switch {
+	case len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF:
+		encoding = "UTF-8 with BOM"
+	case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+		encoding, unit = "UTF-16LE", 2
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		encoding, unit, bigEndian = "UTF-16BE", 2, true
+	}
+
+	nulls, controls, crlf, lf, cr := 0, 0, 0, 0, 0
+	count := len(data) / unit
+	char := func(i int) int {
+		if unit == 1 {
+			return int(data[i])
+		}
+		if bigEndian {
+			return int(data[2*i])<<8 | int(data[2*i+1])
+		}
+		return int(data[2*i+1])<<8 | int(data[2*i])
+	}
+	for i := 0; i < count; i++ {
+		switch c := char(i); {
+		case c == 0:
+			nulls++
+		case c == '\r':
+			if i+1 < count && char(i+1) == '\n' {
+				crlf++
+				i++
+			} else {
+				cr++
+			}
+		case c == '\n':
+			lf++
+		case c < 0x20 && c != '\t' && c != '\f' && c != '\v' && c != 0x1B, c == 0x7F:
+			controls++
+		}
+	}
+
+	// Text may have a few stray null or control bytes, but no more than 1 in 100
+	if (nulls+controls)*100 > count {
+		return fmt.Sprintf("Binary (%d null bytes)", nulls)
+	}
+
+	if encoding == "" {
+		switch {
+		case utf8.Valid(data) && !isASCII(data):
+			encoding = "UTF-8"
+		case utf8.Valid(data):
+			encoding = "ASCII"
+		default:
+			encoding = "8-bit"
+		}
+	}
+	details := []string{encoding}
+
+	var endings []string
+	for _, ending := range []struct {
+		name  string
+		count int
+	}{{"CRLF", crlf}, {"LF", lf}, {"CR", cr}} {
+		if ending.count > 0 {
+			endings = append(endings, ending.name)
+		}
+	}
+	switch len(endings) {
+	case 0:
+		details = append(details, "no line breaks")
+	case 1:
+		details = append(details, endings[0])
+	default:
+		details = append(details, fmt.Sprintf("mixed CRLF %d, LF %d, CR %d", crlf, lf, cr))
+	}
+	if nulls > 0 {
+		details = append(details, fmt.Sprintf("%d null bytes", nulls))
+	}
+	return "Text (" + strings.Join(details, ", ") + ")"
+}
+
+// isASCII reports whether data contains only 7-bit bytes
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// textPreviewSizes lists the amounts of data the Text Preview panel can show
+var textPreviewSizes = []string{"4 KB", "16 KB", "64 KB"}
+
+// decodeText decodes data under the given encoding as free-flowing text. Line breaks
+// and tabs are kept, and other control characters and invalid bytes become the fallback
+// character.
+func decodeText(data []byte, encoding string) string {
+	var builder strings.Builder
+	for index := 0; index < len(data); {
+		r, size := decodeRune(data[index:], encoding)
+		index += size
+		switch {
+		case r == '\n' || r == '\t':
+			builder.WriteRune(r)
+		case r == '\r':
+			// Dropped, so that CRLF line breaks become single breaks
+		case r == utf8.RuneError || !unicode.IsPrint(r):
+			builder.WriteString(fallbackText(encoding, r))
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// createTextPreviewPanel creates the Text Preview side panel, which shows the data
+// around the caret decoded as wrapped text in the selected encoding
+func (h *HexDumpApp) createTextPreviewPanel() panelContent {
+	rangeLabel := widget.NewLabel("")
+	textLabel := widget.NewLabel("")
+	textLabel.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(textLabel)
+
+	// The preview is only decoded again when its range or encoding changes
+	shownStart, shownEnd, shownEncoding := -1, -1, ""
+	size := 4096
+	refresh := func() {
+		if len(h.fileData) == 0 {
+			rangeLabel.SetText(lang.L("No file loaded"))
+			textLabel.SetText("")
+			shownStart = -1
+			return
+		}
+
+		// Start a quarter of the way before the caret, aligned for UTF-16
+		start := max(0, h.caret-size/4) &^ 1
+		end := min(start+size, len(h.fileData))
+		if start == shownStart && end == shownEnd && h.encoding == shownEncoding {
+			return
+		}
+		shownStart, shownEnd, shownEncoding = start, end, h.encoding
+
+		rangeLabel.SetText(fmt.Sprintf("%08X-%08X (%s)", start, end-1, h.encoding))
+		textLabel.SetText(decodeText(h.fileData[start:end], h.encoding))
+		scroll.ScrollToTop()
+	}
+
+	sizeSelect := widget.NewSelect(textPreviewSizes, func(value string) {
+		switch value {
+		case "16 KB":
+			size = 16384
+		case "64 KB":
+			size = 65536
+		default:
+			size = 4096
+		}
+		shownStart = -1
+		refresh()
+	})
+	sizeSelect.SetSelected(textPreviewSizes[0])
+
+	return panelContent{
+		object:  container.NewBorder(container.NewBorder(nil, nil, nil, sizeSelect, rangeLabel), nil, nil, nil, scroll),
+		refresh: refresh,
+		reset:   func() { shownStart = -1 },
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tlvValueColors are the theme colors of the values of alternate TLV entries, so that
+// neighbouring entries can be told apart
+var tlvValueColors = []fyne.ThemeColorName{colorNameTLVValue, colorNameTLVValueAlt}
+
+// Sizes of TLV tags and lengths, in bytes or as BER encodes them
+const (
+	tlvSize1   = "1 byte"
+	tlvSize2   = "2 bytes"
+	tlvSize4   = "4 bytes"
+	tlvSizeBER = "BER (ASN.1)"
+)
+
+// How the TLV walker finds entries nested in the values of others
+const (
+	tlvNestNone        = "None"
+	tlvNestListed      = "Listed tags"
+	tlvNestConstructed = "BER constructed tags"
+	tlvNestAny         = "Any value that parses as TLV"
+)
+
+// maxTLVEntries is the largest number of entries the TLV walker reports
+const maxTLVEntries = 100000
+
+// maxTLVDepth limits how deeply the TLV walker nests entries
+const maxTLVDepth = 16
+
+// tlvFormat describes a tag-length-value encoding
+type tlvFormat struct {
+	tagSize, lengthSize string
+	order               binary.ByteOrder
+	// Whether the length counts the tag and length as well as the value
+	inclusive  bool
+	nesting    string
+	nestedTags map[uint64]bool
+}
+
+// tlvEntry is an entry at offset with a header of headerSize bytes holding its tag and
+// length, followed by length bytes of value, depth levels inside other entries
+type tlvEntry struct {
+	offset, headerSize, length int
+	tag                        uint64
+	depth                      int
+	nested                     bool
+}
+
+// end returns the offset just past the entry's value
+func (entry tlvEntry) end() int {
+	return entry.offset + entry.headerSize + entry.length
+}
+
+// readTLVNumber reads a tag or length of the given size from the start of data,
+// returning it and the number of bytes it takes
+func readTLVNumber(data []byte, size string, order binary.ByteOrder, isTag bool) (uint64, int, bool) {
+	switch size {
+	case tlvSize1:
+		if len(data) >= 1 {
+			return uint64(data[0]), 1, true
+		}
+	case tlvSize2:
+		if len(data) >= 2 {
+			return uint64(order.Uint16(data)), 2, true
+		}
+	case tlvSize4:
+		if len(data) >= 4 {
+			return uint64(order.Uint32(data)), 4, true
+		}
+	case tlvSizeBER:
+		if len(data) == 0 {
+			return 0, 0, false
+		}
+		if isTag {
+			// Tag numbers of 31 and up continue in the bytes with the high bit set;
+			// the tag is kept as its bytes, as usually written
+			size := 1
+			if data[0]&0x1F == 0x1F {
+				for size < len(data) && size <= 4 && data[size]&0x80 != 0 {
+					size++
+				}
+				size++
+			}
+			if size > len(data) || size > 8 {
+				return 0, 0, false
+			}
+			var tag uint64
+			for _, b := range data[:size] {
+				tag = tag<<8 | uint64(b)
+			}
+			return tag, size, true
+		}
+		// Short lengths are one byte; long ones give the number of bytes that follow.
+		// Indefinite lengths (0x80) aren't supported.
+		if data[0] < 0x80 {
+			return uint64(data[0]), 1, true
+		}
+		count := int(data[0] & 0x7F)
+		if count == 0 || count > 4 || 1+count > len(data) {
+			return 0, 0, false
+		}
+		var length uint64
+		for _, b := range data[1 : 1+count] {
+			length = length<<8 | uint64(b)
+		}
+		return length, 1 + count, true
+	}
+	return 0, 0, false
+}
+
+// walkTLV splits data[start:end] into TLV entries, in order of offset, with the entries
+// nested in them following each one. It stops at the first entry that runs past end,
+// returning the offset at which it stopped, or end if the entries fill the range.
+func walkTLV(data []byte, start, end, depth int, format tlvFormat, entries *[]tlvEntry) int {
+	offset := start
+	for offset < end && len(*entries) < maxTLVEntries {
+		tag, tagSize, ok := readTLVNumber(data[offset:end], format.tagSize, format.order, true)
+		if !ok {
+			return offset
+		}
+		length, lengthSize, ok := readTLVNumber(data[offset+tagSize:end], format.lengthSize, format.order, false)
+		if !ok {
+			return offset
+		}
+		headerSize := tagSize + lengthSize
+		if format.inclusive {
+			if length < uint64(headerSize) {
+				return offset
+			}
+			length -= uint64(headerSize)
+		}
+		if length > uint64(end-offset-headerSize) {
+			return offset
+		}
+		entry := tlvEntry{offset: offset, headerSize: headerSize, length: int(length), tag: tag, depth: depth}
+		index := len(*entries)
+		*entries = append(*entries, entry)
+
+		if depth < maxTLVDepth && entry.length > 0 && format.nests(data, entry) {
+			// A value that doesn't parse completely as TLV isn't nested after all
+			if walkTLV(data, entry.offset+headerSize, entry.end(), depth+1, format, entries) == entry.end() {
+				(*entries)[index].nested = true
+			} else {
+				*entries = (*entries)[:index+1]
+			}
+		}
+		offset = entry.end()
+	}
+	return offset
+}
+
+// nests reports whether the value of entry may hold nested entries
+func (format tlvFormat) nests(data []byte, entry tlvEntry) bool {
+	switch format.nesting {
+	case tlvNestListed:
+		return format.nestedTags[entry.tag]
+	case tlvNestConstructed:
+		return data[entry.offset]&0x20 != 0
+	case tlvNestAny:
+		return true
+	}
+	return false
+}
+
+// showTLVWalker asks for the TLV encoding and walks the selection, or the whole file when
+// nothing is selected, listing and highlighting its entries
+func (h *HexDumpApp) showTLVWalker() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("TLV Walker"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	sizes := []string{tlvSize1, tlvSize2, tlvSize4, tlvSizeBER}
+	tagSelect := widget.NewSelect(sizes, nil)
+	tagSelect.SetSelected(tlvSize1)
+	lengthSelect := widget.NewSelect(sizes, nil)
+	lengthSelect.SetSelected(tlvSize1)
+	orderSelect := widget.NewSelect([]string{"Little endian", "Big endian"}, nil)
+	orderSelect.SetSelectedIndex(0)
+	if h.bigEndian {
+		orderSelect.SetSelectedIndex(1)
+	}
+	inclusiveCheck := widget.NewCheck(lang.L("The length includes the tag and length"), nil)
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder(lang.L("e.g. 30, 31, A0"))
+	tagsEntry.Disable()
+	nestingSelect := widget.NewSelect([]string{tlvNestNone, tlvNestListed, tlvNestConstructed, tlvNestAny},
+		func(value string) {
+			if value == tlvNestListed {
+				tagsEntry.Enable()
+			} else {
+				tagsEntry.Disable()
+			}
+		})
+	nestingSelect.SetSelected(tlvNestNone)
+
+	tagsItem := widget.NewFormItem(lang.L("Nested tags"), tagsEntry)
+	tagsItem.HintText = "Hex tags whose values hold entries, for Listed tags"
+	dialog.ShowForm(lang.L("TLV Walker"), lang.L("Walk"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Tag size"), tagSelect),
+		widget.NewFormItem(lang.L("Length size"), lengthSelect),
+		widget.NewFormItem(lang.L("Byte order"), orderSelect),
+		widget.NewFormItem("", inclusiveCheck),
+		widget.NewFormItem(lang.L("Nesting"), nestingSelect),
+		tagsItem,
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		format := tlvFormat{tagSize: tagSelect.Selected, lengthSize: lengthSelect.Selected,
+			order: binary.LittleEndian, inclusive: inclusiveCheck.Checked, nesting: nestingSelect.Selected,
+			nestedTags: map[uint64]bool{}}
+		if orderSelect.SelectedIndex() == 1 {
+			format.order = binary.BigEndian
+		}
+		if format.nesting == tlvNestListed {
+			for _, field := range strings.FieldsFunc(tagsEntry.Text, func(r rune) bool { return r == ',' || r == ' ' }) {
+				tag, err := parseOffset("0x" + strings.TrimPrefix(strings.ToLower(field), "0x"))
+				if err != nil || tag < 0 {
+					dialog.ShowError(fmt.Errorf("%q is not a hex tag", field), h.window)
+					return
+				}
+				format.nestedTags[uint64(tag)] = true
+			}
+		}
+
+		start, end := 0, len(h.fileData)
+		if h.hasSelection() {
+			start, end = h.selStart, h.selEnd
+		}
+		var entries []tlvEntry
+		stopped := walkTLV(h.fileData, start, end, 0, format, &entries)
+		h.tlvEntries = entries
+		h.updateDisplay()
+		h.updateStatus()
+		h.showTLVList(start, end, stopped)
+	}, h.window)
+}
+
+// clearTLV removes the entries found by the TLV walker
+func (h *HexDumpApp) clearTLV() {
+	h.tlvEntries = nil
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// tlvAt returns the innermost TLV entry that covers offset, or nil. Nested entries
+// follow their parent, so it's the last one starting at or before offset that covers it.
+func (h *HexDumpApp) tlvAt(offset int) *tlvEntry {
+	index := sort.Search(len(h.tlvEntries), func(i int) bool { return h.tlvEntries[i].offset > offset })
+	for index--; index >= 0; index-- {
+		entry := &h.tlvEntries[index]
+		if offset < entry.end() {
+			return entry
+		}
+		if entry.depth == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// tlvSpans returns highlight spans for the TLV entries intersecting [lineStart, lineEnd):
+// headers in one color, and the values of entries without nested ones alternating
+// between two others
+func (h *HexDumpApp) tlvSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	first := sort.Search(len(h.tlvEntries), func(i int) bool { return h.tlvEntries[i].end() > lineStart })
+	for index := first; index < len(h.tlvEntries); index++ {
+		entry := h.tlvEntries[index]
+		if entry.offset >= lineEnd {
+			break
+		}
+		valueStart := entry.offset + entry.headerSize
+		if start, end := max(entry.offset, lineStart), min(valueStart, lineEnd); start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(colorNameTLVHeader)})
+		}
+		if start, end := max(valueStart, lineStart), min(entry.end(), lineEnd); start < end && !entry.nested {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(tlvValueColors[index%2])})
+		}
+	}
+	return spans
+}
+
+// tlvStatus describes the TLV entry at the caret for the status bar
+func (h *HexDumpApp) tlvStatus() string {
+	if entry := h.tlvAt(h.caret); entry != nil {
+		return fmt.Sprintf("TLV tag %X, length %d", entry.tag, entry.length)
+	}
+	return ""
+}
+
+// showTLVList lists the entries found by the TLV walker in [start, end), indented by
+// nesting, and reports where the walk stopped if the entries don't fill the range.
+// Selecting an entry selects it.
+func (h *HexDumpApp) showTLVList(start, end, stopped int) {
+	entries := h.tlvEntries
+	list := widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := entries[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %stag %X  length %d", entry.offset,
+				strings.Repeat("   ", entry.depth), entry.tag, entry.length))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		entry := entries[id]
+		h.setSelection(entry.offset, entry.end())
+		h.goToOffset(entry.offset)
+	}
+
+	summary := fmt.Sprintf("%d entries in %s-%s", len(entries), formatHex(uint64(start), 8),
+		formatHex(uint64(end-1), 8))
+	switch {
+	case len(entries) == maxTLVEntries:
+		summary = fmt.Sprintf("The first %d entries in %s-%s", maxTLVEntries, formatHex(uint64(start), 8),
+			formatHex(uint64(end-1), 8))
+	case stopped < end:
+		summary += fmt.Sprintf("\nStopped at %s: no entry fits in the %d bytes left", formatHex(uint64(stopped), 8),
+			end-stopped)
+	}
+	window := h.app.NewWindow(fmt.Sprintf("TLV Entries - %s", h.fileName))
+	window.SetContent(container.NewBorder(widget.NewLabel(summary), nil, nil, nil, list))
+	window.Resize(fyne.NewSize(450, 450))
+	window.Show()
+}
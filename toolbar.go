@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// byteOrderNames lists the byte orders of the byte order selector
+var byteOrderNames = []string{"Little-endian", "Big-endian"}
+
+// toolbarItem is a control that can be placed on the toolbar
+type toolbarItem struct {
+	id     string        // Identifies the item in the settings
+	label  string        // Describes the item in the Customize Toolbar dialog
+	icon   fyne.Resource // Replaces the button text when the toolbar is compact
+	create func(h *HexDumpApp) []fyne.CanvasObject
+
+	// overflow creates the item's entry in the overflow menu, for when the window is too
+	// narrow to show it
+	overflow func(h *HexDumpApp) *fyne.MenuItem
+}
+
+// toolbarItems lists every control that can be placed on the toolbar
+var toolbarItems = []toolbarItem{
+	{"open", "Open File", theme.FolderOpenIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Open File..."), h.openFile)}
+	}, commandOverflow("open")},
+	{"save", "Save As", theme.DocumentSaveIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Save As..."), h.saveFileAs)}
+	}, commandOverflow("saveAs")},
+	{"search", "Search", theme.SearchIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Search"), func() { h.showPanel(panelSearch) })}
+	}, commandOverflow("searchPanel")},
+	{"goto", "Go To", theme.NavigateNextIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Go To..."), h.showGoTo)}
+	}, commandOverflow("goTo")},
+	{"jump", "Jump to Offset", nil, func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{h.createJumpEntry()}
+	}, commandOverflow("goTo")},
+	{"grouping", "Byte Grouping", nil, func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewLabel(lang.L("Byte Grouping:")), h.byteGroupSelect}
+	}, selectOverflow("Byte Grouping", func(h *HexDumpApp) *widget.Select { return h.byteGroupSelect })},
+	{"encoding", "Encoding", nil, func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewLabel(lang.L("Encoding:")), h.encodingSelect}
+	}, selectOverflow("Encoding", func(h *HexDumpApp) *widget.Select { return h.encodingSelect })},
+	{"endianness", "Byte Order", nil, func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewLabel(lang.L("Byte Order:")), h.byteOrderSelect}
+	}, selectOverflow("Byte Order", func(h *HexDumpApp) *widget.Select { return h.byteOrderSelect })},
+	{"panel", "Side Panel", theme.ViewRestoreIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Side Panel"), h.togglePanels)}
+	}, commandOverflow("sidePanel")},
+	{"strings", "Strings", theme.FileTextIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Strings"), func() { h.showPanel(panelStrings) })}
+	}, commandOverflow("stringsPanel")},
+	{"checksums", "Checksums", theme.ConfirmIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Checksums"), func() { h.showPanel(panelChecksums) })}
+	}, commandOverflow("checksumsPanel")},
+	{"visualize", "Binary Visualization", theme.ColorPaletteIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Visualize"), h.showVisualization)}
+	}, commandOverflow("visualization")},
+	{"bytepairs", "Byte Pair Statistics", theme.GridIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Byte Pairs"), h.showNgramView)}
+	}, commandOverflow("bytePairs")},
+	{"duplicates", "Find Duplicate Regions", theme.ContentCopyIcon(), func(h *HexDumpApp) []fyne.CanvasObject {
+		return []fyne.CanvasObject{widget.NewButton(lang.L("Duplicates"), h.showDuplicatesDialog)}
+	}, commandOverflow("findDuplicates")},
+}
+
+// defaultToolbar lists the IDs of the toolbar items shown when none are configured
+var defaultToolbar = []string{"open", "jump", "grouping", "encoding"}
+
+// toolbarItemByID returns the toolbar item with the given ID, or nil
+func toolbarItemByID(id string) *toolbarItem {
+	for index := range toolbarItems {
+		if toolbarItems[index].id == id {
+			return &toolbarItems[index]
+		}
+	}
+	return nil
+}
+
+// commandOverflow returns an overflow menu entry running the command with the given ID
+func commandOverflow(id string) func(h *HexDumpApp) *fyne.MenuItem {
+	return func(h *HexDumpApp) *fyne.MenuItem { return h.commandItem(id) }
+}
+
+// selectOverflow returns an overflow menu entry with a submenu of the options of the
+// selector returned by get, the selected one checked
+func selectOverflow(label string, get func(h *HexDumpApp) *widget.Select) func(h *HexDumpApp) *fyne.MenuItem {
+	return func(h *HexDumpApp) *fyne.MenuItem {
+		selector := get(h)
+		var options []*fyne.MenuItem
+		for _, option := range selector.Options {
+			optionItem := fyne.NewMenuItem(option, func() { selector.SetSelected(option) })
+			optionItem.Checked = option == selector.Selected
+			options = append(options, optionItem)
+		}
+		item := fyne.NewMenuItem(lang.L(label), nil)
+		item.ChildMenu = fyne.NewMenu("", options...)
+		return item
+	}
+}
+
+// toolbarGroup holds the controls of one toolbar item, after a separator unless it is
+// the first. In compact form buttons show their icon instead of their text and the
+// labels of selectors are left out.
+type toolbarGroup struct {
+	*fyne.Container
+	item    *toolbarItem
+	full    []fyne.CanvasObject
+	small   []fyne.CanvasObject
+	compact bool
+}
+
+// newToolbarGroup creates the controls of a toolbar item
+func newToolbarGroup(h *HexDumpApp, item *toolbarItem, separated bool) *toolbarGroup {
+	g := &toolbarGroup{item: item}
+	if separated {
+		separator := widget.NewSeparator()
+		g.full = append(g.full, separator)
+		g.small = append(g.small, separator)
+	}
+	for _, object := range item.create(h) {
+		g.full = append(g.full, object)
+		switch control := object.(type) {
+		case *widget.Label:
+			// Selectors show their value, which says what they choose
+		case *widget.Button:
+			if item.icon != nil {
+				g.small = append(g.small, widget.NewButtonWithIcon("", item.icon, control.OnTapped))
+			} else {
+				g.small = append(g.small, control)
+			}
+		default:
+			g.small = append(g.small, object)
+		}
+	}
+	g.Container = container.NewHBox(g.full...)
+	return g
+}
+
+// setCompact switches the group between its full and compact form
+func (g *toolbarGroup) setCompact(compact bool) {
+	if g.compact == compact {
+		return
+	}
+	g.compact = compact
+	if compact {
+		g.Objects = g.small
+	} else {
+		g.Objects = g.full
+	}
+	g.Refresh()
+}
+
+// toolbarLayout lays out the toolbar groups in a row. When they don't fit, they are made
+// compact, and those that still don't fit are moved to the overflow menu, whose button
+// then shows at the right end.
+type toolbarLayout struct {
+	h *HexDumpApp
+}
+
+// Layout implements fyne.Layout
+func (l *toolbarLayout) Layout(_ []fyne.CanvasObject, size fyne.Size) {
+	h := l.h
+	padding := theme.Padding()
+	width := func() float32 {
+		total := float32(0)
+		for _, g := range h.toolbarGroups {
+			total += g.MinSize().Width + padding
+		}
+		return total - padding
+	}
+	for _, g := range h.toolbarGroups {
+		g.setCompact(false)
+	}
+	if width() > size.Width {
+		for _, g := range h.toolbarGroups {
+			g.setCompact(true)
+		}
+	}
+
+	room := size.Width
+	overflowing := width() > size.Width
+	if overflowing {
+		room -= h.toolbarOverflow.MinSize().Width + padding
+	}
+	h.toolbarHidden = nil
+	x := float32(0)
+	for _, g := range h.toolbarGroups {
+		groupWidth := g.MinSize().Width
+		if len(h.toolbarHidden) > 0 || (overflowing && x+groupWidth > room) {
+			h.toolbarHidden = append(h.toolbarHidden, g)
+			g.Hide()
+			continue
+		}
+		g.Show()
+		g.Move(fyne.NewPos(x, 0))
+		g.Resize(fyne.NewSize(groupWidth, size.Height))
+		x += groupWidth + padding
+	}
+
+	if overflowing {
+		h.toolbarOverflow.Show()
+	} else {
+		h.toolbarOverflow.Hide()
+	}
+	buttonSize := h.toolbarOverflow.MinSize()
+	h.toolbarOverflow.Move(fyne.NewPos(size.Width-buttonSize.Width, (size.Height-buttonSize.Height)/2))
+	h.toolbarOverflow.Resize(buttonSize)
+}
+
+// MinSize implements fyne.Layout. The toolbar can be as narrow as the overflow button,
+// so that it doesn't keep the window from being made narrow.
+func (l *toolbarLayout) MinSize(_ []fyne.CanvasObject) fyne.Size {
+	size := l.h.toolbarOverflow.MinSize()
+	for _, g := range l.h.toolbarGroups {
+		size.Height = max(size.Height, g.MinSize().Height)
+	}
+	return size
+}
+
+// showToolbarOverflow shows the menu of the toolbar items that don't fit the window
+func (h *HexDumpApp) showToolbarOverflow() {
+	var items []*fyne.MenuItem
+	for _, g := range h.toolbarHidden {
+		items = append(items, g.item.overflow(h))
+	}
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(h.toolbarOverflow)
+	position.Y += h.toolbarOverflow.Size().Height
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), h.window.Canvas(), position)
+}
+
+// createToolbar creates the toolbar with the controls chosen in the settings
+func (h *HexDumpApp) createToolbar() *fyne.Container {
+	// Byte grouping selector
+	h.byteGroupSelect = widget.NewSelect(
+		[]string{"1 byte", "2 bytes", "4 bytes", "8 bytes", "16 bytes"},
+		h.onByteGroupChanged,
+	)
+	h.byteGroupSelect.SetSelected("1 byte")
+
+	// Encoding selector
+	h.encodingSelect = widget.NewSelect(
+		encodingNames,
+		h.onEncodingChanged,
+	)
+	h.encodingSelect.SetSelected("ISO Latin-1")
+
+	// Byte order selector
+	h.byteOrderSelect = widget.NewSelect(byteOrderNames, h.onByteOrderChanged)
+	h.byteOrderSelect.SetSelected("Little-endian")
+
+	h.toolbarOverflow = widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), h.showToolbarOverflow)
+	h.toolbarBox = container.New(&toolbarLayout{h})
+	h.rebuildToolbar()
+
+	return container.NewStack(newThemedRectangle(colorNameBar), h.toolbarBox)
+}
+
+// rebuildToolbar fills the toolbar with the configured items, separated by separators
+func (h *HexDumpApp) rebuildToolbar() {
+	ids := appSettings.Toolbar
+	if ids == nil {
+		ids = defaultToolbar
+	}
+
+	h.toolbarGroups = nil
+	h.toolbarBox.Objects = nil
+	for _, id := range ids {
+		item := toolbarItemByID(id)
+		if item == nil {
+			continue
+		}
+		g := newToolbarGroup(h, item, len(h.toolbarGroups) > 0)
+		h.toolbarGroups = append(h.toolbarGroups, g)
+		h.toolbarBox.Add(g.Container)
+	}
+	h.toolbarBox.Add(h.toolbarOverflow)
+	h.toolbarBox.Refresh()
+}
+
+// createJumpEntry creates the toolbar field that moves the caret to an offset expression
+// when Enter is pressed
+func (h *HexDumpApp) createJumpEntry() fyne.CanvasObject {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(lang.L("Jump to, e.g. end-0x200"))
+	entry.OnSubmitted = func(text string) {
+		if len(h.fileData) == 0 {
+			return
+		}
+		offset, err := h.evaluateOffset(text)
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		if offset == len(h.fileData) {
+			offset-- // "end" means the last byte
+		}
+		if offset < 0 || offset >= len(h.fileData) {
+			dialog.ShowError(fmt.Errorf("offset 0x%X is outside the file", offset), h.window)
+			return
+		}
+		h.selAnchor = offset
+		h.caret = offset
+		h.setSelection(offset, offset+1)
+		h.scrollToByte(offset)
+	}
+
+	// An HBox gives the entry only its minimum width, so give it room for an expression
+	return container.NewGridWrap(fyne.NewSize(190, entry.MinSize().Height), entry)
+}
+
+// onByteOrderChanged handles byte order selection changes
+func (h *HexDumpApp) onByteOrderChanged(value string) {
+	h.bigEndian = value == "Big-endian"
+	if h.signedValues {
+		h.updateDisplay()
+	}
+	h.refreshPanels()
+}
+
+// byteOrder returns the byte order chosen for interpreting multi-byte values
+func (h *HexDumpApp) byteOrder() binary.ByteOrder {
+	if h.bigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// showCustomizeToolbar lets the user choose which items the toolbar shows and in
+// which order
+func (h *HexDumpApp) showCustomizeToolbar() {
+	// Shown items first, in their toolbar order, then the rest
+	shown := appSettings.Toolbar
+	if shown == nil {
+		shown = defaultToolbar
+	}
+	var order []string
+	for _, id := range shown {
+		if toolbarItemByID(id) != nil && !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+	for _, item := range toolbarItems {
+		if !slices.Contains(order, item.id) {
+			order = append(order, item.id)
+		}
+	}
+	enabled := make(map[string]bool)
+	for _, id := range shown {
+		enabled[id] = true
+	}
+
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(order) },
+		func() fyne.CanvasObject { return widget.NewCheck("", nil) },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			check := item.(*widget.Check)
+			itemID := order[id]
+			check.OnChanged = nil
+			check.SetText(lang.L(toolbarItemByID(itemID).label))
+			check.SetChecked(enabled[itemID])
+			check.OnChanged = func(checked bool) { enabled[itemID] = checked }
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+
+	move := func(delta int) {
+		target := selected + delta
+		if selected < 0 || target < 0 || target >= len(order) {
+			return
+		}
+		order[selected], order[target] = order[target], order[selected]
+		list.Select(target)
+		list.Refresh()
+	}
+	buttons := container.NewHBox(
+		widget.NewButton(lang.L("Move Up"), func() { move(-1) }),
+		widget.NewButton(lang.L("Move Down"), func() { move(1) }),
+		widget.NewButton(lang.L("Reset"), func() {
+			order = append([]string(nil), defaultToolbar...)
+			for _, item := range toolbarItems {
+				if !slices.Contains(order, item.id) {
+					order = append(order, item.id)
+				}
+			}
+			clear(enabled)
+			for _, id := range defaultToolbar {
+				enabled[id] = true
+			}
+			list.UnselectAll()
+			selected = -1
+			list.Refresh()
+		}),
+	)
+
+	content := container.NewBorder(nil, buttons, nil, nil, list)
+	customize := dialog.NewCustomConfirm(lang.L("Customize Toolbar"), lang.L("OK"), lang.L("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		toolbar := []string{}
+		for _, id := range order {
+			if enabled[id] {
+				toolbar = append(toolbar, id)
+			}
+		}
+		appSettings.Toolbar = toolbar
+		saveSettings()
+		h.rebuildToolbar()
+	}, h.window)
+	customize.Resize(fyne.NewSize(350, 450))
+	customize.Show()
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// createTooltipLayer creates the layer above the window content on which the hover
+// tooltip is drawn. It holds no interactive widgets, so events pass through it.
+func (h *HexDumpApp) createTooltipLayer() fyne.CanvasObject {
+	h.tooltipLabel = widget.NewLabel("")
+	h.tooltipLabel.TextStyle.Monospace = true
+	h.tooltipBox = container.NewStack(newThemedRectangle(colorNameTooltip), h.tooltipLabel)
+	h.tooltipBox.Hide()
+	h.tooltipLayer = container.NewWithoutLayout(h.tooltipBox)
+	return h.tooltipLayer
+}
+
+// showTooltip shows text in the tooltip near position, a position on the window's canvas
+func (h *HexDumpApp) showTooltip(text string, position fyne.Position) {
+	if h.tooltipLayer == nil {
+		return
+	}
+	h.tooltipLabel.SetText(text)
+	size := h.tooltipBox.MinSize()
+	h.tooltipBox.Resize(size)
+
+	// Place the tooltip below and to the right of the pointer, keeping it in the window
+	layerPosition := fyne.CurrentApp().Driver().AbsolutePositionForObject(h.tooltipLayer)
+	layerSize := h.tooltipLayer.Size()
+	x := position.X - layerPosition.X + 12
+	y := position.Y - layerPosition.Y + 18
+	if x+size.Width > layerSize.Width {
+		x = max(0, layerSize.Width-size.Width)
+	}
+	if y+size.Height > layerSize.Height {
+		y = max(0, position.Y-layerPosition.Y-size.Height-6)
+	}
+	h.tooltipBox.Move(fyne.NewPos(x, y))
+	h.tooltipBox.Show()
+	h.tooltipLayer.Refresh()
+}
+
+// hideTooltip hides the hover tooltip
+func (h *HexDumpApp) hideTooltip() {
+	if h.tooltipBox != nil && h.tooltipBox.Visible() {
+		h.tooltipBox.Hide()
+	}
+}
+
+// hoverByte shows the tooltip for the byte at offset under the pointer, naming the file
+// signature it belongs to and previewing its encodings, or hides the tooltip if offset
+// is negative or there is nothing to show
+func (h *HexDumpApp) hoverByte(offset int, position fyne.Position) {
+	if offset < 0 {
+		h.hideTooltip()
+		return
+	}
+	var lines []string
+	if name := h.signatureAt(offset); name != "" {
+		lines = append(lines, "Signature: "+name)
+	}
+	if pointer := h.pointerAt(offset); pointer != nil {
+		lines = append(lines, "Pointer to "+formatHex(uint64(pointer.target), 8)+" (Ctrl+click to follow)")
+	}
+	if group := h.groupPreview(offset); group != "" {
+		lines = append(lines, group)
+	}
+	if appSettings.EncodingTooltips {
+		lines = append(lines, encodingPreview(h.fileData, offset))
+	}
+	if len(lines) == 0 {
+		h.hideTooltip()
+		return
+	}
+	h.showTooltip(strings.Join(lines, "\n"), position)
+}
+
+// groupPreview describes the value of the byte group holding offset in the selected
+// byte order, as integers and as a floating-point number, when bytes are grouped by 2,
+// 4, or 8. It is empty for other groupings and for the short group at the end of a line.
+func (h *HexDumpApp) groupPreview(offset int) string {
+	size := h.bytesPerGroup
+	if size != 2 && size != 4 && size != 8 {
+		return ""
+	}
+	lineStart := h.lineStart(h.lineOf(offset))
+	start := lineStart + (offset-lineStart)/size*size
+	if start+size > h.lineEnd(lineStart) {
+		return ""
+	}
+	data := h.fileData[start : start+size]
+	order := h.byteOrder()
+	orderName := "little-endian"
+	if h.bigEndian {
+		orderName = "big-endian"
+	}
+	lines := []string{fmt.Sprintf("Group %s, %s:", formatHex(uint64(start), 8), orderName)}
+	for _, inspected := range inspectorTypes {
+		if inspected.size == size {
+			lines = append(lines, fmt.Sprintf("%-12s %s", inspected.name+":", inspected.format(data, order)))
+		}
+	}
+	if size == 2 {
+		lines = append(lines, fmt.Sprintf("%-12s %v", "Float16:", float16Value(order.Uint16(data))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// float16Value returns the value of an IEEE 754 half-precision floating-point number
+func float16Value(bits uint16) float64 {
+	sign := 1.0
+	if bits&0x8000 != 0 {
+		sign = -1
+	}
+	exponent := int(bits>>10) & 0x1F
+	fraction := float64(bits & 0x3FF)
+	switch exponent {
+	case 0:
+		return sign * math.Ldexp(fraction, -24) // Subnormal
+	case 0x1F:
+		if fraction == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(fraction+1024, exponent-25)
+}
+
+// encodingPreview describes the character decoded from the bytes at offset under each
+// supported encoding
+func encodingPreview(data []byte, offset int) string {
+	bytes := data[offset:min(offset+4, len(data))]
+	lines := []string{fmt.Sprintf("Offset %08X: %02X", offset, bytes[0])}
+
+	for _, encoding := range encodingNames {
+		r, size := decodeRune(bytes, encoding)
+		lines = append(lines, fmt.Sprintf("%-12s %s", encoding+":", describeRune(r, bytes[:size])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// decodeRune decodes the first character of data under the given encoding, returning
+// it, or utf8.RuneError if the bytes are invalid, and the number of bytes it uses
+func decodeRune(data []byte, encoding string) (rune, int) {
+	switch encoding {
+	case "UTF-8":
+		return utf8.DecodeRune(data)
+	case "UTF-16LE":
+		return decodeUTF16LE(data)
+	case "GB 18030":
+		return decodeGB18030(data)
+	}
+	return rune(data[0]), 1
+}
+
+// decodeUTF16LE decodes the first character of data as UTF-16LE, returning it and the
+// number of bytes it uses
+func decodeUTF16LE(data []byte) (rune, int) {
+	if len(data) < 2 {
+		return utf8.RuneError, len(data)
+	}
+	first := uint16(data[0]) | uint16(data[1])<<8
+	if utf16.IsSurrogate(rune(first)) && len(data) >= 4 {
+		second := uint16(data[2]) | uint16(data[3])<<8
+		if r := utf16.DecodeRune(rune(first), rune(second)); r != utf8.RuneError {
+			return r, 4
+		}
+	}
+	if utf16.IsSurrogate(rune(first)) {
+		return utf8.RuneError, 2
+	}
+	return rune(first), 2
+}
+
+// decodeGB18030 decodes the first character of data as GB 18030, returning it and the
+// number of bytes it uses
+func decodeGB18030(data []byte) (rune, int) {
+	size := min(gb18030SequenceLength(data), len(data))
+	decoded, err := simplifiedchinese.GB18030.NewDecoder().Bytes(data[:size])
+	if err != nil {
+		return utf8.RuneError, size
+	}
+	r, _ := utf8.DecodeRune(decoded)
+	return r, size
+}
+
+// describeRune formats a decoded character with its code point and the bytes it came from
+func describeRune(r rune, source []byte) string {
+	if r == utf8.RuneError {
+		return fmt.Sprintf("invalid (% X)", source)
+	}
+	shown := string(r)
+	if !unicode.IsPrint(r) {
+		shown = "."
+	}
+	return fmt.Sprintf("%s  U+%04X (% X)", shown, r, source)
+}
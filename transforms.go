@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+)
+
+// Transform names, as shown in the GUI and accepted by the CLI
+const (
+	transformXOR  = "XOR key"
+	transformSwap = "Endianness swap"
+)
+
+// transformNames lists the supported byte transforms in display order
+var transformNames = []string{transformXOR, transformSwap}
+
+// xorBytes returns a copy of data with each byte XORed with the repeating key
+func xorBytes(data []byte, key []byte) []byte {
+	result := make([]byte, len(data))
+	if len(key) == 0 {
+		copy(result, data)
+		return result
+	}
+	for index, b := range data {
+		result[index] = b ^ key[index%len(key)]
+	}
+	return result
+}
+
+// swapEndianness returns a copy of data with the byte order of each width-byte
+// unit reversed. A trailing partial unit is copied unchanged.
+func swapEndianness(data []byte, width int) []byte {
+	result := make([]byte, len(data))
+	copy(result, data)
+	if width < 2 {
+		return result
+	}
+	for index := 0; index+width <= len(result); index += width {
+		unit := result[index : index+width]
+		for low, high := 0, width-1; low < high; low, high = low+1, high-1 {
+			unit[low], unit[high] = unit[high], unit[low]
+		}
+	}
+	return result
+}
+
+// selectionCodec is a decoding or encoding of the selection that opens its result in a
+// new window, whose file name has suffix added
+type selectionCodec struct {
+	name   string
+	suffix string
+	apply  func(data []byte) ([]byte, error)
+}
+
+// selectionCodecs lists the codecs of the Decode/Encode menu in display order
+var selectionCodecs = []selectionCodec{
+	{"Quoted-Printable Decode", ".qp-decoded", decodeQuotedPrintable},
+	{"Quoted-Printable Encode", ".qp-encoded", encodeQuotedPrintable},
+	{"URL (Percent) Decode", ".url-decoded", decodePercent},
+	{"URL (Percent) Encode", ".url-encoded", encodePercent},
+}
+
+// decodeQuotedPrintable decodes quoted-printable data, as used in email bodies
+func decodeQuotedPrintable(data []byte) ([]byte, error) {
+	decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid quoted-printable data: %w", err)
+	}
+	return decoded, nil
+}
+
+// encodeQuotedPrintable encodes data as quoted-printable text with CRLF line breaks
+func encodeQuotedPrintable(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := quotedprintable.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodePercent decodes the %XX escapes of percent-encoded (URL-encoded) data. A % not
+// followed by two hex digits is kept as it is, and + is not treated as a space.
+func decodePercent(data []byte) ([]byte, error) {
+	decoded := make([]byte, 0, len(data))
+	for index := 0; index < len(data); index++ {
+		if data[index] == '%' && index+2 < len(data) && isHexDigit(data[index+1]) && isHexDigit(data[index+2]) {
+			value, _ := hex.DecodeString(string(data[index+1 : index+3]))
+			decoded = append(decoded, value[0])
+			index += 2
+			continue
+		}
+		decoded = append(decoded, data[index])
+	}
+	return decoded, nil
+}
+
+// encodePercent percent-encodes every byte of data except the unreserved characters
+// of RFC 3986: letters, digits, and - . _ ~
+func encodePercent(data []byte) ([]byte, error) {
+	var builder strings.Builder
+	for _, b := range data {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return []byte(builder.String()), nil
+}
+
+// isHexDigit reports whether b is a hexadecimal digit
+func isHexDigit(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')
+}
+
+// applyCodec decodes or encodes the selection with codec and opens the result in a new window
+func (h *HexDumpApp) applyCodec(codec selectionCodec) {
+	if !h.hasSelection() {
+		dialog.ShowInformation(codec.name, "Select the bytes to transform first.", h.window)
+		return
+	}
+	result, err := codec.apply(h.selectedBytes())
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	h.openDataInNewWindow(h.fileName+codec.suffix, result)
+}
+
+// codecMenu returns a submenu with an item for each selection codec
+func (h *HexDumpApp) codecMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(selectionCodecs))
+	for index, codec := range selectionCodecs {
+		items[index] = fyne.NewMenuItem(codec.name, func() { h.applyCodec(codec) })
+	}
+	return fyne.NewMenu(lang.L("Decode/Encode"), items...)
+}
+
+// parseHexBytes parses a string of hex digits into bytes. Whitespace and an
+// optional "0x" prefix are ignored, so "DE AD BE EF" and "0xdeadbeef" are equivalent.
+func parseHexBytes(text string) ([]byte, error) {
+	text = strings.Join(strings.Fields(text), "")
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "0x"), "0X")
+	if text == "" {
+		return nil, fmt.Errorf("no hex digits given")
+	}
+	data, err := hex.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string %q: %w", text, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// appVersion is the version of this build, set for releases with
+// -ldflags "-X main.appVersion=1.2.0"
+var appVersion = "1.0.0"
+
+// latestReleaseURL is the GitHub API endpoint describing the latest release
+const latestReleaseURL = "https://api.github.com/repos/fpl9000/hexdump/releases/latest"
+
+// updateCheckInterval is how often the startup update check asks GitHub
+const updateCheckInterval = 24 * time.Hour
+
+// downloadTimeout limits how long downloading an update may take
+const downloadTimeout = 10 * time.Minute
+
+// checksumSuffix ends the name of the asset holding the SHA-256 of an executable asset,
+// as in hexdump-windows-amd64.exe.sha256
+const checksumSuffix = ".sha256"
+
+// release is the part of a GitHub release the update check uses
+type release struct {
+	TagName string         `json:"tag_name"`
+	HTMLURL string         `json:"html_url"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+// releaseAsset is a file attached to a release
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int    `json:"size"`
+}
+
+// fetchLatestRelease asks GitHub for the latest release
+func fetchLatestRelease() (release, error) {
+	var latest release
+	request, err := http.NewRequest(http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return latest, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	client := &http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return latest, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return latest, fmt.Errorf("GitHub answered %s", response.Status)
+	}
+	if err := json.NewDecoder(response.Body).Decode(&latest); err != nil {
+		return latest, fmt.Errorf("reading the release: %w", err)
+	}
+	return latest, nil
+}
+
+// compareVersions compares two versions such as "v1.2.10" and "1.3", number by number,
+// returning -1, 0, or 1. A suffix such as "-beta" is ignored.
+func compareVersions(a, b string) int {
+	parts := func(version string) []string {
+		version, _, _ = strings.Cut(strings.TrimPrefix(version, "v"), "-")
+		return strings.Split(version, ".")
+	}
+	aParts, bParts := parts(a), parts(b)
+	for index := range max(len(aParts), len(bParts)) {
+		var aNumber, bNumber int
+		if index < len(aParts) {
+			aNumber, _ = strconv.Atoi(aParts[index])
+		}
+		if index < len(bParts) {
+			bNumber, _ = strconv.Atoi(bParts[index])
+		}
+		if aNumber != bNumber {
+			if aNumber < bNumber {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// executableAssetName returns the name of the release asset that is the executable for
+// the given system, such as hexdump-windows-amd64.exe
+func executableAssetName(goos, goarch string) string {
+	name := "hexdump-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// executableAsset returns the asset of a release that is the executable for this
+// system, together with the asset holding its SHA-256. A release without both can't
+// be installed.
+func (r release) executableAsset() (executable, checksum releaseAsset, ok bool) {
+	name := executableAssetName(runtime.GOOS, runtime.GOARCH)
+	var foundExecutable, foundChecksum bool
+	for _, asset := range r.Assets {
+		switch asset.Name {
+		case name:
+			executable, foundExecutable = asset, true
+		case name + checksumSuffix:
+			checksum, foundChecksum = asset, true
+		}
+	}
+	return executable, checksum, foundExecutable && foundChecksum && executable.Size > 0
+}
+
+// parseChecksum reads the SHA-256 from the contents of a checksum asset, which hold the
+// hex digest optionally followed by the file name, as sha256sum writes it
+func parseChecksum(text string) ([]byte, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the checksum file is empty")
+	}
+	digest, err := hex.DecodeString(fields[0])
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("the checksum file doesn't hold a SHA-256")
+	}
+	return digest, nil
+}
+
+// fetchChecksum downloads a checksum asset and returns the SHA-256 it holds
+func fetchChecksum(client *http.Client, asset releaseAsset) ([]byte, error) {
+	response, err := client.Get(asset.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", asset.Name, response.Status)
+	}
+	text, err := io.ReadAll(io.LimitReader(response.Body, 4096))
+	if err != nil {
+		return nil, err
+	}
+	return parseChecksum(string(text))
+}
+
+// oldExecutablePath returns where installUpdate moves the running executable, which
+// Windows won't let it delete
+func oldExecutablePath(executable string) string {
+	return executable + ".old"
+}
+
+// removeOldExecutable deletes the executable left behind by the last update
+func removeOldExecutable() {
+	if executable, err := os.Executable(); err == nil {
+		os.Remove(oldExecutablePath(executable))
+	}
+}
+
+// installUpdate downloads asset next to the running executable and, once its size and
+// its SHA-256 match those the release publishes, puts it in its place, reporting
+// progress as downloadFile does. The new version runs from the next start.
+func installUpdate(asset, checksum releaseAsset, progress func(done, total int) bool) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	client := &http.Client{Timeout: downloadTimeout}
+	digest, err := fetchChecksum(client, checksum)
+	if err != nil {
+		return err
+	}
+	download := executable + ".new"
+	if err := downloadFile(client, asset.URL, download, asset.Size, digest, progress); err != nil {
+		os.Remove(download)
+		return err
+	}
+
+	// A running executable can be renamed but not replaced on Windows
+	old := oldExecutablePath(executable)
+	os.Remove(old)
+	if err := os.Rename(executable, old); err != nil {
+		os.Remove(download)
+		return err
+	}
+	if err := os.Rename(download, executable); err != nil {
+		os.Rename(old, executable)
+		return err
+	}
+	return nil
+}
+
+// downloadFile downloads the file at address to path, calling progress with the bytes
+// downloaded of size. It stops with an error if progress returns false, and fails if
+// the file is not size bytes long or its SHA-256 is not digest.
+func downloadFile(client *http.Client, address, path string, size int, digest []byte,
+	progress func(done, total int) bool) error {
+	response, err := client.Get(address)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", address, response.Status)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	done := 0
+	hash := sha256.New()
+	buffer := make([]byte, 32*1024)
+	for {
+		n, readErr := response.Body.Read(buffer)
+		if _, err := file.Write(buffer[:n]); err != nil {
+			file.Close()
+			return err
+		}
+		hash.Write(buffer[:n])
+		done += n
+		if done > size {
+			file.Close()
+			return fmt.Errorf("the download is larger than the release says")
+		}
+		if progress != nil && !progress(done, size) {
+			file.Close()
+			return fmt.Errorf("download cancelled")
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			file.Close()
+			return readErr
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if done != size {
+		return fmt.Errorf("the download is %d bytes instead of %d", done, size)
+	}
+	if sum := hash.Sum(nil); !bytes.Equal(sum, digest) {
+		return fmt.Errorf("the download's SHA-256 %x doesn't match the published %x", sum, digest)
+	}
+	return nil
+}
+
+// checkForUpdatesAtStartup checks for a newer release in the background, if the user
+// turned the check on and it hasn't run for a day, and only speaks up if there is one
+func (h *HexDumpApp) checkForUpdatesAtStartup() {
+	removeOldExecutable()
+	if appSettings.CheckForUpdates && time.Since(appSettings.LastUpdateCheck) >= updateCheckInterval {
+		h.checkForUpdates(true)
+	}
+}
+
+// showUpdateCheck checks for a newer release, reporting the result either way
+func (h *HexDumpApp) showUpdateCheck() {
+	h.checkForUpdates(false)
+}
+
+// checkForUpdates asks GitHub for the latest release as a background task and offers it
+// if it is newer than this version. If quiet, nothing is shown unless it is.
+func (h *HexDumpApp) checkForUpdates(quiet bool) {
+	task := h.startTask(lang.L("Check for Updates"))
+	go func() {
+		defer h.recoverPanic()
+		latest, err := fetchLatestRelease()
+		fyne.Do(func() {
+			var result string
+			newer := err == nil && compareVersions(latest.TagName, appVersion) > 0
+			switch {
+			case err != nil:
+				slog.Warn("checking for updates", "error", err)
+				result = err.Error()
+			case newer:
+				result = lang.L("Version {{.Version}} is available", map[string]any{"Version": latest.TagName})
+			default:
+				result = lang.L("Up to date")
+			}
+			var open func()
+			if newer {
+				open = func() { h.showUpdateAvailable(latest) }
+			}
+			if !h.finishTask(task, result, open) {
+				return
+			}
+			if err == nil {
+				appSettings.LastUpdateCheck = time.Now()
+				saveSettings()
+			}
+			switch {
+			case newer:
+				open()
+			case quiet:
+			case err != nil:
+				dialog.ShowError(err, h.window)
+			default:
+				dialog.ShowInformation(lang.L("Check for Updates"),
+					lang.L("You have the latest version, {{.Version}}.", map[string]any{"Version": appVersion}), h.window)
+			}
+		})
+	}()
+}
+
+// showUpdateAvailable offers a newer release: to download and install it if it has an
+// executable for this system, or else to open its page
+func (h *HexDumpApp) showUpdateAvailable(latest release) {
+	message := widget.NewLabel(lang.L("Version {{.Version}} is available. This is version {{.Current}}.",
+		map[string]any{"Version": latest.TagName, "Current": appVersion}))
+	content := container.NewVBox(message)
+	if page, err := url.Parse(latest.HTMLURL); err == nil && latest.HTMLURL != "" {
+		content.Add(widget.NewHyperlink(lang.L("Release notes"), page))
+	}
+
+	asset, checksum, ok := latest.executableAsset()
+	if !ok {
+		dialog.ShowCustom(lang.L("Update Available"), lang.L("Close"), content, h.window)
+		return
+	}
+	dialog.ShowCustomConfirm(lang.L("Update Available"), lang.L("Download and Install"), lang.L("Later"), content,
+		func(ok bool) {
+			if ok {
+				h.downloadUpdate(latest, asset, checksum)
+			}
+		}, h.window)
+}
+
+// downloadUpdate downloads and installs a release's executable as a background task
+func (h *HexDumpApp) downloadUpdate(latest release, asset, checksum releaseAsset) {
+	task := h.startTask(lang.L("Download Update"))
+	go func() {
+		defer h.recoverPanic()
+		err := installUpdate(asset, checksum, task.report)
+		fyne.Do(func() {
+			result := lang.L("Installed version {{.Version}}", map[string]any{"Version": latest.TagName})
+			if err != nil {
+				result = err.Error()
+			}
+			if !h.finishTask(task, result, nil) {
+				return
+			}
+			if err != nil {
+				slog.Warn("installing update", "version", latest.TagName, "error", err)
+				dialog.ShowError(err, h.window)
+				return
+			}
+			slog.Info("installed update", "version", latest.TagName)
+			dialog.ShowInformation(lang.L("Update Installed"),
+				lang.L("Version {{.Version}} is installed. Restart the application to use it.",
+					map[string]any{"Version": latest.TagName}), h.window)
+		})
+	}()
+}
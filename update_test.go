@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestExecutableAsset(t *testing.T) {
+	name := executableAssetName(runtime.GOOS, runtime.GOARCH)
+	other := executableAssetName(runtime.GOOS, runtime.GOARCH+"64")
+	tests := []struct {
+		name   string
+		assets []releaseAsset
+		want   string
+		wantOK bool
+	}{
+		{"executable and checksum", []releaseAsset{{Name: name + checksumSuffix, Size: 64}, {Name: name, Size: 100}}, name, true},
+		{"checksum only", []releaseAsset{{Name: name + checksumSuffix, Size: 64}, {Name: name + ".sig", Size: 64}}, "", false},
+		{"no checksum", []releaseAsset{{Name: name, Size: 100}}, name, false},
+		{"other architecture", []releaseAsset{{Name: other, Size: 100}, {Name: other + checksumSuffix, Size: 64}}, "", false},
+		{"empty executable", []releaseAsset{{Name: name}, {Name: name + checksumSuffix, Size: 64}}, name, false},
+	}
+	for _, test := range tests {
+		asset, _, ok := release{Assets: test.assets}.executableAsset()
+		if asset.Name != test.want || ok != test.wantOK {
+			t.Errorf("%s: got %q, %v, want %q, %v", test.name, asset.Name, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	digest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	tests := []struct {
+		text    string
+		wantErr bool
+	}{
+		{digest, false},
+		{digest + "  hexdump-linux-amd64\n", false},
+		{"", true},
+		{digest[:40], true},
+		{"not a digest", true},
+	}
+	for _, test := range tests {
+		got, err := parseChecksum(test.text)
+		if (err != nil) != test.wantErr || (err == nil && len(got) != 32) {
+			t.Errorf("parseChecksum(%q) = %x, %v", test.text, got, err)
+		}
+	}
+}
+
+func TestDownloadFileVerifies(t *testing.T) {
+	content := []byte("new executable")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+	digest := sha256.Sum256(content)
+	wrong := sha256.Sum256([]byte("other"))
+
+	tests := []struct {
+		name    string
+		size    int
+		digest  []byte
+		wantErr bool
+	}{
+		{"matching", len(content), digest[:], false},
+		{"wrong digest", len(content), wrong[:], true},
+		{"shorter than published", len(content) + 1, digest[:], true},
+		{"longer than published", len(content) - 1, digest[:], true},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "download")
+		err := downloadFile(server.Client(), server.URL, path, test.size, test.digest, nil)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v", test.name, err)
+		}
+	}
+}
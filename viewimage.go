@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// menuCloseDelay is how long the capture of the view waits for the menu it was chosen
+// from to disappear from the window
+const menuCloseDelay = 150 * time.Millisecond
+
+// captureView returns an image of the visible part of the data list, as drawn, with its
+// colors and highlights
+func (h *HexDumpApp) captureView() (image.Image, error) {
+	if h.dataList == nil || len(h.fileData) == 0 {
+		return nil, errors.New("no file is shown")
+	}
+	canvas := h.window.Canvas()
+	captured := canvas.Capture()
+	if captured == nil || canvas.Size().Width == 0 {
+		return nil, errors.New("the window could not be captured")
+	}
+
+	// The capture is in pixels, which may be more than the canvas has units
+	scale := float32(captured.Bounds().Dx()) / canvas.Size().Width
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(h.dataList)
+	size := h.dataList.Size()
+	bounds := image.Rect(int(position.X*scale), int(position.Y*scale),
+		int((position.X+size.Width)*scale), int((position.Y+size.Height)*scale)).
+		Add(captured.Bounds().Min).Intersect(captured.Bounds())
+	view := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(view, view.Bounds(), captured, bounds.Min, draw.Src)
+	return view, nil
+}
+
+// captureViewPNG encodes an image of the visible part of the data list as PNG
+func (h *HexDumpApp) captureViewPNG() ([]byte, error) {
+	view, err := h.captureView()
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, view); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// afterMenuCloses calls f once the menu it was chosen from is no longer drawn, so that
+// captures of the window don't show it
+func afterMenuCloses(f func()) {
+	go func() {
+		time.Sleep(menuCloseDelay)
+		fyne.Do(f)
+	}()
+}
+
+// copyViewImage copies an image of the visible part of the data list to the clipboard,
+// for pasting into slides and chats
+func (h *HexDumpApp) copyViewImage() {
+	afterMenuCloses(func() {
+		data, err := h.captureViewPNG()
+		if err == nil {
+			err = copyToSystemClipboard(flavorPNG, data)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("copying the view as an image: %w", err), h.window)
+		}
+	})
+}
+
+// saveViewPNG saves an image of the visible part of the data list to a PNG file chosen
+// by the user
+func (h *HexDumpApp) saveViewPNG() {
+	afterMenuCloses(func() {
+		data, err := h.captureViewPNG()
+		if err != nil {
+			dialog.ShowError(err, h.window)
+			return
+		}
+		filename, err := nativedialog.File().Filter("PNG images", "png").Title("Save View as PNG").Save()
+		if err != nil {
+			if err.Error() != "Cancelled" {
+				dialog.ShowError(err, h.window)
+			}
+			return
+		}
+		if filepath.Ext(filename) == "" {
+			filename += ".png"
+		}
+		if err := os.WriteFile(filename, data, 0o644); err != nil {
+			dialog.ShowError(err, h.window)
+		}
+	})
+}
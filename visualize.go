@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Visualization layouts and color schemes, as shown in the GUI
+const (
+	layoutLinear  = "Linear"
+	layoutHilbert = "Hilbert curve"
+	colorByClass  = "Byte class"
+	colorEntropy  = "Entropy"
+)
+
+// Visualization image dimensions
+const (
+	linearImageWidth  = 256 // Width in pixels of the linear layout
+	maxLinearHeight   = 2048
+	maxHilbertSide    = 512 // Largest side in pixels of the Hilbert layout
+	minEntropyWindow  = 64  // Smallest number of bytes over which entropy is computed
+	visualizeViewSize = 512 // Minimum display size of the image
+)
+
+// byteClassColor returns the theme color representing the class of byte b
+func byteClassColor(b byte) fyne.ThemeColorName {
+	switch {
+	case b == 0x00:
+		return colorNameClassZero
+	case b == 0xFF:
+		return colorNameClassFF
+	case b >= 0x20 && b <= 0x7E:
+		return colorNameClassPrintable
+	case b < 0x20 || b == 0x7F:
+		return colorNameClassControl
+	default:
+		return colorNameClassHigh
+	}
+}
+
+// shannonEntropy returns the entropy of data in bits per byte, from 0 to 8
+func shannonEntropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(len(data))
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// hilbertPoint converts a distance d along a Hilbert curve filling a side x side square
+// (side being a power of two) into x and y coordinates
+func hilbertPoint(side, d int) (int, int) {
+	x, y := 0, 0
+	for s := 1; s < side; s *= 2 {
+		rx := 1 & (d / 2)
+		ry := 1 & (d ^ rx)
+		if ry == 0 {
+			if rx == 1 {
+				x, y = s-1-x, s-1-y
+			}
+			x, y = y, x
+		}
+		x += s * rx
+		y += s * ry
+		d /= 4
+	}
+	return x, y
+}
+
+// hilbertDistance is the inverse of hilbertPoint: it converts x and y coordinates in a
+// side x side square into the distance along the Hilbert curve
+func hilbertDistance(side, x, y int) int {
+	d := 0
+	for s := side / 2; s > 0; s /= 2 {
+		rx, ry := 0, 0
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		d += s * s * ((3 * rx) ^ ry)
+		if ry == 0 {
+			if rx == 1 {
+				x, y = side-1-x, side-1-y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// binaryVisualization maps the bytes of a file onto the pixels of an image
+type binaryVisualization struct {
+	layout        string
+	width, height int
+	bytesPerPixel int
+	dataLength    int
+}
+
+// newBinaryVisualization chooses image dimensions for data of the given length
+func newBinaryVisualization(layout string, dataLength int) *binaryVisualization {
+	v := &binaryVisualization{layout: layout, dataLength: dataLength}
+	if layout == layoutHilbert {
+		side := 1
+		for side < maxHilbertSide && side*side < dataLength {
+			side *= 2
+		}
+		v.width, v.height = side, side
+		v.bytesPerPixel = max(1, (dataLength+side*side-1)/(side*side))
+	} else {
+		v.width = linearImageWidth
+		v.bytesPerPixel = max(1, (dataLength+linearImageWidth*maxLinearHeight-1)/(linearImageWidth*maxLinearHeight))
+		pixels := (dataLength + v.bytesPerPixel - 1) / v.bytesPerPixel
+		v.height = max(1, (pixels+linearImageWidth-1)/linearImageWidth)
+	}
+	return v
+}
+
+// pixelOf returns the image coordinates of pixel number index
+func (v *binaryVisualization) pixelOf(index int) (int, int) {
+	if v.layout == layoutHilbert {
+		return hilbertPoint(v.width, index)
+	}
+	return index % v.width, index / v.width
+}
+
+// offsetAt returns the file offset shown by the pixel at x, y, or -1 if it shows no data
+func (v *binaryVisualization) offsetAt(x, y int) int {
+	index := y*v.width + x
+	if v.layout == layoutHilbert {
+		index = hilbertDistance(v.width, x, y)
+	}
+	offset := index * v.bytesPerPixel
+	if offset >= v.dataLength {
+		return -1
+	}
+	return offset
+}
+
+// render draws data with the given color scheme
+func (v *binaryVisualization) render(data []byte, scheme string) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, v.width, v.height))
+	entropyWindow := max(minEntropyWindow, v.bytesPerPixel)
+	var classColors [256]color.RGBA
+	for value := range classColors {
+		classColors[value] = paletteRGBA(byteClassColor(byte(value)))
+	}
+
+	for index := 0; index*v.bytesPerPixel < len(data); index++ {
+		start := index * v.bytesPerPixel
+		end := min(start+v.bytesPerPixel, len(data))
+
+		var pixel color.RGBA
+		if scheme == colorEntropy {
+			// Center the entropy window on the pixel's bytes
+			windowStart := max(0, start-(entropyWindow-v.bytesPerPixel)/2)
+			windowEnd := min(len(data), windowStart+entropyWindow)
+			pixel = heatColor(shannonEntropy(data[windowStart:windowEnd]) / 8)
+		} else {
+			// Average the class colors of the pixel's bytes
+			var r, g, b int
+			for _, value := range data[start:end] {
+				c := classColors[value]
+				r, g, b = r+int(c.R), g+int(c.G), b+int(c.B)
+			}
+			count := end - start
+			pixel = color.RGBA{R: uint8(r / count), G: uint8(g / count), B: uint8(b / count), A: 255}
+		}
+
+		x, y := v.pixelOf(index)
+		img.SetRGBA(x, y, pixel)
+	}
+	return img
+}
+
+// showVisualization opens the binary visualization window for the current file.
+// Clicking a pixel selects the bytes it represents in the data view.
+func (h *HexDumpApp) showVisualization() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Binary Visualization"), lang.L("No file is loaded."), h.window)
+		return
+	}
+
+	data := h.fileData
+	infoLabel := widget.NewLabel("")
+	hoverLabel := widget.NewLabel(lang.L("Click a pixel to go to its offset"))
+	view := newPixelView(image.NewRGBA(image.Rect(0, 0, 1, 1)), fyne.NewSize(visualizeViewSize, visualizeViewSize))
+
+	// Rendering runs in the background; generation discards results of a redraw
+	// that a newer one has superseded
+	var current *binaryVisualization
+	generation := 0
+	layoutSelect := widget.NewSelect([]string{layoutLinear, layoutHilbert}, nil)
+	schemeSelect := widget.NewSelect([]string{colorByClass, colorEntropy}, nil)
+
+	redraw := func(string) {
+		if layoutSelect.Selected == "" || schemeSelect.Selected == "" {
+			return
+		}
+		generation++
+		requested := generation
+		layout, scheme := layoutSelect.Selected, schemeSelect.Selected
+		infoLabel.SetText(lang.L("Rendering..."))
+		go func() {
+			defer h.recoverPanic()
+			v := newBinaryVisualization(layout, len(data))
+			img := v.render(data, scheme)
+			fyne.Do(func() {
+				if requested != generation {
+					return
+				}
+				current = v
+				view.setImage(img)
+				scale := float32(visualizeViewSize) / float32(v.width)
+				view.minSize = fyne.NewSize(visualizeViewSize, float32(v.height)*scale)
+				view.Refresh()
+				infoLabel.SetText(fmt.Sprintf("%dx%d pixels, %d byte(s) per pixel", v.width, v.height,
+					v.bytesPerPixel))
+			})
+		}()
+	}
+	layoutSelect.OnChanged = redraw
+	schemeSelect.OnChanged = redraw
+	layoutSelect.SetSelected(layoutHilbert)
+	schemeSelect.SetSelected(colorByClass)
+
+	view.onHover = func(x, y int) {
+		if current == nil {
+			return
+		}
+		if offset := current.offsetAt(x, y); offset >= 0 {
+			hoverLabel.SetText(fmt.Sprintf("Offset %08X", offset))
+		}
+	}
+	view.onTapped = func(x, y int) {
+		if current == nil {
+			return
+		}
+		if offset := current.offsetAt(x, y); offset >= 0 {
+			h.setSelection(offset, offset+current.bytesPerPixel)
+			h.goToOffset(offset)
+		}
+	}
+
+	legend := widget.NewLabel(lang.L("Byte class: black 00, white FF, blue printable ASCII, green control, red other"))
+	toolbar := container.NewHBox(widget.NewLabel(lang.L("Layout:")), layoutSelect, widget.NewLabel(lang.L("Color:")), schemeSelect)
+
+	window := h.app.NewWindow(fmt.Sprintf("Binary Visualization - %s", h.fileName))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(toolbar, infoLabel),
+		container.NewVBox(legend, hoverLabel),
+		nil, nil,
+		container.NewScroll(view),
+	))
+	window.Resize(fyne.NewSize(600, 700))
+	window.Show()
+}
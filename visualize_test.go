@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+func TestHilbertDistanceInvertsHilbertPoint(t *testing.T) {
+	for _, side := range []int{1, 2, 4, 8, 64, 512} {
+		for d := 0; d < side*side; d++ {
+			x, y := hilbertPoint(side, d)
+			if got := hilbertDistance(side, x, y); got != d {
+				t.Fatalf("side %d: hilbertDistance(%d, %d) = %d, want %d", side, x, y, got, d)
+			}
+		}
+	}
+}
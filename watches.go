@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// evaluateWatch evaluates a watch expression of the form type@offset, such as
+// "u32le@caret+8". The type is a numeric template field type, optionally followed by
+// "le" or "be" to override the chosen byte order, and the offset is an offset
+// expression. It returns the offset and the formatted value.
+func (h *HexDumpApp) evaluateWatch(text string) (int, string, error) {
+	typeName, offsetText, found := strings.Cut(strings.TrimSpace(text), "@")
+	if !found {
+		return 0, "", fmt.Errorf("expected type@offset, such as u32le@caret+8")
+	}
+
+	typeName = strings.ToLower(strings.TrimSpace(typeName))
+	order := h.byteOrder()
+	if _, ok := fieldSizes[typeName]; !ok {
+		switch {
+		case strings.HasSuffix(typeName, "le"):
+			order = binary.LittleEndian
+		case strings.HasSuffix(typeName, "be"):
+			order = binary.BigEndian
+		}
+		typeName = strings.TrimSuffix(strings.TrimSuffix(typeName, "le"), "be")
+	}
+	size, ok := fieldSizes[typeName]
+	if !ok {
+		return 0, "", fmt.Errorf("unknown type %q", typeName)
+	}
+
+	offset, err := h.evaluateOffset(offsetText)
+	if err != nil {
+		return 0, "", err
+	}
+	if offset < 0 || offset+size > len(h.fileData) {
+		return offset, "", fmt.Errorf("outside the file")
+	}
+	return offset, formatFieldValue(typeName, h.fileData[offset:offset+size], order), nil
+}
+
+// createWatchPanel creates the Watches side panel, which shows the values of watch
+// expressions such as "u32le@caret+8" and updates them as the caret moves. The
+// expressions are saved with the preferences.
+func (h *HexDumpApp) createWatchPanel() panelContent {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("u32le@caret+8")
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(appSettings.Watches) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), label)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			expression := appSettings.Watches[id]
+			offset, value, err := h.evaluateWatch(expression)
+			text := fmt.Sprintf("%s [%08X] = %s", expression, offset, value)
+			if err != nil {
+				text = fmt.Sprintf("%s: %v", expression, err)
+			}
+			row.Objects[0].(*widget.Label).SetText(text)
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				appSettings.Watches = append(appSettings.Watches[:id:id], appSettings.Watches[id+1:]...)
+				saveSettings()
+				list.Refresh()
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		if offset, _, err := h.evaluateWatch(appSettings.Watches[id]); err == nil {
+			h.goToOffset(offset)
+		}
+	}
+
+	add := func() {
+		text := strings.TrimSpace(entry.Text)
+		if text == "" {
+			return
+		}
+		appSettings.Watches = append(appSettings.Watches, text)
+		saveSettings()
+		entry.SetText("")
+		list.Refresh()
+	}
+	entry.OnSubmitted = func(string) { add() }
+
+	return panelContent{
+		object: container.NewBorder(
+			container.NewBorder(nil, nil, nil, widget.NewButton(lang.L("Add"), add), entry),
+			nil, nil, nil,
+			list,
+		),
+		refresh: list.Refresh,
+	}
+}
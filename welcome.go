@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxRecentFiles is the number of recently opened files remembered
+const maxRecentFiles = 10
+
+// welcomeRecentFiles is the number of recent files the welcome screen lists
+const welcomeRecentFiles = 5
+
+// addRecentFile moves a file to the top of the list of recently opened files
+func addRecentFile(filePath string) {
+	if absolute, err := filepath.Abs(filePath); err == nil {
+		filePath = absolute
+	}
+	recent := slices.DeleteFunc(slices.Clone(appSettings.RecentFiles), func(p string) bool { return p == filePath })
+	appSettings.RecentFiles = append([]string{filePath}, recent[:min(len(recent), maxRecentFiles-1)]...)
+}
+
+// recentMenu returns the File → Open Recent submenu listing the recently opened files
+func (h *HexDumpApp) recentMenu() *fyne.Menu {
+	var items []*fyne.MenuItem
+	for _, filePath := range appSettings.RecentFiles {
+		items = append(items, fyne.NewMenuItem(filePath, func() { h.openRecentFile(filePath) }))
+	}
+	if len(items) == 0 {
+		none := fyne.NewMenuItem(lang.L("No recent files"), nil)
+		none.Disabled = true
+		items = append(items, none)
+	}
+	return fyne.NewMenu("", items...)
+}
+
+// refreshRecentFiles updates the Open Recent submenu and the welcome screen after the
+// list of recent files changed
+func (h *HexDumpApp) refreshRecentFiles() {
+	if h.recentItem != nil {
+		h.recentItem.ChildMenu = h.recentMenu()
+		if menu := h.window.MainMenu(); menu != nil {
+			menu.Refresh()
+		}
+	}
+	h.refreshWelcome()
+}
+
+// openRecentFile opens a recently opened file, asking first if that discards edits
+func (h *HexDumpApp) openRecentFile(filePath string) {
+	h.confirmDiscardEdits(func() { h.loadFileFromPath(filePath) })
+}
+
+// openClipboardData shows the contents of the clipboard as a new file: the bytes it
+// spells out if it holds hex digits such as "DE AD BE EF", otherwise its text as UTF-8
+func (h *HexDumpApp) openClipboardData() {
+	text := h.window.Clipboard().Content()
+	if text == "" {
+		dialog.ShowInformation(lang.L("Open Clipboard Data"), lang.L("The clipboard is empty."), h.window)
+		return
+	}
+	data, err := parseHexBytes(text)
+	if err != nil {
+		data = []byte(text)
+	}
+	h.confirmDiscardEdits(func() { h.loadData("clipboard.bin", data) })
+}
+
+// openSampleFile shows a small generated PNG image, a file whose signature, chunks and
+// metadata the analysis tools can be tried on
+func (h *HexDumpApp) openSampleFile() {
+	h.confirmDiscardEdits(func() { h.loadData("sample.png", sampleImage()) })
+}
+
+// sampleImage returns the sample file: a 16x16 PNG image of a color gradient
+func sampleImage() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for y := range 16 {
+		for x := range 16 {
+			img.Set(x, y, color.RGBA{R: uint8(x * 16), G: uint8(y * 16), B: 0x80, A: 0xFF})
+		}
+	}
+	var buffer bytes.Buffer
+	png.Encode(&buffer, img)
+	return buffer.Bytes()
+}
+
+// createWelcome creates the welcome screen, shown over the data area while no file is
+// open: quick actions to open a file, the recent files, and the keyboard shortcuts
+func (h *HexDumpApp) createWelcome() fyne.CanvasObject {
+	title := widget.NewLabel(lang.L("Hex Dump Utility"))
+	title.TextStyle.Bold = true
+	title.SizeName = theme.SizeNameHeadingText
+
+	actions := container.NewVBox(
+		widget.NewButtonWithIcon(lang.L("Open File..."), theme.FolderOpenIcon(), h.openFile),
+		widget.NewButtonWithIcon(lang.L("Open Clipboard Data"), theme.ContentPasteIcon(), h.openClipboardData),
+		widget.NewButtonWithIcon(lang.L("Open Sample File"), theme.FileImageIcon(), h.openSampleFile),
+	)
+	h.welcomeRecent = container.NewVBox()
+	h.welcomeShortcuts = container.New(layout.NewFormLayout())
+
+	showAtStartup := widget.NewCheck(lang.L("Show this screen when no file is open"), func(on bool) {
+		appSettings.WelcomeScreen = on
+		saveSettings()
+	})
+	showAtStartup.Checked = appSettings.WelcomeScreen
+
+	heading := func(text string) *widget.Label {
+		label := widget.NewLabel(text)
+		label.TextStyle.Bold = true
+		return label
+	}
+	start := container.NewVBox(heading(lang.L("Start")), actions, heading(lang.L("Recent Files")), h.welcomeRecent)
+	shortcuts := container.NewVBox(heading(lang.L("Keyboard Shortcuts")), h.welcomeShortcuts)
+	content := container.NewVBox(title, container.NewGridWithColumns(2, start, shortcuts), showAtStartup)
+
+	h.welcome = container.NewStack(newThemedRectangle(theme.ColorNameBackground),
+		container.NewVScroll(container.NewCenter(content)))
+	h.refreshWelcome()
+	if !appSettings.WelcomeScreen {
+		h.welcome.Hide()
+	}
+	return h.welcome
+}
+
+// refreshWelcome lists the recent files and the current keyboard shortcuts on the
+// welcome screen
+func (h *HexDumpApp) refreshWelcome() {
+	if h.welcome == nil {
+		return
+	}
+	h.welcomeRecent.RemoveAll()
+	for _, filePath := range appSettings.RecentFiles[:min(len(appSettings.RecentFiles), welcomeRecentFiles)] {
+		button := widget.NewButton(filepath.Base(filePath), func() { h.openRecentFile(filePath) })
+		button.Alignment = widget.ButtonAlignLeading
+		button.Importance = widget.LowImportance
+		h.welcomeRecent.Add(button)
+	}
+	if len(h.welcomeRecent.Objects) == 0 {
+		h.welcomeRecent.Add(widget.NewLabel(lang.L("No recent files")))
+	}
+
+	h.welcomeShortcuts.RemoveAll()
+	for _, c := range commands {
+		if key := h.shortcuts[c.id]; key != "" {
+			keyLabel := widget.NewLabel(key)
+			keyLabel.TextStyle.Monospace = true
+			h.welcomeShortcuts.Add(widget.NewLabel(c.name()))
+			h.welcomeShortcuts.Add(keyLabel)
+		}
+	}
+}
+
+// hideWelcome hides the welcome screen, once a file is open
+func (h *HexDumpApp) hideWelcome() {
+	if h.welcome != nil {
+		h.welcome.Hide()
+	}
+}
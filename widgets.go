@@ -0,0 +1,186 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tappableLabel is a label that reports single and double taps, for use in lists
+// and trees where a double-click should open the item
+type tappableLabel struct {
+	widget.Label
+	onTapped       func()
+	onDoubleTapped func()
+}
+
+// newTappableLabel creates a tappable label with the given text
+func newTappableLabel(text string) *tappableLabel {
+	label := &tappableLabel{}
+	label.Text = text
+	label.ExtendBaseWidget(label)
+	return label
+}
+
+// Tapped implements fyne.Tappable
+func (l *tappableLabel) Tapped(*fyne.PointEvent) {
+	if l.onTapped != nil {
+		l.onTapped()
+	}
+}
+
+// DoubleTapped implements fyne.DoubleTappable
+func (l *tappableLabel) DoubleTapped(*fyne.PointEvent) {
+	if l.onDoubleTapped != nil {
+		l.onDoubleTapped()
+	}
+}
+
+// themedRectangle is a rectangle filled with a theme color, which follows the palette
+// chosen in the preferences
+type themedRectangle struct {
+	widget.BaseWidget
+	rectangle *canvas.Rectangle
+	colorName fyne.ThemeColorName
+}
+
+// newThemedRectangle creates a rectangle filled with the named theme color
+func newThemedRectangle(colorName fyne.ThemeColorName) *themedRectangle {
+	r := &themedRectangle{rectangle: canvas.NewRectangle(theme.Color(colorName)), colorName: colorName}
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+// CreateRenderer implements fyne.Widget
+func (r *themedRectangle) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(r.rectangle)
+}
+
+// Refresh implements fyne.Widget, picking up the color again when the theme changes
+func (r *themedRectangle) Refresh() {
+	r.rectangle.FillColor = theme.Color(r.colorName)
+	r.BaseWidget.Refresh()
+}
+
+// pixelView shows an image scaled to fill the widget and reports hovers and taps in
+// the image's own pixel coordinates
+type pixelView struct {
+	widget.BaseWidget
+	image     *canvas.Image
+	imageSize fyne.Size // Size of the source image in pixels
+	minSize   fyne.Size
+	onHover   func(x, y int)
+	onTapped  func(x, y int)
+}
+
+// newPixelView creates a pixel view of img with the given minimum display size
+func newPixelView(img image.Image, minSize fyne.Size) *pixelView {
+	view := &pixelView{minSize: minSize}
+	view.image = canvas.NewImageFromImage(img)
+	view.image.FillMode = canvas.ImageFillStretch
+	view.image.ScaleMode = canvas.ImageScalePixels
+	view.setImage(img)
+	view.ExtendBaseWidget(view)
+	return view
+}
+
+// setImage replaces the displayed image
+func (v *pixelView) setImage(img image.Image) {
+	bounds := img.Bounds()
+	v.imageSize = fyne.NewSize(float32(bounds.Dx()), float32(bounds.Dy()))
+	v.image.Image = img
+	v.image.Refresh()
+}
+
+// pixelAt converts a position in the widget to image pixel coordinates
+func (v *pixelView) pixelAt(pos fyne.Position) (int, int, bool) {
+	size := v.Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return 0, 0, false
+	}
+	x := int(pos.X / size.Width * v.imageSize.Width)
+	y := int(pos.Y / size.Height * v.imageSize.Height)
+	if x < 0 || y < 0 || x >= int(v.imageSize.Width) || y >= int(v.imageSize.Height) {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// CreateRenderer implements fyne.Widget
+func (v *pixelView) CreateRenderer() fyne.WidgetRenderer {
+	return &pixelViewRenderer{view: v}
+}
+
+// MouseIn implements desktop.Hoverable
+func (v *pixelView) MouseIn(event *desktop.MouseEvent) {
+	v.MouseMoved(event)
+}
+
+// MouseMoved implements desktop.Hoverable
+func (v *pixelView) MouseMoved(event *desktop.MouseEvent) {
+	if x, y, ok := v.pixelAt(event.Position); ok && v.onHover != nil {
+		v.onHover(x, y)
+	}
+}
+
+// MouseOut implements desktop.Hoverable
+func (v *pixelView) MouseOut() {}
+
+// Tapped implements fyne.Tappable
+func (v *pixelView) Tapped(event *fyne.PointEvent) {
+	if x, y, ok := v.pixelAt(event.Position); ok && v.onTapped != nil {
+		v.onTapped(x, y)
+	}
+}
+
+// pixelViewRenderer draws a pixelView
+type pixelViewRenderer struct {
+	view *pixelView
+}
+
+// Layout implements fyne.WidgetRenderer
+func (r *pixelViewRenderer) Layout(size fyne.Size) {
+	r.view.image.Resize(size)
+}
+
+// MinSize implements fyne.WidgetRenderer
+func (r *pixelViewRenderer) MinSize() fyne.Size {
+	return r.view.minSize
+}
+
+// Objects implements fyne.WidgetRenderer
+func (r *pixelViewRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.view.image}
+}
+
+// Refresh implements fyne.WidgetRenderer
+func (r *pixelViewRenderer) Refresh() {
+	r.view.image.Refresh()
+}
+
+// Destroy implements fyne.WidgetRenderer
+func (r *pixelViewRenderer) Destroy() {}
+
+// heatColor maps t in [0, 1] onto a black-blue-yellow-white heat map palette
+func heatColor(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	switch {
+	case t == 0:
+		return color.RGBA{A: 255}
+	case t < 1.0/3:
+		s := t * 3
+		return color.RGBA{R: 0, G: uint8(80 * s), B: uint8(80 + 175*s), A: 255}
+	case t < 2.0/3:
+		s := (t - 1.0/3) * 3
+		return color.RGBA{R: uint8(255 * s), G: uint8(80 + 140*s), B: uint8(255 * (1 - s)), A: 255}
+	default:
+		s := (t - 2.0/3) * 3
+		return color.RGBA{R: 255, G: uint8(220 + 35*s), B: uint8(255 * s), A: 255}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxReferences is the largest number of references listed
+const maxReferences = 10000
+
+// reference is a value at offset that may refer to the searched location
+type reference struct {
+	offset int
+	width  int
+	kind   string
+}
+
+// findReferences returns the locations in data holding value as a 32-bit or 64-bit
+// integer in either byte order, in order of offset
+func findReferences(data []byte, value uint64) []reference {
+	type encoding struct {
+		kind  string
+		bytes []byte
+	}
+	var encodings []encoding
+	if value <= 0xFFFFFFFF {
+		encodings = append(encodings,
+			encoding{"32-bit LE", binary.LittleEndian.AppendUint32(nil, uint32(value))},
+			encoding{"32-bit BE", binary.BigEndian.AppendUint32(nil, uint32(value))})
+	}
+	encodings = append(encodings,
+		encoding{"64-bit LE", binary.LittleEndian.AppendUint64(nil, value)},
+		encoding{"64-bit BE", binary.BigEndian.AppendUint64(nil, value)})
+
+	var refs []reference
+	for _, enc := range encodings {
+		pattern := searchPattern{data: enc.bytes, mask: bytes.Repeat([]byte{0xFF}, len(enc.bytes))}
+		for _, offset := range pattern.findAll(data, maxReferences) {
+			refs = append(refs, reference{offset: offset, width: len(enc.bytes), kind: enc.kind})
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].offset < refs[j].offset })
+	return refs[:min(len(refs), maxReferences)]
+}
+
+// showFindReferences searches the file for values referring to the caret offset, as
+// the offset plus a base address or as its virtual address in the address map, and
+// lists the candidate referencing locations
+func (h *HexDumpApp) showFindReferences() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("Find References"), lang.L("No file is loaded."), h.window)
+		return
+	}
+	target := h.caret
+
+	baseEntry := widget.NewEntry()
+	baseEntry.SetText("0")
+	mapCheck := widget.NewCheck(lang.L("Use the virtual address from the address map"), func(checked bool) {
+		if checked {
+			baseEntry.Disable()
+		} else {
+			baseEntry.Enable()
+		}
+	})
+	if _, mapped := h.toVirtual(target); !mapped {
+		mapCheck.Disable()
+	}
+
+	baseItem := widget.NewFormItem(lang.L("Base address"), baseEntry)
+	baseItem.HintText = "Added to the offset; the address of the file's first byte"
+	dialog.ShowForm(fmt.Sprintf("Find References to %08X", target), "Find", "Cancel", []*widget.FormItem{
+		baseItem,
+		widget.NewFormItem("", mapCheck),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		var value uint64
+		if mapCheck.Checked {
+			value, _ = h.toVirtual(target)
+		} else {
+			base, err := parseOffset(baseEntry.Text)
+			if err != nil || base < 0 {
+				dialog.ShowError(fmt.Errorf("the base address must be a non-negative number"), h.window)
+				return
+			}
+			value = uint64(base) + uint64(target)
+		}
+		h.showReferences(target, value, findReferences(h.fileData, value))
+	}, h.window)
+}
+
+// showReferences lists the references to target, found by searching for value.
+// Selecting one selects the referencing bytes.
+func (h *HexDumpApp) showReferences(target int, value uint64, refs []reference) {
+	if len(refs) == 0 {
+		dialog.ShowInformation(lang.L("Find References"),
+			fmt.Sprintf("No 32-bit or 64-bit value in the file equals 0x%X.", value), h.window)
+		return
+	}
+
+	list := widget.NewList(
+		func() int { return len(refs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %s", refs[id].offset, refs[id].kind))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		h.setSelection(refs[id].offset, refs[id].offset+refs[id].width)
+		h.goToOffset(refs[id].offset)
+	}
+
+	summary := fmt.Sprintf("%d candidate references to %08X (value 0x%X)", len(refs), target, value)
+	window := h.app.NewWindow(fmt.Sprintf("References to %08X - %s", target, h.fileName))
+	window.SetContent(container.NewBorder(widget.NewLabel(summary), nil, nil, nil, list))
+	window.Resize(fyne.NewSize(400, 400))
+	window.Show()
+}
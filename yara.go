@@ -0,0 +1,752 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+)
+
+// This file implements the subset of the YARA rule language most used for triage,
+// without the YARA library:
+//
+//   - text strings with the nocase, ascii and wide modifiers
+//   - hex strings, where "?" matches any nibble
+//   - conditions built from $a, #a and @a[1] comparisons, "any of them", "2 of ($a*)",
+//     filesize, true, false, and, or, not and parentheses
+//
+// Rules using anything else, such as regular expressions, hex jumps or modules, are
+// reported as unsupported.
+
+// maxYARAStringMatches is the largest number of matches found for each string
+const maxYARAStringMatches = 1000
+
+// yaraString is a string of a YARA rule, matched by any of its patterns
+type yaraString struct {
+	id       string // Including the "$"
+	patterns []searchPattern
+}
+
+// yaraRule is a parsed YARA rule
+type yaraRule struct {
+	name      string
+	strings   []yaraString
+	condition yaraExpr
+}
+
+// yaraMatch is a string of a rule that matched, in a rule whose condition is true
+type yaraMatch struct {
+	rule     string
+	stringID string
+	offset   int
+	length   int
+}
+
+// yaraScan is the data a condition is evaluated against: the offsets of the matches of
+// each string, by id
+type yaraScan struct {
+	matches  map[string][]int
+	fileSize int
+}
+
+// yaraExpr is a node of a parsed rule condition. Expressions yield integers, with
+// booleans as 0 or 1.
+type yaraExpr func(scan *yaraScan) int
+
+// yaraParser parses YARA rule text
+type yaraParser struct {
+	text string
+	pos  int
+}
+
+// parseYARA parses the rules in text
+func parseYARA(text string) ([]yaraRule, error) {
+	p := &yaraParser{text: text}
+	var rules []yaraRule
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.text) {
+			return rules, nil
+		}
+		rule, err := p.parseRule()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line(), err)
+		}
+		rules = append(rules, rule)
+	}
+}
+
+// line returns the line number of the parser's position
+func (p *yaraParser) line() int {
+	return strings.Count(p.text[:p.pos], "\n") + 1
+}
+
+// skipSpace skips white space and comments
+func (p *yaraParser) skipSpace() {
+	for p.pos < len(p.text) {
+		switch {
+		case unicode.IsSpace(rune(p.text[p.pos])):
+			p.pos++
+		case strings.HasPrefix(p.text[p.pos:], "//"):
+			if end := strings.IndexByte(p.text[p.pos:], '\n'); end >= 0 {
+				p.pos += end
+			} else {
+				p.pos = len(p.text)
+			}
+		case strings.HasPrefix(p.text[p.pos:], "/*"):
+			if end := strings.Index(p.text[p.pos+2:], "*/"); end >= 0 {
+				p.pos += end + 4
+			} else {
+				p.pos = len(p.text)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// isWordByte reports whether b can be part of an identifier
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// word returns the identifier or number at the parser's position, after any prefix
+// character such as "$", and advances past it
+func (p *yaraParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	if p.pos < len(p.text) && strings.IndexByte("$#@", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+	for p.pos < len(p.text) && (isWordByte(p.text[p.pos]) || p.text[p.pos] == '*' && start < p.pos) {
+		p.pos++
+	}
+	return p.text[start:p.pos]
+}
+
+// peekWord returns the word at the parser's position without advancing
+func (p *yaraParser) peekWord() string {
+	pos := p.pos
+	word := p.word()
+	p.pos = pos
+	return word
+}
+
+// accept advances past token and reports whether it was there
+func (p *yaraParser) accept(token string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.text[p.pos:], token) {
+		return false
+	}
+	// A keyword must not run into a longer identifier
+	if isWordByte(token[len(token)-1]) && p.pos+len(token) < len(p.text) && isWordByte(p.text[p.pos+len(token)]) {
+		return false
+	}
+	p.pos += len(token)
+	return true
+}
+
+// expect advances past token, or fails
+func (p *yaraParser) expect(token string) error {
+	if !p.accept(token) {
+		return fmt.Errorf("expected %q", token)
+	}
+	return nil
+}
+
+// parseRule parses one rule
+func (p *yaraParser) parseRule() (yaraRule, error) {
+	for p.accept("private") || p.accept("global") {
+	}
+	if p.accept("import") || p.accept("include") {
+		return yaraRule{}, fmt.Errorf("modules and includes are not supported")
+	}
+	if err := p.expect("rule"); err != nil {
+		return yaraRule{}, err
+	}
+	rule := yaraRule{name: p.word()}
+	if rule.name == "" {
+		return rule, fmt.Errorf("expected a rule name")
+	}
+	if p.accept(":") {
+		for p.peekWord() != "" { // Tags
+			p.word()
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return rule, err
+	}
+
+	if p.accept("meta") {
+		if err := p.expect(":"); err != nil {
+			return rule, err
+		}
+		for p.peekWord() != "strings" && p.peekWord() != "condition" {
+			if p.word() == "" || p.expect("=") != nil {
+				return rule, fmt.Errorf("invalid meta entry")
+			}
+			p.skipSpace()
+			if p.pos < len(p.text) && p.text[p.pos] == '"' {
+				if _, err := p.parseQuoted(); err != nil {
+					return rule, err
+				}
+			} else if p.word() == "" {
+				return rule, fmt.Errorf("invalid meta value")
+			}
+		}
+	}
+
+	if p.accept("strings") {
+		if err := p.expect(":"); err != nil {
+			return rule, err
+		}
+		for strings.HasPrefix(p.peekWord(), "$") {
+			str, err := p.parseString()
+			if err != nil {
+				return rule, err
+			}
+			rule.strings = append(rule.strings, str)
+		}
+	}
+
+	if err := p.expect("condition"); err != nil {
+		return rule, err
+	}
+	if err := p.expect(":"); err != nil {
+		return rule, err
+	}
+	condition, err := p.parseOr(&rule)
+	if err != nil {
+		return rule, err
+	}
+	rule.condition = condition
+	return rule, p.expect("}")
+}
+
+// parseQuoted parses a double-quoted string with C-style escapes
+func (p *yaraParser) parseQuoted() ([]byte, error) {
+	p.pos++ // Opening quote
+	var value []byte
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return value, nil
+		case '\n':
+			return nil, fmt.Errorf("unterminated string")
+		case '\\':
+			if p.pos >= len(p.text) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			escape := p.text[p.pos]
+			p.pos++
+			switch escape {
+			case 'n':
+				value = append(value, '\n')
+			case 't':
+				value = append(value, '\t')
+			case 'r':
+				value = append(value, '\r')
+			case 'x':
+				if p.pos+2 > len(p.text) {
+					return nil, fmt.Errorf("invalid \\x escape")
+				}
+				b, err := strconv.ParseUint(p.text[p.pos:p.pos+2], 16, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid \\x escape")
+				}
+				value = append(value, byte(b))
+				p.pos += 2
+			default:
+				value = append(value, escape)
+			}
+		default:
+			value = append(value, c)
+		}
+	}
+	return nil, fmt.Errorf("unterminated string")
+}
+
+// parseString parses a string definition such as $a = "text" nocase wide
+func (p *yaraParser) parseString() (yaraString, error) {
+	str := yaraString{id: p.word()}
+	if err := p.expect("="); err != nil {
+		return str, err
+	}
+	p.skipSpace()
+	if p.pos >= len(p.text) {
+		return str, fmt.Errorf("expected a string value")
+	}
+
+	switch p.text[p.pos] {
+	case '{':
+		end := strings.IndexByte(p.text[p.pos:], '}')
+		if end < 0 {
+			return str, fmt.Errorf("unterminated hex string")
+		}
+		body := p.text[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+		if strings.ContainsAny(body, "[]()|~") {
+			return str, fmt.Errorf("hex string %s: jumps, alternatives and negation are not supported", str.id)
+		}
+		pattern, err := parseHexPattern(body)
+		if err != nil {
+			return str, fmt.Errorf("hex string %s: %w", str.id, err)
+		}
+		str.patterns = []searchPattern{pattern}
+	case '"':
+		value, err := p.parseQuoted()
+		if err != nil {
+			return str, err
+		}
+		if len(value) == 0 {
+			return str, fmt.Errorf("string %s is empty", str.id)
+		}
+		nocase, ascii, wide := false, false, false
+		for {
+			switch p.peekWord() {
+			case "nocase":
+				nocase = true
+			case "ascii":
+				ascii = true
+			case "wide":
+				wide = true
+			case "fullword", "private", "xor", "base64", "base64wide":
+				return str, fmt.Errorf("string %s: the %s modifier is not supported", str.id, p.peekWord())
+			default:
+				if !wide || ascii {
+					str.patterns = append(str.patterns, yaraTextPattern(value, nocase))
+				}
+				if wide {
+					var widened []byte
+					for _, b := range value {
+						widened = append(widened, b, 0)
+					}
+					str.patterns = append(str.patterns, yaraTextPattern(widened, nocase))
+				}
+				return str, nil
+			}
+			p.word()
+		}
+	case '/':
+		return str, fmt.Errorf("string %s: regular expressions are not supported", str.id)
+	default:
+		return str, fmt.Errorf("expected a string value for %s", str.id)
+	}
+	return str, nil
+}
+
+// yaraTextPattern returns a pattern matching value, ignoring the case of ASCII letters
+// when nocase is set. Upper and lower case letters differ only in bit 5, which the
+// mask leaves out.
+func yaraTextPattern(value []byte, nocase bool) searchPattern {
+	pattern := searchPattern{data: bytes.Clone(value), mask: bytes.Repeat([]byte{0xFF}, len(value))}
+	if nocase {
+		for index, b := range value {
+			if b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' {
+				pattern.data[index] = b &^ 0x20
+				pattern.mask[index] = 0xDF
+			}
+		}
+	}
+	return pattern
+}
+
+// parseOr parses a condition of terms joined by "or"
+func (p *yaraParser) parseOr(rule *yaraRule) (yaraExpr, error) {
+	left, err := p.parseAnd(rule)
+	for err == nil && p.accept("or") {
+		var right yaraExpr
+		if right, err = p.parseAnd(rule); err == nil {
+			l, r := left, right
+			left = func(scan *yaraScan) int { return boolInt(l(scan) != 0 || r(scan) != 0) }
+		}
+	}
+	return left, err
+}
+
+// parseAnd parses a condition of terms joined by "and"
+func (p *yaraParser) parseAnd(rule *yaraRule) (yaraExpr, error) {
+	left, err := p.parseNot(rule)
+	for err == nil && p.accept("and") {
+		var right yaraExpr
+		if right, err = p.parseNot(rule); err == nil {
+			l, r := left, right
+			left = func(scan *yaraScan) int { return boolInt(l(scan) != 0 && r(scan) != 0) }
+		}
+	}
+	return left, err
+}
+
+// parseNot parses a term with any number of leading "not"s
+func (p *yaraParser) parseNot(rule *yaraRule) (yaraExpr, error) {
+	if p.accept("not") {
+		operand, err := p.parseNot(rule)
+		if err != nil {
+			return nil, err
+		}
+		return func(scan *yaraScan) int { return boolInt(operand(scan) == 0) }, nil
+	}
+	return p.parseComparison(rule)
+}
+
+// parseComparison parses a value, optionally compared with another
+func (p *yaraParser) parseComparison(rule *yaraRule) (yaraExpr, error) {
+	left, err := p.parseValue(rule)
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.accept(operator) {
+			continue
+		}
+		right, err := p.parseValue(rule)
+		if err != nil {
+			return nil, err
+		}
+		compare := map[string]func(a, b int) bool{
+			"==": func(a, b int) bool { return a == b },
+			"!=": func(a, b int) bool { return a != b },
+			"<=": func(a, b int) bool { return a <= b },
+			">=": func(a, b int) bool { return a >= b },
+			"<":  func(a, b int) bool { return a < b },
+			">":  func(a, b int) bool { return a > b },
+		}[operator]
+		return func(scan *yaraScan) int { return boolInt(compare(left(scan), right(scan))) }, nil
+	}
+	return left, nil
+}
+
+// parseValue parses a parenthesized condition, a string reference, a count, an offset,
+// a quantified set of strings, filesize, true, false or a number
+func (p *yaraParser) parseValue(rule *yaraRule) (yaraExpr, error) {
+	if p.accept("(") {
+		inner, err := p.parseOr(rule)
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+
+	word := p.word()
+	switch {
+	case word == "":
+		return nil, fmt.Errorf("unexpected %q in condition", p.text[p.pos:min(p.pos+10, len(p.text))])
+	case word == "true", word == "false":
+		value := boolInt(word == "true")
+		return func(*yaraScan) int { return value }, nil
+	case word == "filesize":
+		return func(scan *yaraScan) int { return scan.fileSize }, nil
+	case word == "any" || word == "all" || word[0] >= '0' && word[0] <= '9' && p.peekWord() == "of":
+		return p.parseOf(rule, word)
+	case word[0] >= '0' && word[0] <= '9':
+		return p.parseNumber(word)
+	}
+
+	if strings.IndexByte("$#@", word[0]) < 0 {
+		return nil, fmt.Errorf("%q is not supported in conditions", word)
+	}
+	id := "$" + word[1:]
+	if !rule.hasString(id) {
+		return nil, fmt.Errorf("undefined string %s", id)
+	}
+	switch word[0] {
+	case '$':
+		if p.accept("at") {
+			at, err := p.parseValue(rule)
+			if err != nil {
+				return nil, err
+			}
+			return func(scan *yaraScan) int {
+				offset := at(scan)
+				index := sort.SearchInts(scan.matches[id], offset)
+				return boolInt(index < len(scan.matches[id]) && scan.matches[id][index] == offset)
+			}, nil
+		}
+		return func(scan *yaraScan) int { return boolInt(len(scan.matches[id]) > 0) }, nil
+	case '#':
+		return func(scan *yaraScan) int { return len(scan.matches[id]) }, nil
+	case '@':
+		index := func(*yaraScan) int { return 1 }
+		if p.accept("[") {
+			var err error
+			if index, err = p.parseOr(rule); err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		}
+		return func(scan *yaraScan) int {
+			n := index(scan)
+			if n < 1 || n > len(scan.matches[id]) {
+				return -1 // YARA's value is undefined, which compares as false in practice
+			}
+			return scan.matches[id][n-1]
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in condition", word)
+}
+
+// parseNumber parses a decimal or 0x number with an optional KB or MB suffix
+func (p *yaraParser) parseNumber(word string) (yaraExpr, error) {
+	multiplier := 1
+	if number, ok := strings.CutSuffix(word, "KB"); ok {
+		word, multiplier = number, 1024
+	} else if number, ok := strings.CutSuffix(word, "MB"); ok {
+		word, multiplier = number, 1024*1024
+	}
+	value, err := strconv.ParseInt(word, 0, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", word)
+	}
+	result := int(value) * multiplier
+	return func(*yaraScan) int { return result }, nil
+}
+
+// parseOf parses the rest of "any of them", "all of ($a, $b*)" or "2 of them"
+func (p *yaraParser) parseOf(rule *yaraRule, quantifier string) (yaraExpr, error) {
+	if err := p.expect("of"); err != nil {
+		return nil, err
+	}
+	var ids []string
+	if p.accept("them") {
+		for _, str := range rule.strings {
+			ids = append(ids, str.id)
+		}
+	} else {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			pattern := p.word()
+			if !strings.HasPrefix(pattern, "$") {
+				return nil, fmt.Errorf("expected a string in the set")
+			}
+			found := false
+			for _, str := range rule.strings {
+				if str.id == pattern || strings.HasSuffix(pattern, "*") && strings.HasPrefix(str.id, strings.TrimSuffix(pattern, "*")) {
+					ids = append(ids, str.id)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("undefined string %s", pattern)
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	needed := len(ids)
+	switch quantifier {
+	case "any":
+		needed = 1
+	case "all":
+	default:
+		count, err := strconv.Atoi(quantifier)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantifier %q", quantifier)
+		}
+		needed = count
+	}
+	return func(scan *yaraScan) int {
+		matched := 0
+		for _, id := range ids {
+			if len(scan.matches[id]) > 0 {
+				matched++
+			}
+		}
+		return boolInt(matched >= needed)
+	}, nil
+}
+
+// hasString reports whether the rule defines the string with the given id
+func (r *yaraRule) hasString(id string) bool {
+	for _, str := range r.strings {
+		if str.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// boolInt returns 1 for true and 0 for false
+func boolInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// scanYARA matches the rules against data, returning the string matches of the rules
+// whose conditions are true, in order of offset. progress, if not nil, is told how many
+// rules have been matched, and stops the scan by returning false.
+func scanYARA(rules []yaraRule, data []byte, progress func(done, total int) bool) []yaraMatch {
+	var matches []yaraMatch
+	for index, rule := range rules {
+		if progress != nil && !progress(index, len(rules)) {
+			return nil
+		}
+		scan := &yaraScan{matches: make(map[string][]int), fileSize: len(data)}
+		lengths := make(map[int]int) // Match lengths by offset, per string in turn
+		var found []yaraMatch
+		for _, str := range rule.strings {
+			clear(lengths)
+			for _, pattern := range str.patterns {
+				for _, offset := range pattern.findAll(data, maxYARAStringMatches) {
+					lengths[offset] = len(pattern.data)
+				}
+			}
+			for offset, length := range lengths {
+				scan.matches[str.id] = append(scan.matches[str.id], offset)
+				found = append(found, yaraMatch{rule: rule.name, stringID: str.id, offset: offset, length: length})
+			}
+			sort.Ints(scan.matches[str.id])
+		}
+		if rule.condition(scan) != 0 {
+			if len(found) == 0 {
+				// A rule matching without strings, such as on filesize, covers the file
+				found = append(found, yaraMatch{rule: rule.name, length: len(data)})
+			}
+			matches = append(matches, found...)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].offset != matches[j].offset {
+			return matches[i].offset < matches[j].offset
+		}
+		return matches[i].rule < matches[j].rule
+	})
+	return matches
+}
+
+// yaraSpans returns highlight spans for the YARA matches intersecting [lineStart, lineEnd)
+func (h *HexDumpApp) yaraSpans(lineStart, lineEnd int) []highlightSpan {
+	var spans []highlightSpan
+	for _, match := range h.yaraMatches {
+		if match.offset >= lineEnd {
+			break
+		}
+		start, end := max(match.offset, lineStart), min(match.offset+match.length, lineEnd)
+		if start < end {
+			spans = append(spans, highlightSpan{start: start, end: end, color: theme.Color(colorNameYARA)})
+		}
+	}
+	return spans
+}
+
+// yaraAt returns the names of the rules and strings matching the byte at offset, for
+// the status bar
+func (h *HexDumpApp) yaraAt(offset int) string {
+	var names []string
+	for _, match := range h.yaraMatches {
+		if match.offset > offset {
+			break
+		}
+		if offset < match.offset+match.length {
+			names = append(names, strings.TrimSpace(match.rule+" "+match.stringID))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// scanYARAFile asks for a YARA rule file, scans the file with its rules, and lists and
+// highlights the matches
+func (h *HexDumpApp) scanYARAFile() {
+	if len(h.fileData) == 0 {
+		dialog.ShowInformation(lang.L("YARA Scan"), lang.L("No file is loaded."), h.window)
+		return
+	}
+	filename, err := nativedialog.File().Filter("YARA rules", "yar", "yara").Title("YARA Rules").Load()
+	if err != nil {
+		if err.Error() != "Cancelled" {
+			dialog.ShowError(err, h.window)
+		}
+		return
+	}
+	text, err := os.ReadFile(filename)
+	if err != nil {
+		dialog.ShowError(err, h.window)
+		return
+	}
+	rules, err := parseYARA(string(text))
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", filename, err), h.window)
+		return
+	}
+
+	task := h.startTask(lang.L("YARA Scan"))
+	data := h.fileData
+	go func() {
+		defer h.recoverPanic()
+		matches := scanYARA(rules, data, task.report)
+		fyne.Do(func() {
+			show := func() { h.showYARAMatches(len(rules)) }
+			if !h.finishTask(task, fmt.Sprintf("%d match(es) of %d rule(s)", len(matches), len(rules)), show) {
+				return
+			}
+			h.yaraMatches = matches
+			h.updateDisplay()
+			h.updateStatus()
+			h.showYARAMatches(len(rules))
+		})
+	}()
+}
+
+// clearYARAMatches removes the highlighted YARA matches
+func (h *HexDumpApp) clearYARAMatches() {
+	h.yaraMatches = nil
+	h.updateDisplay()
+	h.updateStatus()
+}
+
+// showYARAMatches lists the YARA matches in a tool window. Clicking one selects it.
+func (h *HexDumpApp) showYARAMatches(ruleCount int) {
+	matches := h.yaraMatches
+	if len(matches) == 0 {
+		dialog.ShowInformation(lang.L("YARA Scan"), fmt.Sprintf("None of the %d rules matched.", ruleCount), h.window)
+		return
+	}
+
+	matched := make(map[string]bool)
+	for _, match := range matches {
+		matched[match.rule] = true
+	}
+	list := widget.NewList(
+		func() int { return len(matches) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			match := matches[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%08X  %s %s (%d bytes)", match.offset, match.rule,
+				match.stringID, match.length))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		h.setSelection(matches[id].offset, matches[id].offset+matches[id].length)
+		h.goToOffset(matches[id].offset)
+	}
+
+	window := h.app.NewWindow(fmt.Sprintf("YARA Matches - %s", h.fileName))
+	summary := widget.NewLabel(fmt.Sprintf("%d of %d rules matched, %d string matches", len(matched), ruleCount,
+		len(matches)))
+	window.SetContent(container.NewBorder(summary, nil, nil, nil, list))
+	window.Resize(fyne.NewSize(450, 400))
+	window.Show()
+}